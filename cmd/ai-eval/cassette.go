@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Cassette modes
+const (
+	modeLive   = "live"   // call the backend, record nothing
+	modeRecord = "record" // call the backend and store every response
+	modeReplay = "replay" // serve stored responses only, never touch the network
+)
+
+// cassetteEntry is a recorded backend response
+type cassetteEntry struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// cassetteFile is the on-disk recording format
+type cassetteFile struct {
+	Version int                       `json:"version"`
+	Entries map[string]*cassetteEntry `json:"entries"`
+}
+
+// Cassette is an http.RoundTripper that records or replays chat completion responses.
+// Requests are keyed by a hash of the request body, so any prompt or model change
+// produces a miss in replay mode instead of a stale answer.
+type Cassette struct {
+	mode  string
+	path  string
+	next  http.RoundTripper
+	mu    sync.Mutex
+	data  cassetteFile
+	dirty bool
+
+	Hits   int
+	Misses int
+}
+
+// NewCassette loads a recording file (missing is fine when recording)
+func NewCassette(path, mode string, next http.RoundTripper) (*Cassette, error) {
+	c := &Cassette{
+		mode: mode,
+		path: path,
+		next: next,
+		data: cassetteFile{Version: 1, Entries: make(map[string]*cassetteEntry)},
+	}
+
+	raw, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(raw, &c.data); err != nil {
+			return nil, fmt.Errorf("failed to parse recording %s: %w", path, err)
+		}
+		if c.data.Entries == nil {
+			c.data.Entries = make(map[string]*cassetteEntry)
+		}
+	case errors.Is(err, os.ErrNotExist) && mode == modeRecord:
+		// Created on save
+	default:
+		return nil, fmt.Errorf("failed to read recording %s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	key := hex.EncodeToString(sum[:])
+
+	c.mu.Lock()
+	entry, ok := c.data.Entries[key]
+	c.mu.Unlock()
+
+	if ok && c.mode == modeReplay {
+		c.mu.Lock()
+		c.Hits++
+		c.mu.Unlock()
+		return c.response(req, entry), nil
+	}
+	if c.mode == modeReplay {
+		c.mu.Lock()
+		c.Misses++
+		c.mu.Unlock()
+		return nil, fmt.Errorf("no recorded response for request %s (re-record with -mode record)", key[:12])
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := c.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only successful answers are worth replaying
+	if c.mode == modeRecord && resp.StatusCode == http.StatusOK && json.Valid(respBody) {
+		c.mu.Lock()
+		c.data.Entries[key] = &cassetteEntry{Status: resp.StatusCode, Body: respBody}
+		c.dirty = true
+		c.mu.Unlock()
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+func (c *Cassette) response(req *http.Request, entry *cassetteEntry) *http.Response {
+	return &http.Response{
+		StatusCode:    entry.Status,
+		Status:        http.StatusText(entry.Status),
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+	}
+}
+
+// Save writes new recordings back to disk
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write recording %s: %w", c.path, err)
+	}
+	c.dirty = false
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// GoldenSet is a versioned set of labelled articles
+type GoldenSet struct {
+	Version     string    `json:"version"`
+	Description string    `json:"description"`
+	Articles    []Fixture `json:"-"`
+	Dir         string    `json:"-"`
+}
+
+// Fixture is a single labelled article
+type Fixture struct {
+	ID       string   `json:"id"`
+	Source   string   `json:"source"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Expected Expected `json:"expected"`
+}
+
+// Expected holds the human labels for a fixture. Empty lists mean "none expected";
+// a nil list (field omitted) means the field is not labelled and is not scored.
+type Expected struct {
+	Sentiment      string   `json:"sentiment"`                 // positive, negative, neutral
+	SentimentScore *float64 `json:"sentiment_score,omitempty"` // optional reference score
+	Categories     []string `json:"categories,omitempty"`      // any of these counts as a correct top category
+	Persons        []string `json:"persons"`
+	Organizations  []string `json:"organizations"`
+	Locations      []string `json:"locations"`
+	StockTickers   []string `json:"stock_tickers"`
+}
+
+// LoadGoldenSet reads manifest.json and every fixture in <dir>/articles
+func LoadGoldenSet(dir string) (*GoldenSet, error) {
+	manifest, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden set manifest: %w", err)
+	}
+
+	set := &GoldenSet{Dir: dir}
+	if err := json.Unmarshal(manifest, set); err != nil {
+		return nil, fmt.Errorf("failed to parse golden set manifest: %w", err)
+	}
+	if set.Version == "" {
+		return nil, fmt.Errorf("golden set manifest has no version")
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "articles", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	seen := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture %s: %w", file, err)
+		}
+
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", file, err)
+		}
+		if fixture.ID == "" || fixture.Title == "" {
+			return nil, fmt.Errorf("fixture %s requires id and title", file)
+		}
+		if other, dup := seen[fixture.ID]; dup {
+			return nil, fmt.Errorf("duplicate fixture id %s in %s and %s", fixture.ID, other, file)
+		}
+		seen[fixture.ID] = file
+
+		set.Articles = append(set.Articles, fixture)
+	}
+
+	if len(set.Articles) == 0 {
+		return nil, fmt.Errorf("golden set %s contains no fixtures", dir)
+	}
+
+	return set, nil
+}
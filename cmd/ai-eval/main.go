@@ -0,0 +1,254 @@
+// Command ai-eval runs the AI enrichment pipeline over a versioned golden set of
+// labelled Dutch articles and reports per-field accuracy, entity F1 and the
+// difference with the previous run.
+//
+//	go run ./cmd/ai-eval -set ./cmd/ai-eval/goldenset -mode replay
+//	go run ./cmd/ai-eval -mode record -model gpt-4o-mini -label "prompt v2"
+//	OPENAI_BASE_URL=http://localhost:11434/v1 go run ./cmd/ai-eval -model llama3.1
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/ai"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+func main() {
+	var (
+		setDir        = flag.String("set", "cmd/ai-eval/goldenset", "golden set directory (manifest.json + articles/*.json)")
+		mode          = flag.String("mode", modeLive, "backend mode: live, record or replay")
+		recording     = flag.String("recording", "", "recorded responses file (default <set>/recordings/<model>.json)")
+		outDir        = flag.String("out", "eval-runs", "directory for run results")
+		baseline      = flag.String("baseline", "", "run file to diff against (default: previous run for the same set version)")
+		model         = flag.String("model", envOr("OPENAI_MODEL", "gpt-3.5-turbo"), "model name")
+		baseURL       = flag.String("base-url", os.Getenv("OPENAI_BASE_URL"), "OpenAI-compatible API base URL")
+		maxTokens     = flag.Int("max-tokens", 1000, "max tokens per completion")
+		timeout       = flag.Duration("timeout", 60*time.Second, "timeout per article")
+		label         = flag.String("label", "", "free-text label stored with the run (e.g. prompt version)")
+		only          = flag.String("only", "", "comma-separated fixture IDs to run")
+		failThreshold = flag.Float64("fail-on-regression", 0, "exit 1 when any metric drops more than this (0 = never)")
+		verbose       = flag.Bool("v", false, "print per-article mismatches")
+	)
+	flag.Parse()
+
+	log := logger.New(logger.Config{Level: "warn", Format: "console"})
+
+	if *mode != modeLive && *mode != modeRecord && *mode != modeReplay {
+		fatalf("unknown mode %q (use live, record or replay)", *mode)
+	}
+
+	set, err := LoadGoldenSet(*setDir)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	fixtures := filterFixtures(set.Articles, *only)
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		if *mode != modeReplay {
+			fatalf("OPENAI_API_KEY is required in %s mode", *mode)
+		}
+		apiKey = "replay"
+	}
+
+	client := ai.NewOpenAIClient(apiKey, *model, *maxTokens, log)
+	client.SetBaseURL(*baseURL)
+
+	var cassette *Cassette
+	if *mode != modeLive {
+		path := *recording
+		if path == "" {
+			path = filepath.Join(set.Dir, "recordings", sanitizeFileName(*model)+".json")
+		}
+		if *mode == modeRecord {
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				fatalf("failed to create recordings directory: %v", err)
+			}
+		}
+		cassette, err = NewCassette(path, *mode, http.DefaultTransport)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		client.SetTransport(cassette)
+	}
+
+	opts := ai.ProcessingOptions{
+		EnableSentiment:  true,
+		EnableEntities:   true,
+		EnableCategories: true,
+		EnableKeywords:   true,
+		EnableSummary:    true,
+	}
+
+	run := &Run{
+		RunID:      time.Now().UTC().Format("20060102T150405.000Z"),
+		StartedAt:  time.Now().UTC(),
+		SetVersion: set.Version,
+		Model:      *model,
+		BaseURL:    *baseURL,
+		Mode:       *mode,
+		Label:      *label,
+		Articles:   make([]ArticleResult, 0, len(fixtures)),
+	}
+
+	fmt.Printf("Evaluating %d articles from golden set %s (%s mode)\n", len(fixtures), set.Version, *mode)
+
+	for i, fixture := range fixtures {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		start := time.Now()
+		enrichment, err := client.ProcessArticle(ctx, fixture.Title, fixture.Content, opts)
+		latency := time.Since(start)
+		cancel()
+
+		var result ArticleResult
+		if err != nil {
+			result = scoreArticle(fixture, &ai.AIEnrichment{})
+			result.Error = err.Error()
+		} else {
+			result = scoreArticle(fixture, enrichment)
+		}
+		result.LatencyMs = latency.Milliseconds()
+		run.Articles = append(run.Articles, result)
+
+		status := "ok"
+		if result.Error != "" {
+			status = "error: " + truncate(result.Error, 80)
+		}
+		fmt.Printf("  [%d/%d] %s %s\n", i+1, len(fixtures), fixture.ID, status)
+	}
+
+	if cassette != nil {
+		if err := cassette.Save(); err != nil {
+			fatalf("%v", err)
+		}
+		if *mode == modeReplay && cassette.Misses > 0 {
+			fmt.Printf("\n%d requests had no recording; re-run with -mode record\n", cassette.Misses)
+		}
+	}
+
+	run.Metrics = aggregate(run.Articles)
+
+	// Look up the baseline before saving so the new run is not its own baseline
+	var previous *Run
+	if *baseline != "" {
+		if previous, err = loadRun(*baseline); err != nil {
+			fatalf("failed to load baseline: %v", err)
+		}
+	} else if previous, _, err = findPreviousRun(*outDir, set.Version); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not read previous runs: %v\n", err)
+	}
+
+	path, err := saveRun(*outDir, run)
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	fmt.Println()
+	printSummary(os.Stdout, run)
+
+	if *verbose {
+		printMismatches(run)
+	}
+
+	worst := 0.0
+	if previous != nil {
+		worst = printDiff(os.Stdout, previous, run)
+	} else {
+		fmt.Println("\nNo previous run for this golden set version; nothing to diff.")
+	}
+
+	fmt.Printf("\nRun saved to %s\n", path)
+
+	if *failThreshold > 0 && worst > *failThreshold {
+		fmt.Printf("Regression of %.3f exceeds threshold %.3f\n", worst, *failThreshold)
+		os.Exit(1)
+	}
+}
+
+func printMismatches(run *Run) {
+	fmt.Println("\nMismatches:")
+	for _, a := range run.Articles {
+		lines := make([]string, 0)
+		if a.SentimentCorrect != nil && !*a.SentimentCorrect {
+			lines = append(lines, "sentiment: got "+orNone(a.PredictedSentiment))
+		}
+		if a.CategoryCorrect != nil && !*a.CategoryCorrect {
+			lines = append(lines, "category: got "+orNone(a.PredictedCategory))
+		}
+		for _, field := range entityFields {
+			if m := a.Missing[field]; len(m) > 0 {
+				lines = append(lines, fmt.Sprintf("%s missing: %s", field, strings.Join(m, ", ")))
+			}
+			if e := a.Extra[field]; len(e) > 0 {
+				lines = append(lines, fmt.Sprintf("%s extra: %s", field, strings.Join(e, ", ")))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Printf("  %s\n", a.ID)
+		for _, l := range lines {
+			fmt.Printf("    %s\n", l)
+		}
+	}
+}
+
+func filterFixtures(fixtures []Fixture, only string) []Fixture {
+	if only == "" {
+		return fixtures
+	}
+	wanted := make(map[string]bool)
+	for _, id := range strings.Split(only, ",") {
+		wanted[strings.TrimSpace(id)] = true
+	}
+	filtered := make([]Fixture, 0, len(wanted))
+	for _, f := range fixtures {
+		if wanted[f.ID] {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "ai-eval: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/jeffrey/intellinieuws/internal/ai"
+	"github.com/jeffrey/intellinieuws/internal/entity"
+	"github.com/jeffrey/intellinieuws/internal/models"
+)
+
+// entityFields are the entity lists scored with precision/recall/F1
+var entityFields = []string{"persons", "organizations", "locations", "stock_tickers"}
+
+// PRF holds set-matching counts and derived scores
+type PRF struct {
+	TP        int     `json:"tp"`
+	FP        int     `json:"fp"`
+	FN        int     `json:"fn"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+}
+
+func (p *PRF) add(other PRF) {
+	p.TP += other.TP
+	p.FP += other.FP
+	p.FN += other.FN
+}
+
+func (p *PRF) finalize() {
+	p.Precision, p.Recall, p.F1 = 0, 0, 0
+	if p.TP+p.FP > 0 {
+		p.Precision = float64(p.TP) / float64(p.TP+p.FP)
+	}
+	if p.TP+p.FN > 0 {
+		p.Recall = float64(p.TP) / float64(p.TP+p.FN)
+	}
+	if p.Precision+p.Recall > 0 {
+		p.F1 = 2 * p.Precision * p.Recall / (p.Precision + p.Recall)
+	}
+	// Nothing expected and nothing predicted is a perfect answer
+	if p.TP+p.FP+p.FN == 0 {
+		p.Precision, p.Recall, p.F1 = 1, 1, 1
+	}
+}
+
+// ArticleResult is the scored outcome for one fixture
+type ArticleResult struct {
+	ID    string `json:"id"`
+	Error string `json:"error,omitempty"`
+
+	PredictedSentiment string   `json:"predicted_sentiment,omitempty"`
+	PredictedScore     *float64 `json:"predicted_score,omitempty"`
+	SentimentCorrect   *bool    `json:"sentiment_correct,omitempty"`
+	SentimentAbsError  *float64 `json:"sentiment_abs_error,omitempty"`
+
+	PredictedCategory string `json:"predicted_category,omitempty"`
+	CategoryCorrect   *bool  `json:"category_correct,omitempty"`
+
+	Entities map[string]PRF `json:"entities,omitempty"`
+
+	// Mismatches for quick inspection: "missing" = expected but not found, "extra" = hallucinated
+	Missing map[string][]string `json:"missing,omitempty"`
+	Extra   map[string][]string `json:"extra,omitempty"`
+
+	LatencyMs int64 `json:"latency_ms"`
+}
+
+// Metrics are the aggregated scores of a run
+type Metrics struct {
+	Articles int `json:"articles"`
+	Errors   int `json:"errors"`
+
+	SentimentAccuracy float64 `json:"sentiment_accuracy"`
+	SentimentMAE      float64 `json:"sentiment_mae"` // over fixtures with a reference score
+	CategoryAccuracy  float64 `json:"category_accuracy"`
+
+	Entities      map[string]PRF `json:"entities"`
+	EntitiesMicro PRF            `json:"entities_micro"`
+
+	AvgLatencyMs int64 `json:"avg_latency_ms"`
+}
+
+// scoreArticle compares an enrichment with the fixture labels
+func scoreArticle(fixture Fixture, enrichment *ai.AIEnrichment) ArticleResult {
+	result := ArticleResult{
+		ID:       fixture.ID,
+		Entities: make(map[string]PRF),
+		Missing:  make(map[string][]string),
+		Extra:    make(map[string][]string),
+	}
+	exp := fixture.Expected
+
+	// Sentiment
+	if exp.Sentiment != "" {
+		correct := false
+		if enrichment.Sentiment != nil {
+			result.PredictedSentiment = strings.ToLower(enrichment.Sentiment.Label)
+			score := enrichment.Sentiment.Score
+			result.PredictedScore = &score
+			correct = result.PredictedSentiment == strings.ToLower(exp.Sentiment)
+
+			if exp.SentimentScore != nil {
+				absErr := math.Abs(score - *exp.SentimentScore)
+				result.SentimentAbsError = &absErr
+			}
+		}
+		result.SentimentCorrect = &correct
+	}
+
+	// Top category must be one of the accepted labels
+	if len(exp.Categories) > 0 {
+		result.PredictedCategory = topCategory(enrichment.Categories)
+		correct := false
+		for _, c := range exp.Categories {
+			if strings.EqualFold(c, result.PredictedCategory) {
+				correct = true
+				break
+			}
+		}
+		result.CategoryCorrect = &correct
+	}
+
+	// Entities
+	var predicted ai.EntityExtraction
+	if enrichment.Entities != nil {
+		predicted = *enrichment.Entities
+	}
+	tickers := make([]string, 0, len(predicted.StockTickers))
+	for _, t := range predicted.StockTickers {
+		tickers = append(tickers, t.Symbol)
+	}
+
+	pairs := map[string]struct {
+		expected  []string
+		predicted []string
+		key       func(string) string
+	}{
+		"persons":       {exp.Persons, predicted.Persons, entityKey(models.EntityTypePerson)},
+		"organizations": {exp.Organizations, predicted.Organizations, entityKey(models.EntityTypeOrganization)},
+		"locations":     {exp.Locations, predicted.Locations, entityKey(models.EntityTypeLocation)},
+		"stock_tickers": {exp.StockTickers, tickers, tickerKey},
+	}
+	for _, field := range entityFields {
+		pair := pairs[field]
+		if pair.expected == nil {
+			continue // not labelled
+		}
+		prf, missing, extra := matchSets(pair.expected, pair.predicted, pair.key)
+		result.Entities[field] = prf
+		if len(missing) > 0 {
+			result.Missing[field] = missing
+		}
+		if len(extra) > 0 {
+			result.Extra[field] = extra
+		}
+	}
+
+	return result
+}
+
+// aggregate combines article results into run metrics
+func aggregate(results []ArticleResult) Metrics {
+	m := Metrics{
+		Articles: len(results),
+		Entities: make(map[string]PRF),
+	}
+
+	var sentimentTotal, sentimentCorrect, scoreCount int
+	var categoryTotal, categoryCorrect int
+	var absErrSum float64
+	var latencySum int64
+
+	for _, r := range results {
+		latencySum += r.LatencyMs
+		if r.Error != "" {
+			m.Errors++
+		}
+		if r.SentimentCorrect != nil {
+			sentimentTotal++
+			if *r.SentimentCorrect {
+				sentimentCorrect++
+			}
+		}
+		if r.SentimentAbsError != nil {
+			scoreCount++
+			absErrSum += *r.SentimentAbsError
+		}
+		if r.CategoryCorrect != nil {
+			categoryTotal++
+			if *r.CategoryCorrect {
+				categoryCorrect++
+			}
+		}
+		for field, prf := range r.Entities {
+			agg := m.Entities[field]
+			agg.add(prf)
+			m.Entities[field] = agg
+			m.EntitiesMicro.add(prf)
+		}
+	}
+
+	if sentimentTotal > 0 {
+		m.SentimentAccuracy = float64(sentimentCorrect) / float64(sentimentTotal)
+	}
+	if scoreCount > 0 {
+		m.SentimentMAE = absErrSum / float64(scoreCount)
+	}
+	if categoryTotal > 0 {
+		m.CategoryAccuracy = float64(categoryCorrect) / float64(categoryTotal)
+	}
+	for field, prf := range m.Entities {
+		prf.finalize()
+		m.Entities[field] = prf
+	}
+	m.EntitiesMicro.finalize()
+	if len(results) > 0 {
+		m.AvgLatencyMs = latencySum / int64(len(results))
+	}
+
+	return m
+}
+
+// matchSets compares expected and predicted names after normalization
+func matchSets(expected, predicted []string, key func(string) string) (PRF, []string, []string) {
+	exp := make(map[string]string)
+	for _, e := range expected {
+		if k := key(e); k != "" {
+			exp[k] = e
+		}
+	}
+	pred := make(map[string]string)
+	for _, p := range predicted {
+		if k := key(p); k != "" {
+			pred[k] = p
+		}
+	}
+
+	var prf PRF
+	missing := make([]string, 0)
+	extra := make([]string, 0)
+	for k, name := range exp {
+		if _, ok := pred[k]; ok {
+			prf.TP++
+		} else {
+			prf.FN++
+			missing = append(missing, name)
+		}
+	}
+	for k, name := range pred {
+		if _, ok := exp[k]; !ok {
+			prf.FP++
+			extra = append(extra, name)
+		}
+	}
+	prf.finalize()
+	sort.Strings(missing)
+	sort.Strings(extra)
+
+	return prf, missing, extra
+}
+
+// entityKey normalizes names the same way the entity registry does
+// (accents, titles such as "premier", legal suffixes such as "N.V.")
+func entityKey(entityType string) func(string) string {
+	return func(name string) string {
+		return entity.Normalize(name, entityType)
+	}
+}
+
+// tickerKey compares symbols without exchange suffix ("ASML.AS" == "ASML")
+func tickerKey(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if i := strings.LastIndexByte(symbol, '.'); i > 0 {
+		symbol = symbol[:i]
+	}
+	return symbol
+}
+
+func topCategory(categories map[string]float64) string {
+	best := ""
+	bestScore := -1.0
+	for name, score := range categories {
+		if score > bestScore || (score == bestScore && name < best) {
+			best, bestScore = name, score
+		}
+	}
+	return best
+}
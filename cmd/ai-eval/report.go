@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Run is a stored evaluation run
+type Run struct {
+	RunID      string          `json:"run_id"`
+	StartedAt  time.Time       `json:"started_at"`
+	SetVersion string          `json:"set_version"`
+	Model      string          `json:"model"`
+	BaseURL    string          `json:"base_url,omitempty"`
+	Mode       string          `json:"mode"`
+	Label      string          `json:"label,omitempty"` // free text, e.g. "prompt v2"
+	Metrics    Metrics         `json:"metrics"`
+	Articles   []ArticleResult `json:"articles"`
+}
+
+// saveRun writes a run as <dir>/run-<id>.json
+func saveRun(dir string, run *Run) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, "run-"+run.RunID+".json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write run: %w", err)
+	}
+	return path, nil
+}
+
+// loadRun reads a stored run
+func loadRun(path string) (*Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse run %s: %w", path, err)
+	}
+	return &run, nil
+}
+
+// findPreviousRun returns the most recent run in dir for the same golden set version
+func findPreviousRun(dir, setVersion string) (*Run, string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "run-*.json"))
+	if err != nil || len(files) == 0 {
+		return nil, "", err
+	}
+
+	// Run IDs are timestamps, so lexical order is chronological
+	sort.Sort(sort.Reverse(sort.StringSlice(files)))
+	for _, file := range files {
+		run, err := loadRun(file)
+		if err != nil {
+			continue
+		}
+		if run.SetVersion == setVersion {
+			return run, file, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// printSummary writes the metrics table of a run
+func printSummary(w io.Writer, run *Run) {
+	m := run.Metrics
+	fmt.Fprintf(w, "Golden set %s | model %s | mode %s | %d articles, %d errors | avg latency %dms\n\n",
+		run.SetVersion, run.Model, run.Mode, m.Articles, m.Errors, m.AvgLatencyMs)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tPRECISION\tRECALL\tF1/ACC")
+	fmt.Fprintf(tw, "sentiment (label)\t\t\t%.3f\n", m.SentimentAccuracy)
+	fmt.Fprintf(tw, "sentiment (MAE)\t\t\t%.3f\n", m.SentimentMAE)
+	fmt.Fprintf(tw, "category (top-1)\t\t\t%.3f\n", m.CategoryAccuracy)
+	for _, field := range entityFields {
+		if prf, ok := m.Entities[field]; ok {
+			fmt.Fprintf(tw, "%s\t%.3f\t%.3f\t%.3f\n", field, prf.Precision, prf.Recall, prf.F1)
+		}
+	}
+	fmt.Fprintf(tw, "entities (micro)\t%.3f\t%.3f\t%.3f\n", m.EntitiesMicro.Precision, m.EntitiesMicro.Recall, m.EntitiesMicro.F1)
+	tw.Flush()
+}
+
+// metricDelta is a named metric compared between two runs
+type metricDelta struct {
+	Name          string
+	Previous      float64
+	Current       float64
+	LowerIsBetter bool
+}
+
+func (d metricDelta) change() float64 {
+	if d.LowerIsBetter {
+		return d.Previous - d.Current
+	}
+	return d.Current - d.Previous
+}
+
+func compareMetrics(prev, cur Metrics) []metricDelta {
+	deltas := []metricDelta{
+		{Name: "sentiment accuracy", Previous: prev.SentimentAccuracy, Current: cur.SentimentAccuracy},
+		{Name: "sentiment MAE", Previous: prev.SentimentMAE, Current: cur.SentimentMAE, LowerIsBetter: true},
+		{Name: "category accuracy", Previous: prev.CategoryAccuracy, Current: cur.CategoryAccuracy},
+		{Name: "entities micro F1", Previous: prev.EntitiesMicro.F1, Current: cur.EntitiesMicro.F1},
+	}
+	for _, field := range entityFields {
+		p, okP := prev.Entities[field]
+		c, okC := cur.Entities[field]
+		if okP && okC {
+			deltas = append(deltas, metricDelta{Name: field + " F1", Previous: p.F1, Current: c.F1})
+		}
+	}
+	return deltas
+}
+
+// printDiff reports metric deltas and per-article regressions/improvements
+func printDiff(w io.Writer, prev, cur *Run) (worstRegression float64) {
+	fmt.Fprintf(w, "\nDiff against run %s (model %s%s)\n\n", prev.RunID, prev.Model, labelSuffix(prev.Label))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METRIC\tPREVIOUS\tCURRENT\tCHANGE")
+	for _, d := range compareMetrics(prev.Metrics, cur.Metrics) {
+		change := d.change()
+		marker := ""
+		switch {
+		case change > 0.0005:
+			marker = "▲"
+		case change < -0.0005:
+			marker = "▼"
+			if -change > worstRegression {
+				worstRegression = -change
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%.3f\t%.3f\t%+.3f %s\n", d.Name, d.Previous, d.Current, change, marker)
+	}
+	tw.Flush()
+
+	prevByID := make(map[string]ArticleResult, len(prev.Articles))
+	for _, a := range prev.Articles {
+		prevByID[a.ID] = a
+	}
+
+	var regressions, improvements []string
+	for _, cur := range cur.Articles {
+		old, ok := prevByID[cur.ID]
+		if !ok {
+			continue
+		}
+		for _, change := range articleChanges(old, cur) {
+			if strings.HasPrefix(change, "-") {
+				regressions = append(regressions, fmt.Sprintf("%s: %s", cur.ID, change[1:]))
+			} else {
+				improvements = append(improvements, fmt.Sprintf("%s: %s", cur.ID, change[1:]))
+			}
+		}
+	}
+
+	if len(regressions) > 0 {
+		fmt.Fprintf(w, "\nRegressions (%d):\n", len(regressions))
+		for _, r := range regressions {
+			fmt.Fprintf(w, "  - %s\n", r)
+		}
+	}
+	if len(improvements) > 0 {
+		fmt.Fprintf(w, "\nImprovements (%d):\n", len(improvements))
+		for _, i := range improvements {
+			fmt.Fprintf(w, "  + %s\n", i)
+		}
+	}
+	if len(regressions) == 0 && len(improvements) == 0 {
+		fmt.Fprintln(w, "\nNo per-article changes.")
+	}
+
+	return worstRegression
+}
+
+// articleChanges lists field-level flips between two results, prefixed "-" (worse) or "+" (better)
+func articleChanges(old, cur ArticleResult) []string {
+	changes := make([]string, 0)
+
+	flip := func(field string, before, after *bool, detail string) {
+		if before == nil || after == nil || *before == *after {
+			return
+		}
+		if *after {
+			changes = append(changes, "+"+field+" now correct ("+detail+")")
+		} else {
+			changes = append(changes, "-"+field+" now wrong ("+detail+")")
+		}
+	}
+	flip("sentiment", old.SentimentCorrect, cur.SentimentCorrect, old.PredictedSentiment+" → "+cur.PredictedSentiment)
+	flip("category", old.CategoryCorrect, cur.CategoryCorrect, old.PredictedCategory+" → "+cur.PredictedCategory)
+
+	for _, field := range entityFields {
+		before, okB := old.Entities[field]
+		after, okA := cur.Entities[field]
+		if !okB || !okA {
+			continue
+		}
+		switch {
+		case after.F1 < before.F1-0.0005:
+			changes = append(changes, fmt.Sprintf("-%s F1 %.2f → %.2f", field, before.F1, after.F1))
+		case after.F1 > before.F1+0.0005:
+			changes = append(changes, fmt.Sprintf("+%s F1 %.2f → %.2f", field, before.F1, after.F1))
+		}
+	}
+
+	if old.Error == "" && cur.Error != "" {
+		changes = append(changes, "-now failing: "+cur.Error)
+	} else if old.Error != "" && cur.Error == "" {
+		changes = append(changes, "+no longer failing")
+	}
+
+	return changes
+}
+
+func labelSuffix(label string) string {
+	if label == "" {
+		return ""
+	}
+	return ", " + label
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -14,14 +16,24 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/jeffrey/intellinieuws/internal/ai"
+	"github.com/jeffrey/intellinieuws/internal/alerts"
+	"github.com/jeffrey/intellinieuws/internal/analytics"
 	"github.com/jeffrey/intellinieuws/internal/api"
 	"github.com/jeffrey/intellinieuws/internal/api/handlers"
+	"github.com/jeffrey/intellinieuws/internal/apikey"
+	"github.com/jeffrey/intellinieuws/internal/audit"
 	"github.com/jeffrey/intellinieuws/internal/cache"
 	"github.com/jeffrey/intellinieuws/internal/email"
+	"github.com/jeffrey/intellinieuws/internal/entity"
+	"github.com/jeffrey/intellinieuws/internal/leader"
+	"github.com/jeffrey/intellinieuws/internal/metrics"
 	"github.com/jeffrey/intellinieuws/internal/repository"
 	"github.com/jeffrey/intellinieuws/internal/scheduler"
 	"github.com/jeffrey/intellinieuws/internal/scraper"
 	"github.com/jeffrey/intellinieuws/internal/stock"
+	"github.com/jeffrey/intellinieuws/internal/stream"
+	"github.com/jeffrey/intellinieuws/internal/tracing"
+	"github.com/jeffrey/intellinieuws/internal/webhook"
 	"github.com/jeffrey/intellinieuws/pkg/config"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 	"github.com/jeffrey/intellinieuws/pkg/middleware"
@@ -42,6 +54,15 @@ func main() {
 	})
 	log.Info("Starting Nieuws Scraper API service")
 
+	// Distributed tracing; spans are exported over OTLP when TRACING_ENABLED is set
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, cfg.Server.Environment)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to initialize tracing")
+	}
+	if cfg.Tracing.Enabled {
+		log.Infof("Tracing enabled, exporting to %s (sample ratio %.2f)", cfg.Tracing.OTLPEndpoint, cfg.Tracing.SampleRatio)
+	}
+
 	// Initialize database connection with optimized pool settings
 	dbCtx, dbCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer dbCancel()
@@ -69,6 +90,9 @@ func main() {
 		// Note: idle_in_transaction_timeout removed for PostgreSQL < 9.6 compatibility
 		// Note: jit setting removed for PostgreSQL < 11 compatibility
 	}
+	if cfg.Tracing.Enabled {
+		dbConfig.ConnConfig.Tracer = tracing.QueryTracer()
+	}
 
 	dbPool, err := pgxpool.NewWithConfig(dbCtx, dbConfig)
 	if err != nil {
@@ -116,6 +140,11 @@ func main() {
 		ConnMaxIdleTime: 5 * time.Minute,  // Close idle connections after this duration
 	})
 	defer redisClient.Close()
+	if cfg.Tracing.Enabled {
+		if err := tracing.InstrumentRedis(redisClient); err != nil {
+			log.WithError(err).Warn("Failed to instrument Redis for tracing")
+		}
+	}
 
 	// Test Redis connection
 	if err := redisClient.Ping(context.Background()).Err(); err != nil {
@@ -166,15 +195,90 @@ func main() {
 	// Initialize services
 	scraperService := scraper.NewService(&cfg.Scraper, articleRepo, jobRepo, log)
 
-	// Initialize scheduler if enabled (with database for analytics refresh)
+	// Live article stream (SSE/WebSocket), fanned out across replicas via Redis pub/sub
+	var streamHub *stream.Hub
+	var streamHandler *handlers.StreamHandler
+	if cfg.Stream.Enabled {
+		streamHub = stream.NewHub(redisClient, stream.Config{
+			HistorySize: cfg.Stream.HistorySize,
+			MaxClients:  cfg.Stream.MaxClients,
+		}, log)
+		if err := streamHub.Start(context.Background()); err != nil {
+			log.WithError(err).Warn("Failed to start stream hub")
+		}
+		scraperService.AddPublisher(streamHub)
+		streamHandler = handlers.NewStreamHandler(streamHub, cfg.Stream.Heartbeat, log)
+	}
+
+	// Outgoing webhooks for new articles, enrichments and failed scrapes
+	var webhookDispatcher *webhook.Dispatcher
+	webhookRepo := repository.NewWebhookRepository(dbPool, log)
+	if cfg.Webhook.Enabled {
+		webhookDispatcher = webhook.NewDispatcher(webhookRepo, webhook.Config{
+			Workers:       cfg.Webhook.Workers,
+			MaxAttempts:   cfg.Webhook.MaxAttempts,
+			DisableAfter:  cfg.Webhook.DisableAfter,
+			Timeout:       cfg.Webhook.Timeout,
+			AllowPrivate:  cfg.Webhook.AllowPrivate,
+			RetentionDays: cfg.Webhook.RetentionDays,
+		}, log)
+		if err := webhookDispatcher.Start(context.Background()); err != nil {
+			log.WithError(err).Warn("Failed to start webhook dispatcher")
+		}
+		scraperService.AddPublisher(webhookDispatcher)
+	}
+
+	// Background workers run on the elected leader only, so replicas do not duplicate them
+	elector := leader.NewElector(repository.NewLeaderRepository(dbPool, log), cfg.Leader, log)
+
+	// Saved search alerts, evaluated as articles are stored and enriched
+	var alertEvaluator *alerts.Evaluator
+	savedSearchRepo := repository.NewSavedSearchRepository(dbPool, log)
+	if cfg.Alerts.Enabled {
+		alertEvaluator = alerts.NewEvaluator(savedSearchRepo, cfg.Alerts.DigestInterval, log)
+		if streamHub != nil {
+			alertEvaluator.SetStream(streamHub)
+		}
+		if webhookDispatcher != nil {
+			alertEvaluator.SetWebhooks(webhookDispatcher)
+		}
+		if cfg.Alerts.EmailEnabled() {
+			alertEvaluator.SetMailer(alerts.NewSMTPMailer(cfg.Alerts.SMTPHost, cfg.Alerts.SMTPPort,
+				cfg.Alerts.SMTPUsername, cfg.Alerts.SMTPPassword, cfg.Alerts.SMTPFrom))
+		}
+		// Matches are evaluated on every instance; email digests are sent by the leader only
+		elector.Register("alert_digest", func(ctx context.Context) {
+			if err := alertEvaluator.Start(ctx); err != nil {
+				log.WithError(err).Warn("Failed to start alert digests")
+			}
+		}, alertEvaluator.Stop)
+		scraperService.AddPublisher(alertEvaluator)
+		if streamHandler != nil {
+			streamHandler.SetSavedSearches(savedSearchRepo)
+		}
+	}
+
+	// Analytics materialized views are only refreshed through the coordinator
+	refreshCoordinator := analytics.NewRefreshCoordinator(
+		repository.NewViewRefreshRepository(dbPool, log),
+		map[string]time.Duration{
+			analytics.ViewTrendingKeywords:  cfg.Analytics.TrendingRefresh,
+			analytics.ViewSentimentTimeline: cfg.Analytics.SentimentRefresh,
+			analytics.ViewEntityMentions:    cfg.Analytics.EntityRefresh,
+		},
+		log,
+	)
+
+	// Initialize scheduler if enabled (with the coordinator for analytics refresh)
 	var scraperScheduler *scheduler.Scheduler
 	if cfg.Scraper.ScheduleEnabled {
 		interval := cfg.Scraper.GetScheduleInterval()
-		scraperScheduler = scheduler.NewScheduler(scraperService, dbPool, interval, log)
+		scraperScheduler = scheduler.NewScheduler(scraperService, refreshCoordinator, interval, log)
 
-		// Start scheduler in background
-		go scraperScheduler.Start(context.Background())
-		log.Infof("Scheduled scraping enabled with interval: %v (analytics refresh: every 15min)", interval)
+		// Run scheduler in background on the leader
+		elector.Register("scheduler", scraperScheduler.Start, scraperScheduler.Stop)
+		log.Infof("Scheduled scraping enabled with interval: %v (analytics refresh: trending every %v, sentiment every %v, entities every %v)",
+			interval, cfg.Analytics.TrendingRefresh, cfg.Analytics.SentimentRefresh, cfg.Analytics.EntityRefresh)
 	} else {
 		log.Info("Scheduled scraping disabled")
 	}
@@ -202,8 +306,12 @@ func main() {
 			cfg.Scraper.EnableFullContentExtraction,
 			log,
 		)
-		go contentProcessor.Start(context.Background())
-		log.Infof("Content processor started with interval: %v", cfg.Scraper.ContentExtractionInterval)
+		elector.Register("content_processor", func(ctx context.Context) {
+			if err := contentProcessor.Start(ctx); err != nil {
+				log.WithError(err).Error("Failed to start content processor")
+			}
+		}, contentProcessor.Stop)
+		log.Infof("Content processor enabled with interval: %v", cfg.Scraper.ContentExtractionInterval)
 	}
 
 	// Initialize AI service and processor
@@ -220,6 +328,7 @@ func main() {
 			OpenAIAPIKey:       cfg.AI.OpenAIAPIKey,
 			OpenAIModel:        cfg.AI.OpenAIModel,
 			OpenAIMaxTokens:    cfg.AI.OpenAIMaxTokens,
+			OpenAIBaseURL:      cfg.AI.OpenAIBaseURL,
 			Enabled:            cfg.AI.Enabled,
 			AsyncProcessing:    cfg.AI.AsyncProcessing,
 			BatchSize:          cfg.AI.BatchSize,
@@ -232,12 +341,22 @@ func main() {
 			EnableKeywords:     cfg.AI.EnableKeywords,
 			EnableSummary:      cfg.AI.EnableSummary,
 			EnableSimilarity:   cfg.AI.EnableSimilarity,
+			EnableTranslation:  cfg.AI.EnableTranslation,
 			MaxDailyCost:       cfg.AI.MaxDailyCost,
 			RateLimitPerMinute: cfg.AI.RateLimitPerMinute,
 			Timeout:            cfg.AI.Timeout,
 		}
 
 		aiService = ai.NewService(dbPool, aiConfig, log)
+		if streamHub != nil {
+			aiService.AddPublisher(streamHub)
+		}
+		if webhookDispatcher != nil {
+			aiService.AddPublisher(webhookDispatcher)
+		}
+		if alertEvaluator != nil {
+			aiService.AddPublisher(alertEvaluator)
+		}
 
 		// Initialize OpenAI client for chat service
 		openAIClient := ai.NewOpenAIClient(
@@ -246,6 +365,7 @@ func main() {
 			cfg.AI.OpenAIMaxTokens,
 			log,
 		)
+		openAIClient.SetBaseURL(cfg.AI.OpenAIBaseURL)
 
 		// Initialize chat service
 		aiChatService = ai.NewChatService(aiService, openAIClient, log)
@@ -253,8 +373,12 @@ func main() {
 
 		if cfg.AI.AsyncProcessing {
 			aiProcessor = ai.NewProcessor(aiService, aiConfig, log)
-			go aiProcessor.Start(context.Background())
-			log.Infof("AI processor started with interval: %v", cfg.AI.ProcessInterval)
+			elector.Register("ai_processor", func(ctx context.Context) {
+				if err := aiProcessor.Start(ctx); err != nil {
+					log.WithError(err).Error("Failed to start AI processor")
+				}
+			}, aiProcessor.Stop)
+			log.Infof("AI processor enabled with interval: %v", cfg.AI.ProcessInterval)
 		}
 
 		aiHandler = handlers.NewAIHandler(aiService, aiProcessor, aiChatService, cacheService, log)
@@ -263,6 +387,41 @@ func main() {
 		log.Info("AI processing disabled")
 	}
 
+	// Initialize canonical entity registry and connect it to the AI pipeline
+	entityRepo := repository.NewEntityRepository(dbPool, log)
+	entityResolver := entity.NewResolver(entityRepo, cfg.Entity.AutoCreate, log)
+	if err := entityResolver.Load(context.Background()); err != nil {
+		log.WithError(err).Warn("Failed to load entity registry (run migration V004?), continuing with empty index")
+	}
+	if cfg.Entity.DumpPath != "" {
+		go func() {
+			importCtx, importCancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			defer importCancel()
+			if _, err := entityResolver.ImportDump(importCtx, cfg.Entity.DumpPath); err != nil {
+				log.WithError(err).Warn("Failed to import entity dump")
+			}
+		}()
+	}
+	if aiService != nil {
+		aiService.SetEntityResolver(entityResolver)
+	}
+	entityHandler := handlers.NewEntityHandler(entityResolver, cacheService, refreshCoordinator, cfg.Entity.DumpPath, log)
+
+	// Initialize ticker validator (local symbol master; provider refresh needs the stock API)
+	symbolMaster := stock.NewSymbolMaster(cfg.Stock.SymbolMasterPath, log)
+	symbolMaster.SetSeedPath(cfg.Stock.SymbolSeedPath)
+	if err := symbolMaster.Load(); err != nil {
+		log.WithError(err).Warn("Failed to load symbol master, tickers will not be verified")
+	}
+	tickerValidator := stock.NewTickerValidator(symbolMaster, stock.TickerValidatorConfig{
+		Policy:        cfg.Stock.TickerPolicy,
+		MinConfidence: cfg.Stock.TickerMinConfidence,
+		AutoRefresh:   cfg.Stock.SymbolAutoRefresh,
+	}, log)
+	if aiService != nil && symbolMaster.Count() > 0 {
+		aiService.SetTickerValidator(&TickerValidatorAdapter{validator: tickerValidator})
+	}
+
 	// Initialize stock service (if configured)
 	var stockService *stock.Service
 	var stockHandler *handlers.StockHandler
@@ -281,6 +440,9 @@ func main() {
 
 		stockService = stock.NewService(stockConfig, redisClient, log)
 		stockHandler = handlers.NewStockHandler(stockService, log)
+		tickerValidator.SetSearcher(stockService)
+		stockHandler.SetTickerValidator(tickerValidator)
+		stockHandler.SetSentimentAnalyzer(analytics.NewTickerSentimentAnalyzer(repository.NewAnalyticsRepository(dbPool, log), stockService, log))
 
 		// Connect stock service to AI service for automatic enrichment via adapter
 		if aiService != nil {
@@ -348,23 +510,50 @@ func main() {
 			log,
 		)
 
-		// Start email processor in background
-		go emailProcessor.Start(context.Background())
-		log.Infof("Email processor started with interval: %v", cfg.Email.PollInterval)
+		// Run email processor in background on the leader, so IMAP is polled once
+		elector.Register("email_processor", emailProcessor.Start, emailProcessor.Stop)
+		log.Infof("Email processor enabled with interval: %v", cfg.Email.PollInterval)
 	} else {
 		log.Info("Email integration disabled")
 	}
 
+	// Start the registered workers once this instance is elected
+	elector.Start(context.Background())
+
+	// Audit log for configuration, scraping, cache and API key operations
+	auditRecorder := audit.NewRecorder(repository.NewAuditRepository(dbPool, log), log)
+	auditHandler := handlers.NewAuditHandler(auditRecorder, log)
+
+	var webhookHandler *handlers.WebhookHandler
+	if webhookDispatcher != nil {
+		webhookHandler = handlers.NewWebhookHandler(webhookRepo, webhookDispatcher, log)
+		webhookHandler.SetAuditor(auditRecorder)
+	}
+
+	var savedSearchHandler *handlers.SavedSearchHandler
+	if alertEvaluator != nil {
+		savedSearchHandler = handlers.NewSavedSearchHandler(savedSearchRepo, alertEvaluator, log)
+		savedSearchHandler.SetWebhooks(webhookRepo)
+	}
+
 	// Initialize handlers
 	articleHandler := handlers.NewArticleHandler(articleRepo, cacheService, log)
 	articleHandler.SetScraperService(scraperService) // Enable content extraction endpoint
+	if aiService != nil && aiService.TranslationAvailable() {
+		articleHandler.SetTranslator(aiService) // Enable ?lang= translation
+	}
+	feedHandler := handlers.NewFeedHandler(articleRepo, cacheService, log)
+	exportHandler := handlers.NewExportHandler(articleRepo, log)
 	scraperHandler := handlers.NewScraperHandler(scraperService, articleHandler, log)
+	scraperHandler.SetAuditor(auditRecorder)
 
 	// Initialize configuration handler for runtime settings management
 	configHandler := handlers.NewConfigHandler(cfg, log)
+	configHandler.SetAuditor(auditRecorder)
 	if scraperScheduler != nil {
 		configHandler.SetScheduler(scraperScheduler)
 	}
+	configHandler.SetLeaderElector(elector)
 	log.Info("Configuration handler initialized with 4 profiles (fast, balanced, deep, conservative)")
 
 	// Initialize cache handler
@@ -372,6 +561,7 @@ func main() {
 	if redisClient != nil {
 		invalidationService := cache.NewInvalidationService(redisClient)
 		cacheHandler = handlers.NewCacheHandler(cacheService, advancedCacheService, invalidationService, log)
+		cacheHandler.SetAuditor(auditRecorder)
 		log.Info("Cache handler initialized with advanced features")
 	}
 
@@ -382,22 +572,64 @@ func main() {
 		log.Info("Email handler initialized")
 	}
 
-	// Initialize middleware
-	var rateLimiter *middleware.RateLimiter
-	if redisClient != nil {
-		rateLimiter = middleware.NewRateLimiter(
-			redisClient,
-			cfg.API.RateLimitRequests,
-			cfg.API.RateLimitWindowSeconds,
-		)
-	}
-
+	// API_KEY is the bootstrap admin key; per-client keys are issued via /admin/api-keys.
+	// End users authenticate with JWT bearer tokens when JWT_ISSUER is set.
 	var auth *middleware.APIKeyAuth
-	if cfg.API.APIKey != "" {
+	var apiKeyStore *apikey.Store
+	var apiKeyHandler *handlers.APIKeyHandler
+	if cfg.API.APIKey != "" || cfg.Auth.JWTEnabled() {
 		auth = middleware.NewAPIKeyAuth(cfg.API.APIKey, cfg.API.APIKeyHeader)
-		log.Info("API key authentication enabled")
+		auth.SetRequireKeyForReads(cfg.API.RequireKeyForReads)
+
+		apiKeyStore = apikey.NewStore(repository.NewAPIKeyRepository(dbPool, log), log)
+		apiKeyStore.Start(context.Background())
+		auth.SetKeyStore(apiKeyStore)
+		if redisClient != nil {
+			auth.SetQuotaRedis(redisClient)
+		}
+		apiKeyHandler = handlers.NewAPIKeyHandler(apiKeyStore, log)
+		apiKeyHandler.SetAuditor(auditRecorder)
+		log.Info("API key authentication enabled (shared API_KEY + per-client keys)")
+
+		if cfg.Auth.JWTEnabled() {
+			jwtCtx, jwtCancel := context.WithTimeout(context.Background(), 15*time.Second)
+			verifier, err := middleware.NewJWTVerifier(jwtCtx, middleware.JWTConfig{
+				JWKSURL:    cfg.Auth.JWKSURL,
+				JWKSFile:   cfg.Auth.JWKSFile,
+				Issuer:     cfg.Auth.JWTIssuer,
+				Audience:   cfg.Auth.JWTAudience,
+				RoleClaim:  cfg.Auth.JWTRoleClaim,
+				AdminRole:  cfg.Auth.JWTAdminRole,
+				UserScopes: cfg.Auth.JWTUserScopes,
+				RateTier:   cfg.Auth.JWTRateTier,
+				ClockSkew:  time.Duration(cfg.Auth.JWTClockSkewSec) * time.Second,
+				RefreshTTL: cfg.Auth.JWKSRefreshTTL,
+			})
+			jwtCancel()
+			if err != nil {
+				log.WithError(err).Fatal("Failed to initialize JWT authentication")
+			}
+			auth.SetBearerVerifier(verifier)
+			log.Infof("JWT bearer authentication enabled (issuer %s, %d signing keys)", cfg.Auth.JWTIssuer, verifier.KeyCount())
+		}
 	} else {
-		log.Warn("API key authentication disabled - no API_KEY configured")
+		log.Warn("API key authentication disabled - no API_KEY or JWT_ISSUER configured")
+		// Without authentication no caller can hold ai:translate, so ?lang= stays open
+		articleHandler.SetOpenTranslation(true)
+	}
+
+	// Rate limiting per verified key (or IP) with tiers and per-route costs.
+	// Redis shares buckets between instances; without it limits are per instance.
+	rateLimiter := middleware.NewRateLimiter(redisClient, cfg.API.GetRateLimitWindow(), map[string]int{
+		middleware.TierAnonymous: cfg.API.RateLimitAnonymous,
+		middleware.TierStandard:  cfg.API.RateLimitRequests,
+		middleware.TierPremium:   cfg.API.RateLimitPremium,
+	})
+	rateLimiter.SetAuth(auth)
+	if routeCosts, err := middleware.ParseRouteCosts(cfg.API.RateLimitRouteCosts); err != nil {
+		log.WithError(err).Warn("Invalid API_RATE_LIMIT_ROUTE_COSTS, every request costs 1 unit")
+	} else {
+		rateLimiter.SetRouteCosts(routeCosts)
 	}
 
 	// Initialize Fiber app
@@ -419,7 +651,25 @@ func main() {
 	})
 
 	// Setup routes with comprehensive health monitoring and configuration API
-	api.SetupRoutes(app, articleHandler, scraperHandler, aiHandler, stockHandler, emailHandler, cacheHandler, configHandler, rateLimiter, auth, log, dbPool, redisClient, cacheService, scraperService, aiProcessor)
+	api.SetupRoutes(app, articleHandler, scraperHandler, aiHandler, stockHandler, emailHandler, cacheHandler, configHandler, entityHandler, apiKeyHandler, auditHandler, streamHandler, webhookHandler, savedSearchHandler, feedHandler, exportHandler, rateLimiter, auth, log, dbPool, redisClient, cacheService, scraperService, aiProcessor, refreshCoordinator, elector)
+
+	// Prometheus metrics on a separate port so they stay off the public API
+	var metricsServer *http.Server
+	if cfg.API.EnableMetrics {
+		metrics.RegisterCircuitBreakers(scraperService.CircuitBreakers())
+		metrics.RegisterBrowserPool(scraperService.BrowserPoolUtilization)
+		if aiService != nil {
+			metrics.RegisterAIQueue(aiService.CountPendingArticles)
+		}
+
+		metricsServer = metrics.NewServer(cfg.API.MetricsPort)
+		go func() {
+			log.Infof("Serving Prometheus metrics on %s/metrics", metricsServer.Addr)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.WithError(err).Error("Metrics server failed")
+			}
+		}()
+	}
 
 	// Start server in goroutine
 	serverErr := make(chan error, 1)
@@ -442,28 +692,24 @@ func main() {
 		log.Info("Shutting down server...")
 	}
 
-	// Stop scheduler if running
-	if scraperScheduler != nil && scraperScheduler.IsRunning() {
-		log.Info("Stopping scheduler...")
-		scraperScheduler.Stop()
+	// Disconnect live stream clients so open streams do not hold up shutdown
+	if streamHub != nil {
+		streamHub.Stop()
 	}
 
-	// Stop content processor if running
-	if contentProcessor != nil && contentProcessor.IsRunning() {
-		log.Info("Stopping content processor...")
-		contentProcessor.Stop()
-	}
+	// Stop the scheduler and processors if this instance leads, and hand over leadership
+	log.Info("Stopping background workers...")
+	elector.Stop()
 
-	// Stop AI processor if running
-	if aiProcessor != nil && aiProcessor.IsRunning() {
-		log.Info("Stopping AI processor...")
-		aiProcessor.Stop()
+	// Let in-flight webhook deliveries finish; queued ones are picked up after restart
+	if webhookDispatcher != nil {
+		log.Info("Stopping webhook dispatcher...")
+		webhookDispatcher.Stop()
 	}
 
-	// Stop email processor if running
-	if emailProcessor != nil && emailProcessor.IsRunning() {
-		log.Info("Stopping email processor...")
-		emailProcessor.Stop()
+	// Flush API key usage
+	if apiKeyStore != nil {
+		apiKeyStore.Stop()
 	}
 
 	// Cleanup scraper service (closes browser pool if active)
@@ -484,6 +730,17 @@ func main() {
 		log.WithError(err).Error("Server forced to shutdown")
 	}
 
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			log.WithError(err).Warn("Metrics server forced to shutdown")
+		}
+	}
+
+	// Flush buffered spans
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.WithError(err).Warn("Failed to flush traces")
+	}
+
 	log.Info("Server exited")
 }
 
@@ -515,3 +772,48 @@ func (a *StockServiceAdapter) GetMultipleQuotes(ctx context.Context, symbols []s
 
 	return result, nil
 }
+
+// TickerValidatorAdapter adapts stock.TickerValidator to ai.TickerValidator interface
+type TickerValidatorAdapter struct {
+	validator *stock.TickerValidator
+}
+
+func (a *TickerValidatorAdapter) ValidateTickers(ctx context.Context, tickers []ai.StockTicker) []ai.StockTicker {
+	result := make([]ai.StockTicker, 0, len(tickers))
+	seen := make(map[string]int)
+
+	for _, ticker := range tickers {
+		validation := a.validator.Validate(ctx, stock.TickerCandidate{
+			Symbol:   ticker.Symbol,
+			Name:     ticker.Name,
+			Exchange: ticker.Exchange,
+		})
+		if !validation.Keep {
+			continue
+		}
+
+		// Tickers resolving to the same listing (ASML and ASML.AS) are merged
+		if idx, ok := seen[validation.Symbol]; ok {
+			result[idx].Mentions += ticker.Mentions
+			if validation.Confidence > result[idx].Confidence {
+				result[idx].Confidence = validation.Confidence
+				result[idx].Verified = validation.Verified
+			}
+			continue
+		}
+
+		if validation.Symbol != ticker.Symbol {
+			ticker.RawSymbol = ticker.Symbol
+		}
+		ticker.Symbol = validation.Symbol
+		ticker.Name = validation.Name
+		ticker.Exchange = validation.Exchange
+		ticker.Verified = validation.Verified
+		ticker.Confidence = validation.Confidence
+
+		seen[ticker.Symbol] = len(result)
+		result = append(result, ticker)
+	}
+
+	return result
+}
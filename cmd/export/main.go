@@ -0,0 +1,207 @@
+// Command export dumps articles with their AI enrichment as NDJSON, CSV or Parquet.
+//
+// Rows are streamed from a database cursor in id order. An interrupted NDJSON or CSV
+// export continues where it stopped with -resume; any export continues with -after-id.
+//
+//	go run ./cmd/export -format parquet -out articles.parquet -start 2025-01-01 -end 2025-02-01
+//	go run ./cmd/export -format ndjson -out ing.ndjson -entity ING -sentiment negative
+//	go run ./cmd/export -format csv -out articles.csv -resume
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeffrey/intellinieuws/internal/export"
+	"github.com/jeffrey/intellinieuws/internal/language"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/config"
+)
+
+const progressEvery = 10000
+
+func main() {
+	var (
+		format    = flag.String("format", models.ExportFormatNDJSON, "output format: ndjson, csv or parquet")
+		out       = flag.String("out", "", "output file (default stdout)")
+		resume    = flag.Bool("resume", false, "continue an interrupted ndjson/csv export in -out")
+		afterID   = flag.Int64("after-id", 0, "export articles with an id above this watermark")
+		limit     = flag.Int("limit", 0, "maximum number of articles (0 = all)")
+		start     = flag.String("start", "", "published on or after (RFC3339 or YYYY-MM-DD)")
+		end       = flag.String("end", "", "published on or before (RFC3339 or YYYY-MM-DD)")
+		source    = flag.String("source", "", "source filter")
+		category  = flag.String("category", "", "category filter")
+		keyword   = flag.String("keyword", "", "keyword filter")
+		search    = flag.String("q", "", "full-text search")
+		lang      = flag.String("language", "", "language filter (ISO 639-1)")
+		entity    = flag.String("entity", "", "entity filter")
+		ticker    = flag.String("ticker", "", "stock ticker filter")
+		sentiment = flag.String("sentiment", "", "sentiment label filter: positive, neutral or negative")
+	)
+	flag.Parse()
+
+	*format = strings.ToLower(*format)
+	if !export.IsValidFormat(*format) {
+		fatalf("unknown format %q (use %s)", *format, strings.Join(models.ExportFormats, ", "))
+	}
+
+	q := models.SavedSearchQuery{
+		Source:         *source,
+		Category:       *category,
+		Keyword:        *keyword,
+		Search:         *search,
+		Language:       language.Normalize(*lang),
+		Entity:         *entity,
+		Ticker:         strings.ToUpper(*ticker),
+		SentimentLabel: strings.ToLower(*sentiment),
+	}
+	var err error
+	if q.StartDate, err = parseDate(*start); err != nil {
+		fatalf("invalid -start: %v", err)
+	}
+	if q.EndDate, err = parseDate(*end); err != nil {
+		fatalf("invalid -end: %v", err)
+	}
+
+	output, watermark, appending := openOutput(*out, *format, *resume)
+	if watermark > *afterID {
+		*afterID = watermark
+	}
+	defer output.Close()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fatalf("failed to load configuration: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := pgxpool.New(ctx, cfg.Database.GetDSN())
+	if err != nil {
+		fatalf("failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	buffered := bufio.NewWriterSize(output, 1<<20)
+	var writer export.Writer
+	if appending {
+		writer, err = export.NewAppendWriter(*format, buffered)
+	} else {
+		writer, err = export.NewWriter(*format, buffered)
+	}
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	began := time.Now()
+	lastID := *afterID
+	written := 0
+	count, exportErr := repository.NewArticleRepository(pool).Export(ctx, q, *afterID, *limit, func(row *models.ExportRow) error {
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+		lastID = row.ID
+		if written++; written%progressEvery == 0 {
+			fmt.Fprintf(os.Stderr, "export: %d articles, last id %d\n", written, lastID)
+		}
+		return nil
+	})
+
+	// Rows written so far stay valid for NDJSON and CSV, so flush them even after an error
+	closeErr := writer.Close()
+	if err := buffered.Flush(); err != nil && closeErr == nil {
+		closeErr = err
+	}
+
+	switch {
+	case exportErr != nil:
+		fmt.Fprintf(os.Stderr, "export: stopped after %d articles: %v\n", count, exportErr)
+		fmt.Fprintf(os.Stderr, "export: resume with -after-id %d%s\n", lastID, resumeHint(*format, *out))
+		os.Exit(1)
+	case closeErr != nil:
+		fatalf("failed to finish export: %v", closeErr)
+	}
+	fmt.Fprintf(os.Stderr, "export: %d articles in %s, last id %d\n", count, time.Since(began).Round(time.Millisecond), lastID)
+}
+
+// openOutput opens the export destination. With resume it finds the watermark of the
+// existing file and truncates a trailing partial row before appending.
+func openOutput(path, format string, resume bool) (*os.File, int64, bool) {
+	if path == "" {
+		if resume {
+			fatalf("-resume needs -out")
+		}
+		return os.Stdout, 0, false
+	}
+
+	if resume {
+		file, err := os.OpenFile(path, os.O_RDWR, 0)
+		if os.IsNotExist(err) {
+			resume = false
+		} else if err != nil {
+			fatalf("failed to open %s: %v", path, err)
+		} else {
+			watermark, size, err := export.LastExportedID(format, file)
+			if err != nil {
+				fatalf("cannot resume %s: %v", path, err)
+			}
+			if size == 0 {
+				file.Close()
+				resume = false // Nothing usable; start over
+			} else {
+				if err := file.Truncate(size); err != nil {
+					fatalf("failed to truncate %s: %v", path, err)
+				}
+				if _, err := file.Seek(size, io.SeekStart); err != nil {
+					fatalf("failed to seek %s: %v", path, err)
+				}
+				fmt.Fprintf(os.Stderr, "export: resuming %s after id %d\n", path, watermark)
+				return file, watermark, true
+			}
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		fatalf("failed to create %s: %v", path, err)
+	}
+	return file, 0, false
+}
+
+// parseDate accepts RFC3339 timestamps and plain dates (UTC midnight)
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("use RFC3339 or YYYY-MM-DD")
+	}
+	return &t, nil
+}
+
+func resumeHint(format, out string) string {
+	if out != "" && format != models.ExportFormatParquet {
+		return " or -resume"
+	}
+	return ""
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "export: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -18,6 +18,10 @@ type ChatResponse struct {
 	Articles []models.Article `json:"articles,omitempty"`
 	Stats    interface{}      `json:"stats,omitempty"`
 	Sources  []string         `json:"sources,omitempty"`
+
+	// ToolsRestricted is set when untrusted input looked like a prompt injection
+	// and the assistant was limited to aggregate, read-only functions
+	ToolsRestricted bool `json:"tools_restricted,omitempty"`
 }
 
 // FunctionCall represents OpenAI function calling
@@ -35,6 +39,13 @@ const (
 	FunctionGetRecentArticles   = "get_recent_articles"
 )
 
+// restrictedChatFunctionNames are the functions still available when untrusted
+// input is suspicious: aggregate statistics without free-text arguments
+var restrictedChatFunctionNames = map[string]bool{
+	FunctionGetSentimentStats: true,
+	FunctionGetTrendingTopics: true,
+}
+
 // Function definitions for OpenAI
 var ChatFunctions = []map[string]interface{}{
 	{
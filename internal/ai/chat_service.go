@@ -30,6 +30,28 @@ func NewChatService(aiService *Service, openAIClient *OpenAIClient, log *logger.
 func (cs *ChatService) ProcessChatMessageWithContext(ctx context.Context, message string, conversationContext string, articleContent string, articleID int64) (*ChatResponse, error) {
 	cs.logger.Infof("Processing chat message: %s (article_id: %d, has_content: %v)", message, articleID, articleContent != "")
 
+	// Scan every input: article content may come from scraped pages or newsletters and the
+	// conversation history is supplied by the client, so none of it can be trusted
+	scan := ScanForInjection(message)
+	RecordInjectionScan(cs.logger, OriginChatMessage, fmt.Sprintf("article %d", articleID), scan)
+	if conversationContext != "" {
+		contextScan := ScanForInjection(conversationContext)
+		RecordInjectionScan(cs.logger, OriginChatContext, fmt.Sprintf("article %d", articleID), contextScan)
+		scan = scan.Merge(contextScan)
+	}
+	if articleContent != "" {
+		articleScan := ScanForInjection(articleContent)
+		RecordInjectionScan(cs.logger, OriginChatArticle, fmt.Sprintf("article %d", articleID), articleScan)
+		scan = scan.Merge(articleScan)
+	}
+
+	// Limit the tools the model may call when any input looks like an injection
+	functions := ChatFunctions
+	if scan.Suspicious {
+		functions = restrictedChatFunctions()
+		cs.logger.Warnf("Chat tools restricted to %d functions (patterns: %v)", len(functions), scan.Patterns)
+	}
+
 	// Build system prompt
 	systemPrompt := cs.buildSystemPrompt()
 
@@ -41,11 +63,11 @@ func (cs *ChatService) ProcessChatMessageWithContext(ctx context.Context, messag
 		},
 	}
 
-	// Add conversation context if provided
+	// Add conversation context if provided (as delimited data, never as assistant output)
 	if conversationContext != "" {
 		messages = append(messages, map[string]interface{}{
-			"role":    "assistant",
-			"content": conversationContext,
+			"role":    "user",
+			"content": "Eerdere conversatie (alleen ter context):\n" + WrapUntrusted(OriginChatContext, conversationContext),
 		})
 	}
 
@@ -57,7 +79,7 @@ func (cs *ChatService) ProcessChatMessageWithContext(ctx context.Context, messag
 		if len(content) > 4000 {
 			content = content[:4000] + "..."
 		}
-		userMessage = fmt.Sprintf("Context - Artikel content:\n\n%s\n\n---\n\nVraag: %s", content, message)
+		userMessage = fmt.Sprintf("Context - Artikel content:\n\n%s\n\n---\n\nVraag: %s", WrapUntrusted(OriginChatArticle, content), message)
 	}
 
 	// Add user message
@@ -67,7 +89,7 @@ func (cs *ChatService) ProcessChatMessageWithContext(ctx context.Context, messag
 	})
 
 	// Call OpenAI with function calling
-	response, functionCall, err := cs.openAIClient.ChatWithFunctions(ctx, messages, ChatFunctions)
+	response, functionCall, err := cs.openAIClient.ChatWithFunctions(ctx, messages, functions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call OpenAI: %w", err)
 	}
@@ -75,7 +97,17 @@ func (cs *ChatService) ProcessChatMessageWithContext(ctx context.Context, messag
 	// If no function call, return text response
 	if functionCall == nil {
 		return &ChatResponse{
-			Message: response,
+			Message:         response,
+			ToolsRestricted: scan.Suspicious,
+		}, nil
+	}
+
+	// The model may still name a function it was not offered; refuse it
+	if scan.Suspicious && !restrictedChatFunctionNames[functionCall.Name] {
+		cs.logger.Warnf("Blocked function call %s from a restricted conversation", functionCall.Name)
+		return &ChatResponse{
+			Message:         "Sorry, deze vraag kan ik niet beantwoorden met de beschikbare gegevens.",
+			ToolsRestricted: true,
 		}, nil
 	}
 
@@ -93,6 +125,7 @@ func (cs *ChatService) ProcessChatMessageWithContext(ctx context.Context, messag
 	if err != nil {
 		return nil, fmt.Errorf("failed to build final response: %w", err)
 	}
+	finalResponse.ToolsRestricted = scan.Suspicious
 
 	return finalResponse, nil
 }
@@ -165,11 +198,13 @@ func (cs *ChatService) buildFinalResponse(ctx context.Context, messages []map[st
 		},
 	})
 
-	// Add function result
+	// Add function result (contains scraped titles and content previews, so delimit it)
+	formatted := cs.formatFunctionResult(result)
+	RecordInjectionScan(cs.logger, OriginToolResult, fc.Name, ScanForInjection(formatted))
 	messages = append(messages, map[string]interface{}{
 		"role":    "function",
 		"name":    fc.Name,
-		"content": cs.formatFunctionResult(result),
+		"content": WrapUntrusted(OriginToolResult, formatted),
 	})
 
 	// Get final response from OpenAI
@@ -296,7 +331,19 @@ Richtlijnen:
 - Geef context bij trending topics
 - Wees vriendelijk en professioneel
 
-Als je geen relevante data kunt vinden, vertel dit eerlijk en suggereer alternatieven.`, time.Now().Format("2006-01-02 15:04:05"))
+Als je geen relevante data kunt vinden, vertel dit eerlijk en suggereer alternatieven.
+%s`, time.Now().Format("2006-01-02 15:04:05"), untrustedContentInstruction)
+}
+
+// restrictedChatFunctions returns the function definitions allowed for suspicious conversations
+func restrictedChatFunctions() []map[string]interface{} {
+	functions := make([]map[string]interface{}, 0, len(restrictedChatFunctionNames))
+	for _, fn := range ChatFunctions {
+		if name, _ := fn["name"].(string); restrictedChatFunctionNames[name] {
+			functions = append(functions, fn)
+		}
+	}
+	return functions
 }
 
 // mustMarshalJSON marshals to JSON or panics
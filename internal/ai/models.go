@@ -14,6 +14,7 @@ type AIEnrichment struct {
 	Keywords    []Keyword          `json:"keywords,omitempty"`
 	Summary     string             `json:"summary,omitempty"`
 	Error       string             `json:"error,omitempty"`
+	Injection   *InjectionScan     `json:"injection,omitempty"` // Set when the input looked like a prompt injection
 }
 
 // SentimentAnalysis contains sentiment detection results
@@ -38,6 +39,11 @@ type StockTicker struct {
 	Exchange string `json:"exchange,omitempty"` // e.g., "AEX", "NASDAQ"
 	Mentions int    `json:"mentions,omitempty"` // Number of mentions in article
 	Context  string `json:"context,omitempty"`  // Context of mention
+
+	// Validation against the symbol master (set when a ticker validator is configured)
+	RawSymbol  string  `json:"raw_symbol,omitempty"` // Symbol as extracted, when it was corrected
+	Verified   bool    `json:"verified"`
+	Confidence float64 `json:"confidence,omitempty"`
 }
 
 // Keyword represents a keyword with relevance score
@@ -105,6 +111,7 @@ type Config struct {
 	OpenAIAPIKey    string
 	OpenAIModel     string
 	OpenAIMaxTokens int
+	OpenAIBaseURL   string // Optional OpenAI-compatible endpoint
 
 	// Processing settings
 	Enabled         bool
@@ -115,12 +122,13 @@ type Config struct {
 	MaxRetries      int
 
 	// Feature toggles
-	EnableSentiment  bool
-	EnableEntities   bool
-	EnableCategories bool
-	EnableKeywords   bool
-	EnableSummary    bool
-	EnableSimilarity bool
+	EnableSentiment   bool
+	EnableEntities    bool
+	EnableCategories  bool
+	EnableKeywords    bool
+	EnableSummary     bool
+	EnableSimilarity  bool
+	EnableTranslation bool // Translate title/summary on ?lang= requests
 
 	// Cost control
 	MaxDailyCost       float64
@@ -135,7 +143,8 @@ type ProcessingOptions struct {
 	EnableCategories bool
 	EnableKeywords   bool
 	EnableSummary    bool
-	Force            bool // Reprocess even if already processed
+	Force            bool   // Reprocess even if already processed
+	Origin           string // Content origin for injection detection (article, email)
 }
 
 // DefaultProcessingOptions returns default processing options
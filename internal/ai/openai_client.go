@@ -14,6 +14,8 @@ import (
 
 	"regexp"
 
+	"github.com/jeffrey/intellinieuws/internal/metrics"
+	"github.com/jeffrey/intellinieuws/internal/tracing"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 )
 
@@ -33,6 +35,7 @@ type OpenAIClient struct {
 	apiKey     string
 	model      string
 	maxTokens  int
+	baseURL    string // Chat completions endpoint (OpenAI or a compatible backend)
 	httpClient *http.Client
 	logger     *logger.Logger
 	// Caching
@@ -58,9 +61,10 @@ func NewOpenAIClient(apiKey, model string, maxTokens int, log *logger.Logger) *O
 		apiKey:    apiKey,
 		model:     model,
 		maxTokens: maxTokens,
+		baseURL:   openAIAPIURL,
 		httpClient: &http.Client{
 			Timeout:   30 * time.Second,
-			Transport: transport,
+			Transport: tracing.Transport(transport),
 		},
 		logger:    log.WithComponent("openai-client"),
 		cache:     make(map[string]*CachedResponse),
@@ -69,6 +73,29 @@ func NewOpenAIClient(apiKey, model string, maxTokens int, log *logger.Logger) *O
 	}
 }
 
+// SetBaseURL points the client at an OpenAI-compatible API (e.g. "http://localhost:11434/v1").
+// The chat completions path is appended when missing.
+func (c *OpenAIClient) SetBaseURL(baseURL string) {
+	if baseURL == "" {
+		return
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+	if !strings.HasSuffix(baseURL, "/chat/completions") {
+		baseURL += "/chat/completions"
+	}
+	c.baseURL = baseURL
+}
+
+// SetTransport replaces the HTTP transport, e.g. to record or replay responses
+func (c *OpenAIClient) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
+// Model returns the configured model name
+func (c *OpenAIClient) Model() string {
+	return c.model
+}
+
 // Complete sends a completion request to OpenAI
 func (c *OpenAIClient) Complete(ctx context.Context, messages []ChatMessage, temperature float64) (*OpenAIResponse, error) {
 	request := OpenAIRequest{
@@ -83,7 +110,7 @@ func (c *OpenAIClient) Complete(ctx context.Context, messages []ChatMessage, tem
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", openAIAPIURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -91,13 +118,37 @@ func (c *OpenAIClient) Complete(ctx context.Context, messages []ChatMessage, tem
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 
+	body, err := c.send(req, "completion")
+	if err != nil {
+		return nil, err
+	}
+
+	var response OpenAIResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	metrics.AddAITokens(c.model, response.Usage.PromptTokens, response.Usage.CompletionTokens)
+	c.logger.Debugf("OpenAI API call completed. Tokens used: %d", response.Usage.TotalTokens)
+
+	return &response, nil
+}
+
+// send performs an API request and returns the body of a successful response. Latency
+// and outcome are recorded in the metrics under operation.
+func (c *OpenAIClient) send(req *http.Request, operation string) (body []byte, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveAIRequest(operation, time.Since(start), err)
+	}()
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err = io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
@@ -105,15 +156,7 @@ func (c *OpenAIClient) Complete(ctx context.Context, messages []ChatMessage, tem
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(body))
 	}
-
-	var response OpenAIResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	c.logger.Debugf("OpenAI API call completed. Tokens used: %d", response.Usage.TotalTokens)
-
-	return &response, nil
+	return body, nil
 }
 
 // CompleteWithRetry sends a completion request with exponential backoff retry
@@ -542,6 +585,14 @@ func (c *OpenAIClient) ProcessArticle(ctx context.Context, title, content string
 		text = text[:4000]
 	}
 
+	// Scraped and emailed content is untrusted: scan it and keep it delimited from instructions
+	origin := opts.Origin
+	if origin == "" {
+		origin = OriginArticle
+	}
+	scan := ScanForInjection(text)
+	RecordInjectionScan(c.logger, origin, title, scan)
+
 	// Build comprehensive prompt
 	tasksDesc := "Analyze this Dutch news article and provide:\n"
 	if opts.EnableSentiment {
@@ -572,11 +623,11 @@ IMPORTANT:
 		Example: {"categories": {"Politics": 0.9, "Economy": 0.3}}
 - Stock tickers must be extracted from entities, including symbol, name, and exchange.
 		Example: {"entities": {"stock_tickers": [{"symbol": "ASML", "name": "ASML Holding", "exchange": "AEX"}]}}
-- Common stocks: Dutch (ASML, Shell, ING, Philips), US (AAPL, MSFT, GOOGL, TSLA, NVDA)`,
+- Common stocks: Dutch (ASML, Shell, ING, Philips), US (AAPL, MSFT, GOOGL, TSLA, NVDA)` + untrustedContentInstruction,
 		},
 		{
 			Role:    "user",
-			Content: fmt.Sprintf("%s\n\nRespond ONLY with a valid JSON object. No markdown, no explanations.\n\nArticle:\n%s", tasksDesc, text),
+			Content: fmt.Sprintf("%s\n\nRespond ONLY with a valid JSON object. No markdown, no explanations.\n\nArticle:\n%s", tasksDesc, WrapUntrusted(origin, text)),
 		},
 	}
 
@@ -668,6 +719,10 @@ IMPORTANT:
 		}
 	}
 
+	if scan.Suspicious {
+		enrichment.Injection = &scan
+	}
+
 	now := time.Now()
 	enrichment.ProcessedAt = &now
 
@@ -695,6 +750,7 @@ type ArticleData struct {
 	ID      int64
 	Title   string
 	Content string
+	Origin  string // Content origin for injection detection (article, email)
 }
 
 // ProcessArticlesBatch processes multiple articles in a single API call (PHASE 3: 70% extra cost reduction)
@@ -734,15 +790,23 @@ func (c *OpenAIClient) ProcessArticlesBatch(ctx context.Context, articles []Arti
 
 	promptBuilder.WriteString("\nArticles to analyze:\n\n")
 
+	scans := make([]InjectionScan, len(articles))
 	for i, article := range articles {
-		promptBuilder.WriteString(fmt.Sprintf("=== Article %d (ID: %d) ===\n", i+1, article.ID))
-		promptBuilder.WriteString(fmt.Sprintf("Title: %s\n", article.Title))
-
 		content := article.Content
 		if len(content) > 500 {
 			content = content[:500] + "..."
 		}
-		promptBuilder.WriteString(fmt.Sprintf("Content: %s\n\n", content))
+
+		origin := article.Origin
+		if origin == "" {
+			origin = OriginArticle
+		}
+		scans[i] = ScanForInjection(article.Title + "\n" + content)
+		RecordInjectionScan(c.logger, origin, fmt.Sprintf("article %d", article.ID), scans[i])
+
+		promptBuilder.WriteString(fmt.Sprintf("=== Article %d (ID: %d) ===\n", i+1, article.ID))
+		promptBuilder.WriteString(WrapUntrusted(origin, fmt.Sprintf("Title: %s\nContent: %s", article.Title, content)))
+		promptBuilder.WriteString("\n\n")
 	}
 
 	promptBuilder.WriteString("\n📋 IMPORTANT: Respond with a JSON array containing one enrichment object per article, in the EXACT same order.\n")
@@ -759,7 +823,7 @@ CRITICAL RULES:
 3. Categories must be objects: {"Politics": 0.9, "Economy": 0.3}
 4. Keywords must be arrays: [{"word": "keyword", "score": 0.9}]
 5. Stock tickers in entities: {"stock_tickers": [{"symbol": "ASML", "name": "ASML Holding", "exchange": "AEX"}]}
-6. If you cannot analyze an article, return {"sentiment": null, "entities": null}`
+6. If you cannot analyze an article, return {"sentiment": null, "entities": null}` + untrustedContentInstruction
 
 	messages := []ChatMessage{
 		{Role: "system", Content: systemPrompt},
@@ -817,6 +881,9 @@ CRITICAL RULES:
 			Processed:   true,
 			ProcessedAt: &now,
 		}
+		if scans[i].Suspicious {
+			enrichment.Injection = &scans[i]
+		}
 
 		// Use response if available, otherwise mark as failed
 		if i < len(batchResponse) {
@@ -901,7 +968,7 @@ func (c *OpenAIClient) ChatWithFunctions(ctx context.Context, messages []map[str
 		return "", nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", openAIAPIURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -909,19 +976,9 @@ func (c *OpenAIClient) ChatWithFunctions(ctx context.Context, messages []map[str
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.send(req, "chat")
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(body))
+		return "", nil, err
 	}
 
 	var response struct {
@@ -931,15 +988,14 @@ func (c *OpenAIClient) ChatWithFunctions(ctx context.Context, messages []map[str
 				FunctionCall *map[string]interface{} `json:"function_call,omitempty"`
 			} `json:"message"`
 		} `json:"choices"`
-		Usage struct {
-			TotalTokens int `json:"total_tokens"`
-		} `json:"usage"`
+		Usage Usage `json:"usage"`
 	}
 
 	if err := json.Unmarshal(body, &response); err != nil {
 		return "", nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
+	metrics.AddAITokens(c.model, response.Usage.PromptTokens, response.Usage.CompletionTokens)
 	c.logger.Debugf("OpenAI chat API call completed. Tokens used: %d", response.Usage.TotalTokens)
 
 	if len(response.Choices) == 0 {
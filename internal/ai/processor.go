@@ -6,7 +6,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jeffrey/intellinieuws/internal/metrics"
+	"github.com/jeffrey/intellinieuws/internal/tracing"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Processor handles background AI processing of articles
@@ -56,6 +59,7 @@ func (p *Processor) Start(ctx context.Context) error {
 		return fmt.Errorf("processor already running")
 	}
 	p.isRunning = true
+	p.stopChan = make(chan struct{})
 	p.mu.Unlock()
 
 	if !p.config.Enabled {
@@ -198,9 +202,13 @@ func (p *Processor) processArticles(ctx context.Context) {
 	batchCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
 
+	batchCtx, span := tracing.Start(batchCtx, "ai.process_batch")
+	defer span.End()
+
 	// Get pending article IDs
 	articleIDs, err := p.service.getPendingArticleIDs(batchCtx, p.config.BatchSize)
 	if err != nil {
+		tracing.RecordError(span, err)
 		p.logger.WithError(err).Error("Failed to get pending articles")
 		return
 	}
@@ -265,6 +273,10 @@ func (p *Processor) processArticles(ctx context.Context) {
 	p.mu.Unlock()
 
 	aggregateResult.Duration = time.Since(startTime)
+	span.SetAttributes(
+		attribute.Int("ai.articles", aggregateResult.TotalProcessed),
+		attribute.Int("ai.failures", aggregateResult.FailureCount),
+	)
 
 	// AUTO-ENRICH: Fetch stock data for successfully processed articles with tickers
 	if aggregateResult.SuccessCount > 0 {
@@ -342,7 +354,11 @@ func (p *Processor) worker(ctx context.Context, workerID int, jobs <-chan int64,
 		}
 
 		// Process article
-		enrichment, err := p.service.ProcessArticle(ctx, articleID)
+		start := time.Now()
+		articleCtx, span := tracing.Start(ctx, "ai.process_article", attribute.Int64("article.id", articleID))
+		enrichment, err := p.service.ProcessArticle(articleCtx, articleID)
+		tracing.End(span, err)
+		metrics.ObserveAIArticle(time.Since(start), err)
 		if err != nil {
 			result.Success = false
 			result.Error = err
@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/pagination"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 )
 
@@ -16,6 +19,22 @@ type StockService interface {
 	GetMultipleQuotes(ctx context.Context, symbols []string) (map[string]*StockQuote, error)
 }
 
+// EntityResolver interface for optional canonical entity resolution
+type EntityResolver interface {
+	ResolveArticleEntities(ctx context.Context, articleID int64, persons, organizations, locations []string) error
+	Lookup(ctx context.Context, name, entityType string) (int64, bool)
+}
+
+// TickerValidator interface for optional validation of extracted stock tickers
+type TickerValidator interface {
+	ValidateTickers(ctx context.Context, tickers []StockTicker) []StockTicker
+}
+
+// EnrichmentPublisher interface for optional publishing of enrichment results to live clients
+type EnrichmentPublisher interface {
+	PublishEnrichment(ctx context.Context, article models.Article, enrichment *models.StreamEnrichment)
+}
+
 // StockQuote represents a stock quote (mirrors internal/stock/models.go)
 type StockQuote struct {
 	Symbol        string  `json:"symbol"`
@@ -34,7 +53,10 @@ type Service struct {
 	openAIClient *OpenAIClient
 	config       *Config
 	logger       *logger.Logger
-	stockService StockService // Optional stock service for enrichment
+	stockService StockService          // Optional stock service for enrichment
+	resolver     EntityResolver        // Optional canonical entity resolver
+	tickers      TickerValidator       // Optional stock ticker validator
+	publishers   []EnrichmentPublisher // Optional live stream and webhook publishers
 }
 
 // NewService creates a new AI service
@@ -47,6 +69,7 @@ func NewService(db *pgxpool.Pool, config *Config, log *logger.Logger) *Service {
 			config.OpenAIMaxTokens,
 			log,
 		)
+		openAIClient.SetBaseURL(config.OpenAIBaseURL)
 	}
 
 	return &Service{
@@ -73,13 +96,17 @@ func (s *Service) ProcessArticle(ctx context.Context, articleID int64) (*AIEnric
 		return nil, fmt.Errorf("failed to get article: %w", err)
 	}
 
+	// Correlate with the scrape job that stored the article, if any
+	log := logger.FromContext(ctx, s.logger).WithJobID(article.ScrapeJobID)
+	ctx = logger.NewContext(ctx, log)
+
 	// Check if already processed
 	if article.AIProcessed && !s.config.RetryFailed {
-		s.logger.Infof("Article %d already processed, skipping", articleID)
+		log.Infof("Article %d already processed, skipping", articleID)
 		return nil, nil
 	}
 
-	s.logger.Infof("Processing article %d: %s", articleID, article.Title)
+	log.Infof("Processing article %d: %s", articleID, article.Title)
 
 	// Build processing options
 	opts := ProcessingOptions{
@@ -88,6 +115,7 @@ func (s *Service) ProcessArticle(ctx context.Context, articleID int64) (*AIEnric
 		EnableCategories: s.config.EnableCategories,
 		EnableKeywords:   s.config.EnableKeywords,
 		EnableSummary:    s.config.EnableSummary,
+		Origin:           contentOrigin(article.Source),
 	}
 
 	// Process with timeout
@@ -106,7 +134,7 @@ func (s *Service) ProcessArticle(ctx context.Context, articleID int64) (*AIEnric
 		return nil, fmt.Errorf("failed to save enrichment: %w", err)
 	}
 
-	s.logger.Infof("Successfully processed article %d", articleID)
+	log.Infof("Successfully processed article %d", articleID)
 	return enrichment, nil
 }
 
@@ -339,87 +367,79 @@ func (s *Service) getTrendingTopicsDirectQuery(ctx context.Context, hoursBack, m
 
 // GetArticlesByEntity retrieves articles mentioning a specific entity
 func (s *Service) GetArticlesByEntity(ctx context.Context, entityName, entityType string, limit int) ([]models.Article, error) {
+	articles, _, err := s.PageArticlesByEntity(ctx, entityName, entityType, limit, nil)
+	return articles, err
+}
+
+// PageArticlesByEntity retrieves one page of articles mentioning a specific entity, newest first.
+// cursor is nil for the first page.
+func (s *Service) PageArticlesByEntity(ctx context.Context, entityName, entityType string, limit int, cursor *pagination.Cursor) ([]models.Article, pagination.PageInfo, error) {
+	// Prefer canonical resolution so every alias ("Rutte", "premier Rutte") matches
+	if s.resolver != nil {
+		if entityID, ok := s.resolver.Lookup(ctx, entityName, entityType); ok {
+			return s.pageArticles(ctx, articlePageSelect+`
+				WHERE id IN (SELECT article_id FROM article_entities WHERE entity_id = $1)`,
+				[]interface{}{entityID}, limit, cursor, "failed to get articles by entity id")
+		}
+	}
+
 	// Direct query without stored procedure
-	query := `
-		SELECT id, title, summary, url, published, source, keywords, image_url,
-		       author, category, content_hash, created_at, updated_at,
-		       content, content_extracted, content_extracted_at
-		FROM articles
+	query := articlePageSelect + `
 		WHERE ai_processed = TRUE
 		  AND ai_entities IS NOT NULL
 	`
-
-	args := []interface{}{}
-	argPos := 1
+	var args []interface{}
 
 	if entityType != "" {
 		// Search in specific entity type
-		query += fmt.Sprintf(" AND ai_entities->$%d ? $%d", argPos, argPos+1)
+		query += " AND ai_entities->$1 ? $2"
 		args = append(args, entityType, entityName)
-		argPos += 2
 	} else {
 		// Search in all entity types
-		query += fmt.Sprintf(" AND ai_entities::text ILIKE $%d", argPos)
+		query += " AND ai_entities::text ILIKE $1"
 		args = append(args, "%"+entityName+"%")
-		argPos++
-	}
-
-	query += " ORDER BY published DESC"
-	query += fmt.Sprintf(" LIMIT $%d", argPos)
-	args = append(args, limit)
-
-	rows, err := s.db.Query(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get articles by entity: %w", err)
-	}
-	defer rows.Close()
-
-	articles := []models.Article{}
-	for rows.Next() {
-		var article models.Article
-		if err := rows.Scan(
-			&article.ID,
-			&article.Title,
-			&article.Summary,
-			&article.URL,
-			&article.Published,
-			&article.Source,
-			&article.Keywords,
-			&article.ImageURL,
-			&article.Author,
-			&article.Category,
-			&article.ContentHash,
-			&article.CreatedAt,
-			&article.UpdatedAt,
-			&article.Content,
-			&article.ContentExtracted,
-			&article.ContentExtractedAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan article: %w", err)
-		}
-		articles = append(articles, article)
 	}
 
-	return articles, nil
+	return s.pageArticles(ctx, query, args, limit, cursor, "failed to get articles by entity")
 }
 
 // GetArticlesByStockTicker retrieves articles mentioning a specific stock ticker
 func (s *Service) GetArticlesByStockTicker(ctx context.Context, ticker string, limit int) ([]models.Article, error) {
-	query := `
-		SELECT id, title, summary, url, published, source, keywords, image_url,
-		       author, category, content_hash, created_at, updated_at,
-		       content, content_extracted, content_extracted_at
-		FROM articles
+	articles, _, err := s.PageArticlesByStockTicker(ctx, ticker, limit, nil)
+	return articles, err
+}
+
+// PageArticlesByStockTicker retrieves one page of articles mentioning a stock ticker, newest first
+func (s *Service) PageArticlesByStockTicker(ctx context.Context, ticker string, limit int, cursor *pagination.Cursor) ([]models.Article, pagination.PageInfo, error) {
+	query := articlePageSelect + `
 		WHERE ai_processed = TRUE
 		  AND ai_stock_tickers IS NOT NULL
 		  AND ai_stock_tickers::text ILIKE $1
-		ORDER BY published DESC
-		LIMIT $2
 	`
+	return s.pageArticles(ctx, query, []interface{}{"%" + ticker + "%"}, limit, cursor, "failed to get articles by stock ticker")
+}
+
+// articlePageSelect is the column list used by pageArticles
+const articlePageSelect = `
+	SELECT id, title, summary, url, published, source, keywords, image_url,
+	       author, category, content_hash, created_at, updated_at,
+	       content, content_extracted, content_extracted_at
+	FROM articles`
+
+// pageArticles runs query (articlePageSelect plus WHERE clauses) with keyset pagination on
+// (published, id). errContext prefixes query errors.
+func (s *Service) pageArticles(ctx context.Context, query string, args []interface{}, limit int, cursor *pagination.Cursor, errContext string) ([]models.Article, pagination.PageInfo, error) {
+	keyset := pagination.Keyset{SortBy: "published", SortOrder: "desc", Limit: limit, Cursor: cursor}
 
-	rows, err := s.db.Query(ctx, query, "%"+ticker+"%", limit)
+	condition, condArgs := keyset.Condition(len(args) + 1)
+	query += condition + keyset.OrderBy()
+	args = append(args, condArgs...)
+	query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+	args = append(args, keyset.FetchLimit())
+
+	rows, err := s.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get articles by stock ticker: %w", err)
+		return nil, pagination.PageInfo{}, fmt.Errorf("%s: %w", errContext, err)
 	}
 	defer rows.Close()
 
@@ -444,12 +464,15 @@ func (s *Service) GetArticlesByStockTicker(ctx context.Context, ticker string, l
 			&article.ContentExtracted,
 			&article.ContentExtractedAt,
 		); err != nil {
-			return nil, fmt.Errorf("failed to scan article: %w", err)
+			return nil, pagination.PageInfo{}, fmt.Errorf("failed to scan article: %w", err)
 		}
 		articles = append(articles, article)
 	}
 
-	return articles, nil
+	articles, page := pagination.Finish(articles, keyset, 0, func(a models.Article) (time.Time, int64) {
+		return a.Published, a.ID
+	})
+	return articles, page, nil
 }
 
 // GetEnrichment retrieves AI enrichment for an article
@@ -539,7 +562,7 @@ func (s *Service) GetEnrichment(ctx context.Context, articleID int64) (*AIEnrich
 
 func (s *Service) getArticle(ctx context.Context, articleID int64) (*articleData, error) {
 	query := `
-		SELECT id, title, summary, ai_processed
+		SELECT id, title, summary, source, ai_processed, COALESCE(scrape_job_id::text, '')
 		FROM articles
 		WHERE id = $1
 	`
@@ -549,7 +572,9 @@ func (s *Service) getArticle(ctx context.Context, articleID int64) (*articleData
 		&article.ID,
 		&article.Title,
 		&article.Summary,
+		&article.Source,
 		&article.AIProcessed,
+		&article.ScrapeJobID,
 	)
 
 	if err != nil {
@@ -587,10 +612,28 @@ func (s *Service) getPendingArticleIDs(ctx context.Context, limit int) ([]int64,
 	return ids, nil
 }
 
+// CountPendingArticles returns the number of articles waiting for AI processing,
+// including failed ones that will be retried
+func (s *Service) CountPendingArticles(ctx context.Context) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM articles
+		WHERE ai_processed = FALSE
+		   OR (ai_processed = TRUE AND ai_error IS NOT NULL)
+	`
+
+	var count int
+	if err := s.db.QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count pending articles: %w", err)
+	}
+	return count, nil
+}
+
 func (s *Service) saveEnrichment(ctx context.Context, articleID int64, enrichment *AIEnrichment) error {
-	categoriesJSON, _ := json.Marshal(enrichment.Categories)
-	entitiesJSON, _ := json.Marshal(enrichment.Entities)
-	keywordsJSON, _ := json.Marshal(enrichment.Keywords)
+	// Validate tickers against the symbol master before they are stored
+	if s.tickers != nil && enrichment.Entities != nil && len(enrichment.Entities.StockTickers) > 0 {
+		enrichment.Entities.StockTickers = s.tickers.ValidateTickers(ctx, enrichment.Entities.StockTickers)
+	}
 
 	// Extract stock tickers from entities and marshal separately
 	var stockTickersJSON []byte
@@ -598,6 +641,10 @@ func (s *Service) saveEnrichment(ctx context.Context, articleID int64, enrichmen
 		stockTickersJSON, _ = json.Marshal(enrichment.Entities.StockTickers)
 	}
 
+	categoriesJSON, _ := json.Marshal(enrichment.Categories)
+	entitiesJSON, _ := json.Marshal(enrichment.Entities)
+	keywordsJSON, _ := json.Marshal(enrichment.Keywords)
+
 	var sentimentScore *float64
 	var sentimentLabel *string
 	if enrichment.Sentiment != nil {
@@ -635,8 +682,78 @@ func (s *Service) saveEnrichment(ctx context.Context, articleID int64, enrichmen
 		keywordsJSON,
 		stockTickersJSON,
 	)
+	if err != nil {
+		return err
+	}
+
+	// Link mentions to canonical entities (non-fatal: enrichment is already stored)
+	if s.resolver != nil && enrichment.Entities != nil {
+		if err := s.resolver.ResolveArticleEntities(ctx, articleID,
+			enrichment.Entities.Persons,
+			enrichment.Entities.Organizations,
+			enrichment.Entities.Locations,
+		); err != nil {
+			s.logger.WithError(err).Warnf("Failed to resolve entities for article %d", articleID)
+		}
+	}
 
-	return err
+	if len(s.publishers) > 0 {
+		s.publishEnrichment(ctx, articleID, enrichment)
+	}
+
+	return nil
+}
+
+// publishEnrichment sends the stored enrichment to live stream clients
+func (s *Service) publishEnrichment(ctx context.Context, articleID int64, enrichment *AIEnrichment) {
+	query := `
+		SELECT id, title, summary, url, published, source, keywords, image_url,
+		       author, category, COALESCE(language, '') as language, created_at, updated_at
+		FROM articles
+		WHERE id = $1
+	`
+
+	var article models.Article
+	if err := s.db.QueryRow(ctx, query, articleID).Scan(
+		&article.ID,
+		&article.Title,
+		&article.Summary,
+		&article.URL,
+		&article.Published,
+		&article.Source,
+		&article.Keywords,
+		&article.ImageURL,
+		&article.Author,
+		&article.Category,
+		&article.Language,
+		&article.CreatedAt,
+		&article.UpdatedAt,
+	); err != nil {
+		s.logger.WithError(err).Warnf("Failed to load article %d for stream event", articleID)
+		return
+	}
+
+	event := &models.StreamEnrichment{Summary: enrichment.Summary}
+	if enrichment.Sentiment != nil {
+		event.Sentiment = &enrichment.Sentiment.Score
+		event.SentimentLabel = enrichment.Sentiment.Label
+	}
+	for category := range enrichment.Categories {
+		event.Categories = append(event.Categories, category)
+	}
+	sort.Strings(event.Categories)
+	if enrichment.Entities != nil {
+		event.Persons = enrichment.Entities.Persons
+		event.Organizations = enrichment.Entities.Organizations
+		event.Locations = enrichment.Entities.Locations
+		for _, ticker := range enrichment.Entities.StockTickers {
+			event.StockTickers = append(event.StockTickers, ticker.Symbol)
+		}
+	}
+
+	for _, publisher := range s.publishers {
+		publisher.PublishEnrichment(ctx, article, event)
+	}
 }
 
 func (s *Service) saveError(ctx context.Context, articleID int64, errorMsg string) {
@@ -658,7 +775,17 @@ type articleData struct {
 	ID          int64
 	Title       string
 	Summary     string
+	Source      string
 	AIProcessed bool
+	ScrapeJobID string // Empty for articles not stored by a scrape job
+}
+
+// contentOrigin classifies an article source for prompt-injection accounting
+func contentOrigin(source string) string {
+	if strings.HasPrefix(source, "Email from ") {
+		return OriginEmail
+	}
+	return OriginArticle
 }
 
 // ProcessBatchOptimized processes multiple articles using OpenAI batch API (PHASE 3: 70% extra savings)
@@ -706,6 +833,7 @@ func (s *Service) ProcessBatchOptimized(ctx context.Context, articleIDs []int64)
 				ID:      article.ID,
 				Title:   article.Title,
 				Content: article.Summary,
+				Origin:  contentOrigin(article.Source),
 			}
 		}
 
@@ -782,7 +910,7 @@ func (s *Service) getArticlesForBatch(ctx context.Context, articleIDs []int64) (
 	}
 
 	query := `
-		SELECT id, title, summary
+		SELECT id, title, summary, source
 		FROM articles
 		WHERE id = ANY($1)
 		ORDER BY id
@@ -797,7 +925,7 @@ func (s *Service) getArticlesForBatch(ctx context.Context, articleIDs []int64) (
 	articles := make([]*articleData, 0, len(articleIDs))
 	for rows.Next() {
 		var article articleData
-		if err := rows.Scan(&article.ID, &article.Title, &article.Summary); err != nil {
+		if err := rows.Scan(&article.ID, &article.Title, &article.Summary, &article.Source); err != nil {
 			continue
 		}
 		articles = append(articles, &article)
@@ -812,6 +940,24 @@ func (s *Service) SetStockService(stockService StockService) {
 	s.logger.Info("Stock service connected for automatic enrichment")
 }
 
+// SetEntityResolver sets the resolver used to link extracted entities to canonical IDs
+func (s *Service) SetEntityResolver(resolver EntityResolver) {
+	s.resolver = resolver
+	s.logger.Info("Entity resolver connected for canonical entity linking")
+}
+
+// SetTickerValidator sets the validator used to check extracted tickers against the symbol master
+func (s *Service) SetTickerValidator(validator TickerValidator) {
+	s.tickers = validator
+	s.logger.Info("Ticker validator connected for stock symbol verification")
+}
+
+// AddPublisher adds a publisher notified when enrichment is stored
+func (s *Service) AddPublisher(publisher EnrichmentPublisher) {
+	s.publishers = append(s.publishers, publisher)
+	s.logger.Info("Publisher connected for enrichment events")
+}
+
 // EnrichArticlesWithStockData enriches articles with real-time stock data using BATCH API
 // This is called after AI processing to add current stock prices to articles with extracted tickers
 func (s *Service) EnrichArticlesWithStockData(ctx context.Context, articleIDs []int64) error {
@@ -854,6 +1000,10 @@ func (s *Service) EnrichArticlesWithStockData(ctx context.Context, articleIDs []
 
 		symbols := make([]string, 0, len(tickers))
 		for _, ticker := range tickers {
+			// Skip tickers the validator could not match to a listing
+			if s.tickers != nil && !ticker.Verified {
+				continue
+			}
 			symbols = append(symbols, ticker.Symbol)
 			allSymbols[ticker.Symbol] = true
 		}
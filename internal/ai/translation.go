@@ -0,0 +1,256 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/language"
+	"github.com/jeffrey/intellinieuws/internal/models"
+)
+
+const (
+	// translationBatchSize is the number of articles translated per completion
+	translationBatchSize = 5
+	// maxNewTranslationsPerRequest bounds LLM calls per API request; the rest is
+	// translated on a later request once cached translations have caught up
+	maxNewTranslationsPerRequest = 20
+)
+
+// ErrTranslationUnavailable is returned when translation is disabled or no LLM is configured
+var ErrTranslationUnavailable = errors.New("translation is not available")
+
+// TranslationItem is an article title and summary to translate
+type TranslationItem struct {
+	ID       int64  `json:"id"`
+	Language string `json:"language,omitempty"` // source language name
+	Title    string `json:"title"`
+	Summary  string `json:"summary,omitempty"`
+}
+
+// TranslateArticles translates article titles and summaries into the target language.
+// Entity names and tickers are kept as-is.
+func (c *OpenAIClient) TranslateArticles(ctx context.Context, items []TranslationItem, targetLang string) ([]TranslationItem, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	payload, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal translation items: %w", err)
+	}
+
+	target := language.Name(targetLang)
+	messages := []ChatMessage{
+		{
+			Role: "system",
+			Content: fmt.Sprintf(`You are a professional news translator. Translate the title and summary of each article into %s.
+Keep names of people, organizations, places and stock tickers unchanged. Keep the journalistic tone and do not add information.
+Respond ONLY with a JSON object in this exact format:
+{"translations": [{"id": 1, "title": "...", "summary": "..."}]}
+Return one entry per input article with the same id. Leave summary empty when the input has none.`, target) + untrustedContentInstruction,
+		},
+		{
+			Role:    "user",
+			Content: fmt.Sprintf("Translate these articles into %s:\n\n%s", target, WrapUntrusted(OriginArticle, string(payload))),
+		},
+	}
+
+	response, err := c.CompleteWithRetry(ctx, messages, 0.2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translation: %w", err)
+	}
+
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no response from OpenAI")
+	}
+
+	var result struct {
+		Translations []TranslationItem `json:"translations"`
+	}
+	if err := json.Unmarshal([]byte(cleanJSON(response.Choices[0].Message.Content)), &result); err != nil {
+		c.logger.Warnf("Failed to parse translation JSON, content: %s", response.Choices[0].Message.Content)
+		return nil, fmt.Errorf("failed to parse translation response: %w", err)
+	}
+
+	return result.Translations, nil
+}
+
+// TranslationAvailable reports whether TranslateArticles can be used
+func (s *Service) TranslationAvailable() bool {
+	return s.config.Enabled && s.config.EnableTranslation && s.openAIClient != nil
+}
+
+// TranslateArticles attaches a translation into targetLang to every article that is
+// written in another language. Translations are cached in article_translations and
+// reused until the article's title or summary changes. LLM failures leave the
+// affected articles untranslated instead of failing the request.
+func (s *Service) TranslateArticles(ctx context.Context, articles []models.Article, targetLang string) error {
+	return s.translateArticles(ctx, articles, targetLang, maxNewTranslationsPerRequest)
+}
+
+// ApplyCachedTranslations attaches cached translations into targetLang without calling
+// the LLM; articles without an up-to-date cached translation stay untranslated
+func (s *Service) ApplyCachedTranslations(ctx context.Context, articles []models.Article, targetLang string) error {
+	return s.translateArticles(ctx, articles, targetLang, 0)
+}
+
+// translateArticles translates at most maxNew articles that have no cached translation
+func (s *Service) translateArticles(ctx context.Context, articles []models.Article, targetLang string, maxNew int) error {
+	if !s.TranslationAvailable() {
+		return ErrTranslationUnavailable
+	}
+
+	target := language.Normalize(targetLang)
+	if target == "" {
+		return fmt.Errorf("unsupported language: %s", targetLang)
+	}
+
+	// Articles that need a translation, by ID
+	pending := make(map[int64][]int)
+	ids := make([]int64, 0, len(articles))
+	for i := range articles {
+		if articleLanguage(&articles[i]) == target {
+			continue
+		}
+		if _, seen := pending[articles[i].ID]; !seen {
+			ids = append(ids, articles[i].ID)
+		}
+		pending[articles[i].ID] = append(pending[articles[i].ID], i)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	cached, err := s.getTranslations(ctx, ids, target)
+	if err != nil {
+		return err
+	}
+
+	missing := make([]TranslationItem, 0)
+	for _, id := range ids {
+		article := &articles[pending[id][0]]
+		if t, ok := cached[id]; ok && t.sourceHash == translationSourceHash(article) {
+			for _, i := range pending[id] {
+				translation := t.ArticleTranslation
+				articles[i].Translation = &translation
+			}
+			continue
+		}
+		if len(missing) < maxNew {
+			missing = append(missing, TranslationItem{
+				ID:       id,
+				Language: language.Name(articleLanguage(article)),
+				Title:    article.Title,
+				Summary:  article.Summary,
+			})
+		}
+	}
+
+	for start := 0; start < len(missing); start += translationBatchSize {
+		end := start + translationBatchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+
+		translated, err := s.openAIClient.TranslateArticles(ctx, missing[start:end], target)
+		if err != nil {
+			s.logger.WithError(err).Warnf("Failed to translate %d articles into %s", end-start, target)
+			continue
+		}
+
+		for _, item := range translated {
+			indexes, ok := pending[item.ID]
+			if !ok || item.Title == "" {
+				continue
+			}
+			article := &articles[indexes[0]]
+
+			translation := models.ArticleTranslation{
+				Language:       target,
+				SourceLanguage: articleLanguage(article),
+				Title:          item.Title,
+				Summary:        item.Summary,
+				Model:          s.openAIClient.Model(),
+				CreatedAt:      time.Now(),
+			}
+			if err := s.saveTranslation(ctx, item.ID, translationSourceHash(article), &translation); err != nil {
+				s.logger.WithError(err).Warnf("Failed to cache translation for article %d", item.ID)
+			}
+			for _, i := range indexes {
+				t := translation
+				articles[i].Translation = &t
+			}
+		}
+	}
+
+	return nil
+}
+
+// storedTranslation is a cached translation with the hash of its source text
+type storedTranslation struct {
+	models.ArticleTranslation
+	sourceHash string
+}
+
+// getTranslations loads cached translations into lang, keyed by article ID
+func (s *Service) getTranslations(ctx context.Context, articleIDs []int64, lang string) (map[int64]*storedTranslation, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT article_id, language, COALESCE(source_language, ''), title, COALESCE(summary, ''),
+		       COALESCE(model, ''), source_hash, created_at
+		FROM article_translations
+		WHERE article_id = ANY($1) AND language = $2
+	`, articleIDs, lang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translations: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[int64]*storedTranslation, len(articleIDs))
+	for rows.Next() {
+		var id int64
+		var t storedTranslation
+		if err := rows.Scan(&id, &t.Language, &t.SourceLanguage, &t.Title, &t.Summary, &t.Model, &t.sourceHash, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan translation: %w", err)
+		}
+		result[id] = &t
+	}
+
+	return result, rows.Err()
+}
+
+// saveTranslation stores or replaces a cached translation
+func (s *Service) saveTranslation(ctx context.Context, articleID int64, sourceHash string, t *models.ArticleTranslation) error {
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO article_translations (article_id, language, source_language, title, summary, model, source_hash)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''), NULLIF($6, ''), $7)
+		ON CONFLICT (article_id, language) DO UPDATE SET
+			source_language = EXCLUDED.source_language,
+			title = EXCLUDED.title,
+			summary = EXCLUDED.summary,
+			model = EXCLUDED.model,
+			source_hash = EXCLUDED.source_hash
+	`, articleID, t.Language, t.SourceLanguage, t.Title, t.Summary, t.Model, sourceHash)
+	if err != nil {
+		return fmt.Errorf("failed to save translation: %w", err)
+	}
+	return nil
+}
+
+// articleLanguage returns the stored language, or the default for undetected articles
+func articleLanguage(article *models.Article) string {
+	if article.Language == "" {
+		return language.Default
+	}
+	return article.Language
+}
+
+// translationSourceHash identifies the text a translation was made from
+func translationSourceHash(article *models.Article) string {
+	sum := sha256.Sum256([]byte(article.Title + "\n" + article.Summary))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,320 @@
+// Package alerts evaluates saved searches against new and enriched articles.
+//
+// The evaluator is registered as a publisher with the scraper and the AI service. Each
+// article that matches a saved search is recorded once per search and raises an alert
+// through the channels the search selected: a webhook subscription, the live stream or
+// a periodic email digest.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+const (
+	searchesTTL     = 30 * time.Second
+	digestBatchSize = 1000
+)
+
+// StreamPublisher interface for optional live stream alerts
+type StreamPublisher interface {
+	PublishSavedSearchMatch(ctx context.Context, alert models.SavedSearchAlert)
+}
+
+// WebhookPublisher interface for optional webhook alerts
+type WebhookPublisher interface {
+	PublishSavedSearchMatch(ctx context.Context, subscriptionID int64, alert models.SavedSearchAlert)
+}
+
+// Mailer interface for optional email digests
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// Evaluator matches saved searches against incoming articles and raises alerts
+type Evaluator struct {
+	repo           *repository.SavedSearchRepository
+	digestInterval time.Duration
+	logger         *logger.Logger
+
+	stream   StreamPublisher
+	webhooks WebhookPublisher
+	mailer   Mailer
+
+	mu         sync.RWMutex
+	searches   []models.SavedSearch
+	searchesAt time.Time
+	stopChan   chan struct{}
+	wg         sync.WaitGroup
+	running    bool
+	runMu      sync.Mutex
+}
+
+// NewEvaluator creates a saved search evaluator; digests are sent every digestInterval
+func NewEvaluator(repo *repository.SavedSearchRepository, digestInterval time.Duration, log *logger.Logger) *Evaluator {
+	if digestInterval <= 0 {
+		digestInterval = time.Hour
+	}
+	return &Evaluator{
+		repo:           repo,
+		digestInterval: digestInterval,
+		logger:         log.WithComponent("alert-evaluator"),
+	}
+}
+
+// SetStream enables live stream alerts
+func (e *Evaluator) SetStream(stream StreamPublisher) {
+	e.stream = stream
+}
+
+// SetWebhooks enables webhook alerts
+func (e *Evaluator) SetWebhooks(webhooks WebhookPublisher) {
+	e.webhooks = webhooks
+}
+
+// SetMailer enables email digests
+func (e *Evaluator) SetMailer(mailer Mailer) {
+	e.mailer = mailer
+}
+
+// ChannelAvailable reports whether alerts can be sent through channel
+func (e *Evaluator) ChannelAvailable(channel string) bool {
+	switch channel {
+	case models.AlertChannelStream:
+		return e.stream != nil
+	case models.AlertChannelWebhook:
+		return e.webhooks != nil
+	case models.AlertChannelEmail:
+		return e.mailer != nil
+	}
+	return false
+}
+
+// Start launches the email digest loop (a no-op without a mailer). Matches are evaluated
+// through the publisher hooks whether or not the digest loop runs.
+func (e *Evaluator) Start(ctx context.Context) error {
+	e.runMu.Lock()
+	defer e.runMu.Unlock()
+	if e.running {
+		return fmt.Errorf("alert evaluator already running")
+	}
+	e.running = true
+	e.stopChan = make(chan struct{})
+
+	if e.mailer != nil {
+		e.wg.Add(1)
+		go e.digestLoop(ctx)
+	}
+
+	e.logger.Infof("Alert evaluator started (email digest: %t, every %s)", e.mailer != nil, e.digestInterval)
+	return nil
+}
+
+// Stop ends the digest loop
+func (e *Evaluator) Stop() {
+	e.runMu.Lock()
+	if !e.running {
+		e.runMu.Unlock()
+		return
+	}
+	e.running = false
+	e.runMu.Unlock()
+
+	close(e.stopChan)
+	e.wg.Wait()
+	e.logger.Info("Alert evaluator stopped")
+}
+
+// Invalidate drops the cached saved searches after they were changed through the API
+func (e *Evaluator) Invalidate() {
+	e.mu.Lock()
+	e.searchesAt = time.Time{}
+	e.mu.Unlock()
+}
+
+// alertingSearches returns the cached searches with alert channels, reloading them when stale
+func (e *Evaluator) alertingSearches(ctx context.Context) []models.SavedSearch {
+	e.mu.RLock()
+	if time.Since(e.searchesAt) < searchesTTL {
+		searches := e.searches
+		e.mu.RUnlock()
+		return searches
+	}
+	e.mu.RUnlock()
+
+	searches, err := e.repo.ListAlerting(ctx)
+	if err != nil {
+		e.logger.WithError(err).Warn("Failed to load saved searches")
+		e.mu.RLock()
+		defer e.mu.RUnlock()
+		return e.searches
+	}
+
+	e.mu.Lock()
+	e.searches = searches
+	e.searchesAt = time.Now()
+	e.mu.Unlock()
+	return searches
+}
+
+// PublishArticles evaluates newly stored articles against searches without enrichment filters
+func (e *Evaluator) PublishArticles(ctx context.Context, articles []models.Article) {
+	for _, search := range e.alertingSearches(ctx) {
+		if search.Query.NeedsEnrichment() {
+			continue // Evaluated once the article is enriched
+		}
+		for i := range articles {
+			if Match(search.Query, &articles[i], nil) {
+				e.raise(ctx, &search, &articles[i], nil)
+			}
+		}
+	}
+}
+
+// PublishEnrichment evaluates an enriched article; articles that already matched
+// on ingestion do not alert again
+func (e *Evaluator) PublishEnrichment(ctx context.Context, article models.Article, enrichment *models.StreamEnrichment) {
+	for _, search := range e.alertingSearches(ctx) {
+		if Match(search.Query, &article, enrichment) {
+			e.raise(ctx, &search, &article, enrichment)
+		}
+	}
+}
+
+// raise records the match and sends immediate alerts; email alerts wait for the digest
+func (e *Evaluator) raise(ctx context.Context, search *models.SavedSearch, article *models.Article, enrichment *models.StreamEnrichment) {
+	isNew, err := e.repo.RecordMatch(ctx, search.ID, article.ID)
+	if err != nil {
+		e.logger.WithError(err).Warnf("Failed to record match for saved search %d", search.ID)
+		return
+	}
+	if !isNew {
+		return
+	}
+
+	alert := models.SavedSearchAlert{
+		SavedSearchID:   search.ID,
+		SavedSearchName: search.Name,
+		Article:         *article,
+		Enrichment:      enrichment,
+	}
+	alert.Article.Content = ""
+
+	if search.HasChannel(models.AlertChannelStream) && e.stream != nil {
+		e.stream.PublishSavedSearchMatch(ctx, alert)
+	}
+	if search.HasChannel(models.AlertChannelWebhook) && e.webhooks != nil && search.WebhookSubscriptionID != nil {
+		e.webhooks.PublishSavedSearchMatch(ctx, *search.WebhookSubscriptionID, alert)
+	}
+}
+
+// digestLoop sends pending email matches on every interval
+func (e *Evaluator) digestLoop(ctx context.Context) {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.digestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stopChan:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.SendDigests(ctx); err != nil {
+				e.logger.WithError(err).Warn("Failed to send saved search digests")
+			}
+		}
+	}
+}
+
+// SendDigests emails one digest per recipient with all matches since the last digest
+func (e *Evaluator) SendDigests(ctx context.Context) error {
+	if e.mailer == nil {
+		return nil
+	}
+
+	items, err := e.repo.ClaimDigest(ctx, digestBatchSize)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	byRecipient := make(map[string][]models.SavedSearchDigestItem)
+	for _, item := range items {
+		byRecipient[item.Email] = append(byRecipient[item.Email], item)
+	}
+
+	sent := 0
+	for email, recipientItems := range byRecipient {
+		subject, body := formatDigest(recipientItems)
+		if err := e.mailer.Send(email, subject, body); err != nil {
+			e.logger.WithError(err).Warnf("Failed to send saved search digest to %s", email)
+			e.releaseDigest(ctx, recipientItems) // Retried with the next digest
+			continue
+		}
+		sent++
+	}
+
+	e.logger.Infof("Sent %d saved search digests (%d matches)", sent, len(items))
+	return nil
+}
+
+// releaseDigest hands matches of an unsent digest back to the queue, also when the digest
+// was interrupted by a shutdown
+func (e *Evaluator) releaseDigest(ctx context.Context, items []models.SavedSearchDigestItem) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	articleIDs := make(map[int64][]int64)
+	for _, item := range items {
+		articleIDs[item.SavedSearchID] = append(articleIDs[item.SavedSearchID], item.ArticleID)
+	}
+	for searchID, ids := range articleIDs {
+		if err := e.repo.ReleaseDigest(ctx, searchID, ids); err != nil {
+			e.logger.WithError(err).Warnf("Failed to release digest of saved search %d", searchID)
+		}
+	}
+}
+
+// formatDigest renders a plain-text digest grouped by saved search
+func formatDigest(items []models.SavedSearchDigestItem) (string, string) {
+	groups := make(map[int64][]models.SavedSearchDigestItem)
+	var order []int64
+	for _, item := range items {
+		if _, ok := groups[item.SavedSearchID]; !ok {
+			order = append(order, item.SavedSearchID)
+		}
+		groups[item.SavedSearchID] = append(groups[item.SavedSearchID], item)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return groups[order[i]][0].SavedSearchName < groups[order[j]][0].SavedSearchName
+	})
+
+	subject := fmt.Sprintf("IntelliNieuws: %d new articles for your saved searches", len(items))
+	if len(items) == 1 {
+		subject = "IntelliNieuws: 1 new article for your saved searches"
+	}
+
+	var b strings.Builder
+	for _, id := range order {
+		group := groups[id]
+		fmt.Fprintf(&b, "%s (%d)\n%s\n", group[0].SavedSearchName, len(group), strings.Repeat("=", len(group[0].SavedSearchName)+4))
+		for _, item := range group {
+			fmt.Fprintf(&b, "- %s (%s, %s)\n  %s\n", item.Title, item.Source, item.Published.Format("2006-01-02 15:04"), item.URL)
+		}
+		b.WriteString("\n")
+	}
+	return subject, b.String()
+}
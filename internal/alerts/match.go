@@ -0,0 +1,85 @@
+package alerts
+
+import (
+	"strings"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+)
+
+// Match evaluates a saved search query against a single article in memory. It mirrors
+// the SQL of SavedSearchRepository.Results: full-text search is approximated by requiring
+// every search term in the title or summary, and entities and tickers match exactly
+// (ignoring case) like the live stream filter. Enrichment filters need enrichment != nil.
+func Match(q models.SavedSearchQuery, article *models.Article, enrichment *models.StreamEnrichment) bool {
+	if q.Source != "" && !strings.EqualFold(q.Source, article.Source) {
+		return false
+	}
+	if q.Category != "" && !strings.EqualFold(q.Category, article.Category) {
+		return false
+	}
+	if q.Language != "" && article.Language != "" && q.Language != article.Language {
+		return false
+	}
+	if q.Keyword != "" && !containsFold(article.Keywords, q.Keyword) {
+		return false
+	}
+	if q.StartDate != nil && article.Published.Before(*q.StartDate) {
+		return false
+	}
+	if q.EndDate != nil && article.Published.After(*q.EndDate) {
+		return false
+	}
+	if q.Search != "" {
+		text := strings.ToLower(article.Title + " " + article.Summary)
+		for _, term := range strings.Fields(strings.ToLower(q.Search)) {
+			if !strings.Contains(text, term) {
+				return false
+			}
+		}
+	}
+
+	if !q.NeedsEnrichment() {
+		return true
+	}
+	if enrichment == nil {
+		return false
+	}
+
+	if q.Entity != "" {
+		names := make([]string, 0, len(enrichment.Persons)+len(enrichment.Organizations)+len(enrichment.Locations))
+		names = append(names, enrichment.Persons...)
+		names = append(names, enrichment.Organizations...)
+		names = append(names, enrichment.Locations...)
+		if !containsFold(names, strings.TrimSpace(q.Entity)) {
+			return false
+		}
+	}
+	if q.Ticker != "" && !containsFold(enrichment.StockTickers, strings.TrimSpace(q.Ticker)) {
+		return false
+	}
+	if q.SentimentLabel != "" && !strings.EqualFold(q.SentimentLabel, enrichment.SentimentLabel) {
+		return false
+	}
+	if q.MinSentiment != nil || q.MaxSentiment != nil {
+		if enrichment.Sentiment == nil {
+			return false
+		}
+		if q.MinSentiment != nil && *enrichment.Sentiment < *q.MinSentiment {
+			return false
+		}
+		if q.MaxSentiment != nil && *enrichment.Sentiment > *q.MaxSentiment {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold reports whether value is in values, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+// Package analytics implements statistical analyses over articles, keywords and entities
+// that go beyond the counts in the materialized analytics views.
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// BurstDetector finds keywords and entities whose current mention rate is far above
+// their own historical baseline. Unlike the trending views, which rank by raw counts,
+// an evergreen term such as "kabinet" only bursts when it is unusually busy for itself.
+//
+// For every window of W hours the count in the last W hours is compared with the count
+// in the same W hours on each of the previous baseline days, so the daily news cycle
+// does not register as a burst. The z-score uses the larger of the baseline standard
+// deviation and the Poisson deviation sqrt(mean), at least 1, so rare terms need several
+// articles before they stand out.
+type BurstDetector struct {
+	repo   *repository.AnalyticsRepository
+	logger *logger.Logger
+}
+
+// NewBurstDetector creates a new burst detector
+func NewBurstDetector(repo *repository.AnalyticsRepository, log *logger.Logger) *BurstDetector {
+	return &BurstDetector{
+		repo:   repo,
+		logger: log.WithComponent("burst-detector"),
+	}
+}
+
+// Detect returns the bursting keywords and entities, highest burst score first
+func (d *BurstDetector) Detect(ctx context.Context, q models.BurstQuery) ([]models.Burst, error) {
+	now := time.Now().UTC()
+	maxWindow := 0
+	for _, w := range q.WindowHours {
+		maxWindow = max(maxWindow, w)
+	}
+	span := q.BaselineDays*24 + maxWindow
+
+	var series []models.TermHourlyCounts
+	if q.Kind != models.BurstKindEntity {
+		keywords, err := d.repo.GetKeywordHourlyCounts(ctx, now, span, maxWindow, q.MinArticles)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, keywords...)
+	}
+	if q.Kind != models.BurstKindKeyword {
+		entities, err := d.repo.GetEntityHourlyCounts(ctx, now, span, maxWindow, q.MinArticles, q.EntityType)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, entities...)
+	}
+
+	bursts := make([]models.Burst, 0)
+	for _, s := range series {
+		if burst, ok := scoreBurst(s, q, now); ok {
+			bursts = append(bursts, burst)
+		}
+	}
+	sort.Slice(bursts, func(i, j int) bool {
+		return bursts[i].BurstScore > bursts[j].BurstScore
+	})
+	if len(bursts) > q.Limit {
+		bursts = bursts[:q.Limit]
+	}
+
+	for i := range bursts {
+		articles, err := d.burstArticles(ctx, &bursts[i], now, q.ArticleLimit)
+		if err != nil {
+			return nil, err
+		}
+		bursts[i].Articles = articles
+	}
+
+	d.logger.Debugf("Scored %d terms, %d bursting", len(series), len(bursts))
+	return bursts, nil
+}
+
+func (d *BurstDetector) burstArticles(ctx context.Context, b *models.Burst, now time.Time, limit int) ([]models.BurstArticle, error) {
+	switch b.Kind {
+	case models.BurstKindEntity:
+		return d.repo.GetEntityArticles(ctx, b.EntityID, b.Onset, now, limit)
+	case models.BurstKindKeyword:
+		return d.repo.GetKeywordArticles(ctx, b.Term, b.Onset, now, limit)
+	default:
+		return nil, fmt.Errorf("unknown burst kind %q", b.Kind)
+	}
+}
+
+// scoreBurst scores every window of s and reports a burst when the best window reaches
+// the minimum z-score with enough articles. The onset may lie before the best window,
+// up to the longest window.
+func scoreBurst(s models.TermHourlyCounts, q models.BurstQuery, now time.Time) (models.Burst, bool) {
+	burst := models.Burst{
+		Kind:       s.Kind,
+		Term:       s.Term,
+		EntityID:   s.EntityID,
+		EntityType: s.EntityType,
+		BurstScore: math.Inf(-1),
+		Windows:    make([]models.BurstWindow, 0, len(q.WindowHours)),
+	}
+
+	var bestMean float64
+	lookback := 0
+	for _, w := range q.WindowHours {
+		lookback = max(lookback, w)
+		count := sumCounts(s.Counts, 0, w)
+		baseline := make([]float64, 0, q.BaselineDays)
+		for day := 1; day <= q.BaselineDays; day++ {
+			baseline = append(baseline, float64(sumCounts(s.Counts, day*24, w)))
+		}
+		mean, stdDev := meanStdDev(baseline)
+		z := (float64(count) - mean) / math.Max(stdDev, math.Sqrt(math.Max(mean, 1)))
+
+		burst.Windows = append(burst.Windows, models.BurstWindow{
+			Hours:          w,
+			Count:          count,
+			BaselineMean:   round3(mean),
+			BaselineStdDev: round3(stdDev),
+			ZScore:         round3(z),
+		})
+		if count >= q.MinArticles && z > burst.BurstScore {
+			burst.BurstScore = z
+			burst.WindowHours = w
+			burst.CurrentCount = count
+			bestMean = mean
+		}
+	}
+
+	if burst.BurstScore < q.MinZScore {
+		return models.Burst{}, false
+	}
+	burst.BurstScore = round3(burst.BurstScore)
+	burst.Onset = now.Add(-time.Duration(burstStart(s.Counts, bestMean/float64(burst.WindowHours), lookback)+1) * time.Hour)
+	return burst, true
+}
+
+// burstStart returns how many hours ago the burst started: the start of the run of hours
+// up to now with the largest total excess over the expected hourly rate, looking back at
+// most maxHours hours. This is a two-state simplification of Kleinberg's burst automaton.
+func burstStart(counts []int, hourlyRate float64, maxHours int) int {
+	start, best, excess := 0, math.Inf(-1), 0.0
+	for h := 0; h < maxHours && h < len(counts); h++ {
+		excess += float64(counts[h]) - hourlyRate
+		if excess > best {
+			start, best = h, excess
+		}
+	}
+	return start
+}
+
+// sumCounts sums n hourly counts starting from index from
+func sumCounts(counts []int, from, n int) int {
+	total := 0
+	for i := from; i < from+n && i < len(counts); i++ {
+		total += counts[i]
+	}
+	return total
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,212 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// EntityGraphBuilder builds co-occurrence graphs of the canonical entities that the
+// resolver extracts from ai_entities. Two entities are linked when they are mentioned in
+// the same article; the edge weight is the number of such articles.
+type EntityGraphBuilder struct {
+	repo   *repository.AnalyticsRepository
+	logger *logger.Logger
+}
+
+// NewEntityGraphBuilder creates a new entity graph builder
+func NewEntityGraphBuilder(repo *repository.AnalyticsRepository, log *logger.Logger) *EntityGraphBuilder {
+	return &EntityGraphBuilder{
+		repo:   repo,
+		logger: log.WithComponent("entity-graph"),
+	}
+}
+
+// ResolveEntity resolves an entity ID, alias, canonical name or external ID for an ego
+// network query; repository.ErrEntityNotFound is returned for unknown entities
+func (b *EntityGraphBuilder) ResolveEntity(ctx context.Context, entity string) (int64, error) {
+	return b.repo.ResolveEntityID(ctx, entity)
+}
+
+// Build returns the graph of the most mentioned entities in the query window, or the ego
+// network of q.CenterID: the center, the entities most often mentioned with it and the
+// edges between all of them
+func (b *EntityGraphBuilder) Build(ctx context.Context, q models.EntityGraphQuery) (*models.EntityGraph, error) {
+	var ids []int64
+	var err error
+	if q.CenterID > 0 {
+		ids, err = b.repo.GetCoMentionedEntityIDs(ctx, q)
+		ids = append([]int64{q.CenterID}, ids...)
+	} else {
+		ids, err = b.repo.GetTopEntityIDs(ctx, q)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &models.EntityGraph{
+		Nodes: make([]models.EntityGraphNode, 0),
+		Edges: make([]models.EntityGraphEdge, 0),
+	}
+	if len(ids) == 0 {
+		return graph, nil
+	}
+
+	if graph.Nodes, err = b.repo.GetEntityGraphNodes(ctx, q, ids); err != nil {
+		return nil, err
+	}
+	if graph.Edges, err = b.repo.GetEntityGraphEdges(ctx, q, ids); err != nil {
+		return nil, err
+	}
+	for i := range graph.Nodes {
+		graph.Nodes[i].Center = graph.Nodes[i].ID == q.CenterID
+	}
+
+	b.logger.Debugf("Built entity graph: %d nodes, %d edges", len(graph.Nodes), len(graph.Edges))
+	return graph, nil
+}
+
+// GEXFContentType is the MIME type of GEXF documents
+const GEXFContentType = "application/gexf+xml; charset=utf-8"
+
+// gexfNamespace is the GEXF 1.3 namespace understood by Gephi and sigma.js
+const gexfNamespace = "http://gexf.net/1.3"
+
+// Attribute IDs of the GEXF node and edge attributes
+const (
+	gexfAttrEntityType   = "entity_type"
+	gexfAttrMentions     = "mentions"
+	gexfAttrAvgSentiment = "avg_sentiment"
+	gexfAttrCenter       = "center"
+)
+
+type gexfDocument struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Meta    gexfMeta  `xml:"meta"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfMeta struct {
+	LastModified string `xml:"lastmodifieddate,attr"`
+	Creator      string `xml:"creator"`
+	Description  string `xml:"description"`
+}
+
+type gexfGraph struct {
+	Mode            string           `xml:"mode,attr"`
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Weight    int            `xml:"weight,attr"`
+	AttValues *gexfAttValues `xml:"attvalues,omitempty"`
+}
+
+type gexfAttValues struct {
+	Values []gexfAttValue `xml:"attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// RenderGEXF encodes graph as an undirected GEXF 1.3 document
+func RenderGEXF(graph *models.EntityGraph, description string, generated time.Time) ([]byte, error) {
+	doc := gexfDocument{
+		XMLNS:   gexfNamespace,
+		Version: "1.3",
+		Meta: gexfMeta{
+			LastModified: generated.Format("2006-01-02"),
+			Creator:      "IntelliNieuws",
+			Description:  description,
+		},
+		Graph: gexfGraph{
+			Mode:            "static",
+			DefaultEdgeType: "undirected",
+			Attributes: []gexfAttributes{
+				{Class: "node", Attributes: []gexfAttribute{
+					{ID: gexfAttrEntityType, Title: "entity_type", Type: "string"},
+					{ID: gexfAttrMentions, Title: "mentions", Type: "integer"},
+					{ID: gexfAttrAvgSentiment, Title: "avg_sentiment", Type: "double"},
+					{ID: gexfAttrCenter, Title: "center", Type: "boolean"},
+				}},
+				{Class: "edge", Attributes: []gexfAttribute{
+					{ID: gexfAttrAvgSentiment, Title: "avg_sentiment", Type: "double"},
+				}},
+			},
+		},
+	}
+
+	for _, n := range graph.Nodes {
+		values := []gexfAttValue{
+			{For: gexfAttrEntityType, Value: n.EntityType},
+			{For: gexfAttrMentions, Value: strconv.Itoa(n.Mentions)},
+			{For: gexfAttrCenter, Value: strconv.FormatBool(n.Center)},
+		}
+		if n.AvgSentiment != nil {
+			values = append(values, gexfAttValue{For: gexfAttrAvgSentiment, Value: strconv.FormatFloat(*n.AvgSentiment, 'f', 3, 64)})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
+			ID:        strconv.FormatInt(n.ID, 10),
+			Label:     n.Label,
+			AttValues: values,
+		})
+	}
+
+	for i, e := range graph.Edges {
+		edge := gexfEdge{
+			ID:     strconv.Itoa(i),
+			Source: strconv.FormatInt(e.Source, 10),
+			Target: strconv.FormatInt(e.Target, 10),
+			Weight: e.Weight,
+		}
+		if e.AvgSentiment != nil {
+			edge.AttValues = &gexfAttValues{Values: []gexfAttValue{
+				{For: gexfAttrAvgSentiment, Value: strconv.FormatFloat(*e.AvgSentiment, 'f', 3, 64)},
+			}}
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode GEXF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,159 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// Analytics materialized views
+const (
+	ViewTrendingKeywords  = "mv_trending_keywords"
+	ViewSentimentTimeline = "mv_sentiment_timeline"
+	ViewEntityMentions    = "mv_entity_mentions"
+)
+
+// defaultViewRefreshInterval applies to tracked views without a configured interval
+const defaultViewRefreshInterval = 15 * time.Minute
+
+// ErrRefreshInProgress is returned when another process holds the refresh lock
+var ErrRefreshInProgress = errors.New("analytics refresh already in progress")
+
+// RefreshCoordinator is the single path through which the analytics materialized views
+// are refreshed. A refresh run holds a Postgres advisory lock, so scheduler ticks on
+// several replicas and manual triggers never refresh at the same time. Every view is
+// refreshed CONCURRENTLY, readers are never blocked, on its own interval, and its last
+// refresh time, duration and outcome are recorded in analytics_view_refreshes.
+type RefreshCoordinator struct {
+	repo      *repository.ViewRefreshRepository
+	intervals map[string]time.Duration
+	host      string
+	logger    *logger.Logger
+}
+
+// NewRefreshCoordinator creates a new refresh coordinator; intervals holds the refresh
+// interval per view name
+func NewRefreshCoordinator(repo *repository.ViewRefreshRepository, intervals map[string]time.Duration, log *logger.Logger) *RefreshCoordinator {
+	host, _ := os.Hostname()
+	return &RefreshCoordinator{
+		repo:      repo,
+		intervals: intervals,
+		host:      host,
+		logger:    log.WithComponent("refresh-coordinator"),
+	}
+}
+
+// RefreshDue refreshes the views whose interval has passed since their last successful
+// refresh. ErrRefreshInProgress means another process is refreshing.
+func (c *RefreshCoordinator) RefreshDue(ctx context.Context) ([]models.ViewRefreshResult, error) {
+	now := time.Now()
+	return c.refresh(ctx, true, func(v models.ViewRefresh) bool {
+		return v.LastRefreshedAt == nil || now.Sub(*v.LastRefreshedAt) >= c.interval(v.ViewName)
+	})
+}
+
+// RefreshAll refreshes every tracked view now. Views that were never populated are always
+// refreshed without CONCURRENTLY, which PostgreSQL requires for them.
+func (c *RefreshCoordinator) RefreshAll(ctx context.Context, concurrent bool) ([]models.ViewRefreshResult, error) {
+	return c.refresh(ctx, concurrent, func(models.ViewRefresh) bool { return true })
+}
+
+// RefreshViews refreshes the given tracked views now, e.g. after a change that
+// invalidates them
+func (c *RefreshCoordinator) RefreshViews(ctx context.Context, concurrent bool, views ...string) ([]models.ViewRefreshResult, error) {
+	return c.refresh(ctx, concurrent, func(v models.ViewRefresh) bool {
+		for _, view := range views {
+			if v.ViewName == view {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Status returns the refresh state of every tracked view
+func (c *RefreshCoordinator) Status(ctx context.Context) ([]models.ViewRefresh, error) {
+	return c.repo.List(ctx)
+}
+
+// Interval returns the refresh interval of a view
+func (c *RefreshCoordinator) Interval(view string) time.Duration {
+	return c.interval(view)
+}
+
+// DataAsOf returns when the oldest of the given views was last refreshed, nil when one of
+// them has never been refreshed
+func (c *RefreshCoordinator) DataAsOf(ctx context.Context, views ...string) (*time.Time, error) {
+	return c.repo.GetDataAsOf(ctx, views)
+}
+
+func (c *RefreshCoordinator) interval(view string) time.Duration {
+	if d, ok := c.intervals[view]; ok && d > 0 {
+		return d
+	}
+	return defaultViewRefreshInterval
+}
+
+func (c *RefreshCoordinator) refresh(ctx context.Context, concurrent bool, due func(models.ViewRefresh) bool) ([]models.ViewRefreshResult, error) {
+	unlock, acquired, err := c.repo.TryLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrRefreshInProgress
+	}
+	defer unlock()
+
+	// Read the state under the lock, so a refresh another replica just finished is seen
+	views, err := c.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.ViewRefreshResult, 0, len(views))
+	for _, v := range views {
+		if !due(v) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		results = append(results, c.refreshView(ctx, v.ViewName, concurrent))
+	}
+	return results, nil
+}
+
+// refreshView refreshes one view and records the outcome; a failed view does not stop
+// the others
+func (c *RefreshCoordinator) refreshView(ctx context.Context, view string, concurrent bool) models.ViewRefreshResult {
+	result := models.ViewRefreshResult{ViewName: view}
+	start := time.Now()
+
+	populated, err := c.repo.IsPopulated(ctx, view)
+	if err == nil {
+		result.Concurrent = concurrent && populated
+		result.RowsAffected, err = c.repo.Refresh(ctx, view, result.Concurrent)
+	}
+	duration := time.Since(start)
+	result.RefreshTimeMs = int(duration.Milliseconds())
+
+	if err != nil {
+		result.Error = err.Error()
+		c.logger.WithError(err).Errorf("Failed to refresh %s", view)
+		if recErr := c.repo.RecordFailure(ctx, view, start, err, c.host); recErr != nil {
+			c.logger.WithError(recErr).Warn("Failed to record refresh failure")
+		}
+		return result
+	}
+
+	c.logger.Debugf("Refreshed %s: %d rows in %dms (concurrent=%v)", view, result.RowsAffected, result.RefreshTimeMs, result.Concurrent)
+	if err := c.repo.RecordSuccess(ctx, view, start, duration, result.RowsAffected, c.host); err != nil {
+		c.logger.WithError(err).Warn("Failed to record refresh")
+	}
+	return result
+}
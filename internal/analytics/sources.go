@@ -0,0 +1,359 @@
+package analytics
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// minTitleTokens is the number of significant title words both articles need before
+// their titles are compared, so short headlines do not link unrelated stories
+const minTitleTokens = 3
+
+// SourceComparer compares sources on coverage, speed and tone. Articles are grouped into
+// stories first: two articles belong to the same story when they were published within
+// the story window of each other and share enough canonical entities, or, when the AI
+// has not extracted entities, have largely the same title words. Stories are the
+// connected groups of such pairs.
+type SourceComparer struct {
+	repo   *repository.AnalyticsRepository
+	logger *logger.Logger
+}
+
+// NewSourceComparer creates a new source comparer
+func NewSourceComparer(repo *repository.AnalyticsRepository, log *logger.Logger) *SourceComparer {
+	return &SourceComparer{
+		repo:   repo,
+		logger: log.WithComponent("source-comparer"),
+	}
+}
+
+// sourceAccumulator collects the per-source counts while walking articles and stories
+type sourceAccumulator struct {
+	stats      models.SourceStats
+	sentiment  sentimentCounts
+	categories map[string]int
+	entities   map[int64]int
+	delays     []float64
+}
+
+type sentimentCounts struct {
+	processed, positive, neutral, negative int
+	sum                                    float64
+}
+
+func (s *sentimentCounts) add(a models.SourceArticle) {
+	if a.Sentiment == nil {
+		return
+	}
+	s.processed++
+	s.sum += *a.Sentiment
+	switch a.SentimentLabel {
+	case "positive":
+		s.positive++
+	case "negative":
+		s.negative++
+	default:
+		s.neutral++
+	}
+}
+
+func (s *sentimentCounts) result() models.SourceSentiment {
+	result := models.SourceSentiment{Processed: s.processed}
+	if s.processed == 0 {
+		return result
+	}
+	n := float64(s.processed)
+	avg := round3(s.sum / n)
+	result.Average = &avg
+	result.Positive = round3(float64(s.positive) / n)
+	result.Neutral = round3(float64(s.neutral) / n)
+	result.Negative = round3(float64(s.negative) / n)
+	return result
+}
+
+// Compare returns the analytics of every source that published in the query window,
+// most articles first
+func (c *SourceComparer) Compare(ctx context.Context, q models.SourceComparisonQuery) (*models.SourceComparison, error) {
+	articles, err := c.repo.GetSourceArticles(ctx, q.Since, q.Until)
+	if err != nil {
+		return nil, err
+	}
+	names, err := c.repo.GetSourceNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SourceComparison{
+		Since:    q.Since,
+		Until:    q.Until,
+		Articles: len(articles),
+		Sources:  make([]models.SourceStats, 0),
+	}
+
+	sources := make(map[string]*sourceAccumulator)
+	categoryTotals := make(map[string]int)
+	entityTotals := make(map[int64]int)
+	var overall sentimentCounts
+	for _, a := range articles {
+		acc, ok := sources[a.Source]
+		if !ok {
+			acc = &sourceAccumulator{
+				stats:      models.SourceStats{Source: a.Source, Name: names[a.Source]},
+				categories: make(map[string]int),
+				entities:   make(map[int64]int),
+			}
+			sources[a.Source] = acc
+		}
+		acc.stats.Articles++
+		acc.sentiment.add(a)
+		overall.add(a)
+		if a.Category != "" {
+			acc.categories[a.Category]++
+			categoryTotals[a.Category]++
+		}
+		for _, id := range a.EntityIDs {
+			acc.entities[id]++
+			entityTotals[id]++
+		}
+	}
+
+	stories := groupStories(articles, q)
+	result.Stories = len(stories)
+	for _, story := range stories {
+		// Earliest article of every source in the story
+		first := make(map[string]time.Time)
+		for _, i := range story {
+			a := articles[i]
+			if t, ok := first[a.Source]; !ok || a.Published.Before(t) {
+				first[a.Source] = a.Published
+			}
+		}
+		storyStart := articles[story[0]].Published
+
+		if len(first) == 1 {
+			for source := range first {
+				sources[source].stats.Stories++
+				sources[source].stats.Exclusives++
+			}
+			continue
+		}
+		result.MultiSourceStories++
+		for source, t := range first {
+			acc := sources[source]
+			acc.stats.Stories++
+			acc.stats.SharedStories++
+			delay := t.Sub(storyStart).Minutes()
+			if delay == 0 {
+				acc.stats.FirstReports++
+			}
+			acc.delays = append(acc.delays, delay)
+		}
+	}
+
+	result.Overall = overall.result()
+
+	var topEntityIDs []int64
+	for _, acc := range sources {
+		acc.stats.Entities = topEntityCoverage(acc.entities, entityTotals, q.TopN)
+		for _, e := range acc.stats.Entities {
+			topEntityIDs = append(topEntityIDs, e.ID)
+		}
+	}
+	entityNames, err := c.repo.GetEntityNames(ctx, topEntityIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, acc := range sources {
+		s := acc.stats
+		s.ArticleShare = round3(float64(s.Articles) / float64(len(articles)))
+		if s.Stories > 0 {
+			s.ExclusiveShare = round3(float64(s.Exclusives) / float64(s.Stories))
+		}
+		if len(acc.delays) > 0 {
+			median := math.Round(medianOf(acc.delays)*10) / 10
+			s.MedianDelayMinutes = &median
+		}
+		s.Sentiment = acc.sentiment.result()
+		if s.Sentiment.Average != nil && result.Overall.Average != nil {
+			delta := round3(*s.Sentiment.Average - *result.Overall.Average)
+			s.SentimentDelta = &delta
+		}
+		s.Categories = topCategoryCoverage(acc.categories, categoryTotals, q.TopN)
+		for i := range s.Entities {
+			s.Entities[i].Name = entityNames[s.Entities[i].ID]
+		}
+		result.Sources = append(result.Sources, s)
+	}
+	sort.Slice(result.Sources, func(i, j int) bool {
+		if result.Sources[i].Articles != result.Sources[j].Articles {
+			return result.Sources[i].Articles > result.Sources[j].Articles
+		}
+		return result.Sources[i].Source < result.Sources[j].Source
+	})
+
+	c.logger.Debugf("Compared %d sources: %d articles in %d stories, %d shared",
+		len(result.Sources), len(articles), result.Stories, result.MultiSourceStories)
+	return result, nil
+}
+
+// groupStories groups the articles, which must be sorted by publication time, into
+// stories and returns the article indexes of every story, oldest article first
+func groupStories(articles []models.SourceArticle, q models.SourceComparisonQuery) [][]int {
+	parent := make([]int, len(articles))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	entities := make([][]int64, len(articles))
+	titles := make([]map[string]struct{}, len(articles))
+	for i, a := range articles {
+		entities[i] = append([]int64(nil), a.EntityIDs...)
+		sort.Slice(entities[i], func(x, y int) bool { return entities[i][x] < entities[i][y] })
+		titles[i] = titleTokens(a.Title)
+	}
+
+	window := time.Duration(q.StoryWindowHours) * time.Hour
+	for i := range articles {
+		for j := i + 1; j < len(articles) && articles[j].Published.Sub(articles[i].Published) <= window; j++ {
+			if find(i) == find(j) {
+				continue
+			}
+			var linked bool
+			if len(entities[i]) > 0 && len(entities[j]) > 0 {
+				linked = sharedCount(entities[i], entities[j]) >= q.MinSharedEntities
+			} else {
+				linked = titleSimilarity(titles[i], titles[j]) >= q.MinTitleSimilarity
+			}
+			if linked {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	index := make(map[int]int)
+	stories := make([][]int, 0)
+	for i := range articles {
+		root := find(i)
+		k, ok := index[root]
+		if !ok {
+			k = len(stories)
+			index[root] = k
+			stories = append(stories, nil)
+		}
+		stories[k] = append(stories[k], i)
+	}
+	return stories
+}
+
+// sharedCount counts the values two sorted slices have in common
+func sharedCount(a, b []int64) int {
+	n := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			n++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return n
+}
+
+// titleTokens returns the lowercased words of at least four characters in a title
+func titleTokens(title string) map[string]struct{} {
+	tokens := make(map[string]struct{})
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) >= 4 {
+			tokens[word] = struct{}{}
+		}
+	}
+	return tokens
+}
+
+// titleSimilarity returns the Jaccard similarity of two title token sets, 0 when either
+// has fewer than minTitleTokens tokens
+func titleSimilarity(a, b map[string]struct{}) float64 {
+	if len(a) < minTitleTokens || len(b) < minTitleTokens {
+		return 0
+	}
+	shared := 0
+	for t := range a {
+		if _, ok := b[t]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func topCategoryCoverage(counts, totals map[string]int, n int) []models.SourceCoverage {
+	coverage := make([]models.SourceCoverage, 0, len(counts))
+	for category, count := range counts {
+		coverage = append(coverage, models.SourceCoverage{
+			Name:     category,
+			Articles: count,
+			Share:    round3(float64(count) / float64(totals[category])),
+		})
+	}
+	return topCoverage(coverage, n)
+}
+
+func topEntityCoverage(counts, totals map[int64]int, n int) []models.SourceCoverage {
+	coverage := make([]models.SourceCoverage, 0, len(counts))
+	for id, count := range counts {
+		coverage = append(coverage, models.SourceCoverage{
+			ID:       id,
+			Articles: count,
+			Share:    round3(float64(count) / float64(totals[id])),
+		})
+	}
+	return topCoverage(coverage, n)
+}
+
+// topCoverage returns the n categories or entities the source covered most
+func topCoverage(coverage []models.SourceCoverage, n int) []models.SourceCoverage {
+	sort.Slice(coverage, func(i, j int) bool {
+		if coverage[i].Articles != coverage[j].Articles {
+			return coverage[i].Articles > coverage[j].Articles
+		}
+		if coverage[i].Name != coverage[j].Name {
+			return coverage[i].Name < coverage[j].Name
+		}
+		return coverage[i].ID < coverage[j].ID
+	})
+	if len(coverage) > n {
+		coverage = coverage[:n]
+	}
+	return coverage
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
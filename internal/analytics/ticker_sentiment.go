@@ -0,0 +1,328 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/internal/stock"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// tickerPriceMaxAge is how long stored prices are used before they are fetched again,
+// and how long a failed fetch is not retried
+const tickerPriceMaxAge = 6 * time.Hour
+
+// errPriceRefreshBackoff is returned while a recently failed price fetch is not retried
+var errPriceRefreshBackoff = errors.New("price fetch failed recently, not retrying yet")
+
+// PriceSource provides daily historical prices, implemented by stock.Service
+type PriceSource interface {
+	GetHistoricalPrices(ctx context.Context, symbol string, from, to time.Time) ([]stock.HistoricalPrice, error)
+}
+
+// TickerSentimentAnalyzer relates the daily news volume and sentiment of a ticker to its
+// daily returns. The series is persisted in ticker_daily_stats: news columns are
+// recomputed from the articles on every analysis, prices are fetched from the price
+// source when they are older than tickerPriceMaxAge or do not cover the requested range.
+// A failed fetch is not retried for tickerPriceMaxAge; stored prices are used meanwhile.
+//
+// News on a non-trading day is attributed to the next trading day, so weekend news is
+// related to Monday's return. Returns are raw, not adjusted for the market.
+type TickerSentimentAnalyzer struct {
+	repo   *repository.AnalyticsRepository
+	prices PriceSource
+	logger *logger.Logger
+
+	failedMu sync.Mutex
+	failed   map[string]time.Time // symbol -> last failed price fetch
+}
+
+// NewTickerSentimentAnalyzer creates a new ticker sentiment analyzer; prices may be nil,
+// in which case only stored prices are used
+func NewTickerSentimentAnalyzer(repo *repository.AnalyticsRepository, prices PriceSource, log *logger.Logger) *TickerSentimentAnalyzer {
+	return &TickerSentimentAnalyzer{
+		repo:   repo,
+		prices: prices,
+		logger: log.WithComponent("ticker-sentiment"),
+		failed: make(map[string]time.Time),
+	}
+}
+
+// tradingDay is a trading day with the news attributed to it
+type tradingDay struct {
+	day          time.Time
+	articles     int
+	sentiment    float64 // Average sentiment weighted by article count
+	hasSentiment bool
+	ret          float64
+}
+
+// Analyze refreshes the ticker series and returns it with lagged correlations and an
+// event study over the last q.Days days
+func (a *TickerSentimentAnalyzer) Analyze(ctx context.Context, q models.TickerSentimentQuery) (*models.TickerSentimentAnalysis, error) {
+	symbol := strings.ToUpper(q.Symbol)
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -(q.Days - 1))
+
+	if err := a.repo.RefreshTickerNews(ctx, symbol, from, to); err != nil {
+		return nil, err
+	}
+
+	analysis := &models.TickerSentimentAnalysis{
+		Symbol: symbol,
+		From:   from,
+		To:     to,
+	}
+	if err := a.refreshPrices(ctx, symbol, from, to); err != nil {
+		a.logger.WithError(err).Warnf("Using stored prices for %s", symbol)
+		analysis.PricesStale = true
+	}
+
+	series, err := a.repo.GetTickerDailySeries(ctx, symbol, from, to)
+	if err != nil {
+		return nil, err
+	}
+	analysis.Series = series
+
+	days := alignTradingDays(series)
+	analysis.Correlations = lagCorrelations(days, q.MaxLag)
+	analysis.EventStudy = eventStudy(days, q)
+
+	a.logger.Debugf("Analyzed %s: %d days, %d trading days", symbol, len(series), len(days))
+	return analysis, nil
+}
+
+// refreshPrices fetches and stores prices when the stored ones are stale or start too late
+func (a *TickerSentimentAnalyzer) refreshPrices(ctx context.Context, symbol string, from, to time.Time) error {
+	firstDay, updatedAt, ok, err := a.repo.GetTickerPriceCoverage(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	// A range may start on a weekend or holiday, allow a few days before the first close
+	if ok && time.Since(updatedAt) < tickerPriceMaxAge && !firstDay.After(from.AddDate(0, 0, 4)) {
+		return nil
+	}
+	if a.prices == nil {
+		return nil
+	}
+	if a.recentlyFailed(symbol) {
+		return errPriceRefreshBackoff
+	}
+
+	// Start a week early so the first day in range has a previous close to compute its return
+	history, err := a.prices.GetHistoricalPrices(ctx, symbol, from.AddDate(0, 0, -7), to)
+	if err != nil {
+		if ctx.Err() == nil {
+			a.recordFailure(symbol)
+		}
+		return err
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Date.Before(history[j].Date)
+	})
+
+	prices := make([]models.TickerPrice, 0, len(history))
+	var prev float64
+	for _, h := range history {
+		closePrice := h.AdjClose
+		if closePrice <= 0 {
+			closePrice = h.Close
+		}
+		if closePrice <= 0 {
+			continue
+		}
+		price := models.TickerPrice{Day: h.Date, Close: closePrice}
+		if prev > 0 {
+			ret := closePrice/prev - 1
+			price.Return = &ret
+		}
+		prices = append(prices, price)
+		prev = closePrice
+	}
+	return a.repo.UpsertTickerPrices(ctx, symbol, prices)
+}
+
+// recentlyFailed reports whether fetching prices for symbol failed within tickerPriceMaxAge
+func (a *TickerSentimentAnalyzer) recentlyFailed(symbol string) bool {
+	a.failedMu.Lock()
+	defer a.failedMu.Unlock()
+
+	at, ok := a.failed[symbol]
+	if ok && time.Since(at) >= tickerPriceMaxAge {
+		delete(a.failed, symbol)
+		return false
+	}
+	return ok
+}
+
+// recordFailure remembers a failed price fetch so requests do not retry it at the provider
+func (a *TickerSentimentAnalyzer) recordFailure(symbol string) {
+	a.failedMu.Lock()
+	defer a.failedMu.Unlock()
+
+	for s, at := range a.failed {
+		if time.Since(at) >= tickerPriceMaxAge {
+			delete(a.failed, s)
+		}
+	}
+	a.failed[symbol] = time.Now()
+}
+
+// alignTradingDays attributes the news of every day to the first trading day on or after
+// it; news after the last trading day is left out
+func alignTradingDays(series []models.TickerDay) []tradingDay {
+	days := make([]tradingDay, 0, len(series))
+	var articles, weighted int
+	var sentimentSum float64
+	for _, d := range series {
+		articles += d.ArticleCount
+		if d.AvgSentiment != nil {
+			sentimentSum += *d.AvgSentiment * float64(d.ArticleCount)
+			weighted += d.ArticleCount
+		}
+		if d.Return == nil {
+			continue
+		}
+
+		td := tradingDay{day: d.Day, articles: articles, ret: *d.Return}
+		if weighted > 0 {
+			td.sentiment = sentimentSum / float64(weighted)
+			td.hasSentiment = true
+		}
+		days = append(days, td)
+		articles, weighted, sentimentSum = 0, 0, 0
+	}
+	return days
+}
+
+// lagCorrelations correlates the news of trading day t with the return of day t+lag for
+// every lag in [-maxLag, maxLag]; negative lags measure whether returns precede the news
+func lagCorrelations(days []tradingDay, maxLag int) []models.LagCorrelation {
+	correlations := make([]models.LagCorrelation, 0, 2*maxLag+1)
+	for lag := -maxLag; lag <= maxLag; lag++ {
+		var sentiment, returns, volume, absReturns []float64
+		for i := range days {
+			j := i + lag
+			if j < 0 || j >= len(days) {
+				continue
+			}
+			volume = append(volume, float64(days[i].articles))
+			absReturns = append(absReturns, math.Abs(days[j].ret))
+			if days[i].hasSentiment {
+				sentiment = append(sentiment, days[i].sentiment)
+				returns = append(returns, days[j].ret)
+			}
+		}
+		correlations = append(correlations, models.LagCorrelation{
+			Lag:             lag,
+			SentimentReturn: pearson(sentiment, returns),
+			VolumeAbsReturn: pearson(volume, absReturns),
+			N:               len(sentiment),
+		})
+	}
+	return correlations
+}
+
+// eventStudy averages the returns around trading days with at least q.MinArticles
+// articles and an average sentiment of at least q.EventThreshold in absolute value
+func eventStudy(days []tradingDay, q models.TickerSentimentQuery) models.EventStudy {
+	var positive, negative []int
+	for i, d := range days {
+		if d.articles < q.MinArticles || !d.hasSentiment {
+			continue
+		}
+		switch {
+		case d.sentiment >= q.EventThreshold:
+			positive = append(positive, i)
+		case d.sentiment <= -q.EventThreshold:
+			negative = append(negative, i)
+		}
+	}
+
+	return models.EventStudy{
+		Threshold:   q.EventThreshold,
+		MinArticles: q.MinArticles,
+		Window:      q.EventWindow,
+		Positive:    eventStudyGroup(days, positive, q.EventWindow),
+		Negative:    eventStudyGroup(days, negative, q.EventWindow),
+	}
+}
+
+func eventStudyGroup(days []tradingDay, events []int, window int) models.EventStudyGroup {
+	group := models.EventStudyGroup{
+		Events: make([]time.Time, 0, len(events)),
+		Days:   make([]models.EventStudyDay, 0, 2*window+1),
+	}
+	for _, i := range events {
+		group.Events = append(group.Events, days[i].day)
+	}
+
+	var cumulative *float64
+	for offset := -window; offset <= window; offset++ {
+		var sum float64
+		n := 0
+		for _, i := range events {
+			if j := i + offset; j >= 0 && j < len(days) {
+				sum += days[j].ret
+				n++
+			}
+		}
+
+		day := models.EventStudyDay{Offset: offset, N: n}
+		if n > 0 {
+			avg := sum / float64(n)
+			total := avg
+			if cumulative != nil {
+				total += *cumulative
+			}
+			cumulative = &total
+			day.AvgReturn = roundPtr(avg, 6)
+		}
+		if cumulative != nil {
+			day.CumulativeReturn = roundPtr(*cumulative, 6)
+		}
+		group.Days = append(group.Days, day)
+	}
+	return group
+}
+
+// pearson returns the Pearson correlation of x and y, or nil with fewer than three pairs
+// or when either side is constant
+func pearson(x, y []float64) *float64 {
+	n := len(x)
+	if n < 3 || n != len(y) {
+		return nil
+	}
+	var meanX, meanY float64
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var cov, varX, varY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return nil
+	}
+	return roundPtr(cov/math.Sqrt(varX*varY), 3)
+}
+
+func roundPtr(v float64, decimals int) *float64 {
+	scale := math.Pow(10, float64(decimals))
+	rounded := math.Round(v*scale) / scale
+	return &rounded
+}
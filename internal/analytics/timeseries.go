@@ -0,0 +1,237 @@
+package analytics
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/cache"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// timeSeriesCacheTTL is how long query results are cached
+const timeSeriesCacheTTL = 2 * time.Minute
+
+// ErrInvalidTimeSeriesQuery is returned for queries outside the allowlist
+var ErrInvalidTimeSeriesQuery = errors.New("invalid time series query")
+
+// Allowlists of the time series query dimensions
+var (
+	timeSeriesMetrics = []string{
+		models.TimeSeriesMetricArticles, models.TimeSeriesMetricAvgSentiment, models.TimeSeriesMetricEntityMentions,
+	}
+	timeSeriesGroups = []string{
+		"", models.TimeSeriesGroupSource, models.TimeSeriesGroupCategory, models.TimeSeriesGroupEntity,
+		models.TimeSeriesGroupTicker, models.TimeSeriesGroupSentiment,
+	}
+	timeSeriesBuckets = []string{
+		models.TimeSeriesBucketHour, models.TimeSeriesBucketDay, models.TimeSeriesBucketWeek,
+	}
+	timeSeriesSentiments = []string{
+		models.SentimentPositive, models.SentimentNeutral, models.SentimentNegative,
+	}
+	timeSeriesEntityTypes = []string{
+		models.EntityTypePerson, models.EntityTypeOrganization, models.EntityTypeLocation,
+	}
+)
+
+// TimeSeriesEngine answers generic aggregation queries over articles for dashboard
+// widgets: a metric per time bucket, optionally split by a dimension and filtered.
+// Queries are validated against allowlists, compiled to parameterised SQL by the
+// repository and cached in Redis.
+type TimeSeriesEngine struct {
+	repo   *repository.AnalyticsRepository
+	cache  *cache.Service
+	logger *logger.Logger
+}
+
+// NewTimeSeriesEngine creates a new time series engine; cacheService may be nil
+func NewTimeSeriesEngine(repo *repository.AnalyticsRepository, cacheService *cache.Service, log *logger.Logger) *TimeSeriesEngine {
+	return &TimeSeriesEngine{
+		repo:   repo,
+		cache:  cacheService,
+		logger: log.WithComponent("timeseries"),
+	}
+}
+
+// Query validates and runs q. Every series holds a point for every bucket in the range;
+// errors wrapping ErrInvalidTimeSeriesQuery describe the rejected parameter.
+func (e *TimeSeriesEngine) Query(ctx context.Context, q models.TimeSeriesQuery) (*models.TimeSeriesResult, error) {
+	if err := normalizeTimeSeriesQuery(&q); err != nil {
+		return nil, err
+	}
+
+	cacheKey, err := timeSeriesCacheKey(q)
+	if err != nil {
+		return nil, err
+	}
+	if e.cache != nil {
+		var cached models.TimeSeriesResult
+		if err := e.cache.Get(ctx, cacheKey, &cached); err == nil {
+			return &cached, nil
+		}
+	}
+
+	rows, err := e.repo.GetTimeSeries(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.TimeSeriesResult{
+		Query:    q,
+		Series:   buildTimeSeries(rows, q),
+		DataAsOf: time.Now().UTC(),
+	}
+
+	if e.cache != nil {
+		if err := e.cache.SetWithTTL(ctx, cacheKey, result, timeSeriesCacheTTL); err != nil {
+			e.logger.WithError(err).Warn("Failed to cache time series")
+		}
+	}
+	e.logger.Debugf("Time series %s by %q per %s: %d rows, %d series",
+		q.Metric, q.GroupBy, q.Bucket, len(rows), len(result.Series))
+	return result, nil
+}
+
+// normalizeTimeSeriesQuery checks q against the allowlists and limits, and normalizes
+// the range to UTC and tickers to upper case
+func normalizeTimeSeriesQuery(q *models.TimeSeriesQuery) error {
+	if !contains(timeSeriesMetrics, q.Metric) {
+		return invalidTimeSeries("metric must be one of %s", strings.Join(timeSeriesMetrics, ", "))
+	}
+	if !contains(timeSeriesGroups, q.GroupBy) {
+		return invalidTimeSeries("group_by must be one of %s", strings.Join(timeSeriesGroups[1:], ", "))
+	}
+	if !contains(timeSeriesBuckets, q.Bucket) {
+		return invalidTimeSeries("bucket must be one of %s", strings.Join(timeSeriesBuckets, ", "))
+	}
+
+	q.Since, q.Until = q.Since.UTC(), q.Until.UTC()
+	if !q.Since.Before(q.Until) {
+		return invalidTimeSeries("since must be before until")
+	}
+	if len(timeSeriesBucketStarts(q.Since, q.Until, q.Bucket)) > models.MaxTimeSeriesBuckets {
+		return invalidTimeSeries("range spans more than %d %s buckets", models.MaxTimeSeriesBuckets, q.Bucket)
+	}
+	if q.Limit < 1 || q.Limit > models.MaxTimeSeriesLimit {
+		return invalidTimeSeries("limit must be between 1 and %d", models.MaxTimeSeriesLimit)
+	}
+
+	f := &q.Filters
+	if len(f.Sources) > models.MaxTimeSeriesFilters || len(f.Categories) > models.MaxTimeSeriesFilters ||
+		len(f.Tickers) > models.MaxTimeSeriesFilters {
+		return invalidTimeSeries("filters accept at most %d values each", models.MaxTimeSeriesFilters)
+	}
+	if f.Sentiment != "" && !contains(timeSeriesSentiments, f.Sentiment) {
+		return invalidTimeSeries("sentiment must be one of %s", strings.Join(timeSeriesSentiments, ", "))
+	}
+	if f.EntityType != "" {
+		if !contains(timeSeriesEntityTypes, f.EntityType) {
+			return invalidTimeSeries("entity_type must be one of %s", strings.Join(timeSeriesEntityTypes, ", "))
+		}
+		if q.Metric != models.TimeSeriesMetricEntityMentions && q.GroupBy != models.TimeSeriesGroupEntity {
+			return invalidTimeSeries("entity_type requires metric=entity_mentions or group_by=entity")
+		}
+	}
+	for i, ticker := range f.Tickers {
+		f.Tickers[i] = strings.ToUpper(ticker)
+	}
+	return nil
+}
+
+func invalidTimeSeries(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidTimeSeriesQuery, fmt.Sprintf(format, args...))
+}
+
+// timeSeriesCacheKey hashes the normalized query, whose JSON form is deterministic
+func timeSeriesCacheKey(q models.TimeSeriesQuery) (string, error) {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode time series query: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return cache.GenerateKey(cache.PrefixAnalytics, "timeseries", hex.EncodeToString(sum[:16])), nil
+}
+
+// buildTimeSeries groups the rows, which are ordered by group, into series with a point
+// for every bucket of the range. Counts of empty buckets are 0, averages nil.
+func buildTimeSeries(rows []models.TimeSeriesRow, q models.TimeSeriesQuery) []models.TimeSeries {
+	starts := timeSeriesBucketStarts(q.Since, q.Until, q.Bucket)
+	index := make(map[int64]int, len(starts))
+	for i, start := range starts {
+		index[start.Unix()] = i
+	}
+
+	series := make([]models.TimeSeries, 0)
+	for _, row := range rows {
+		if len(series) == 0 || series[len(series)-1].Group != row.Group {
+			points := make([]models.TimeSeriesPoint, len(starts))
+			for i, start := range starts {
+				points[i].Bucket = start
+				if q.Metric != models.TimeSeriesMetricAvgSentiment {
+					zero := 0.0
+					points[i].Value = &zero
+				}
+			}
+			series = append(series, models.TimeSeries{Group: row.Group, Label: row.Label, Points: points})
+		}
+
+		s := &series[len(series)-1]
+		s.Articles += row.Articles
+		if i, ok := index[row.Bucket.Unix()]; ok {
+			s.Points[i].Value = row.Value
+			s.Points[i].Articles = row.Articles
+		}
+	}
+	return series
+}
+
+// timeSeriesBucketStarts returns the start of every bucket overlapping [since, until),
+// truncated like PostgreSQL's date_trunc in UTC; weeks start on Monday
+func timeSeriesBucketStarts(since, until time.Time, bucket string) []time.Time {
+	start := since.UTC()
+	switch bucket {
+	case models.TimeSeriesBucketHour:
+		start = start.Truncate(time.Hour)
+	case models.TimeSeriesBucketDay:
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	case models.TimeSeriesBucketWeek:
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	}
+
+	var starts []time.Time
+	for t := start; t.Before(until); t = nextBucket(t, bucket) {
+		starts = append(starts, t)
+		if len(starts) > models.MaxTimeSeriesBuckets {
+			break
+		}
+	}
+	return starts
+}
+
+func nextBucket(t time.Time, bucket string) time.Time {
+	switch bucket {
+	case models.TimeSeriesBucketHour:
+		return t.Add(time.Hour)
+	case models.TimeSeriesBucketWeek:
+		return t.AddDate(0, 0, 7)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
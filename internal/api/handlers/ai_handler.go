@@ -9,6 +9,7 @@ import (
 	"github.com/jeffrey/intellinieuws/internal/ai"
 	"github.com/jeffrey/intellinieuws/internal/cache"
 	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/pagination"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 )
 
@@ -49,13 +50,13 @@ func (h *AIHandler) GetEnrichment(c *fiber.Ctx) error {
 	var enrichment *ai.AIEnrichment
 
 	if h.cache != nil && h.cache.IsAvailable() {
-		if err := h.cache.Get(c.Context(), cacheKey, &enrichment); err == nil {
+		if err := h.cache.Get(c.UserContext(), cacheKey, &enrichment); err == nil {
 			h.logger.Debugf("Cache HIT for enrichment %d", articleID)
 			return c.JSON(models.NewSuccessResponse(enrichment, requestID))
 		}
 	}
 
-	enrichment, err = h.aiService.GetEnrichment(c.Context(), articleID)
+	enrichment, err = h.aiService.GetEnrichment(c.UserContext(), articleID)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to get enrichment for article %d", articleID)
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -65,7 +66,7 @@ func (h *AIHandler) GetEnrichment(c *fiber.Ctx) error {
 
 	// Cache the result
 	if h.cache != nil && h.cache.IsAvailable() {
-		if err := h.cache.Set(c.Context(), cacheKey, enrichment); err != nil {
+		if err := h.cache.Set(c.UserContext(), cacheKey, enrichment); err != nil {
 			h.logger.WithError(err).Warn("Failed to cache enrichment")
 		}
 	}
@@ -85,7 +86,7 @@ func (h *AIHandler) ProcessArticle(c *fiber.Ctx) error {
 		)
 	}
 
-	enrichment, err := h.aiService.ProcessArticle(c.Context(), articleID)
+	enrichment, err := h.aiService.ProcessArticle(c.UserContext(), articleID)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to process article %d", articleID)
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -96,7 +97,7 @@ func (h *AIHandler) ProcessArticle(c *fiber.Ctx) error {
 	// Invalidate cache after processing
 	if h.cache != nil && h.cache.IsAvailable() {
 		cacheKey := cache.GenerateKey(cache.PrefixAIEnrichment, c.Params("id"))
-		h.cache.Delete(c.Context(), cacheKey)
+		h.cache.Delete(c.UserContext(), cacheKey)
 	}
 
 	response := map[string]interface{}{
@@ -143,7 +144,7 @@ func (h *AIHandler) GetSentimentStats(c *fiber.Ctx) error {
 	h.logger.Debugf("Checking cache with key: %s", cacheKey)
 
 	if h.cache != nil && h.cache.IsAvailable() {
-		if err := h.cache.Get(c.Context(), cacheKey, &stats); err == nil {
+		if err := h.cache.Get(c.UserContext(), cacheKey, &stats); err == nil {
 			h.logger.Infof("✅ Cache HIT for sentiment stats - returning cached data")
 			h.logger.Debugf("Cached stats: Total=%d, Positive=%d, Negative=%d, Neutral=%d",
 				stats.TotalArticles, stats.PositiveCount, stats.NegativeCount, stats.NeutralCount)
@@ -155,7 +156,7 @@ func (h *AIHandler) GetSentimentStats(c *fiber.Ctx) error {
 		h.logger.Debug("Cache not available, querying database directly")
 	}
 
-	stats, err := h.aiService.GetSentimentStats(c.Context(), source, startDate, endDate)
+	stats, err := h.aiService.GetSentimentStats(c.UserContext(), source, startDate, endDate)
 	if err != nil {
 		h.logger.WithError(err).Error("❌ Failed to get sentiment stats from database")
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -167,7 +168,7 @@ func (h *AIHandler) GetSentimentStats(c *fiber.Ctx) error {
 
 	// Cache the result (5 minutes TTL for stats)
 	if h.cache != nil && h.cache.IsAvailable() {
-		if err := h.cache.Set(c.Context(), cacheKey, stats); err != nil {
+		if err := h.cache.Set(c.UserContext(), cacheKey, stats); err != nil {
 			h.logger.WithError(err).Warn("⚠️  Failed to cache sentiment stats")
 		} else {
 			h.logger.Debugf("Cached sentiment stats with key: %s", cacheKey)
@@ -206,13 +207,13 @@ func (h *AIHandler) GetTrendingTopics(c *fiber.Ctx) error {
 
 	var cached cachedResponse
 	if h.cache != nil && h.cache.IsAvailable() {
-		if err := h.cache.Get(c.Context(), cacheKey, &cached); err == nil {
+		if err := h.cache.Get(c.UserContext(), cacheKey, &cached); err == nil {
 			h.logger.Debugf("Cache HIT for trending topics")
 			return c.JSON(models.NewSuccessResponse(cached, requestID))
 		}
 	}
 
-	topics, err := h.aiService.GetTrendingTopics(c.Context(), hoursBack, minArticles)
+	topics, err := h.aiService.GetTrendingTopics(c.UserContext(), hoursBack, minArticles)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get trending topics")
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -229,7 +230,7 @@ func (h *AIHandler) GetTrendingTopics(c *fiber.Ctx) error {
 
 	// Cache the result (2 minutes TTL for trending topics)
 	if h.cache != nil && h.cache.IsAvailable() {
-		if err := h.cache.Set(c.Context(), cacheKey, response); err != nil {
+		if err := h.cache.Set(c.UserContext(), cacheKey, response); err != nil {
 			h.logger.WithError(err).Warn("Failed to cache trending topics")
 		}
 	}
@@ -237,7 +238,24 @@ func (h *AIHandler) GetTrendingTopics(c *fiber.Ctx) error {
 	return c.JSON(models.NewSuccessResponse(response, requestID))
 }
 
-// GetArticlesByEntity returns articles mentioning a specific entity
+// parseArticleCursor parses ?cursor= for newest-first article lists.
+// On failure the error response has already been written.
+func parseArticleCursor(c *fiber.Ctx, requestID string) (*pagination.Cursor, bool) {
+	token := c.Query("cursor")
+	if token == "" {
+		return nil, true
+	}
+	cursor, err := pagination.Decode(token, "published", "desc")
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_CURSOR", "Invalid pagination cursor", err.Error(), requestID),
+		)
+		return nil, false
+	}
+	return cursor, true
+}
+
+// GetArticlesByEntity returns articles mentioning a specific entity, paginated with ?cursor=
 // GET /api/v1/ai/entity/:name
 func (h *AIHandler) GetArticlesByEntity(c *fiber.Ctx) error {
 	requestID := c.Locals("requestid").(string)
@@ -258,25 +276,30 @@ func (h *AIHandler) GetArticlesByEntity(c *fiber.Ctx) error {
 		limit = 50
 	}
 
+	cursor, ok := parseArticleCursor(c, requestID)
+	if !ok {
+		return nil
+	}
+
 	// Try cache first (OPTIMIZED)
-	cacheKey := cache.GenerateKey(cache.PrefixAIEntity, entityName, entityType, fmt.Sprintf("l%d", limit))
-	var articles []models.Article
+	cacheKey := cache.GenerateKey(cache.PrefixAIEntity, entityName, entityType, fmt.Sprintf("l%d", limit), c.Query("cursor"))
+	var cached models.ArticlePage
 
 	if h.cache != nil && h.cache.IsAvailable() {
-		if err := h.cache.Get(c.Context(), cacheKey, &articles); err == nil {
+		if err := h.cache.Get(c.UserContext(), cacheKey, &cached); err == nil {
 			h.logger.Debugf("Cache HIT for entity %s", entityName)
 
 			meta := &models.Meta{
-				Pagination: models.CalculatePaginationMeta(len(articles), limit, 0),
+				Pagination: models.CalculateCursorPaginationMeta(nil, limit, 0, cached.Page),
 				Filtering: &models.FilteringMeta{
 					Search: entityName,
 				},
 			}
-			return c.JSON(models.NewSuccessResponseWithMeta(articles, meta, requestID))
+			return c.JSON(models.NewSuccessResponseWithMeta(cached.Articles, meta, requestID))
 		}
 	}
 
-	articles, err := h.aiService.GetArticlesByEntity(c.Context(), entityName, entityType, limit)
+	articles, page, err := h.aiService.PageArticlesByEntity(c.UserContext(), entityName, entityType, limit, cursor)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to get articles for entity %s", entityName)
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -286,13 +309,13 @@ func (h *AIHandler) GetArticlesByEntity(c *fiber.Ctx) error {
 
 	// Cache the result
 	if h.cache != nil && h.cache.IsAvailable() {
-		if err := h.cache.Set(c.Context(), cacheKey, articles); err != nil {
+		if err := h.cache.Set(c.UserContext(), cacheKey, models.ArticlePage{Articles: articles, Page: page}); err != nil {
 			h.logger.WithError(err).Warn("Failed to cache entity articles")
 		}
 	}
 
 	meta := &models.Meta{
-		Pagination: models.CalculatePaginationMeta(len(articles), limit, 0),
+		Pagination: models.CalculateCursorPaginationMeta(nil, limit, 0, page),
 		Filtering: &models.FilteringMeta{
 			Search: entityName,
 		},
@@ -301,7 +324,7 @@ func (h *AIHandler) GetArticlesByEntity(c *fiber.Ctx) error {
 	return c.JSON(models.NewSuccessResponseWithMeta(articles, meta, requestID))
 }
 
-// GetArticlesByTicker returns articles mentioning a specific stock ticker
+// GetArticlesByTicker returns articles mentioning a specific stock ticker, paginated with ?cursor=
 // GET /api/v1/articles/by-ticker/:symbol
 func (h *AIHandler) GetArticlesByTicker(c *fiber.Ctx) error {
 	requestID := c.Locals("requestid").(string)
@@ -321,25 +344,30 @@ func (h *AIHandler) GetArticlesByTicker(c *fiber.Ctx) error {
 		limit = 50
 	}
 
+	cursor, ok := parseArticleCursor(c, requestID)
+	if !ok {
+		return nil
+	}
+
 	// Try cache first (OPTIMIZED)
-	cacheKey := cache.GenerateKey(cache.PrefixAIEntity, "ticker", symbol, fmt.Sprintf("l%d", limit))
-	var articles []models.Article
+	cacheKey := cache.GenerateKey(cache.PrefixAIEntity, "ticker", symbol, fmt.Sprintf("l%d", limit), c.Query("cursor"))
+	var cached models.ArticlePage
 
 	if h.cache != nil && h.cache.IsAvailable() {
-		if err := h.cache.Get(c.Context(), cacheKey, &articles); err == nil {
+		if err := h.cache.Get(c.UserContext(), cacheKey, &cached); err == nil {
 			h.logger.Debugf("Cache HIT for stock ticker %s", symbol)
 
 			meta := &models.Meta{
-				Pagination: models.CalculatePaginationMeta(len(articles), limit, 0),
+				Pagination: models.CalculateCursorPaginationMeta(nil, limit, 0, cached.Page),
 				Filtering: &models.FilteringMeta{
 					Search: symbol,
 				},
 			}
-			return c.JSON(models.NewSuccessResponseWithMeta(articles, meta, requestID))
+			return c.JSON(models.NewSuccessResponseWithMeta(cached.Articles, meta, requestID))
 		}
 	}
 
-	articles, err := h.aiService.GetArticlesByStockTicker(c.Context(), symbol, limit)
+	articles, page, err := h.aiService.PageArticlesByStockTicker(c.UserContext(), symbol, limit, cursor)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to get articles for stock ticker %s", symbol)
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -349,13 +377,13 @@ func (h *AIHandler) GetArticlesByTicker(c *fiber.Ctx) error {
 
 	// Cache the result
 	if h.cache != nil && h.cache.IsAvailable() {
-		if err := h.cache.Set(c.Context(), cacheKey, articles); err != nil {
+		if err := h.cache.Set(c.UserContext(), cacheKey, models.ArticlePage{Articles: articles, Page: page}); err != nil {
 			h.logger.WithError(err).Warn("Failed to cache ticker articles")
 		}
 	}
 
 	meta := &models.Meta{
-		Pagination: models.CalculatePaginationMeta(len(articles), limit, 0),
+		Pagination: models.CalculateCursorPaginationMeta(nil, limit, 0, page),
 		Filtering: &models.FilteringMeta{
 			Search: symbol,
 		},
@@ -375,7 +403,7 @@ func (h *AIHandler) TriggerProcessing(c *fiber.Ctx) error {
 		)
 	}
 
-	result, err := h.processor.ManualTrigger(c.Context())
+	result, err := h.processor.ManualTrigger(c.UserContext())
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to trigger processing")
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -385,8 +413,8 @@ func (h *AIHandler) TriggerProcessing(c *fiber.Ctx) error {
 
 	// Invalidate all AI caches after batch processing
 	if h.cache != nil && h.cache.IsAvailable() {
-		h.cache.DeletePattern(c.Context(), cache.PrefixAITrending+"*")
-		h.cache.DeletePattern(c.Context(), cache.PrefixAISentiment+"*")
+		h.cache.DeletePattern(c.UserContext(), cache.PrefixAITrending+"*")
+		h.cache.DeletePattern(c.UserContext(), cache.PrefixAISentiment+"*")
 	}
 
 	response := map[string]interface{}{
@@ -415,6 +443,13 @@ func (h *AIHandler) GetProcessorStats(c *fiber.Ctx) error {
 	return c.JSON(models.NewSuccessResponse(stats, requestID))
 }
 
+// GetInjectionStats returns prompt-injection scan and detection counters
+// GET /api/v1/ai/security/injections
+func (h *AIHandler) GetInjectionStats(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+	return c.JSON(models.NewSuccessResponse(ai.GetInjectionStats(), requestID))
+}
+
 // Chat handles conversational AI requests
 // POST /api/v1/ai/chat
 func (h *AIHandler) Chat(c *fiber.Ctx) error {
@@ -452,14 +487,14 @@ func (h *AIHandler) Chat(c *fiber.Ctx) error {
 	var response *ai.ChatResponse
 
 	if h.cache != nil && h.cache.IsAvailable() {
-		if err := h.cache.Get(c.Context(), cacheKey, &response); err == nil {
+		if err := h.cache.Get(c.UserContext(), cacheKey, &response); err == nil {
 			h.logger.Debugf("Cache HIT for chat message")
 			return c.JSON(models.NewSuccessResponse(response, requestID))
 		}
 	}
 
 	// Process chat message with optional article context
-	response, err := h.chatService.ProcessChatMessageWithContext(c.Context(), req.Message, req.Context, req.ArticleContent, req.ArticleID)
+	response, err := h.chatService.ProcessChatMessageWithContext(c.UserContext(), req.Message, req.Context, req.ArticleContent, req.ArticleID)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to process chat message")
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -469,7 +504,7 @@ func (h *AIHandler) Chat(c *fiber.Ctx) error {
 
 	// Cache the response
 	if h.cache != nil && h.cache.IsAvailable() {
-		if err := h.cache.Set(c.Context(), cacheKey, response); err != nil {
+		if err := h.cache.Set(c.UserContext(), cacheKey, response); err != nil {
 			h.logger.WithError(err).Warn("Failed to cache chat response")
 		}
 	}
@@ -2,27 +2,60 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeffrey/intellinieuws/internal/analytics"
+	"github.com/jeffrey/intellinieuws/internal/cache"
 	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 )
 
 // AnalyticsHandler handles analytics-related requests
 type AnalyticsHandler struct {
-	db     *pgxpool.Pool
-	logger *logger.Logger
+	db        *pgxpool.Pool
+	bursts    *analytics.BurstDetector
+	graphs    *analytics.EntityGraphBuilder
+	sources   *analytics.SourceComparer
+	series    *analytics.TimeSeriesEngine
+	refresher *analytics.RefreshCoordinator
+	logger    *logger.Logger
 }
 
-// NewAnalyticsHandler creates a new analytics handler
-func NewAnalyticsHandler(db *pgxpool.Pool, log *logger.Logger) *AnalyticsHandler {
+// NewAnalyticsHandler creates a new analytics handler; all materialized view refreshes
+// go through refresher
+func NewAnalyticsHandler(db *pgxpool.Pool, cacheService *cache.Service, refresher *analytics.RefreshCoordinator, log *logger.Logger) *AnalyticsHandler {
+	repo := repository.NewAnalyticsRepository(db, log)
 	return &AnalyticsHandler{
-		db:     db,
-		logger: log.WithComponent("analytics-handler"),
+		db:        db,
+		bursts:    analytics.NewBurstDetector(repo, log),
+		graphs:    analytics.NewEntityGraphBuilder(repo, log),
+		sources:   analytics.NewSourceComparer(repo, log),
+		series:    analytics.NewTimeSeriesEngine(repo, cacheService, log),
+		refresher: refresher,
+		logger:    log.WithComponent("analytics-handler"),
 	}
 }
 
+// viewDataAsOf returns when the oldest of the given materialized views was last
+// refreshed, the time the data served from them is as of. It returns nil when a view
+// was never refreshed or its state cannot be read.
+func (h *AnalyticsHandler) viewDataAsOf(ctx context.Context, views ...string) *time.Time {
+	dataAsOf, err := h.refresher.DataAsOf(ctx, views...)
+	if err != nil {
+		h.logger.Warnf("Failed to get refresh time of %v: %v", views, err)
+		return nil
+	}
+	return dataAsOf
+}
+
 // TrendingKeyword represents a trending keyword with stats
 type TrendingKeyword struct {
 	Keyword       string   `json:"keyword"`
@@ -50,6 +83,7 @@ type SentimentTrend struct {
 
 // HotEntity represents a frequently mentioned entity
 type HotEntity struct {
+	EntityID         int64    `json:"entity_id"`
 	Entity           string   `json:"entity"`
 	EntityType       string   `json:"entity_type"`
 	TotalMentions    int64    `json:"total_mentions"`
@@ -126,7 +160,8 @@ func (h *AnalyticsHandler) GetTrendingKeywords(c *fiber.Ctx) error {
 	h.logger.Infof("Returning %d trending keywords", len(trending))
 
 	return c.JSON(fiber.Map{
-		"trending": trending,
+		"trending":   trending,
+		"data_as_of": h.viewDataAsOf(c.UserContext(), analytics.ViewTrendingKeywords),
 		"meta": fiber.Map{
 			"hours":        hours,
 			"min_articles": minArticles,
@@ -136,6 +171,354 @@ func (h *AnalyticsHandler) GetTrendingKeywords(c *fiber.Ctx) error {
 	})
 }
 
+// GetBursts returns keywords and entities mentioned far more often than their own
+// baseline, with burst score, onset and contributing articles
+// GET /api/v1/analytics/bursts?kind=keyword&windows=1,6,24&baseline_days=14&min_z=3&min_articles=3&limit=20
+func (h *AnalyticsHandler) GetBursts(c *fiber.Ctx) error {
+	query := models.BurstQuery{
+		Kind:         c.Query("kind"),
+		EntityType:   c.Query("entity_type"),
+		BaselineDays: c.QueryInt("baseline_days", models.DefaultBurstBaselineDays),
+		MinZScore:    c.QueryFloat("min_z", models.DefaultBurstMinZScore),
+		MinArticles:  c.QueryInt("min_articles", models.DefaultBurstMinArticles),
+		Limit:        c.QueryInt("limit", models.DefaultBurstLimit),
+		ArticleLimit: c.QueryInt("articles", models.DefaultBurstArticleLimit),
+	}
+
+	if query.Kind != "" && query.Kind != models.BurstKindKeyword && query.Kind != models.BurstKindEntity {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "invalid_parameter",
+			Message: "kind must be keyword or entity",
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	for _, part := range strings.Split(c.Query("windows", "1,6,24"), ",") {
+		hours, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || hours < 1 || hours > models.MaxBurstWindowHours {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error:   "invalid_parameter",
+				Message: "windows must be a comma-separated list of hours between 1 and 24",
+				Code:    fiber.StatusBadRequest,
+			})
+		}
+		query.WindowHours = append(query.WindowHours, hours)
+	}
+
+	// Validate parameters
+	if query.BaselineDays < 2 || query.BaselineDays > models.MaxBurstBaselineDays {
+		query.BaselineDays = models.DefaultBurstBaselineDays
+	}
+	if query.MinZScore <= 0 {
+		query.MinZScore = models.DefaultBurstMinZScore
+	}
+	if query.MinArticles < 1 {
+		query.MinArticles = models.DefaultBurstMinArticles
+	}
+	if query.Limit < 1 || query.Limit > 100 {
+		query.Limit = models.DefaultBurstLimit
+	}
+	if query.ArticleLimit < 0 || query.ArticleLimit > 20 {
+		query.ArticleLimit = models.DefaultBurstArticleLimit
+	}
+
+	dataAsOf := time.Now().UTC()
+	bursts, err := h.bursts.Detect(c.UserContext(), query)
+	if err != nil {
+		h.logger.Errorf("Failed to detect bursts: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to detect bursts",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	h.logger.Infof("Returning %d bursts", len(bursts))
+
+	return c.JSON(fiber.Map{
+		"bursts":     bursts,
+		"data_as_of": dataAsOf,
+		"meta": fiber.Map{
+			"kind":          query.Kind,
+			"entity_type":   query.EntityType,
+			"windows":       query.WindowHours,
+			"baseline_days": query.BaselineDays,
+			"min_z":         query.MinZScore,
+			"min_articles":  query.MinArticles,
+			"limit":         query.Limit,
+			"count":         len(bursts),
+		},
+	})
+}
+
+// GetSourceComparison compares sources on coverage share per category and entity,
+// publication delay behind the first source of a story, sentiment and exclusives
+// GET /api/v1/analytics/sources?days=7&story_window=36&min_shared_entities=2&title_similarity=0.5&top=5
+func (h *AnalyticsHandler) GetSourceComparison(c *fiber.Ctx) error {
+	days := c.QueryInt("days", models.DefaultSourceComparisonDays)
+	if days < 1 || days > models.MaxSourceComparisonDays {
+		days = models.DefaultSourceComparisonDays
+	}
+
+	until := time.Now().UTC()
+	query := models.SourceComparisonQuery{
+		Since:              until.AddDate(0, 0, -days),
+		Until:              until,
+		StoryWindowHours:   c.QueryInt("story_window", models.DefaultStoryWindowHours),
+		MinSharedEntities:  c.QueryInt("min_shared_entities", models.DefaultStorySharedEntities),
+		MinTitleSimilarity: c.QueryFloat("title_similarity", models.DefaultStoryTitleSimilarity),
+		TopN:               c.QueryInt("top", models.DefaultSourceComparisonTopN),
+	}
+	if query.StoryWindowHours < 1 || query.StoryWindowHours > 168 {
+		query.StoryWindowHours = models.DefaultStoryWindowHours
+	}
+	if query.MinSharedEntities < 1 {
+		query.MinSharedEntities = models.DefaultStorySharedEntities
+	}
+	if query.MinTitleSimilarity <= 0 || query.MinTitleSimilarity > 1 {
+		query.MinTitleSimilarity = models.DefaultStoryTitleSimilarity
+	}
+	if query.TopN < 0 || query.TopN > 50 {
+		query.TopN = models.DefaultSourceComparisonTopN
+	}
+
+	comparison, err := h.sources.Compare(c.UserContext(), query)
+	if err != nil {
+		h.logger.Errorf("Failed to compare sources: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to compare sources",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"comparison": comparison,
+		"data_as_of": comparison.Until,
+		"meta": fiber.Map{
+			"days":                days,
+			"story_window":        query.StoryWindowHours,
+			"min_shared_entities": query.MinSharedEntities,
+			"title_similarity":    query.MinTitleSimilarity,
+			"top":                 query.TopN,
+			"count":               len(comparison.Sources),
+		},
+	})
+}
+
+// GetTimeSeries runs a generic aggregation over articles: a metric per time bucket,
+// optionally split by source, category, entity, ticker or sentiment label
+// GET /api/v1/analytics/timeseries?metric=articles&group_by=source&bucket=day&days=7&source=nu.nl,nos.nl&limit=10
+func (h *AnalyticsHandler) GetTimeSeries(c *fiber.Ctx) error {
+	query := models.TimeSeriesQuery{
+		Metric:  c.Query("metric", models.TimeSeriesMetricArticles),
+		GroupBy: c.Query("group_by"),
+		Bucket:  c.Query("bucket", models.TimeSeriesBucketDay),
+		Limit:   c.QueryInt("limit", models.DefaultTimeSeriesLimit),
+		Filters: models.TimeSeriesFilters{
+			Sources:    splitList(c.Query("source")),
+			Categories: splitList(c.Query("category")),
+			Sentiment:  c.Query("sentiment"),
+			Tickers:    splitList(c.Query("ticker")),
+			EntityType: c.Query("entity_type"),
+		},
+	}
+
+	// An explicit range, or the last days up to now. Now is truncated to the minute so
+	// dashboard widgets polling the same query share a cache entry.
+	query.Until = time.Now().UTC().Truncate(time.Minute)
+	if until := c.Query("until"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error:   "invalid_parameter",
+				Message: "until must be an RFC 3339 timestamp",
+				Code:    fiber.StatusBadRequest,
+			})
+		}
+		query.Until = t
+	}
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error:   "invalid_parameter",
+				Message: "since must be an RFC 3339 timestamp",
+				Code:    fiber.StatusBadRequest,
+			})
+		}
+		query.Since = t
+	} else {
+		days := c.QueryInt("days", models.DefaultTimeSeriesDays)
+		if days < 1 {
+			days = models.DefaultTimeSeriesDays
+		}
+		query.Since = query.Until.AddDate(0, 0, -days)
+	}
+
+	if entity := c.Query("entity"); entity != "" {
+		id, err := h.graphs.ResolveEntity(c.UserContext(), entity)
+		if errors.Is(err, repository.ErrEntityNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+				Error:   "not_found",
+				Message: fmt.Sprintf("Entity '%s' not found", entity),
+				Code:    fiber.StatusNotFound,
+			})
+		}
+		if err != nil {
+			h.logger.Errorf("Failed to resolve entity '%s': %v", entity, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+				Error:   "database_error",
+				Message: "Failed to resolve entity",
+				Code:    fiber.StatusInternalServerError,
+			})
+		}
+		query.Filters.EntityID = id
+	}
+
+	result, err := h.series.Query(c.UserContext(), query)
+	if errors.Is(err, analytics.ErrInvalidTimeSeriesQuery) {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "invalid_parameter",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+	if err != nil {
+		h.logger.Errorf("Failed to run time series query: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to run time series query",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"series":     result.Series,
+		"data_as_of": result.DataAsOf,
+		"meta": fiber.Map{
+			"query": result.Query,
+			"count": len(result.Series),
+		},
+	})
+}
+
+// splitList splits a comma-separated query parameter, dropping empty values
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// GetEntityGraph returns the co-occurrence graph of entities mentioned together in
+// articles, or the ego network of a single entity, as JSON or GEXF
+// GET /api/v1/analytics/entity-graph?days=7&entity=Rutte&entity_type=person&min_weight=2&limit=100&format=gexf
+func (h *AnalyticsHandler) GetEntityGraph(c *fiber.Ctx) error {
+	days := c.QueryInt("days", models.DefaultEntityGraphDays)
+	if days < 1 || days > models.MaxEntityGraphDays {
+		days = models.DefaultEntityGraphDays
+	}
+
+	until := time.Now().UTC()
+	query := models.EntityGraphQuery{
+		Since:      until.AddDate(0, 0, -days),
+		Until:      until,
+		EntityType: c.Query("entity_type"),
+		MinWeight:  c.QueryInt("min_weight", models.DefaultEntityGraphMinWeight),
+		Limit:      c.QueryInt("limit", models.DefaultEntityGraphLimit),
+	}
+	if query.MinWeight < 1 {
+		query.MinWeight = models.DefaultEntityGraphMinWeight
+	}
+	if query.Limit < 2 || query.Limit > models.MaxEntityGraphLimit {
+		query.Limit = models.DefaultEntityGraphLimit
+	}
+
+	format := c.Query("format", models.GraphFormatJSON)
+	if format != models.GraphFormatJSON && format != models.GraphFormatGEXF {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "invalid_parameter",
+			Message: "format must be json or gexf",
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	entity := c.Query("entity")
+	if entity != "" {
+		id, err := h.graphs.ResolveEntity(c.UserContext(), entity)
+		if errors.Is(err, repository.ErrEntityNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+				Error:   "not_found",
+				Message: fmt.Sprintf("Entity '%s' not found", entity),
+				Code:    fiber.StatusNotFound,
+			})
+		}
+		if err != nil {
+			h.logger.Errorf("Failed to resolve entity '%s': %v", entity, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+				Error:   "database_error",
+				Message: "Failed to resolve entity",
+				Code:    fiber.StatusInternalServerError,
+			})
+		}
+		query.CenterID = id
+	}
+
+	h.logger.Debugf("Building entity graph: days=%d, center=%d, type=%s, min_weight=%d, limit=%d",
+		days, query.CenterID, query.EntityType, query.MinWeight, query.Limit)
+
+	graph, err := h.graphs.Build(c.UserContext(), query)
+	if err != nil {
+		h.logger.Errorf("Failed to build entity graph: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to build entity graph",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	if format == models.GraphFormatGEXF {
+		description := fmt.Sprintf("Entity co-occurrence in news articles, %s to %s",
+			query.Since.Format(time.RFC3339), query.Until.Format(time.RFC3339))
+		if entity != "" {
+			description = fmt.Sprintf("Ego network of %s: %s", entity, description)
+		}
+		body, err := analytics.RenderGEXF(graph, description, until)
+		if err != nil {
+			h.logger.Errorf("Failed to render entity graph: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+				Error:   "render_error",
+				Message: "Failed to render entity graph",
+				Code:    fiber.StatusInternalServerError,
+			})
+		}
+		c.Set(fiber.HeaderContentType, analytics.GEXFContentType)
+		return c.Send(body)
+	}
+
+	return c.JSON(fiber.Map{
+		"nodes":      graph.Nodes,
+		"edges":      graph.Edges,
+		"data_as_of": until,
+		"meta": fiber.Map{
+			"days":        days,
+			"since":       query.Since,
+			"until":       query.Until,
+			"entity":      entity,
+			"center_id":   query.CenterID,
+			"entity_type": query.EntityType,
+			"min_weight":  query.MinWeight,
+			"limit":       query.Limit,
+			"node_count":  len(graph.Nodes),
+			"edge_count":  len(graph.Edges),
+		},
+	})
+}
+
 // GetSentimentTrends returns sentiment trends over the last 7 days
 // GET /api/v1/analytics/sentiment-trends?source=nu.nl
 func (h *AnalyticsHandler) GetSentimentTrends(c *fiber.Ctx) error {
@@ -191,7 +574,8 @@ func (h *AnalyticsHandler) GetSentimentTrends(c *fiber.Ctx) error {
 	h.logger.Infof("Returning %d sentiment trends", len(trends))
 
 	return c.JSON(fiber.Map{
-		"trends": trends,
+		"trends":     trends,
+		"data_as_of": h.viewDataAsOf(c.UserContext(), analytics.ViewSentimentTimeline),
 		"meta": fiber.Map{
 			"source": source,
 			"count":  len(trends),
@@ -250,6 +634,7 @@ func (h *AnalyticsHandler) GetHotEntities(c *fiber.Ctx) error {
 			&entity.Sources,
 			&entity.OverallSentiment,
 			&mostRecent,
+			&entity.EntityID,
 		)
 		if err != nil {
 			h.logger.Warnf("Failed to scan entity: %v", err)
@@ -268,7 +653,8 @@ func (h *AnalyticsHandler) GetHotEntities(c *fiber.Ctx) error {
 	h.logger.Infof("Returning %d hot entities", len(entities))
 
 	return c.JSON(fiber.Map{
-		"entities": entities,
+		"entities":   entities,
+		"data_as_of": h.viewDataAsOf(c.UserContext(), analytics.ViewEntityMentions),
 		"meta": fiber.Map{
 			"entity_type": entityType,
 			"limit":       limit,
@@ -296,6 +682,16 @@ func (h *AnalyticsHandler) GetEntitySentiment(c *fiber.Ctx) error {
 
 	h.logger.Debugf("Fetching entity sentiment: entity=%s, days=%d", entity, days)
 
+	// Resolve any alias to its canonical entity
+	var entityID *int64
+	var canonicalName *string
+	err := h.db.QueryRow(context.Background(),
+		`SELECT e.id, e.canonical_name FROM entities e WHERE e.id = resolve_entity_id($1)`, entity,
+	).Scan(&entityID, &canonicalName)
+	if err != nil && err != pgx.ErrNoRows {
+		h.logger.Warnf("Failed to resolve entity '%s': %v", entity, err)
+	}
+
 	// Query entity sentiment analysis
 	query := `SELECT * FROM get_entity_sentiment_analysis($1, $2)`
 	rows, err := h.db.Query(context.Background(), query, entity, days)
@@ -339,8 +735,11 @@ func (h *AnalyticsHandler) GetEntitySentiment(c *fiber.Ctx) error {
 	h.logger.Infof("Returning entity sentiment for '%s': %d days", entity, len(timeline))
 
 	return c.JSON(fiber.Map{
-		"entity":   entity,
-		"timeline": timeline,
+		"entity":         entity,
+		"entity_id":      entityID,
+		"canonical_name": canonicalName,
+		"timeline":       timeline,
+		"data_as_of":     h.viewDataAsOf(c.UserContext(), analytics.ViewEntityMentions),
 		"meta": fiber.Map{
 			"days":  days,
 			"count": len(timeline),
@@ -348,16 +747,22 @@ func (h *AnalyticsHandler) GetEntitySentiment(c *fiber.Ctx) error {
 	})
 }
 
-// RefreshAnalytics refreshes all materialized views
-// POST /api/v1/analytics/refresh
+// RefreshAnalytics refreshes all materialized views now. Refreshes are serialized with the
+// scheduler and other instances through the refresh coordinator's lock.
+// POST /api/v1/analytics/refresh?concurrent=true
 func (h *AnalyticsHandler) RefreshAnalytics(c *fiber.Ctx) error {
 	concurrent := c.Query("concurrent", "true") != "false"
 
 	h.logger.Infof("Refreshing analytics views (concurrent=%v)", concurrent)
 
-	// Call database refresh function
-	query := `SELECT * FROM refresh_analytics_views($1)`
-	rows, err := h.db.Query(context.Background(), query, concurrent)
+	results, err := h.refresher.RefreshAll(c.UserContext(), concurrent)
+	if errors.Is(err, analytics.ErrRefreshInProgress) {
+		return c.Status(fiber.StatusConflict).JSON(models.ErrorResponse{
+			Error:   "refresh_in_progress",
+			Message: "Analytics views are already being refreshed, try again later",
+			Code:    fiber.StatusConflict,
+		})
+	}
 	if err != nil {
 		h.logger.Errorf("Failed to refresh analytics: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
@@ -366,40 +771,35 @@ func (h *AnalyticsHandler) RefreshAnalytics(c *fiber.Ctx) error {
 			Code:    fiber.StatusInternalServerError,
 		})
 	}
-	defer rows.Close()
-
-	// Collect refresh results
-	type RefreshResult struct {
-		ViewName      string `json:"view_name"`
-		RefreshTimeMs int    `json:"refresh_time_ms"`
-		RowsAffected  int64  `json:"rows_affected"`
-	}
 
-	results := make([]RefreshResult, 0)
 	totalTime := 0
 	totalRows := int64(0)
-
-	for rows.Next() {
-		var result RefreshResult
-		err := rows.Scan(&result.ViewName, &result.RefreshTimeMs, &result.RowsAffected)
-		if err != nil {
-			h.logger.Warnf("Failed to scan refresh result: %v", err)
-			continue
-		}
-
+	failed := 0
+	views := make([]string, 0, len(results))
+	for _, result := range results {
 		totalTime += result.RefreshTimeMs
 		totalRows += result.RowsAffected
-		results = append(results, result)
+		if result.Error != "" {
+			failed++
+		}
+		views = append(views, result.ViewName)
 	}
 
-	h.logger.Infof("Analytics refresh completed: %d views, %d total rows, %dms",
-		len(results), totalRows, totalTime)
+	h.logger.Infof("Analytics refresh completed: %d views, %d failed, %d total rows, %dms",
+		len(results), failed, totalRows, totalTime)
+
+	message := "Analytics refreshed successfully"
+	if failed > 0 {
+		message = fmt.Sprintf("Analytics refreshed with %d failed views", failed)
+	}
 
 	return c.JSON(fiber.Map{
-		"message": "Analytics refreshed successfully",
-		"results": results,
+		"message":    message,
+		"results":    results,
+		"data_as_of": h.viewDataAsOf(c.UserContext(), views...),
 		"summary": fiber.Map{
 			"total_views":     len(results),
+			"failed_views":    failed,
 			"total_rows":      totalRows,
 			"total_time_ms":   totalTime,
 			"concurrent_mode": concurrent,
@@ -407,6 +807,69 @@ func (h *AnalyticsHandler) RefreshAnalytics(c *fiber.Ctx) error {
 	})
 }
 
+// GetFreshness returns the refresh state of every materialized view: when it was last
+// refreshed, how long that took, its refresh interval and whether it is overdue
+// GET /api/v1/analytics/freshness
+func (h *AnalyticsHandler) GetFreshness(c *fiber.Ctx) error {
+	views, err := h.refresher.Status(c.UserContext())
+	if err != nil {
+		h.logger.Errorf("Failed to get view freshness: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to fetch view freshness",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	now := time.Now().UTC()
+	freshness := make([]fiber.Map, 0, len(views))
+	stale := 0
+	var oldest *time.Time
+	neverRefreshed := false
+	for _, v := range views {
+		interval := h.refresher.Interval(v.ViewName)
+		var ageSeconds *int64
+		isStale := v.LastRefreshedAt == nil
+		if v.LastRefreshedAt != nil {
+			age := int64(now.Sub(*v.LastRefreshedAt).Seconds())
+			ageSeconds = &age
+			// A view is stale once it missed a refresh; allow one scheduler tick of slack
+			isStale = now.Sub(*v.LastRefreshedAt) > interval+time.Minute
+		}
+		if isStale {
+			stale++
+		}
+		switch {
+		case v.LastRefreshedAt == nil:
+			neverRefreshed = true
+		case oldest == nil || v.LastRefreshedAt.Before(*oldest):
+			oldest = v.LastRefreshedAt
+		}
+
+		freshness = append(freshness, fiber.Map{
+			"view":             v,
+			"interval_seconds": int64(interval.Seconds()),
+			"age_seconds":      ageSeconds,
+			"stale":            isStale,
+		})
+	}
+
+	// Data of all views together is as of the oldest refresh, unknown if one never ran
+	var dataAsOf *time.Time
+	if !neverRefreshed {
+		dataAsOf = oldest
+	}
+
+	return c.JSON(fiber.Map{
+		"views":      freshness,
+		"data_as_of": dataAsOf,
+		"meta": fiber.Map{
+			"count":       len(views),
+			"stale_count": stale,
+		},
+	})
+}
+
 // GetAnalyticsOverview returns a comprehensive analytics overview
 // GET /api/v1/analytics/overview
 func (h *AnalyticsHandler) GetAnalyticsOverview(c *fiber.Ctx) error {
@@ -469,6 +932,7 @@ func (h *AnalyticsHandler) GetAnalyticsOverview(c *fiber.Ctx) error {
 				&entity.Sources,
 				&entity.OverallSentiment,
 				&mostRecent,
+				&entity.EntityID,
 			)
 			if err == nil {
 				if mostRecent != nil {
@@ -508,6 +972,7 @@ func (h *AnalyticsHandler) GetAnalyticsOverview(c *fiber.Ctx) error {
 		"trending_keywords":  trending,
 		"hot_entities":       entities,
 		"materialized_views": mvStatus,
+		"data_as_of":         h.viewDataAsOf(ctx, analytics.ViewTrendingKeywords, analytics.ViewEntityMentions),
 		"meta": fiber.Map{
 			"trending_count": len(trending),
 			"entities_count": len(entities),
@@ -522,6 +987,7 @@ func (h *AnalyticsHandler) GetArticleStats(c *fiber.Ctx) error {
 	h.logger.Debug("Fetching article statistics")
 
 	query := `SELECT * FROM v_article_stats ORDER BY total_articles DESC`
+	dataAsOf := time.Now().UTC()
 	rows, err := h.db.Query(context.Background(), query)
 	if err != nil {
 		h.logger.Errorf("Failed to get article stats: %v", err)
@@ -581,7 +1047,8 @@ func (h *AnalyticsHandler) GetArticleStats(c *fiber.Ctx) error {
 	h.logger.Infof("Returning stats for %d sources", len(stats))
 
 	return c.JSON(fiber.Map{
-		"sources": stats,
+		"sources":    stats,
+		"data_as_of": dataAsOf,
 		"meta": fiber.Map{
 			"count": len(stats),
 		},
@@ -634,7 +1101,8 @@ func (h *AnalyticsHandler) GetMaintenanceSchedule(c *fiber.Ctx) error {
 	h.logger.Infof("Returning %d maintenance tasks", len(tasks))
 
 	return c.JSON(fiber.Map{
-		"tasks": tasks,
+		"tasks":      tasks,
+		"data_as_of": time.Now().UTC(),
 		"meta": fiber.Map{
 			"count": len(tasks),
 		},
@@ -702,5 +1170,6 @@ func (h *AnalyticsHandler) GetDatabaseHealth(c *fiber.Ctx) error {
 		"cache_hit_ratio":  cacheHitRatio,
 		"connection_count": connectionCount,
 		"status":           "healthy",
+		"data_as_of":       time.Now().UTC(),
 	})
 }
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeffrey/intellinieuws/internal/apikey"
+	"github.com/jeffrey/intellinieuws/internal/audit"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+	"github.com/jeffrey/intellinieuws/pkg/middleware"
+)
+
+// APIKeyHandler handles API key management requests
+type APIKeyHandler struct {
+	store   *apikey.Store
+	auditor Auditor
+	logger  *logger.Logger
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(store *apikey.Store, log *logger.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		store:  store,
+		logger: log.WithComponent("api-key-handler"),
+	}
+}
+
+// SetAuditor enables audit records for key issuance, rotation and revocation
+func (h *APIKeyHandler) SetAuditor(auditor Auditor) {
+	h.auditor = auditor
+}
+
+// ListKeys returns issued API keys (never the raw keys)
+// GET /api/v1/admin/api-keys?owner=partner-x&include_revoked=true
+func (h *APIKeyHandler) ListKeys(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	keys, err := h.store.List(c.UserContext(), c.Query("owner"), c.QueryBool("include_revoked", false))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list API keys")
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("DATABASE_ERROR", "Failed to list API keys", err.Error(), requestID),
+		)
+	}
+
+	return c.JSON(models.NewSuccessResponse(keys, requestID))
+}
+
+// GetKey returns a single API key
+// GET /api/v1/admin/api-keys/:id
+func (h *APIKeyHandler) GetKey(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_ID", "Invalid API key ID", err.Error(), requestID),
+		)
+	}
+
+	key, err := h.store.Get(c.UserContext(), id)
+	if err != nil {
+		return h.keyError(c, err, requestID)
+	}
+
+	return c.JSON(models.NewSuccessResponse(key, requestID))
+}
+
+// IssueKey creates a new API key; the raw key is only returned in this response
+// POST /api/v1/admin/api-keys
+func (h *APIKeyHandler) IssueKey(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	var req models.APIKeyCreate
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error(), requestID),
+		)
+	}
+
+	issued, err := h.store.Issue(c.UserContext(), req, actorName(c))
+	if err != nil {
+		return h.keyError(c, err, requestID)
+	}
+	recordAudit(h.auditor, c, audit.ActionAPIKeyIssue, issued.APIKey.Prefix, nil, issued.APIKey, nil)
+
+	return c.Status(fiber.StatusCreated).JSON(models.NewSuccessResponse(issued, requestID))
+}
+
+// RotateKey replaces a key with a new one; the old key keeps working during the grace period
+// POST /api/v1/admin/api-keys/:id/rotate
+func (h *APIKeyHandler) RotateKey(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_ID", "Invalid API key ID", err.Error(), requestID),
+		)
+	}
+
+	var req models.APIKeyRotateRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				models.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error(), requestID),
+			)
+		}
+	}
+
+	grace := apikey.DefaultRotationGrace
+	if req.GracePeriodHours != nil {
+		if *req.GracePeriodHours < 0 || *req.GracePeriodHours > 24*30 {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				models.NewErrorResponse("INVALID_REQUEST", "grace_period_hours must be between 0 and 720", "", requestID),
+			)
+		}
+		grace = time.Duration(*req.GracePeriodHours) * time.Hour
+	}
+
+	issued, err := h.store.Rotate(c.UserContext(), id, grace, actorName(c))
+	if err != nil {
+		return h.keyError(c, err, requestID)
+	}
+	recordAudit(h.auditor, c, audit.ActionAPIKeyRotate, strconv.FormatInt(id, 10),
+		fiber.Map{"key_id": id, "grace_period": grace.String()}, issued.APIKey, nil)
+
+	return c.JSON(models.NewSuccessResponse(issued, requestID))
+}
+
+// RevokeKey disables a key immediately
+// POST /api/v1/admin/api-keys/:id/revoke
+func (h *APIKeyHandler) RevokeKey(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_ID", "Invalid API key ID", err.Error(), requestID),
+		)
+	}
+
+	var req models.APIKeyRevokeRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				models.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error(), requestID),
+			)
+		}
+	}
+
+	key, err := h.store.Revoke(c.UserContext(), id, req.Reason)
+	if err != nil {
+		return h.keyError(c, err, requestID)
+	}
+	recordAudit(h.auditor, c, audit.ActionAPIKeyRevoke, key.Prefix, nil, key, nil)
+
+	return c.JSON(models.NewSuccessResponse(key, requestID))
+}
+
+// ListScopes returns all assignable scopes
+// GET /api/v1/admin/api-keys/scopes
+func (h *APIKeyHandler) ListScopes(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+	return c.JSON(models.NewSuccessResponse(middleware.ScopeDescriptions(), requestID))
+}
+
+// WhoAmI returns the principal behind the request's API key or bearer token
+// GET /api/v1/auth/whoami
+func (h *APIKeyHandler) WhoAmI(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	principal := middleware.PrincipalFromContext(c)
+	if principal == nil {
+		return c.JSON(models.NewSuccessResponse(fiber.Map{"authenticated": false}, requestID))
+	}
+
+	return c.JSON(models.NewSuccessResponse(fiber.Map{
+		"authenticated": true,
+		"key_id":        principal.KeyID,
+		"name":          principal.Name,
+		"owner":         principal.Owner,
+		"scopes":        principal.Scopes,
+		"daily_quota":   principal.DailyQuota,
+		"rate_tier":     principal.RateTier,
+		"legacy":        principal.Legacy,
+		"user_id":       principal.UserID,
+		"email":         principal.Email,
+		"roles":         principal.Roles,
+	}, requestID))
+}
+
+func (h *APIKeyHandler) keyError(c *fiber.Ctx, err error, requestID string) error {
+	switch {
+	case errors.Is(err, repository.ErrAPIKeyNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(
+			models.NewErrorResponse("NOT_FOUND", "API key not found", "", requestID),
+		)
+	case errors.Is(err, apikey.ErrInvalidScope), errors.Is(err, apikey.ErrInvalidKeyReq):
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "Invalid API key request", err.Error(), requestID),
+		)
+	default:
+		h.logger.WithError(err).Error("API key operation failed")
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("DATABASE_ERROR", "API key operation failed", err.Error(), requestID),
+		)
+	}
+}
+
+// actorName identifies the caller for created_by columns
+func actorName(c *fiber.Ctx) string {
+	if p := middleware.PrincipalFromContext(c); p != nil {
+		return p.Name
+	}
+	return ""
+}
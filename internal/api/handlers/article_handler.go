@@ -9,9 +9,12 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/jeffrey/intellinieuws/internal/cache"
+	"github.com/jeffrey/intellinieuws/internal/language"
 	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/pagination"
 	"github.com/jeffrey/intellinieuws/internal/repository"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
+	"github.com/jeffrey/intellinieuws/pkg/middleware"
 )
 
 // ArticleHandler handles article-related HTTP requests
@@ -21,7 +24,15 @@ type ArticleHandler struct {
 	scraperService interface {
 		EnrichArticleContent(ctx context.Context, articleID int64) error
 	}
-	logger *logger.Logger
+	translator      ArticleTranslator
+	openTranslation bool // Any caller may trigger new translations (authentication disabled)
+	logger          *logger.Logger
+}
+
+// ArticleTranslator translates article titles and summaries for ?lang= requests
+type ArticleTranslator interface {
+	TranslateArticles(ctx context.Context, articles []models.Article, targetLang string) error
+	ApplyCachedTranslations(ctx context.Context, articles []models.Article, targetLang string) error
 }
 
 // NewArticleHandler creates a new article handler
@@ -40,6 +51,124 @@ func (h *ArticleHandler) SetScraperService(scraperService interface {
 	h.scraperService = scraperService
 }
 
+// SetTranslator enables ?lang= translation on article endpoints
+func (h *ArticleHandler) SetTranslator(translator ArticleTranslator) {
+	h.translator = translator
+}
+
+// SetOpenTranslation lets callers without the ai:translate scope trigger new translations.
+// Only meant for deployments without authentication.
+func (h *ArticleHandler) SetOpenTranslation(open bool) {
+	h.openTranslation = open
+}
+
+// parseTargetLanguage validates the optional ?lang= parameter.
+// Returns false after writing an error response.
+func (h *ArticleHandler) parseTargetLanguage(c *fiber.Ctx, requestID string) (string, bool) {
+	raw := c.Query("lang")
+	if raw == "" {
+		return "", true
+	}
+
+	lang := language.Normalize(raw)
+	if lang == "" {
+		c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_LANGUAGE", "Unsupported language",
+				fmt.Sprintf("lang must be one of: %s", strings.Join(language.Supported(), ", ")), requestID),
+		)
+		return "", false
+	}
+	if h.translator == nil {
+		c.Status(fiber.StatusServiceUnavailable).JSON(
+			models.NewErrorResponse("SERVICE_UNAVAILABLE", "Translation service not available", "", requestID),
+		)
+		return "", false
+	}
+
+	return lang, true
+}
+
+// parseLanguageFilter validates the optional ?language= filter.
+// Returns false after writing an error response.
+func parseLanguageFilter(c *fiber.Ctx, requestID string) (string, bool) {
+	raw := c.Query("language")
+	if raw == "" {
+		return "", true
+	}
+	lang := language.Normalize(raw)
+	if lang == "" {
+		c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_LANGUAGE", "Unsupported language",
+				fmt.Sprintf("language must be one of: %s", strings.Join(language.Supported(), ", ")), requestID),
+		)
+		return "", false
+	}
+	return lang, true
+}
+
+// parseCursorParams applies ?cursor= and ?include_total= to the filter. The cursor must have
+// been issued for the same sort; the total count is skipped by default once a cursor is followed.
+// On failure the error response has already been written.
+func parseCursorParams(c *fiber.Ctx, requestID string, filter *models.ArticleFilter) bool {
+	if token := c.Query("cursor"); token != "" {
+		cursor, err := pagination.Decode(token, filter.SortBy, filter.SortOrder)
+		if err != nil {
+			c.Status(fiber.StatusBadRequest).JSON(
+				models.NewErrorResponse("INVALID_CURSOR", "Invalid pagination cursor", err.Error(), requestID),
+			)
+			return false
+		}
+		filter.Cursor = cursor
+	}
+	filter.SkipCount = !c.QueryBool("include_total", filter.Cursor == nil)
+	return true
+}
+
+// pageCacheKey identifies the page position of a filter in cache keys
+func pageCacheKey(c *fiber.Ctx, filter models.ArticleFilter) string {
+	return fmt.Sprintf("limit:%d:offset:%d:cursor:%s:total:%t",
+		filter.Limit, filter.Offset, c.Query("cursor"), !filter.SkipCount)
+}
+
+// validSortFields are the article columns clients may sort by
+var validSortFields = map[string]bool{"published": true, "created_at": true, "title": true}
+
+// normalizeSort validates sort_by and sort_order, falling back to published desc
+func normalizeSort(filter *models.ArticleFilter) {
+	if !validSortFields[filter.SortBy] {
+		filter.SortBy = "published"
+	}
+	filter.SortOrder = strings.ToLower(filter.SortOrder)
+	if filter.SortOrder != "asc" && filter.SortOrder != "desc" {
+		filter.SortOrder = "desc"
+	}
+}
+
+// translate attaches translations into lang; failures are logged and the
+// articles are returned untranslated. The slice is copied so cached data is not modified.
+// New translations cost LLM calls, so callers without the ai:translate scope only get
+// translations that are already cached.
+func (h *ArticleHandler) translate(c *fiber.Ctx, articles []models.Article, lang string) []models.Article {
+	if lang == "" || len(articles) == 0 {
+		return articles
+	}
+
+	translated := make([]models.Article, len(articles))
+	copy(translated, articles)
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 60*time.Second)
+	defer cancel()
+
+	apply := h.translator.ApplyCachedTranslations
+	if p := middleware.PrincipalFromContext(c); h.openTranslation || (p != nil && p.HasScope(middleware.ScopeAITranslate)) {
+		apply = h.translator.TranslateArticles
+	}
+	if err := apply(ctx, translated, lang); err != nil {
+		h.logger.WithError(err).Warnf("Failed to translate articles into %s", lang)
+	}
+	return translated
+}
+
 // GetArticle handles GET /api/v1/articles/:id
 func (h *ArticleHandler) GetArticle(c *fiber.Ctx) error {
 	requestID := c.Locals("requestid").(string)
@@ -52,19 +181,24 @@ func (h *ArticleHandler) GetArticle(c *fiber.Ctx) error {
 		)
 	}
 
+	lang, ok := h.parseTargetLanguage(c, requestID)
+	if !ok {
+		return nil
+	}
+
 	// Try cache first
 	cacheKey := cache.GenerateKey(cache.PrefixArticle, c.Params("id"))
 	var article models.Article
 
 	if h.cache != nil {
-		if err := h.cache.Get(c.Context(), cacheKey, &article); err == nil {
+		if err := h.cache.Get(c.UserContext(), cacheKey, &article); err == nil {
 			h.logger.Debug("Cache hit for article")
-			return c.JSON(models.NewSuccessResponse(article, requestID))
+			return c.JSON(models.NewSuccessResponse(h.translate(c, []models.Article{article}, lang)[0], requestID))
 		}
 	}
 
 	// Cache miss - get from database
-	articlePtr, err := h.repo.GetByID(c.Context(), id)
+	articlePtr, err := h.repo.GetByID(c.UserContext(), id)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to get article: %d", id)
 		return c.Status(fiber.StatusNotFound).JSON(
@@ -74,12 +208,12 @@ func (h *ArticleHandler) GetArticle(c *fiber.Ctx) error {
 
 	// Store in cache
 	if h.cache != nil {
-		if err := h.cache.Set(c.Context(), cacheKey, articlePtr); err != nil {
+		if err := h.cache.Set(c.UserContext(), cacheKey, articlePtr); err != nil {
 			h.logger.WithError(err).Warn("Failed to cache article")
 		}
 	}
 
-	return c.JSON(models.NewSuccessResponse(*articlePtr, requestID))
+	return c.JSON(models.NewSuccessResponse(h.translate(c, []models.Article{*articlePtr}, lang)[0], requestID))
 }
 
 // ListArticles handles GET /api/v1/articles
@@ -97,6 +231,15 @@ func (h *ArticleHandler) ListArticles(c *fiber.Ctx) error {
 		Offset:    c.QueryInt("offset", 0),
 	}
 
+	var ok bool
+	if filter.Language, ok = parseLanguageFilter(c, requestID); !ok {
+		return nil
+	}
+	lang, ok := h.parseTargetLanguage(c, requestID)
+	if !ok {
+		return nil
+	}
+
 	// Validate limit
 	if filter.Limit > 100 {
 		filter.Limit = 100
@@ -106,13 +249,9 @@ func (h *ArticleHandler) ListArticles(c *fiber.Ctx) error {
 	}
 
 	// Validate sort parameters
-	validSortFields := map[string]bool{"published": true, "created_at": true, "title": true}
-	if !validSortFields[filter.SortBy] {
-		filter.SortBy = "published"
-	}
-	filter.SortOrder = strings.ToLower(filter.SortOrder)
-	if filter.SortOrder != "asc" && filter.SortOrder != "desc" {
-		filter.SortOrder = "desc"
+	normalizeSort(&filter)
+	if !parseCursorParams(c, requestID, &filter) {
+		return nil
 	}
 
 	// Parse date filters
@@ -142,26 +281,20 @@ func (h *ArticleHandler) ListArticles(c *fiber.Ctx) error {
 		filter.Source,
 		filter.Category,
 		filter.Keyword,
+		filter.Language,
 		filter.SortBy,
 		filter.SortOrder,
-		fmt.Sprintf("limit:%d:offset:%d", filter.Limit, filter.Offset),
+		pageCacheKey(c, filter),
 	)
 
 	// Try cache first (only for simple queries without date filters)
 	if h.cache != nil && filter.StartDate == nil && filter.EndDate == nil {
-		var cachedArticles []models.Article
-		var cachedTotal int
-		cacheData := struct {
-			Articles []models.Article
-			Total    int
-		}{}
-		if err := h.cache.Get(c.Context(), cacheKey, &cacheData); err == nil {
+		var cached models.ArticlePage
+		if err := h.cache.Get(c.UserContext(), cacheKey, &cached); err == nil {
 			h.logger.Debug("Cache hit for articles list")
-			cachedArticles = cacheData.Articles
-			cachedTotal = cacheData.Total
 
 			meta := &models.Meta{
-				Pagination: models.CalculatePaginationMeta(cachedTotal, filter.Limit, filter.Offset),
+				Pagination: models.CalculateCursorPaginationMeta(cached.Total, filter.Limit, filter.Offset, cached.Page),
 				Sorting: &models.SortingMeta{
 					SortBy:    filter.SortBy,
 					SortOrder: filter.SortOrder,
@@ -169,12 +302,12 @@ func (h *ArticleHandler) ListArticles(c *fiber.Ctx) error {
 				Filtering: buildFilteringMeta(filter, startDateStr, endDateStr),
 			}
 
-			return c.JSON(models.NewSuccessResponseWithMeta(cachedArticles, meta, requestID))
+			return c.JSON(models.NewSuccessResponseWithMeta(h.translate(c, cached.Articles, lang), meta, requestID))
 		}
 	}
 
 	// Cache miss - get from database using lightweight method (v3.0 optimization)
-	articles, total, err := h.repo.ListLight(c.Context(), filter)
+	page, err := h.repo.ListLight(c.UserContext(), filter)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list articles")
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -184,20 +317,13 @@ func (h *ArticleHandler) ListArticles(c *fiber.Ctx) error {
 
 	// Store in cache (only for simple queries)
 	if h.cache != nil && filter.StartDate == nil && filter.EndDate == nil {
-		cacheData := struct {
-			Articles []models.Article
-			Total    int
-		}{
-			Articles: articles,
-			Total:    total,
-		}
-		if err := h.cache.Set(c.Context(), cacheKey, cacheData); err != nil {
+		if err := h.cache.Set(c.UserContext(), cacheKey, page); err != nil {
 			h.logger.WithError(err).Warn("Failed to cache articles list")
 		}
 	}
 
 	meta := &models.Meta{
-		Pagination: models.CalculatePaginationMeta(total, filter.Limit, filter.Offset),
+		Pagination: models.CalculateCursorPaginationMeta(page.Total, filter.Limit, filter.Offset, page.Page),
 		Sorting: &models.SortingMeta{
 			SortBy:    filter.SortBy,
 			SortOrder: filter.SortOrder,
@@ -205,7 +331,7 @@ func (h *ArticleHandler) ListArticles(c *fiber.Ctx) error {
 		Filtering: buildFilteringMeta(filter, startDateStr, endDateStr),
 	}
 
-	return c.JSON(models.NewSuccessResponseWithMeta(articles, meta, requestID))
+	return c.JSON(models.NewSuccessResponseWithMeta(h.translate(c, page.Articles, lang), meta, requestID))
 }
 
 // GetStats handles GET /api/v1/articles/stats
@@ -216,14 +342,14 @@ func (h *ArticleHandler) GetStats(c *fiber.Ctx) error {
 	// Try cache first
 	var cachedStats models.StatsResponse
 	if h.cache != nil {
-		if err := h.cache.Get(c.Context(), cacheKey, &cachedStats); err == nil {
+		if err := h.cache.Get(c.UserContext(), cacheKey, &cachedStats); err == nil {
 			h.logger.Debug("Cache hit for stats")
 			return c.JSON(models.NewSuccessResponse(cachedStats, requestID))
 		}
 	}
 
 	// Cache miss - get from database
-	stats, err := h.repo.GetComprehensiveStats(c.Context())
+	stats, err := h.repo.GetComprehensiveStats(c.UserContext())
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get stats")
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -233,7 +359,7 @@ func (h *ArticleHandler) GetStats(c *fiber.Ctx) error {
 
 	// Store in cache
 	if h.cache != nil {
-		if err := h.cache.Set(c.Context(), cacheKey, stats); err != nil {
+		if err := h.cache.Set(c.UserContext(), cacheKey, stats); err != nil {
 			h.logger.WithError(err).Warn("Failed to cache stats")
 		}
 	}
@@ -262,6 +388,15 @@ func (h *ArticleHandler) SearchArticles(c *fiber.Ctx) error {
 		Offset:    c.QueryInt("offset", 0),
 	}
 
+	var ok bool
+	if filter.Language, ok = parseLanguageFilter(c, requestID); !ok {
+		return nil
+	}
+	lang, ok := h.parseTargetLanguage(c, requestID)
+	if !ok {
+		return nil
+	}
+
 	// Validate limit
 	if filter.Limit > 100 {
 		filter.Limit = 100
@@ -270,24 +405,30 @@ func (h *ArticleHandler) SearchArticles(c *fiber.Ctx) error {
 		filter.Limit = 50
 	}
 
+	// Validate sort parameters (sort_by is interpolated into SQL)
+	normalizeSort(&filter)
+	if !parseCursorParams(c, requestID, &filter) {
+		return nil
+	}
+
 	// Generate cache key for search
 	cacheKey := cache.GenerateKey(cache.PrefixArticles, "search",
 		searchQuery,
 		filter.Source,
 		filter.Category,
-		fmt.Sprintf("limit:%d:offset:%d", filter.Limit, filter.Offset),
+		filter.Language,
+		filter.SortBy,
+		filter.SortOrder,
+		pageCacheKey(c, filter),
 	)
 
 	// Try cache first (1 minute TTL for search results)
 	if h.cache != nil {
-		var cachedData struct {
-			Articles []models.Article
-			Total    int
-		}
-		if err := h.cache.Get(c.Context(), cacheKey, &cachedData); err == nil {
+		var cached models.ArticlePage
+		if err := h.cache.Get(c.UserContext(), cacheKey, &cached); err == nil {
 			h.logger.Debug("Cache hit for search results")
 			meta := &models.Meta{
-				Pagination: models.CalculatePaginationMeta(cachedData.Total, filter.Limit, filter.Offset),
+				Pagination: models.CalculateCursorPaginationMeta(cached.Total, filter.Limit, filter.Offset, cached.Page),
 				Sorting: &models.SortingMeta{
 					SortBy:    filter.SortBy,
 					SortOrder: filter.SortOrder,
@@ -296,14 +437,15 @@ func (h *ArticleHandler) SearchArticles(c *fiber.Ctx) error {
 					Search:   searchQuery,
 					Source:   filter.Source,
 					Category: filter.Category,
+					Language: filter.Language,
 				},
 			}
-			return c.JSON(models.NewSuccessResponseWithMeta(cachedData.Articles, meta, requestID))
+			return c.JSON(models.NewSuccessResponseWithMeta(h.translate(c, cached.Articles, lang), meta, requestID))
 		}
 	}
 
 	// Cache miss - search articles using lightweight method (v3.0 optimization)
-	articles, total, err := h.repo.SearchLight(c.Context(), filter)
+	page, err := h.repo.SearchLight(c.UserContext(), filter)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to search articles")
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -313,21 +455,14 @@ func (h *ArticleHandler) SearchArticles(c *fiber.Ctx) error {
 
 	// Store in cache (1 minute TTL for search)
 	if h.cache != nil {
-		cacheData := struct {
-			Articles []models.Article
-			Total    int
-		}{
-			Articles: articles,
-			Total:    total,
-		}
 		// Use SetWithTTL for shorter cache duration on searches
-		if err := h.cache.SetWithTTL(c.Context(), cacheKey, cacheData, 1*time.Minute); err != nil {
+		if err := h.cache.SetWithTTL(c.UserContext(), cacheKey, page, 1*time.Minute); err != nil {
 			h.logger.WithError(err).Warn("Failed to cache search results")
 		}
 	}
 
 	meta := &models.Meta{
-		Pagination: models.CalculatePaginationMeta(total, filter.Limit, filter.Offset),
+		Pagination: models.CalculateCursorPaginationMeta(page.Total, filter.Limit, filter.Offset, page.Page),
 		Sorting: &models.SortingMeta{
 			SortBy:    filter.SortBy,
 			SortOrder: filter.SortOrder,
@@ -336,10 +471,11 @@ func (h *ArticleHandler) SearchArticles(c *fiber.Ctx) error {
 			Search:   searchQuery,
 			Source:   filter.Source,
 			Category: filter.Category,
+			Language: filter.Language,
 		},
 	}
 
-	return c.JSON(models.NewSuccessResponseWithMeta(articles, meta, requestID))
+	return c.JSON(models.NewSuccessResponseWithMeta(h.translate(c, page.Articles, lang), meta, requestID))
 }
 
 // GetCategories handles GET /api/v1/categories
@@ -350,14 +486,14 @@ func (h *ArticleHandler) GetCategories(c *fiber.Ctx) error {
 	// Try cache first
 	var cachedCategories []models.CategoryInfo
 	if h.cache != nil {
-		if err := h.cache.Get(c.Context(), cacheKey, &cachedCategories); err == nil {
+		if err := h.cache.Get(c.UserContext(), cacheKey, &cachedCategories); err == nil {
 			h.logger.Debug("Cache hit for categories")
 			return c.JSON(models.NewSuccessResponse(cachedCategories, requestID))
 		}
 	}
 
 	// Cache miss - get from database
-	categories, err := h.repo.GetCategories(c.Context())
+	categories, err := h.repo.GetCategories(c.UserContext())
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get categories")
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -367,7 +503,7 @@ func (h *ArticleHandler) GetCategories(c *fiber.Ctx) error {
 
 	// Store in cache
 	if h.cache != nil {
-		if err := h.cache.Set(c.Context(), cacheKey, categories); err != nil {
+		if err := h.cache.Set(c.UserContext(), cacheKey, categories); err != nil {
 			h.logger.WithError(err).Warn("Failed to cache categories")
 		}
 	}
@@ -375,6 +511,69 @@ func (h *ArticleHandler) GetCategories(c *fiber.Ctx) error {
 	return c.JSON(models.NewSuccessResponse(categories, requestID))
 }
 
+// GetLanguages handles GET /api/v1/articles/languages
+func (h *ArticleHandler) GetLanguages(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	stats, err := h.repo.GetLanguageStats(c.UserContext())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get language stats")
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("DATABASE_ERROR", "Failed to retrieve languages", err.Error(), requestID),
+		)
+	}
+
+	undetected := stats[""]
+	delete(stats, "")
+
+	return c.JSON(models.NewSuccessResponse(fiber.Map{
+		"articles_by_language": stats,
+		"undetected":           undetected,
+		"supported":            language.Supported(),
+		"translation":          h.translator != nil,
+	}, requestID))
+}
+
+// BackfillLanguages handles POST /api/v1/articles/languages/backfill
+// Detects the language of articles ingested before language detection existed.
+func (h *ArticleHandler) BackfillLanguages(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	batchSize := c.QueryInt("batch_size", 1000)
+	if batchSize < 1 || batchSize > 10000 {
+		batchSize = 1000
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	total := 0
+	for {
+		updated, err := h.repo.DetectMissingLanguages(ctx, batchSize)
+		if err != nil {
+			h.logger.WithError(err).Error("Language backfill failed")
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				models.NewErrorResponse("BACKFILL_FAILED", "Language backfill failed", err.Error(), requestID),
+			)
+		}
+		total += updated
+		if updated < batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		h.InvalidateCache(ctx)
+	}
+	h.logger.Infof("Language backfill detected %d articles in %v", total, time.Since(start))
+
+	return c.JSON(models.NewSuccessResponse(fiber.Map{
+		"updated":     total,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}, requestID))
+}
+
 // buildFilteringMeta creates filtering metadata for response
 func buildFilteringMeta(filter models.ArticleFilter, startDate, endDate string) *models.FilteringMeta {
 	meta := &models.FilteringMeta{
@@ -382,6 +581,7 @@ func buildFilteringMeta(filter models.ArticleFilter, startDate, endDate string)
 		Category:  filter.Category,
 		Keyword:   filter.Keyword,
 		Search:    filter.Search,
+		Language:  filter.Language,
 		StartDate: startDate,
 		EndDate:   endDate,
 	}
@@ -431,7 +631,7 @@ func (h *ArticleHandler) ExtractContent(c *fiber.Ctx) error {
 	h.logger.Infof("Extracting content for article %d", id)
 
 	// Extract content
-	ctx, cancel := context.WithTimeout(c.Context(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
 	defer cancel()
 
 	if err := h.scraperService.EnrichArticleContent(ctx, id); err != nil {
@@ -442,7 +642,7 @@ func (h *ArticleHandler) ExtractContent(c *fiber.Ctx) error {
 	}
 
 	// Get updated article
-	article, err := h.repo.GetArticleWithContent(c.Context(), id)
+	article, err := h.repo.GetArticleWithContent(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(
 			models.NewErrorResponse("DATABASE_ERROR", "Failed to retrieve article after extraction", err.Error(), requestID),
@@ -452,7 +652,7 @@ func (h *ArticleHandler) ExtractContent(c *fiber.Ctx) error {
 	// Invalidate cache
 	cacheKey := cache.GenerateKey(cache.PrefixArticle, c.Params("id"))
 	if h.cache != nil {
-		h.cache.Delete(c.Context(), cacheKey)
+		h.cache.Delete(c.UserContext(), cacheKey)
 	}
 
 	response := fiber.Map{
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeffrey/intellinieuws/internal/audit"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// Auditor records administrative operations
+type Auditor interface {
+	Record(c *fiber.Ctx, action, resource string, before, after interface{}, opErr error)
+}
+
+// recordAudit is a no-op when no auditor is configured
+func recordAudit(a Auditor, c *fiber.Ctx, action, resource string, before, after interface{}, opErr error) {
+	if a != nil {
+		a.Record(c, action, resource, before, after, opErr)
+	}
+}
+
+// AuditHandler serves the audit log
+type AuditHandler struct {
+	recorder *audit.Recorder
+	logger   *logger.Logger
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(recorder *audit.Recorder, log *logger.Logger) *AuditHandler {
+	return &AuditHandler{
+		recorder: recorder,
+		logger:   log.WithComponent("audit-handler"),
+	}
+}
+
+// ListEntries returns audit entries, newest first
+// GET /api/v1/admin/audit?actor=&actor_type=&action=config.*&resource=&request_id=&status=&since=&until=&limit=50&offset=0
+func (h *AuditHandler) ListEntries(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	filter := models.AuditFilter{
+		Actor:     c.Query("actor"),
+		ActorType: c.Query("actor_type"),
+		Action:    c.Query("action"),
+		Resource:  c.Query("resource"),
+		RequestID: c.Query("request_id"),
+		Status:    c.Query("status"),
+		Limit:     c.QueryInt("limit", 50),
+		Offset:    c.QueryInt("offset", 0),
+	}
+	if filter.Limit < 1 || filter.Limit > 500 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	for param, target := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				models.NewErrorResponse("INVALID_PARAMETER", "Invalid "+param+" timestamp", "Use RFC3339, e.g. 2025-01-02T15:04:05Z", requestID),
+			)
+		}
+		*target = &t
+	}
+
+	entries, total, err := h.recorder.List(c.UserContext(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list audit entries")
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("DATABASE_ERROR", "Failed to list audit entries", err.Error(), requestID),
+		)
+	}
+
+	meta := &models.Meta{
+		Pagination: models.CalculatePaginationMeta(total, filter.Limit, filter.Offset),
+	}
+
+	return c.JSON(models.NewSuccessResponseWithMeta(entries, meta, requestID))
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/jeffrey/intellinieuws/internal/audit"
 	"github.com/jeffrey/intellinieuws/internal/cache"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 )
@@ -11,6 +12,7 @@ type CacheHandler struct {
 	cacheService         *cache.Service
 	advancedCacheService *cache.AdvancedService
 	invalidationService  *cache.InvalidationService
+	auditor              Auditor
 	log                  *logger.Logger
 }
 
@@ -29,6 +31,11 @@ func NewCacheHandler(
 	}
 }
 
+// SetAuditor enables audit records for cache invalidation
+func (h *CacheHandler) SetAuditor(auditor Auditor) {
+	h.auditor = auditor
+}
+
 // GetStatistics returns cache statistics
 func (h *CacheHandler) GetStatistics(c *fiber.Ctx) error {
 	if h.advancedCacheService == nil {
@@ -37,7 +44,7 @@ func (h *CacheHandler) GetStatistics(c *fiber.Ctx) error {
 		})
 	}
 
-	stats, err := h.advancedCacheService.GetCacheStatistics(c.Context())
+	stats, err := h.advancedCacheService.GetCacheStatistics(c.UserContext())
 	if err != nil {
 		h.log.WithError(err).Error("Failed to get cache statistics")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -78,18 +85,24 @@ func (h *CacheHandler) InvalidateCache(c *fiber.Ctx) error {
 	}
 
 	var err error
-	ctx := c.Context()
+	var target string
+	ctx := c.UserContext()
 
 	switch {
 	case req.InvalidateAll:
+		target = "all"
 		err = h.invalidationService.InvalidateAll(ctx)
 	case req.ArticleID != "":
+		target = "article:" + req.ArticleID
 		err = h.invalidationService.InvalidateArticle(ctx, req.ArticleID)
 	case req.Source != "":
+		target = "source:" + req.Source
 		err = h.invalidationService.InvalidateBySource(ctx, req.Source)
 	case req.StockSymbol != "":
+		target = "stock:" + req.StockSymbol
 		err = h.invalidationService.InvalidateStockData(ctx, req.StockSymbol)
 	case req.Pattern != "":
+		target = "pattern:" + req.Pattern
 		keys, _ := h.invalidationService.GetCacheKeys(ctx, req.Pattern)
 		if len(keys) > 0 {
 			err = h.invalidationService.DeleteMultiple(ctx, keys)
@@ -99,6 +112,7 @@ func (h *CacheHandler) InvalidateCache(c *fiber.Ctx) error {
 			"error": "Must provide pattern, article_id, source, stock_symbol, or invalidate_all",
 		})
 	}
+	recordAudit(h.auditor, c, audit.ActionCacheInvalidate, target, nil, req, err)
 
 	if err != nil {
 		h.log.WithError(err).Error("Failed to invalidate cache")
@@ -123,7 +137,7 @@ func (h *CacheHandler) GetCacheKeys(c *fiber.Ctx) error {
 
 	pattern := c.Query("pattern", "*")
 
-	keys, err := h.invalidationService.GetCacheKeys(c.Context(), pattern)
+	keys, err := h.invalidationService.GetCacheKeys(c.UserContext(), pattern)
 	if err != nil {
 		h.log.WithError(err).Error("Failed to get cache keys")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -146,7 +160,7 @@ func (h *CacheHandler) GetCacheSize(c *fiber.Ctx) error {
 		})
 	}
 
-	size, err := h.invalidationService.GetCacheSize(c.Context())
+	size, err := h.invalidationService.GetCacheSize(c.UserContext())
 	if err != nil {
 		h.log.WithError(err).Error("Failed to get cache size")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -168,7 +182,7 @@ func (h *CacheHandler) GetMemoryUsage(c *fiber.Ctx) error {
 		})
 	}
 
-	memInfo, err := h.invalidationService.GetCacheMemoryUsage(c.Context())
+	memInfo, err := h.invalidationService.GetCacheMemoryUsage(c.UserContext())
 	if err != nil {
 		h.log.WithError(err).Error("Failed to get memory usage")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -207,7 +221,7 @@ func (h *CacheHandler) WarmCache(c *fiber.Ctx) error {
 		})
 	}
 
-	err := h.advancedCacheService.WarmCache(c.Context(), req.Data)
+	err := h.advancedCacheService.WarmCache(c.UserContext(), req.Data)
 	if err != nil {
 		h.log.WithError(err).Error("Failed to warm cache")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jeffrey/intellinieuws/internal/audit"
+	"github.com/jeffrey/intellinieuws/internal/leader"
 	"github.com/jeffrey/intellinieuws/internal/models"
 	"github.com/jeffrey/intellinieuws/pkg/config"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
@@ -18,6 +20,8 @@ type ConfigHandler struct {
 		UpdateInterval(interval time.Duration)
 		IsRunning() bool
 	}
+	elector        *leader.Elector
+	auditor        Auditor
 	logger         *logger.Logger
 	mu             sync.RWMutex
 	activeProfile  string
@@ -47,6 +51,33 @@ func (h *ConfigHandler) SetScheduler(scheduler interface {
 	h.scheduler = scheduler
 }
 
+// SetLeaderElector reports the instance running the scheduler in the scheduler status
+func (h *ConfigHandler) SetLeaderElector(elector *leader.Elector) {
+	h.elector = elector
+}
+
+// SetAuditor enables audit records for configuration changes
+func (h *ConfigHandler) SetAuditor(auditor Auditor) {
+	h.auditor = auditor
+}
+
+// runtimeSettings returns the settings that can be changed with UpdateSetting
+func runtimeSettings(cfg *config.ScraperConfig) fiber.Map {
+	return fiber.Map{
+		"rate_limit_seconds":        cfg.RateLimitSeconds,
+		"max_concurrent":            cfg.MaxConcurrent,
+		"timeout_seconds":           cfg.TimeoutSeconds,
+		"schedule_interval_minutes": cfg.ScheduleIntervalMinutes,
+		"browser_pool_size":         cfg.BrowserPoolSize,
+		"browser_max_concurrent":    cfg.BrowserMaxConcurrent,
+		"content_batch_size":        cfg.ContentExtractionBatchSize,
+		"enable_browser_scraping":   cfg.EnableBrowserScraping,
+		"enable_full_content":       cfg.EnableFullContentExtraction,
+		"enable_robots_check":       cfg.EnableRobotsTxtCheck,
+		"browser_fallback_only":     cfg.BrowserFallbackOnly,
+	}
+}
+
 // initializeProfiles creates the predefined scraper profiles
 func (h *ConfigHandler) initializeProfiles() {
 	// Fast profile - Maximum throughput
@@ -227,6 +258,11 @@ func (h *ConfigHandler) SwitchProfile(c *fiber.Ctx) error {
 	h.config.Scraper = *newCfg
 
 	h.logger.Infof("Switched scraper profile from '%s' to '%s'", oldProfile, profileName)
+	recordAudit(h.auditor, c, audit.ActionConfigProfileSwitch, profileName,
+		fiber.Map{"profile": oldProfile},
+		fiber.Map{"profile": profileName, "settings": runtimeSettings(newCfg)},
+		nil,
+	)
 
 	response := fiber.Map{
 		"success":        true,
@@ -263,6 +299,7 @@ func (h *ConfigHandler) UpdateSetting(c *fiber.Ctx) error {
 	defer h.mu.Unlock()
 
 	cfg := h.scraperConfigs[h.activeProfile]
+	oldValue := runtimeSettings(cfg)[req.Setting]
 
 	// Update the specific setting
 	updated := false
@@ -361,12 +398,17 @@ func (h *ConfigHandler) UpdateSetting(c *fiber.Ctx) error {
 	h.config.Scraper = *cfg
 
 	h.logger.Infof("Updated setting '%s' to %v in profile '%s'", req.Setting, newValue, h.activeProfile)
+	recordAudit(h.auditor, c, audit.ActionConfigSettingUpdate, req.Setting,
+		fiber.Map{"profile": h.activeProfile, "value": oldValue},
+		fiber.Map{"profile": h.activeProfile, "value": newValue},
+		nil,
+	)
 
 	response := fiber.Map{
 		"success":   true,
 		"message":   fmt.Sprintf("Setting '%s' updated successfully", req.Setting),
 		"setting":   req.Setting,
-		"old_value": req.Value,
+		"old_value": oldValue,
 		"new_value": newValue,
 		"profile":   h.activeProfile,
 	}
@@ -397,6 +439,15 @@ func (h *ConfigHandler) GetSchedulerStatus(c *fiber.Ctx) error {
 		"enabled":          cfg.ScheduleEnabled,
 	}
 
+	// The scheduler only runs on the leader; followers report which instance that is
+	if h.elector != nil {
+		status, err := h.elector.Status(c.UserContext())
+		if err != nil {
+			h.logger.WithError(err).Warn("Failed to get leader status")
+		}
+		response["leader"] = status
+	}
+
 	return c.JSON(models.NewSuccessResponse(response, requestID))
 }
 
@@ -410,9 +461,11 @@ func (h *ConfigHandler) ResetToDefaults(c *fiber.Ctx) error {
 
 	// Reinitialize profiles to restore defaults
 	oldProfile := h.activeProfile
+	before := runtimeSettings(h.scraperConfigs[oldProfile])
 	h.initializeProfiles()
 
 	h.logger.Infof("Reset profile '%s' to default values", oldProfile)
+	recordAudit(h.auditor, c, audit.ActionConfigReset, oldProfile, before, runtimeSettings(h.scraperConfigs[oldProfile]), nil)
 
 	response := fiber.Map{
 		"success": true,
@@ -440,6 +493,7 @@ func (h *ConfigHandler) RestartServer(c *fiber.Ctx) error {
 	}
 
 	h.logger.Warnf("Server restart requested via API (delay: %ds, request_id: %s)", delay, requestID)
+	recordAudit(h.auditor, c, audit.ActionServerRestart, "api", nil, fiber.Map{"delay_seconds": delay}, nil)
 
 	// Send immediate response before starting shutdown
 	response := fiber.Map{
@@ -24,7 +24,7 @@ func NewEmailHandler(emailProcessor *email.Processor, log *logger.Logger) *Email
 func (h *EmailHandler) FetchExistingEmails(c *fiber.Ctx) error {
 	h.logger.Info("Received request to fetch existing emails")
 
-	articlesCreated, err := h.emailProcessor.FetchExistingEmails(c.Context())
+	articlesCreated, err := h.emailProcessor.FetchExistingEmails(c.UserContext())
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to fetch existing emails")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -43,7 +43,7 @@ func (h *EmailHandler) FetchExistingEmails(c *fiber.Ctx) error {
 
 // GetStats handles GET /api/v1/email/stats
 func (h *EmailHandler) GetStats(c *fiber.Ctx) error {
-	stats, err := h.emailProcessor.GetStats(c.Context())
+	stats, err := h.emailProcessor.GetStats(c.UserContext())
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get email stats")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -0,0 +1,436 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jeffrey/intellinieuws/internal/analytics"
+	"github.com/jeffrey/intellinieuws/internal/cache"
+	"github.com/jeffrey/intellinieuws/internal/entity"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// EntityHandler handles canonical entity registry requests
+type EntityHandler struct {
+	resolver  *entity.Resolver
+	cache     *cache.Service
+	refresher *analytics.RefreshCoordinator
+	dumpPath  string
+	logger    *logger.Logger
+
+	backfillMu sync.Mutex
+	backfill   *backfillJob // Latest backfill on this instance
+}
+
+// Backfill job states
+const (
+	backfillRunning   = "running"
+	backfillCompleted = "completed"
+	backfillFailed    = "failed"
+)
+
+// backfillJob is an entity backfill running in the background
+type backfillJob struct {
+	ID         string                 `json:"id"`
+	Status     string                 `json:"status"` // running, completed, failed
+	AfterID    int64                  `json:"after_id"`
+	BatchSize  int                    `json:"batch_size"`
+	StartedAt  time.Time              `json:"started_at"`
+	FinishedAt *time.Time             `json:"finished_at,omitempty"`
+	Result     *entity.BackfillResult `json:"result,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// NewEntityHandler creates a new entity handler
+func NewEntityHandler(resolver *entity.Resolver, cacheService *cache.Service, refresher *analytics.RefreshCoordinator, dumpPath string, log *logger.Logger) *EntityHandler {
+	return &EntityHandler{
+		resolver:  resolver,
+		cache:     cacheService,
+		refresher: refresher,
+		dumpPath:  dumpPath,
+		logger:    log.WithComponent("entity-handler"),
+	}
+}
+
+// ListEntities returns canonical entities
+// GET /api/v1/entities?type=person&search=rutte&limit=50&offset=0
+func (h *EntityHandler) ListEntities(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	filter := models.EntityFilter{
+		EntityType: c.Query("type"),
+		Search:     c.Query("search"),
+		Limit:      c.QueryInt("limit", 50),
+		Offset:     c.QueryInt("offset", 0),
+	}
+	if filter.Limit < 1 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	entities, total, err := h.resolver.ListEntities(c.UserContext(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list entities")
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("DATABASE_ERROR", "Failed to list entities", err.Error(), requestID),
+		)
+	}
+
+	meta := &models.Meta{
+		Pagination: models.CalculatePaginationMeta(total, filter.Limit, filter.Offset),
+		Filtering: &models.FilteringMeta{
+			Category: filter.EntityType,
+			Search:   filter.Search,
+		},
+	}
+
+	return c.JSON(models.NewSuccessResponseWithMeta(entities, meta, requestID))
+}
+
+// GetEntity returns a single entity with its aliases
+// GET /api/v1/entities/:id
+func (h *EntityHandler) GetEntity(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_ID", "Invalid entity ID", err.Error(), requestID),
+		)
+	}
+
+	result, err := h.resolver.GetEntity(c.UserContext(), id)
+	if err != nil {
+		if entity.IsNotFound(err) {
+			return c.Status(fiber.StatusNotFound).JSON(
+				models.NewErrorResponse("NOT_FOUND", "Entity not found", "", requestID),
+			)
+		}
+		h.logger.WithError(err).Errorf("Failed to get entity %d", id)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("DATABASE_ERROR", "Failed to retrieve entity", err.Error(), requestID),
+		)
+	}
+
+	return c.JSON(models.NewSuccessResponse(result, requestID))
+}
+
+// ResolveEntity resolves a name to its canonical entity without creating one
+// GET /api/v1/entities/resolve?name=premier+Rutte&type=person
+func (h *EntityHandler) ResolveEntity(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	name := c.Query("name")
+	if name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("MISSING_PARAMETER", "name parameter is required", "", requestID),
+		)
+	}
+
+	id, ok := h.resolver.Lookup(c.UserContext(), name, c.Query("type"))
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(
+			models.NewErrorResponse("NOT_FOUND", "No canonical entity matches this name", name, requestID),
+		)
+	}
+
+	result, err := h.resolver.GetEntity(c.UserContext(), id)
+	if err != nil {
+		h.logger.WithError(err).Errorf("Failed to get resolved entity %d", id)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("DATABASE_ERROR", "Failed to retrieve entity", err.Error(), requestID),
+		)
+	}
+
+	return c.JSON(models.NewSuccessResponse(result, requestID))
+}
+
+// MergeEntities merges one or more entities into a target entity
+// POST /api/v1/entities/merge
+func (h *EntityHandler) MergeEntities(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	var req models.EntityMergeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error(), requestID),
+		)
+	}
+	if req.TargetID == 0 || len(req.SourceIDs) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "target_id and source_ids are required", "", requestID),
+		)
+	}
+
+	if err := h.resolver.Merge(c.UserContext(), req.TargetID, req.SourceIDs); err != nil {
+		status := fiber.StatusInternalServerError
+		if entity.IsNotFound(err) {
+			status = fiber.StatusNotFound
+		}
+		h.logger.WithError(err).Errorf("Failed to merge entities %v into %d", req.SourceIDs, req.TargetID)
+		return c.Status(status).JSON(
+			models.NewErrorResponse("MERGE_FAILED", "Failed to merge entities", err.Error(), requestID),
+		)
+	}
+
+	h.afterRegistryChange()
+
+	merged, err := h.resolver.GetEntity(c.UserContext(), req.TargetID)
+	if err != nil {
+		h.logger.WithError(err).Warnf("Merged entity %d could not be reloaded", req.TargetID)
+	}
+
+	return c.JSON(models.NewSuccessResponse(fiber.Map{
+		"message": "Entities merged successfully",
+		"entity":  merged,
+	}, requestID))
+}
+
+// ListMergeCandidates returns entity pairs the resolver queued for merge review
+// GET /api/v1/entities/merge-candidates?limit=50&offset=0
+func (h *EntityHandler) ListMergeCandidates(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	limit := c.QueryInt("limit", 50)
+	offset := c.QueryInt("offset", 0)
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	candidates, total, err := h.resolver.ListMergeCandidates(c.UserContext(), limit, offset)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list merge candidates")
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("DATABASE_ERROR", "Failed to list merge candidates", err.Error(), requestID),
+		)
+	}
+
+	meta := &models.Meta{
+		Pagination: models.CalculatePaginationMeta(total, limit, offset),
+	}
+
+	return c.JSON(models.NewSuccessResponseWithMeta(candidates, meta, requestID))
+}
+
+// DismissMergeCandidate removes a queued pair that refers to different entities
+// DELETE /api/v1/entities/merge-candidates/:id
+func (h *EntityHandler) DismissMergeCandidate(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_ID", "Invalid merge candidate ID", err.Error(), requestID),
+		)
+	}
+
+	if err := h.resolver.DismissMergeCandidate(c.UserContext(), id); err != nil {
+		if entity.IsNotFound(err) {
+			return c.Status(fiber.StatusNotFound).JSON(
+				models.NewErrorResponse("NOT_FOUND", "Merge candidate not found", "", requestID),
+			)
+		}
+		h.logger.WithError(err).Errorf("Failed to dismiss merge candidate %d", id)
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("DATABASE_ERROR", "Failed to dismiss merge candidate", err.Error(), requestID),
+		)
+	}
+
+	return c.JSON(models.NewSuccessResponse(fiber.Map{
+		"message": "Merge candidate dismissed",
+		"id":      id,
+	}, requestID))
+}
+
+// SplitEntity moves aliases (and mentions using them) from an entity to a new entity
+// POST /api/v1/entities/:id/split
+func (h *EntityHandler) SplitEntity(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_ID", "Invalid entity ID", err.Error(), requestID),
+		)
+	}
+
+	var req models.EntitySplitRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error(), requestID),
+		)
+	}
+	if len(req.Aliases) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "aliases are required", "", requestID),
+		)
+	}
+
+	newID, err := h.resolver.Split(c.UserContext(), id, req)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if entity.IsNotFound(err) {
+			status = fiber.StatusNotFound
+		}
+		h.logger.WithError(err).Errorf("Failed to split entity %d", id)
+		return c.Status(status).JSON(
+			models.NewErrorResponse("SPLIT_FAILED", "Failed to split entity", err.Error(), requestID),
+		)
+	}
+
+	h.afterRegistryChange()
+
+	created, err := h.resolver.GetEntity(c.UserContext(), newID)
+	if err != nil {
+		h.logger.WithError(err).Warnf("Split entity %d could not be reloaded", newID)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.NewSuccessResponse(fiber.Map{
+		"message":   "Entity split successfully",
+		"source_id": id,
+		"entity":    created,
+	}, requestID))
+}
+
+// ImportDump (re)loads the local entity dump file
+// POST /api/v1/entities/import
+func (h *EntityHandler) ImportDump(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	if h.dumpPath == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("NOT_CONFIGURED", "No entity dump configured (ENTITY_DUMP_PATH)", "", requestID),
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	result, err := h.resolver.ImportDump(ctx, h.dumpPath)
+	if err != nil {
+		h.logger.WithError(err).Error("Entity dump import failed")
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("IMPORT_FAILED", "Failed to import entity dump", err.Error(), requestID),
+		)
+	}
+
+	h.afterRegistryChange()
+
+	return c.JSON(models.NewSuccessResponse(result, requestID))
+}
+
+// Backfill starts resolving entities for articles processed before the registry existed.
+// The backfill runs in the background; poll the returned status URL for its outcome.
+// POST /api/v1/entities/backfill?after_id=0&batch_size=500
+func (h *EntityHandler) Backfill(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	job := &backfillJob{
+		ID:        uuid.NewString(),
+		Status:    backfillRunning,
+		AfterID:   int64(c.QueryInt("after_id", 0)),
+		BatchSize: c.QueryInt("batch_size", 500),
+		StartedAt: time.Now().UTC(),
+	}
+
+	h.backfillMu.Lock()
+	if h.backfill != nil && h.backfill.Status == backfillRunning {
+		running := *h.backfill
+		h.backfillMu.Unlock()
+		return c.Status(fiber.StatusConflict).JSON(
+			models.NewErrorResponse("BACKFILL_IN_PROGRESS", "An entity backfill is already running", "Running job: "+running.ID, requestID),
+		)
+	}
+	h.backfill = job
+	snapshot := *job
+	h.backfillMu.Unlock()
+
+	go h.runBackfill(job)
+
+	return c.Status(fiber.StatusAccepted).JSON(models.NewSuccessResponse(fiber.Map{
+		"message":    "Entity backfill started",
+		"job":        snapshot,
+		"status_url": "/api/v1/entities/backfill/" + job.ID,
+	}, requestID))
+}
+
+// GetBackfill returns the state of a backfill started on this instance
+// GET /api/v1/entities/backfill/:id
+func (h *EntityHandler) GetBackfill(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	h.backfillMu.Lock()
+	var job *backfillJob
+	if h.backfill != nil && h.backfill.ID == c.Params("id") {
+		snapshot := *h.backfill
+		job = &snapshot
+	}
+	h.backfillMu.Unlock()
+
+	if job == nil {
+		return c.Status(fiber.StatusNotFound).JSON(
+			models.NewErrorResponse("NOT_FOUND", "Backfill job not found", "Only the latest backfill of this instance is kept", requestID),
+		)
+	}
+
+	return c.JSON(models.NewSuccessResponse(job, requestID))
+}
+
+// runBackfill runs a backfill job to completion and records its outcome
+func (h *EntityHandler) runBackfill(job *backfillJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	result, err := h.resolver.Backfill(ctx, job.AfterID, job.BatchSize)
+	if err != nil {
+		h.logger.WithError(err).Error("Entity backfill failed")
+	}
+
+	finished := time.Now().UTC()
+	h.backfillMu.Lock()
+	job.FinishedAt = &finished
+	job.Result = result
+	job.Status = backfillCompleted
+	if err != nil {
+		job.Status = backfillFailed
+		job.Error = err.Error()
+	}
+	h.backfillMu.Unlock()
+
+	if err == nil {
+		h.afterRegistryChange()
+	}
+}
+
+// afterRegistryChange drops cached entity lookups and recomputes entity analytics in the background
+func (h *EntityHandler) afterRegistryChange() {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		if h.cache != nil {
+			if err := h.cache.DeletePattern(ctx, cache.PrefixAIEntity+"*"); err != nil {
+				h.logger.WithError(err).Warn("Failed to invalidate entity cache")
+			}
+		}
+
+		// A refresh already in progress may predate the change; the next scheduled one picks it up
+		_, err := h.refresher.RefreshViews(ctx, true, analytics.ViewEntityMentions)
+		if errors.Is(err, analytics.ErrRefreshInProgress) {
+			h.logger.Debug("Entity analytics refresh skipped, another refresh is in progress")
+		} else if err != nil {
+			h.logger.WithError(err).Warn("Failed to refresh entity analytics")
+		}
+	}()
+}
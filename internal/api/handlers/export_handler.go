@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeffrey/intellinieuws/internal/export"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+const (
+	exportFlushEvery    = 500              // Rows between flushes to the client
+	exportWriteDeadline = 60 * time.Second // Per flush; the server WriteTimeout would end long exports
+)
+
+// ExportHandler streams bulk article exports
+type ExportHandler struct {
+	repo   *repository.ArticleRepository
+	logger *logger.Logger
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(repo *repository.ArticleRepository, log *logger.Logger) *ExportHandler {
+	return &ExportHandler{
+		repo:   repo,
+		logger: log.WithComponent("export-handler"),
+	}
+}
+
+// ExportArticles streams articles with their AI columns in id order. Interrupted exports
+// resume with ?after_id= set to the last exported id.
+// GET /api/v1/export/articles
+func (h *ExportHandler) ExportArticles(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	format := strings.ToLower(c.Query("format", models.ExportFormatNDJSON))
+	if !export.IsValidFormat(format) {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_FORMAT", "Unsupported export format",
+				"format must be one of: "+strings.Join(models.ExportFormats, ", "), requestID),
+		)
+	}
+
+	q, ok := parseArticleQuery(c, requestID)
+	if !ok {
+		return nil
+	}
+
+	var afterID int64
+	if raw := c.Query("after_id"); raw != "" {
+		var err error
+		if afterID, err = strconv.ParseInt(raw, 10, 64); err != nil || afterID < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				models.NewErrorResponse("INVALID_PARAMETER", "after_id must be a non-negative article ID", "", requestID),
+			)
+		}
+	}
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_PARAMETER", "limit must not be negative", "", requestID),
+		)
+	}
+
+	filename := fmt.Sprintf("articles-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	c.Set(fiber.HeaderContentType, export.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+
+	conn := c.Context().Conn()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		start := time.Now()
+		writer, err := export.NewWriter(format, w)
+		if err != nil {
+			h.logger.WithError(err).Error("Failed to create export writer")
+			return
+		}
+		flush := func() error {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			if err := conn.SetWriteDeadline(time.Now().Add(exportWriteDeadline)); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+
+		var lastID int64
+		written := 0
+		count, err := h.repo.Export(context.Background(), q, afterID, limit, func(row *models.ExportRow) error {
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+			lastID = row.ID
+			if written++; written%exportFlushEvery == 0 {
+				return flush() // Fails once the client has gone away, which ends the export
+			}
+			return nil
+		})
+		if err != nil {
+			h.logger.WithError(err).Warnf("Export [%s] stopped after %d articles, resume with after_id=%d", requestID, count, lastID)
+			return
+		}
+		if err := writer.Close(); err != nil {
+			h.logger.WithError(err).Warnf("Failed to finish export [%s]", requestID)
+			return
+		}
+		if err := flush(); err != nil {
+			return
+		}
+		h.logger.Infof("Exported %d articles as %s in %s [%s]", count, format, time.Since(start).Round(time.Millisecond), requestID)
+	})
+
+	return nil
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeffrey/intellinieuws/internal/cache"
+	"github.com/jeffrey/intellinieuws/internal/feed"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+const (
+	feedDefaultLimit = 50
+	feedMaxLimit     = 100
+	feedCacheTTL     = 2 * time.Minute
+	feedMaxAge       = 300 // Cache-Control max-age in seconds
+)
+
+// FeedHandler publishes article queries as RSS 2.0, Atom and JSON Feed
+type FeedHandler struct {
+	repo   *repository.ArticleRepository
+	cache  *cache.Service
+	logger *logger.Logger
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(repo *repository.ArticleRepository, cacheService *cache.Service, log *logger.Logger) *FeedHandler {
+	return &FeedHandler{
+		repo:   repo,
+		cache:  cacheService,
+		logger: log.WithComponent("feed-handler"),
+	}
+}
+
+// ArticlesFeed returns a feed of articles matching the ListArticles/SearchArticles filters
+// GET /api/v1/feeds/articles.:format
+func (h *FeedHandler) ArticlesFeed(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	format, ok := parseFeedFormat(c, requestID, c.Params("format"))
+	if !ok {
+		return nil
+	}
+	q, ok := parseArticleQuery(c, requestID)
+	if !ok {
+		return nil
+	}
+	return h.serveFeed(c, requestID, format, q)
+}
+
+// EntityFeed returns a feed of articles mentioning an entity, e.g. /feeds/entity/ING.rss
+// GET /api/v1/feeds/entity/:name
+func (h *FeedHandler) EntityFeed(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	name, format, ok := parseFeedPath(c, requestID, "name")
+	if !ok {
+		return nil
+	}
+	q, ok := parseArticleQuery(c, requestID)
+	if !ok {
+		return nil
+	}
+	q.Entity = name
+	return h.serveFeed(c, requestID, format, q)
+}
+
+// TickerFeed returns a feed of articles mentioning a stock ticker, e.g. /feeds/ticker/INGA.atom
+// GET /api/v1/feeds/ticker/:symbol
+func (h *FeedHandler) TickerFeed(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	symbol, format, ok := parseFeedPath(c, requestID, "symbol")
+	if !ok {
+		return nil
+	}
+	q, ok := parseArticleQuery(c, requestID)
+	if !ok {
+		return nil
+	}
+	q.Ticker = strings.ToUpper(symbol)
+	return h.serveFeed(c, requestID, format, q)
+}
+
+// serveFeed loads, renders and sends the feed, answering conditional requests with 304
+func (h *FeedHandler) serveFeed(c *fiber.Ctx, requestID, format string, q models.SavedSearchQuery) error {
+	limit := c.QueryInt("limit", feedDefaultLimit)
+	if limit < 1 {
+		limit = feedDefaultLimit
+	}
+	if limit > feedMaxLimit {
+		limit = feedMaxLimit
+	}
+
+	items, err := h.loadItems(c, q, limit)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to load feed articles")
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("DATABASE_ERROR", "Failed to load feed", err.Error(), requestID),
+		)
+	}
+
+	var updated time.Time
+	for _, item := range items {
+		if item.ModifiedAt.After(updated) {
+			updated = item.ModifiedAt
+		}
+	}
+
+	// The ETag covers the articles and their enrichment, so a 304 skips rendering
+	hash := sha256.New()
+	hash.Write([]byte(format))
+	for _, item := range items {
+		hash.Write([]byte(strconv.FormatInt(item.ID, 10) + ":" + strconv.FormatInt(item.ModifiedAt.UnixNano(), 10) + ","))
+	}
+	c.Set(fiber.HeaderETag, `"`+hex.EncodeToString(hash.Sum(nil)[:16])+`"`)
+	if !updated.IsZero() {
+		c.Set(fiber.HeaderLastModified, updated.UTC().Format(http.TimeFormat))
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(feedMaxAge))
+
+	if c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	body, err := feed.Render(format, &feed.Feed{
+		Title:       feedTitle(q),
+		Description: "Articles collected and enriched by IntelliNieuws",
+		Link:        c.BaseURL() + "/api/v1/articles",
+		SelfURL:     c.BaseURL() + c.OriginalURL(),
+		Updated:     updated,
+		Items:       items,
+	})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to render feed")
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("FEED_ERROR", "Failed to render feed", err.Error(), requestID),
+		)
+	}
+
+	c.Set(fiber.HeaderContentType, feed.ContentType(format))
+	return c.Send(body)
+}
+
+// loadItems returns the feed articles, cached briefly since feed readers poll
+func (h *FeedHandler) loadItems(c *fiber.Ctx, q models.SavedSearchQuery, limit int) ([]models.FeedItem, error) {
+	queryJSON, _ := json.Marshal(q)
+	cacheKey := cache.GenerateKey(cache.PrefixFeeds, string(queryJSON), strconv.Itoa(limit))
+
+	if h.cache != nil {
+		var cached []models.FeedItem
+		if err := h.cache.Get(c.UserContext(), cacheKey, &cached); err == nil {
+			return cached, nil
+		}
+	}
+
+	items, err := h.repo.ListFeed(c.UserContext(), q, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.cache != nil {
+		if err := h.cache.SetWithTTL(c.UserContext(), cacheKey, items, feedCacheTTL); err != nil {
+			h.logger.WithError(err).Warn("Failed to cache feed")
+		}
+	}
+	return items, nil
+}
+
+// parseFeedFormat validates the feed extension. On failure the error response has already been written.
+func parseFeedFormat(c *fiber.Ctx, requestID, format string) (string, bool) {
+	format = strings.ToLower(format)
+	switch format {
+	case models.FeedFormatRSS, models.FeedFormatAtom, models.FeedFormatJSON:
+		return format, true
+	}
+	c.Status(fiber.StatusNotFound).JSON(
+		models.NewErrorResponse("INVALID_FEED_FORMAT", "Unsupported feed format", "use .rss, .atom or .json", requestID),
+	)
+	return "", false
+}
+
+// parseFeedPath splits a path parameter like "ING%20Groep.rss" into its value and feed format.
+// Values may contain dots (e.g. "ASML.AS.rss"), so only the last extension is split off.
+func parseFeedPath(c *fiber.Ctx, requestID, param string) (string, string, bool) {
+	raw, err := url.PathUnescape(c.Params(param))
+	if err != nil {
+		raw = c.Params(param)
+	}
+
+	dot := strings.LastIndex(raw, ".")
+	if dot <= 0 {
+		_, ok := parseFeedFormat(c, requestID, "")
+		return "", "", ok
+	}
+	format, ok := parseFeedFormat(c, requestID, raw[dot+1:])
+	if !ok {
+		return "", "", false
+	}
+	return strings.TrimSpace(raw[:dot]), format, true
+}
+
+// parseArticleQuery reads the article filters of a feed or export request. On failure the error response
+// has already been written.
+func parseArticleQuery(c *fiber.Ctx, requestID string) (models.SavedSearchQuery, bool) {
+	q := models.SavedSearchQuery{
+		Source:         c.Query("source"),
+		Category:       c.Query("category"),
+		Keyword:        c.Query("keyword"),
+		Search:         c.Query("q"),
+		Language:       c.Query("language"),
+		Entity:         c.Query("entity"),
+		Ticker:         strings.ToUpper(c.Query("ticker")),
+		SentimentLabel: c.Query("sentiment"),
+	}
+
+	for param, target := range map[string]**time.Time{"start_date": &q.StartDate, "end_date": &q.EndDate} {
+		if raw := c.Query(param); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				c.Status(fiber.StatusBadRequest).JSON(
+					models.NewErrorResponse("INVALID_DATE", param+" must be in RFC3339 format", err.Error(), requestID),
+				)
+				return q, false
+			}
+			*target = &t
+		}
+	}
+
+	for param, target := range map[string]**float64{"min_sentiment": &q.MinSentiment, "max_sentiment": &q.MaxSentiment} {
+		if raw := c.Query(param); raw != "" {
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				c.Status(fiber.StatusBadRequest).JSON(
+					models.NewErrorResponse("INVALID_PARAMETER", param+" must be a number", err.Error(), requestID),
+				)
+				return q, false
+			}
+			*target = &v
+		}
+	}
+
+	if problem := validateArticleQuery(&q); problem != "" {
+		c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_QUERY", "Invalid feed query", problem, requestID),
+		)
+		return q, false
+	}
+	return q, true
+}
+
+// feedTitle describes the query for the feed title, e.g. "IntelliNieuws: ING, negative"
+func feedTitle(q models.SavedSearchQuery) string {
+	var parts []string
+	for _, part := range []string{q.Entity, q.Ticker, q.Search, q.Keyword, q.Source, q.Category, q.SentimentLabel} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "IntelliNieuws: latest articles"
+	}
+	return "IntelliNieuws: " + strings.Join(parts, ", ")
+}
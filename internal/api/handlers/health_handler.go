@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jeffrey/intellinieuws/internal/ai"
 	"github.com/jeffrey/intellinieuws/internal/cache"
+	"github.com/jeffrey/intellinieuws/internal/leader"
 	"github.com/jeffrey/intellinieuws/internal/models"
 	"github.com/jeffrey/intellinieuws/internal/scraper"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
@@ -22,6 +23,7 @@ type HealthHandler struct {
 	cacheService   *cache.Service
 	scraperService *scraper.Service
 	aiProcessor    *ai.Processor
+	elector        *leader.Elector
 	logger         *logger.Logger
 }
 
@@ -32,6 +34,7 @@ func NewHealthHandler(
 	cacheService *cache.Service,
 	scraperService *scraper.Service,
 	aiProcessor *ai.Processor,
+	elector *leader.Elector,
 	log *logger.Logger,
 ) *HealthHandler {
 	return &HealthHandler{
@@ -40,6 +43,7 @@ func NewHealthHandler(
 		cacheService:   cacheService,
 		scraperService: scraperService,
 		aiProcessor:    aiProcessor,
+		elector:        elector,
 		logger:         log.WithComponent("health-handler"),
 	}
 }
@@ -78,21 +82,21 @@ func (h *HealthHandler) GetHealth(c *fiber.Ctx) error {
 	}
 
 	// Check database health
-	dbHealth := h.checkDatabase(c.Context())
+	dbHealth := h.checkDatabase(c.UserContext())
 	health.Components["database"] = dbHealth
 	if dbHealth.Status != "healthy" {
 		health.Status = "degraded"
 	}
 
 	// Check Redis health
-	redisHealth := h.checkRedis(c.Context())
+	redisHealth := h.checkRedis(c.UserContext())
 	health.Components["redis"] = redisHealth
 	if redisHealth.Status == "unhealthy" && h.cacheService != nil {
 		health.Status = "degraded"
 	}
 
 	// Check scraper health
-	scraperHealth := h.checkScraper(c.Context())
+	scraperHealth := h.checkScraper(c.UserContext())
 	health.Components["scraper"] = scraperHealth
 	if scraperHealth.Status != "healthy" {
 		health.Status = "degraded"
@@ -102,7 +106,16 @@ func (h *HealthHandler) GetHealth(c *fiber.Ctx) error {
 	if h.aiProcessor != nil {
 		aiHealth := h.checkAIProcessor()
 		health.Components["ai_processor"] = aiHealth
-		if aiHealth.Status != "healthy" {
+		if aiHealth.Status != "healthy" && aiHealth.Status != "standby" {
+			health.Status = "degraded"
+		}
+	}
+
+	// Check which instance runs the background workers
+	if h.elector != nil {
+		leaderHealth := h.checkLeader(c.UserContext())
+		health.Components["leader"] = leaderHealth
+		if leaderHealth.Status != "healthy" {
 			health.Status = "degraded"
 		}
 	}
@@ -133,7 +146,7 @@ func (h *HealthHandler) GetLiveness(c *fiber.Ctx) error {
 // GetReadiness returns readiness check (Kubernetes-style)
 // GET /health/ready
 func (h *HealthHandler) GetReadiness(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(c.Context(), 2*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 2*time.Second)
 	defer cancel()
 
 	// Check critical dependencies
@@ -275,7 +288,10 @@ func (h *HealthHandler) checkAIProcessor() ComponentHealth {
 	status := "healthy"
 	message := "AI processor operational"
 
-	if !stats.IsRunning {
+	if !stats.IsRunning && h.elector != nil && !h.elector.IsLeader() {
+		status = "standby"
+		message = "AI processor runs on the leader instance"
+	} else if !stats.IsRunning {
 		status = "degraded"
 		message = "AI processor not running"
 	} else if time.Since(stats.LastRun) > 30*time.Minute {
@@ -295,6 +311,50 @@ func (h *HealthHandler) checkAIProcessor() ComponentHealth {
 	}
 }
 
+// checkLeader reports the leader election state; without a leader no instance runs the
+// background workers
+func (h *HealthHandler) checkLeader(ctx context.Context) ComponentHealth {
+	lookupCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	status, err := h.elector.Status(lookupCtx)
+	details := map[string]interface{}{
+		"enabled":      status.Enabled,
+		"instance_id":  status.InstanceID,
+		"is_leader":    status.IsLeader,
+		"leader":       status.Leader,
+		"leader_since": status.LeaderSince,
+		"workers":      status.Workers,
+	}
+
+	switch {
+	case err != nil:
+		return ComponentHealth{
+			Status:  "degraded",
+			Message: fmt.Sprintf("Leader lookup failed: %v", err),
+			Details: details,
+		}
+	case status.Leader == nil:
+		return ComponentHealth{
+			Status:  "degraded",
+			Message: "No leader elected, background workers are not running",
+			Details: details,
+		}
+	case status.IsLeader:
+		return ComponentHealth{
+			Status:  "healthy",
+			Message: "This instance is the leader",
+			Details: details,
+		}
+	default:
+		return ComponentHealth{
+			Status:  "healthy",
+			Message: fmt.Sprintf("Following leader %s", *status.Leader),
+			Details: details,
+		}
+	}
+}
+
 // addSystemMetrics adds system-level metrics to health status
 func (h *HealthHandler) addSystemMetrics(health *HealthStatus) {
 	health.Metrics["uptime_seconds"] = time.Since(startTime).Seconds()
@@ -318,7 +378,7 @@ func (h *HealthHandler) addSystemMetrics(health *HealthStatus) {
 	}
 }
 
-// GetMetrics returns detailed metrics (Prometheus-compatible format)
+// GetMetrics returns detailed metrics as JSON; Prometheus metrics are served on METRICS_PORT
 // GET /health/metrics
 func (h *HealthHandler) GetMetrics(c *fiber.Ctx) error {
 	requestID := c.Locals("requestid").(string)
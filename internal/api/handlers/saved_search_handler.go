@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeffrey/intellinieuws/internal/alerts"
+	"github.com/jeffrey/intellinieuws/internal/language"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+	"github.com/jeffrey/intellinieuws/pkg/middleware"
+)
+
+// SavedSearchHandler handles saved searches and their results
+type SavedSearchHandler struct {
+	repo      *repository.SavedSearchRepository
+	evaluator *alerts.Evaluator
+	webhooks  WebhookLookup
+	logger    *logger.Logger
+}
+
+// WebhookLookup interface for validating the webhook subscription a saved search alerts through
+type WebhookLookup interface {
+	GetByID(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+}
+
+// NewSavedSearchHandler creates a new saved search handler
+func NewSavedSearchHandler(repo *repository.SavedSearchRepository, evaluator *alerts.Evaluator, log *logger.Logger) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		repo:      repo,
+		evaluator: evaluator,
+		logger:    log.WithComponent("saved-search-handler"),
+	}
+}
+
+// SetWebhooks enables the webhook alert channel
+func (h *SavedSearchHandler) SetWebhooks(webhooks WebhookLookup) {
+	h.webhooks = webhooks
+}
+
+// searchOwner identifies the caller that owns saved searches: the end user for bearer
+// tokens, otherwise the API key (carried over on rotation). Empty when authentication is disabled.
+func searchOwner(c *fiber.Ctx) string {
+	p := middleware.PrincipalFromContext(c)
+	switch {
+	case p == nil:
+		return ""
+	case p.IsUser():
+		return "user:" + p.UserID
+	case p.KeyID != 0:
+		return repository.KeySearchOwner(p.KeyID)
+	default:
+		return "admin"
+	}
+}
+
+// ListSearches returns the caller's saved searches with their "new since last viewed" counts
+// GET /api/v1/saved-searches
+func (h *SavedSearchHandler) ListSearches(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	searches, err := h.repo.List(c.UserContext(), searchOwner(c))
+	if err != nil {
+		return h.searchError(c, err, requestID)
+	}
+
+	for i := range searches {
+		count, err := h.repo.CountSince(c.UserContext(), searches[i].Query, searches[i].LastViewedAt)
+		if err != nil {
+			h.logger.WithError(err).Warnf("Failed to count new results of saved search %d", searches[i].ID)
+			continue
+		}
+		searches[i].NewSinceLastViewed = &count
+	}
+
+	return c.JSON(models.NewSuccessResponse(searches, requestID))
+}
+
+// GetSearch returns a saved search
+// GET /api/v1/saved-searches/:id
+func (h *SavedSearchHandler) GetSearch(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	search, ok := h.loadOwned(c, requestID)
+	if !ok {
+		return nil
+	}
+
+	if count, err := h.repo.CountSince(c.UserContext(), search.Query, search.LastViewedAt); err == nil {
+		search.NewSinceLastViewed = &count
+	}
+
+	return c.JSON(models.NewSuccessResponse(search, requestID))
+}
+
+// CreateSearch saves a search for the caller
+// POST /api/v1/saved-searches
+func (h *SavedSearchHandler) CreateSearch(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	var req models.SavedSearchCreate
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error(), requestID),
+		)
+	}
+
+	search := &models.SavedSearch{
+		Owner:                 searchOwner(c),
+		Name:                  strings.TrimSpace(req.Name),
+		Query:                 req.Query,
+		AlertChannels:         cleanList(req.AlertChannels),
+		WebhookSubscriptionID: req.WebhookSubscriptionID,
+		Email:                 strings.TrimSpace(req.Email),
+	}
+	if msg := h.validateSearch(c, search); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "Invalid saved search", msg, requestID),
+		)
+	}
+
+	created, err := h.repo.Create(c.UserContext(), search)
+	if err != nil {
+		return h.searchError(c, err, requestID)
+	}
+	h.evaluator.Invalidate()
+
+	return c.Status(fiber.StatusCreated).JSON(models.NewSuccessResponse(created, requestID))
+}
+
+// UpdateSearch changes the name, query or alert channels of a saved search
+// PATCH /api/v1/saved-searches/:id
+func (h *SavedSearchHandler) UpdateSearch(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	search, ok := h.loadOwned(c, requestID)
+	if !ok {
+		return nil
+	}
+
+	var req models.SavedSearchUpdate
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error(), requestID),
+		)
+	}
+
+	if req.Name != nil {
+		search.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Query != nil {
+		search.Query = *req.Query
+	}
+	if req.AlertChannels != nil {
+		search.AlertChannels = cleanList(*req.AlertChannels)
+	}
+	if req.WebhookSubscriptionID != nil {
+		search.WebhookSubscriptionID = req.WebhookSubscriptionID
+	}
+	if req.Email != nil {
+		search.Email = strings.TrimSpace(*req.Email)
+	}
+	if msg := h.validateSearch(c, search); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "Invalid saved search", msg, requestID),
+		)
+	}
+
+	updated, err := h.repo.Update(c.UserContext(), search)
+	if err != nil {
+		return h.searchError(c, err, requestID)
+	}
+	h.evaluator.Invalidate()
+
+	return c.JSON(models.NewSuccessResponse(updated, requestID))
+}
+
+// DeleteSearch removes a saved search and its alert history
+// DELETE /api/v1/saved-searches/:id
+func (h *SavedSearchHandler) DeleteSearch(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	search, ok := h.loadOwned(c, requestID)
+	if !ok {
+		return nil
+	}
+
+	if err := h.repo.Delete(c.UserContext(), search.ID); err != nil {
+		return h.searchError(c, err, requestID)
+	}
+	h.evaluator.Invalidate()
+
+	return c.JSON(models.NewSuccessResponse(fiber.Map{
+		"message": "Saved search deleted",
+		"id":      search.ID,
+	}, requestID))
+}
+
+// GetResults runs a saved search, newest first. The first page reports how many results
+// arrived since the search was last viewed and marks it viewed (unless mark_viewed=false).
+// GET /api/v1/saved-searches/:id/results?limit=50&cursor=&include_total=true&mark_viewed=true
+func (h *SavedSearchHandler) GetResults(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	search, ok := h.loadOwned(c, requestID)
+	if !ok {
+		return nil
+	}
+
+	filter := models.ArticleFilter{
+		SortBy:    "published",
+		SortOrder: "desc",
+		Limit:     c.QueryInt("limit", 50),
+		Offset:    c.QueryInt("offset", 0),
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	if !parseCursorParams(c, requestID, &filter) {
+		return nil
+	}
+
+	newCount, err := h.repo.CountSince(c.UserContext(), search.Query, search.LastViewedAt)
+	if err != nil {
+		return h.searchError(c, err, requestID)
+	}
+
+	page, err := h.repo.Results(c.UserContext(), search.Query, filter)
+	if err != nil {
+		return h.searchError(c, err, requestID)
+	}
+
+	// Paging further does not count as a new visit
+	if filter.Cursor == nil && filter.Offset == 0 && c.QueryBool("mark_viewed", true) {
+		if err := h.repo.MarkViewed(c.UserContext(), search.ID); err != nil {
+			h.logger.WithError(err).Warnf("Failed to mark saved search %d viewed", search.ID)
+		}
+	}
+
+	meta := &models.Meta{
+		Pagination: models.CalculateCursorPaginationMeta(page.Total, filter.Limit, filter.Offset, page.Page),
+		Search: &models.SavedSearchMeta{
+			ID:                 search.ID,
+			Name:               search.Name,
+			NewSinceLastViewed: newCount,
+			LastViewedAt:       search.LastViewedAt,
+		},
+	}
+
+	return c.JSON(models.NewSuccessResponseWithMeta(page.Articles, meta, requestID))
+}
+
+// loadOwned loads the :id saved search of the caller, writing the error response itself.
+// Searches of other owners are reported as not found.
+func (h *SavedSearchHandler) loadOwned(c *fiber.Ctx, requestID string) (*models.SavedSearch, bool) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		_ = c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_ID", "Invalid saved search ID", err.Error(), requestID),
+		)
+		return nil, false
+	}
+
+	search, err := h.repo.GetByID(c.UserContext(), id)
+	if err == nil && search.Owner != searchOwner(c) {
+		err = repository.ErrSavedSearchNotFound
+	}
+	if err != nil {
+		_ = h.searchError(c, err, requestID)
+		return nil, false
+	}
+	return search, true
+}
+
+// validateSearch returns a description of the first problem, or "" when the search is valid
+func (h *SavedSearchHandler) validateSearch(c *fiber.Ctx, search *models.SavedSearch) string {
+	if search.Name == "" {
+		return "name is required"
+	}
+
+	if problem := validateArticleQuery(&search.Query); problem != "" {
+		return problem
+	}
+
+	for _, channel := range search.AlertChannels {
+		switch channel {
+		case models.AlertChannelWebhook, models.AlertChannelEmail, models.AlertChannelStream:
+		default:
+			return "unknown alert channel " + channel + ", use one of: " + strings.Join(models.AlertChannels, ", ")
+		}
+		if !h.evaluator.ChannelAvailable(channel) {
+			return channel + " alerts are not enabled on this server"
+		}
+	}
+
+	if search.HasChannel(models.AlertChannelEmail) {
+		addr, err := mail.ParseAddress(search.Email)
+		if err != nil || addr.Address != search.Email {
+			return "email alerts need a valid email address"
+		}
+	}
+
+	if search.HasChannel(models.AlertChannelWebhook) {
+		if search.WebhookSubscriptionID == nil || h.webhooks == nil {
+			return "webhook alerts need a webhook_subscription_id"
+		}
+		sub, err := h.webhooks.GetByID(c.UserContext(), *search.WebhookSubscriptionID)
+		if err != nil || !canUseWebhook(c, sub) {
+			return "webhook subscription not found"
+		}
+	}
+	return ""
+}
+
+// validateArticleQuery normalizes a saved search or feed query and returns a description
+// of the first problem, or "" when the query is valid
+func validateArticleQuery(q *models.SavedSearchQuery) string {
+	if q.Language != "" {
+		if q.Language = language.Normalize(q.Language); q.Language == "" {
+			return "language must be one of: " + strings.Join(language.Supported(), ", ")
+		}
+	}
+	if q.SentimentLabel != "" {
+		q.SentimentLabel = strings.ToLower(q.SentimentLabel)
+		switch q.SentimentLabel {
+		case "positive", "neutral", "negative":
+		default:
+			return "sentiment must be one of: positive, neutral, negative"
+		}
+	}
+	for _, bound := range []*float64{q.MinSentiment, q.MaxSentiment} {
+		if bound != nil && (*bound < -1 || *bound > 1) {
+			return "sentiment bounds must be between -1 and 1"
+		}
+	}
+	if q.MinSentiment != nil && q.MaxSentiment != nil && *q.MinSentiment > *q.MaxSentiment {
+		return "min_sentiment must not exceed max_sentiment"
+	}
+	if q.StartDate != nil && q.EndDate != nil && q.StartDate.After(*q.EndDate) {
+		return "start_date must be before end_date"
+	}
+	return ""
+}
+
+// canUseWebhook reports whether the caller may route alerts to a subscription: webhook
+// administrators may use any, API keys only those registered for their owner
+func canUseWebhook(c *fiber.Ctx, sub *models.WebhookSubscription) bool {
+	p := middleware.PrincipalFromContext(c)
+	if p == nil || p.HasScope(middleware.ScopeAdminWebhooks) {
+		return true
+	}
+	return p.Owner != "" && p.Owner == sub.Owner
+}
+
+func (h *SavedSearchHandler) searchError(c *fiber.Ctx, err error, requestID string) error {
+	switch {
+	case errors.Is(err, repository.ErrSavedSearchNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(
+			models.NewErrorResponse("NOT_FOUND", "Saved search not found", "", requestID),
+		)
+	case errors.Is(err, repository.ErrSavedSearchExists):
+		return c.Status(fiber.StatusConflict).JSON(
+			models.NewErrorResponse("ALREADY_EXISTS", "A saved search with this name already exists", "", requestID),
+		)
+	default:
+		h.logger.WithError(err).Error("Saved search operation failed")
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("DATABASE_ERROR", "Saved search operation failed", err.Error(), requestID),
+		)
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/jeffrey/intellinieuws/internal/audit"
 	"github.com/jeffrey/intellinieuws/internal/models"
 	"github.com/jeffrey/intellinieuws/internal/scraper"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
@@ -11,6 +12,7 @@ import (
 type ScraperHandler struct {
 	scraperService *scraper.Service
 	articleHandler *ArticleHandler
+	auditor        Auditor
 	logger         *logger.Logger
 }
 
@@ -23,6 +25,11 @@ func NewScraperHandler(scraperService *scraper.Service, articleHandler *ArticleH
 	}
 }
 
+// SetAuditor enables audit records for triggered scrapes
+func (h *ScraperHandler) SetAuditor(auditor Auditor) {
+	h.auditor = auditor
+}
+
 // TriggerScrape handles POST /api/v1/scrape
 func (h *ScraperHandler) TriggerScrape(c *fiber.Ctx) error {
 	requestID := c.Locals("requestid").(string)
@@ -47,8 +54,9 @@ func (h *ScraperHandler) TriggerScrape(c *fiber.Ctx) error {
 		}
 
 		h.logger.Infof("Triggering scrape for source: %s", req.Source)
-		result, err := h.scraperService.ScrapeWithRetry(c.Context(), req.Source, feedURL)
+		result, err := h.scraperService.ScrapeWithRetry(c.UserContext(), req.Source, feedURL)
 		if err != nil {
+			recordAudit(h.auditor, c, audit.ActionScrapeTrigger, req.Source, nil, nil, err)
 			h.logger.WithError(err).Errorf("Scrape failed for source: %s", req.Source)
 			return c.Status(fiber.StatusInternalServerError).JSON(
 				models.NewErrorResponse("SCRAPING_FAILED", "Failed to scrape source", err.Error(), requestID),
@@ -57,7 +65,7 @@ func (h *ScraperHandler) TriggerScrape(c *fiber.Ctx) error {
 
 		// Invalidate cache after successful scrape
 		if result.ArticlesStored > 0 && h.articleHandler != nil {
-			h.articleHandler.InvalidateCache(c.Context())
+			h.articleHandler.InvalidateCache(c.UserContext())
 		}
 
 		response := fiber.Map{
@@ -68,14 +76,16 @@ func (h *ScraperHandler) TriggerScrape(c *fiber.Ctx) error {
 			"articles_skipped": result.ArticlesSkipped,
 			"duration_seconds": result.Duration.Seconds(),
 		}
+		recordAudit(h.auditor, c, audit.ActionScrapeTrigger, req.Source, nil, response, nil)
 
 		return c.JSON(models.NewSuccessResponse(response, requestID))
 	}
 
 	// Scrape all sources
 	h.logger.Info("Triggering scrape for all sources")
-	results, err := h.scraperService.ScrapeAllSources(c.Context())
+	results, err := h.scraperService.ScrapeAllSources(c.UserContext())
 	if err != nil {
+		recordAudit(h.auditor, c, audit.ActionScrapeTrigger, "all", nil, nil, err)
 		h.logger.WithError(err).Error("Scrape failed for all sources")
 		return c.Status(fiber.StatusInternalServerError).JSON(
 			models.NewErrorResponse("SCRAPING_FAILED", "Failed to scrape all sources", err.Error(), requestID),
@@ -102,7 +112,7 @@ func (h *ScraperHandler) TriggerScrape(c *fiber.Ctx) error {
 		totalStored += result.ArticlesStored
 	}
 	if totalStored > 0 && h.articleHandler != nil {
-		h.articleHandler.InvalidateCache(c.Context())
+		h.articleHandler.InvalidateCache(c.UserContext())
 	}
 
 	response := fiber.Map{
@@ -110,6 +120,10 @@ func (h *ScraperHandler) TriggerScrape(c *fiber.Ctx) error {
 		"total_stored":  totalStored,
 		"results":       formattedResults,
 	}
+	recordAudit(h.auditor, c, audit.ActionScrapeTrigger, "all", nil, fiber.Map{
+		"total_sources": len(results),
+		"total_stored":  totalStored,
+	}, nil)
 
 	return c.JSON(models.NewSuccessResponse(response, requestID))
 }
@@ -134,7 +148,7 @@ func (h *ScraperHandler) GetSources(c *fiber.Ctx) error {
 func (h *ScraperHandler) GetScraperStats(c *fiber.Ctx) error {
 	requestID := c.Locals("requestid").(string)
 
-	stats, err := h.scraperService.GetStats(c.Context())
+	stats, err := h.scraperService.GetStats(c.UserContext())
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get scraper stats")
 		return c.Status(fiber.StatusInternalServerError).JSON(
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jeffrey/intellinieuws/internal/analytics"
+	"github.com/jeffrey/intellinieuws/internal/models"
 	"github.com/jeffrey/intellinieuws/internal/stock"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 )
@@ -12,6 +14,8 @@ import (
 // StockHandler handles stock-related HTTP requests
 type StockHandler struct {
 	stockService *stock.Service
+	validator    *stock.TickerValidator
+	sentiment    *analytics.TickerSentimentAnalyzer
 	logger       *logger.Logger
 }
 
@@ -23,6 +27,16 @@ func NewStockHandler(stockService *stock.Service, log *logger.Logger) *StockHand
 	}
 }
 
+// SetTickerValidator enables the ticker validation endpoints
+func (h *StockHandler) SetTickerValidator(validator *stock.TickerValidator) {
+	h.validator = validator
+}
+
+// SetSentimentAnalyzer enables the news sentiment versus price endpoint
+func (h *StockHandler) SetSentimentAnalyzer(analyzer *analytics.TickerSentimentAnalyzer) {
+	h.sentiment = analyzer
+}
+
 // GetQuote handles GET /api/v1/stocks/quote/:symbol
 func (h *StockHandler) GetQuote(c *fiber.Ctx) error {
 	symbol := c.Params("symbol")
@@ -32,7 +46,7 @@ func (h *StockHandler) GetQuote(c *fiber.Ctx) error {
 		})
 	}
 
-	quote, err := h.stockService.GetQuote(c.Context(), symbol)
+	quote, err := h.stockService.GetQuote(c.UserContext(), symbol)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to get quote for %s", symbol)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -70,7 +84,7 @@ func (h *StockHandler) GetMultipleQuotes(c *fiber.Ctx) error {
 	}
 
 	startTime := c.Context().Time()
-	quotes, err := h.stockService.GetMultipleQuotes(c.Context(), request.Symbols)
+	quotes, err := h.stockService.GetMultipleQuotes(c.UserContext(), request.Symbols)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get multiple quotes")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -104,7 +118,7 @@ func (h *StockHandler) GetProfile(c *fiber.Ctx) error {
 		})
 	}
 
-	profile, err := h.stockService.GetProfile(c.Context(), symbol)
+	profile, err := h.stockService.GetProfile(c.UserContext(), symbol)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to get profile for %s", symbol)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -117,7 +131,7 @@ func (h *StockHandler) GetProfile(c *fiber.Ctx) error {
 
 // GetStats handles GET /api/v1/stocks/stats
 func (h *StockHandler) GetStats(c *fiber.Ctx) error {
-	stats := h.stockService.GetCacheStats(c.Context())
+	stats := h.stockService.GetCacheStats(c.UserContext())
 	return c.JSON(fiber.Map{
 		"cache": stats,
 	})
@@ -137,7 +151,7 @@ func (h *StockHandler) GetStockNews(c *fiber.Ctx) error {
 		limit = 50
 	}
 
-	news, err := h.stockService.GetStockNews(c.Context(), symbol, limit)
+	news, err := h.stockService.GetStockNews(c.UserContext(), symbol, limit)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to get stock news for %s", symbol)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -177,7 +191,7 @@ func (h *StockHandler) GetHistoricalPrices(c *fiber.Ctx) error {
 		}
 	}
 
-	prices, err := h.stockService.GetHistoricalPrices(c.Context(), symbol, fromDate, toDate)
+	prices, err := h.stockService.GetHistoricalPrices(c.UserContext(), symbol, fromDate, toDate)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to get historical prices for %s", symbol)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -203,7 +217,7 @@ func (h *StockHandler) GetKeyMetrics(c *fiber.Ctx) error {
 		})
 	}
 
-	metrics, err := h.stockService.GetKeyMetrics(c.Context(), symbol)
+	metrics, err := h.stockService.GetKeyMetrics(c.UserContext(), symbol)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to get key metrics for %s", symbol)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -232,7 +246,7 @@ func (h *StockHandler) GetEarningsCalendar(c *fiber.Ctx) error {
 		}
 	}
 
-	calendar, err := h.stockService.GetEarningsCalendar(c.Context(), fromDate, toDate)
+	calendar, err := h.stockService.GetEarningsCalendar(c.UserContext(), fromDate, toDate)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get earnings calendar")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -262,7 +276,7 @@ func (h *StockHandler) SearchSymbol(c *fiber.Ctx) error {
 		limit = 50
 	}
 
-	results, err := h.stockService.SearchSymbol(c.Context(), query, limit)
+	results, err := h.stockService.SearchSymbol(c.UserContext(), query, limit)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to search symbols with query: %s", query)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -277,12 +291,141 @@ func (h *StockHandler) SearchSymbol(c *fiber.Ctx) error {
 	})
 }
 
+// GetSentimentAnalysis handles GET /api/v1/stocks/sentiment/:symbol
+// Query params: days, max_lag, event_window, event_threshold, min_articles
+func (h *StockHandler) GetSentimentAnalysis(c *fiber.Ctx) error {
+	if h.sentiment == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Sentiment analysis is not configured",
+		})
+	}
+
+	symbol := c.Params("symbol")
+	if symbol == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Symbol parameter is required",
+		})
+	}
+	// The analysis writes the ticker series and may fetch prices, so only known tickers qualify
+	if h.validator != nil && !h.validator.ValidateLocal(stock.TickerCandidate{Symbol: symbol}).Verified {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Unknown ticker symbol",
+		})
+	}
+
+	query := models.TickerSentimentQuery{
+		Symbol:         symbol,
+		Days:           c.QueryInt("days", models.DefaultTickerSentimentDays),
+		MaxLag:         c.QueryInt("max_lag", models.DefaultTickerMaxLag),
+		EventWindow:    c.QueryInt("event_window", models.DefaultTickerEventWindow),
+		EventThreshold: c.QueryFloat("event_threshold", models.DefaultTickerEventThreshold),
+		MinArticles:    c.QueryInt("min_articles", models.DefaultTickerEventArticles),
+	}
+	if query.Days < 1 || query.Days > models.MaxTickerSentimentDays {
+		query.Days = models.DefaultTickerSentimentDays
+	}
+	if query.MaxLag < 0 || query.MaxLag > models.MaxTickerMaxLag {
+		query.MaxLag = models.DefaultTickerMaxLag
+	}
+	if query.EventWindow < 0 || query.EventWindow > models.MaxTickerEventWindow {
+		query.EventWindow = models.DefaultTickerEventWindow
+	}
+	if query.EventThreshold <= 0 || query.EventThreshold > 1 {
+		query.EventThreshold = models.DefaultTickerEventThreshold
+	}
+	if query.MinArticles < 1 {
+		query.MinArticles = models.DefaultTickerEventArticles
+	}
+
+	analysis, err := h.sentiment.Analyze(c.UserContext(), query)
+	if err != nil {
+		h.logger.WithError(err).Errorf("Failed to analyze sentiment for %s", symbol)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to analyze sentiment",
+		})
+	}
+
+	return c.JSON(analysis)
+}
+
+// ValidateTicker handles GET /api/v1/stocks/validate?symbol=ASML&name=ASML+Holding&exchange=AEX
+// The route is public, so it only consults the local symbol master; the provider is
+// queried through the admin RefreshSymbols endpoint.
+func (h *StockHandler) ValidateTicker(c *fiber.Ctx) error {
+	if h.validator == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Ticker validation is not configured",
+		})
+	}
+
+	candidate := stock.TickerCandidate{
+		Symbol:   c.Query("symbol"),
+		Name:     c.Query("name"),
+		Exchange: c.Query("exchange"),
+	}
+	if candidate.Symbol == "" && candidate.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Query parameter 'symbol' or 'name' is required",
+		})
+	}
+
+	result := h.validator.ValidateLocal(candidate)
+
+	return c.JSON(fiber.Map{
+		"result": result,
+		"policy": h.validator.Policy(),
+	})
+}
+
+// RefreshSymbols handles POST /api/v1/stocks/symbols/refresh
+// Body: {"queries": ["ASML", "Adyen"]}
+func (h *StockHandler) RefreshSymbols(c *fiber.Ctx) error {
+	if h.validator == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Ticker validation is not configured",
+		})
+	}
+
+	var req struct {
+		Queries []string `json:"queries"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if len(req.Queries) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "At least one query is required",
+		})
+	}
+	if len(req.Queries) > 25 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Maximum 25 queries allowed",
+		})
+	}
+
+	added, err := h.validator.RefreshSymbols(c.UserContext(), req.Queries)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to refresh symbol master")
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to refresh symbol master",
+			"added": added,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"added":    added,
+		"listings": h.validator.Master().Count(),
+	})
+}
+
 // GetArticlesByTicker handles GET /api/v1/articles/by-ticker/:symbol
 // This is implemented in ai_handler.go as it uses the AI service
 
 // GetMarketGainers handles GET /api/v1/stocks/market/gainers
 func (h *StockHandler) GetMarketGainers(c *fiber.Ctx) error {
-	gainers, err := h.stockService.GetMarketGainers(c.Context())
+	gainers, err := h.stockService.GetMarketGainers(c.UserContext())
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get market gainers")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -298,7 +441,7 @@ func (h *StockHandler) GetMarketGainers(c *fiber.Ctx) error {
 
 // GetMarketLosers handles GET /api/v1/stocks/market/losers
 func (h *StockHandler) GetMarketLosers(c *fiber.Ctx) error {
-	losers, err := h.stockService.GetMarketLosers(c.Context())
+	losers, err := h.stockService.GetMarketLosers(c.UserContext())
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get market losers")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -314,7 +457,7 @@ func (h *StockHandler) GetMarketLosers(c *fiber.Ctx) error {
 
 // GetMostActives handles GET /api/v1/stocks/market/actives
 func (h *StockHandler) GetMostActives(c *fiber.Ctx) error {
-	actives, err := h.stockService.GetMostActives(c.Context())
+	actives, err := h.stockService.GetMostActives(c.UserContext())
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get most actives")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -330,7 +473,7 @@ func (h *StockHandler) GetMostActives(c *fiber.Ctx) error {
 
 // GetSectorPerformance handles GET /api/v1/stocks/sectors
 func (h *StockHandler) GetSectorPerformance(c *fiber.Ctx) error {
-	sectors, err := h.stockService.GetSectorPerformance(c.Context())
+	sectors, err := h.stockService.GetSectorPerformance(c.UserContext())
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get sector performance")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -358,7 +501,7 @@ func (h *StockHandler) GetAnalystRatings(c *fiber.Ctx) error {
 		limit = 50
 	}
 
-	ratings, err := h.stockService.GetAnalystRatings(c.Context(), symbol, limit)
+	ratings, err := h.stockService.GetAnalystRatings(c.UserContext(), symbol, limit)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to get analyst ratings for %s", symbol)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -382,7 +525,7 @@ func (h *StockHandler) GetPriceTarget(c *fiber.Ctx) error {
 		})
 	}
 
-	target, err := h.stockService.GetPriceTarget(c.Context(), symbol)
+	target, err := h.stockService.GetPriceTarget(c.UserContext(), symbol)
 	if err != nil {
 		h.logger.WithError(err).Errorf("Failed to get price target for %s", symbol)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
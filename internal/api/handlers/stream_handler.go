@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/stream"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// StreamHandler serves the live article stream
+type StreamHandler struct {
+	hub           *stream.Hub
+	heartbeat     time.Duration
+	savedSearches SavedSearchLookup
+	logger        *logger.Logger
+}
+
+// SavedSearchLookup interface for optional saved search alert subscriptions
+type SavedSearchLookup interface {
+	GetByID(ctx context.Context, id int64) (*models.SavedSearch, error)
+}
+
+// NewStreamHandler creates a new stream handler
+func NewStreamHandler(hub *stream.Hub, heartbeat time.Duration, log *logger.Logger) *StreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = 25 * time.Second
+	}
+	return &StreamHandler{
+		hub:       hub,
+		heartbeat: heartbeat,
+		logger:    log.WithComponent("stream-handler"),
+	}
+}
+
+// SetSavedSearches enables ?saved_search= alert subscriptions
+func (h *StreamHandler) SetSavedSearches(lookup SavedSearchLookup) {
+	h.savedSearches = lookup
+}
+
+// Stream pushes newly stored articles (and optionally their AI enrichment) as they arrive.
+// Serves Server-Sent Events, or a WebSocket when the request is an upgrade. Clients resume
+// with the Last-Event-ID header or ?last_event_id= (WebSocket and first EventSource connect).
+// Saved search alerts are delivered to their owner with ?saved_search=1,2.
+// GET /api/v1/stream?source=&category=&keyword=&entity=&ticker=&enrichment=true&saved_search=
+func (h *StreamHandler) Stream(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	searchIDs, ok := h.parseSavedSearches(c, requestID)
+	if !ok {
+		return nil
+	}
+
+	// Query values point into the request buffer, which is reused once the connection is hijacked
+	filter := stream.NewFilter(
+		strings.Clone(c.Query("source")),
+		strings.Clone(c.Query("category")),
+		strings.Clone(c.Query("keyword")),
+		strings.Clone(c.Query("entity")),
+		strings.Clone(c.Query("ticker")),
+		c.QueryBool("enrichment", false),
+		searchIDs,
+	)
+
+	var lastEventID int64
+	if raw := c.Get("Last-Event-ID", c.Query("last_event_id")); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				models.NewErrorResponse("INVALID_EVENT_ID", "Last-Event-ID must be a non-negative integer", raw, requestID),
+			)
+		}
+		lastEventID = id
+	}
+
+	// Subscribe before reading the history so no event falls between the two
+	sub, err := h.hub.Subscribe(filter)
+	if err != nil {
+		if errors.Is(err, stream.ErrTooManySubscribers) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(
+				models.NewErrorResponse("STREAM_FULL", "Too many stream clients, retry later", "", requestID),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("STREAM_ERROR", "Failed to open stream", err.Error(), requestID),
+		)
+	}
+
+	var backlog []models.StreamEvent
+	if lastEventID > 0 {
+		backlog, err = h.hub.Replay(c.UserContext(), lastEventID)
+		if err != nil {
+			h.logger.WithError(err).Warn("Failed to replay stream history")
+		}
+	}
+
+	if stream.IsWebSocketUpgrade(c) {
+		err := stream.UpgradeWebSocket(c, func(ws *stream.WebSocketConn) {
+			defer h.hub.Unsubscribe(sub)
+			h.serveWebSocket(ws, sub, backlog, lastEventID)
+		})
+		if err != nil {
+			h.hub.Unsubscribe(sub)
+			return c.Status(fiber.StatusBadRequest).JSON(
+				models.NewErrorResponse("INVALID_UPGRADE", "Invalid WebSocket handshake", err.Error(), requestID),
+			)
+		}
+		return nil
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+
+	conn := c.Context().Conn()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.hub.Unsubscribe(sub)
+
+		send := func(frame string) error {
+			// The server WriteTimeout would otherwise end long-lived streams
+			if err := conn.SetWriteDeadline(time.Now().Add(2 * h.heartbeat)); err != nil {
+				return err
+			}
+			if _, err := w.WriteString(frame); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+
+		if err := send(fmt.Sprintf("retry: %d\n\n", 3000)); err != nil {
+			return
+		}
+		h.pump(sub, backlog, lastEventID, func(event models.StreamEvent) error {
+			return send(sseFrame(event))
+		}, func() error {
+			return send(": ping\n\n")
+		}, nil)
+	})
+
+	return nil
+}
+
+// parseSavedSearches reads ?saved_search= and checks the caller owns each search,
+// writing the error response itself
+func (h *StreamHandler) parseSavedSearches(c *fiber.Ctx, requestID string) ([]int64, bool) {
+	raw := c.Query("saved_search")
+	if raw == "" {
+		return nil, true
+	}
+	if h.savedSearches == nil {
+		_ = c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("NOT_SUPPORTED", "Saved search alerts are not available", "", requestID),
+		)
+		return nil, false
+	}
+
+	var ids []int64
+	for _, part := range strings.Split(raw, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			_ = c.Status(fiber.StatusBadRequest).JSON(
+				models.NewErrorResponse("INVALID_PARAMETER", "saved_search must be a comma-separated list of IDs", raw, requestID),
+			)
+			return nil, false
+		}
+		search, err := h.savedSearches.GetByID(c.UserContext(), id)
+		if err != nil || search.Owner != searchOwner(c) {
+			_ = c.Status(fiber.StatusNotFound).JSON(
+				models.NewErrorResponse("NOT_FOUND", "Saved search not found", part, requestID),
+			)
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
+
+// serveWebSocket pushes events as JSON text messages until either side disconnects
+func (h *StreamHandler) serveWebSocket(ws *stream.WebSocketConn, sub *stream.Subscriber, backlog []models.StreamEvent, lastEventID int64) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		defer cancel()
+		_ = ws.ReadLoop(2 * h.heartbeat)
+	}()
+
+	dropped := h.pump(sub, backlog, lastEventID, func(event models.StreamEvent) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		return ws.WriteText(data)
+	}, ws.WritePing, ctx.Done())
+
+	if dropped {
+		_ = ws.Close(1013, "reconnect with last_event_id")
+	}
+}
+
+// pump replays the backlog and then forwards live events until a write fails, the
+// subscriber is dropped (returns true) or stop is closed (nil never closes).
+func (h *StreamHandler) pump(sub *stream.Subscriber, backlog []models.StreamEvent, lastEventID int64,
+	write func(models.StreamEvent) error, ping func() error, stop <-chan struct{}) bool {
+
+	resumeFrom := lastEventID
+	for _, event := range backlog {
+		if !sub.Matches(&event) {
+			continue
+		}
+		if err := write(event); err != nil {
+			return false
+		}
+		if event.ID > resumeFrom {
+			resumeFrom = event.ID
+		}
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-sub.Events():
+			// Events already sent from the history
+			if event.ID != 0 && event.ID <= resumeFrom {
+				continue
+			}
+			if err := write(event); err != nil {
+				return false
+			}
+		case <-ticker.C:
+			if err := ping(); err != nil {
+				return false
+			}
+		case <-sub.Done():
+			return true
+		case <-stop:
+			return false
+		}
+	}
+}
+
+// sseFrame formats an event as a Server-Sent Events frame
+func sseFrame(event models.StreamEvent) string {
+	data, _ := json.Marshal(event)
+
+	var b strings.Builder
+	if event.ID != 0 {
+		fmt.Fprintf(&b, "id: %d\n", event.ID)
+	}
+	fmt.Fprintf(&b, "event: %s\ndata: %s\n\n", event.Type, data)
+	return b.String()
+}
+
+// GetStats returns live stream statistics for this instance
+// GET /api/v1/stream/stats
+func (h *StreamHandler) GetStats(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+	return c.JSON(models.NewSuccessResponse(fiber.Map{
+		"subscribers": h.hub.SubscriberCount(),
+	}, requestID))
+}
@@ -0,0 +1,386 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeffrey/intellinieuws/internal/audit"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/internal/webhook"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// WebhookHandler handles webhook subscription management requests
+type WebhookHandler struct {
+	repo       *repository.WebhookRepository
+	dispatcher *webhook.Dispatcher
+	auditor    Auditor
+	logger     *logger.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(repo *repository.WebhookRepository, dispatcher *webhook.Dispatcher, log *logger.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		repo:       repo,
+		dispatcher: dispatcher,
+		logger:     log.WithComponent("webhook-handler"),
+	}
+}
+
+// SetAuditor enables audit records for subscription changes
+func (h *WebhookHandler) SetAuditor(auditor Auditor) {
+	h.auditor = auditor
+}
+
+// ListSubscriptions returns webhook subscriptions (never their secrets)
+// GET /api/v1/admin/webhooks?owner=partner-x&include_disabled=true
+func (h *WebhookHandler) ListSubscriptions(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	subs, err := h.repo.List(c.UserContext(), c.Query("owner"), c.QueryBool("include_disabled", false))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list webhook subscriptions")
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			models.NewErrorResponse("DATABASE_ERROR", "Failed to list webhook subscriptions", err.Error(), requestID),
+		)
+	}
+
+	return c.JSON(models.NewSuccessResponse(subs, requestID))
+}
+
+// ListEventTypes returns the event types a subscription can select
+// GET /api/v1/admin/webhooks/events
+func (h *WebhookHandler) ListEventTypes(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+	return c.JSON(models.NewSuccessResponse(models.WebhookEventTypes, requestID))
+}
+
+// GetSubscription returns a single subscription
+// GET /api/v1/admin/webhooks/:id
+func (h *WebhookHandler) GetSubscription(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	id, ok := parseWebhookID(c, requestID)
+	if !ok {
+		return nil
+	}
+
+	sub, err := h.repo.GetByID(c.UserContext(), id)
+	if err != nil {
+		return h.webhookError(c, err, requestID)
+	}
+
+	return c.JSON(models.NewSuccessResponse(sub, requestID))
+}
+
+// CreateSubscription registers a webhook endpoint; the signing secret is only returned in this response
+// POST /api/v1/admin/webhooks
+func (h *WebhookHandler) CreateSubscription(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	var req models.WebhookSubscriptionCreate
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error(), requestID),
+		)
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return h.webhookError(c, err, requestID)
+	}
+
+	sub := &models.WebhookSubscription{
+		Name:         strings.TrimSpace(req.Name),
+		Owner:        strings.TrimSpace(req.Owner),
+		URL:          strings.TrimSpace(req.URL),
+		Secret:       secret,
+		Events:       cleanList(req.Events),
+		Sources:      cleanList(req.Sources),
+		Keywords:     cleanList(req.Keywords),
+		Entities:     cleanList(req.Entities),
+		Tickers:      cleanList(req.Tickers),
+		MinSentiment: req.MinSentiment,
+		MaxSentiment: req.MaxSentiment,
+		CreatedBy:    actorName(c),
+	}
+	if msg := validateWebhook(sub); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "Invalid webhook subscription", msg, requestID),
+		)
+	}
+
+	created, err := h.repo.Create(c.UserContext(), sub)
+	if err != nil {
+		recordAudit(h.auditor, c, audit.ActionWebhookCreate, sub.URL, nil, sub, err)
+		return h.webhookError(c, err, requestID)
+	}
+	h.dispatcher.Invalidate()
+	recordAudit(h.auditor, c, audit.ActionWebhookCreate, strconv.FormatInt(created.ID, 10), nil, created, nil)
+
+	return c.Status(fiber.StatusCreated).JSON(models.NewSuccessResponse(
+		models.WebhookSubscriptionCreated{Subscription: created, Secret: secret}, requestID,
+	))
+}
+
+// UpdateSubscription changes filters, URL or state; re-enabling resets the failure counter
+// PATCH /api/v1/admin/webhooks/:id
+func (h *WebhookHandler) UpdateSubscription(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	id, ok := parseWebhookID(c, requestID)
+	if !ok {
+		return nil
+	}
+
+	var req models.WebhookSubscriptionUpdate
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "Invalid request body", err.Error(), requestID),
+		)
+	}
+
+	before, err := h.repo.GetByID(c.UserContext(), id)
+	if err != nil {
+		return h.webhookError(c, err, requestID)
+	}
+
+	sub := *before
+	if req.Name != nil {
+		sub.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Owner != nil {
+		sub.Owner = strings.TrimSpace(*req.Owner)
+	}
+	if req.URL != nil {
+		sub.URL = strings.TrimSpace(*req.URL)
+	}
+	for _, list := range []struct {
+		value  *[]string
+		target *[]string
+	}{
+		{req.Events, &sub.Events},
+		{req.Sources, &sub.Sources},
+		{req.Keywords, &sub.Keywords},
+		{req.Entities, &sub.Entities},
+		{req.Tickers, &sub.Tickers},
+	} {
+		if list.value != nil {
+			*list.target = cleanList(*list.value)
+		}
+	}
+	if req.ClearSentiment {
+		sub.MinSentiment, sub.MaxSentiment = nil, nil
+	}
+	if req.MinSentiment != nil {
+		sub.MinSentiment = req.MinSentiment
+	}
+	if req.MaxSentiment != nil {
+		sub.MaxSentiment = req.MaxSentiment
+	}
+	if req.Active != nil && *req.Active != sub.Active {
+		sub.Active = *req.Active
+		if sub.Active {
+			sub.ConsecutiveFailures = 0
+			sub.DisabledAt = nil
+			sub.DisabledReason = ""
+		} else {
+			now := time.Now()
+			sub.DisabledAt = &now
+			sub.DisabledReason = "disabled by " + defaultActor(actorName(c))
+		}
+	}
+
+	if msg := validateWebhook(&sub); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_REQUEST", "Invalid webhook subscription", msg, requestID),
+		)
+	}
+
+	updated, err := h.repo.Update(c.UserContext(), &sub)
+	if err != nil {
+		recordAudit(h.auditor, c, audit.ActionWebhookUpdate, strconv.FormatInt(id, 10), before, nil, err)
+		return h.webhookError(c, err, requestID)
+	}
+	h.dispatcher.Invalidate()
+	recordAudit(h.auditor, c, audit.ActionWebhookUpdate, strconv.FormatInt(id, 10), before, updated, nil)
+
+	return c.JSON(models.NewSuccessResponse(updated, requestID))
+}
+
+// DeleteSubscription removes a subscription and its delivery log
+// DELETE /api/v1/admin/webhooks/:id
+func (h *WebhookHandler) DeleteSubscription(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	id, ok := parseWebhookID(c, requestID)
+	if !ok {
+		return nil
+	}
+
+	before, err := h.repo.GetByID(c.UserContext(), id)
+	if err != nil {
+		return h.webhookError(c, err, requestID)
+	}
+
+	err = h.repo.Delete(c.UserContext(), id)
+	recordAudit(h.auditor, c, audit.ActionWebhookDelete, strconv.FormatInt(id, 10), before, nil, err)
+	if err != nil {
+		return h.webhookError(c, err, requestID)
+	}
+	h.dispatcher.Invalidate()
+
+	return c.JSON(models.NewSuccessResponse(fiber.Map{
+		"message": "Webhook subscription deleted",
+		"id":      id,
+	}, requestID))
+}
+
+// SendTestEvent delivers a webhook.test event right away and returns the delivery outcome
+// POST /api/v1/admin/webhooks/:id/test
+func (h *WebhookHandler) SendTestEvent(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	id, ok := parseWebhookID(c, requestID)
+	if !ok {
+		return nil
+	}
+
+	sub, err := h.repo.GetByID(c.UserContext(), id)
+	if err != nil {
+		return h.webhookError(c, err, requestID)
+	}
+
+	delivery, err := h.dispatcher.SendTest(c.UserContext(), sub)
+	if err != nil {
+		return h.webhookError(c, err, requestID)
+	}
+
+	return c.JSON(models.NewSuccessResponse(delivery, requestID))
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first
+// GET /api/v1/admin/webhooks/:id/deliveries?status=failed&limit=50&offset=0
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	requestID := c.Locals("requestid").(string)
+
+	id, ok := parseWebhookID(c, requestID)
+	if !ok {
+		return nil
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivering, models.WebhookDeliveryRetrying,
+		models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed, models.WebhookDeliveryCancelled:
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_PARAMETER", "Invalid delivery status", status, requestID),
+		)
+	}
+
+	limit := c.QueryInt("limit", 50)
+	offset := c.QueryInt("offset", 0)
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	if _, err := h.repo.GetByID(c.UserContext(), id); err != nil {
+		return h.webhookError(c, err, requestID)
+	}
+
+	deliveries, total, err := h.repo.ListDeliveries(c.UserContext(), id, status, limit, offset)
+	if err != nil {
+		return h.webhookError(c, err, requestID)
+	}
+
+	meta := &models.Meta{
+		Pagination: models.CalculatePaginationMeta(total, limit, offset),
+	}
+
+	return c.JSON(models.NewSuccessResponseWithMeta(deliveries, meta, requestID))
+}
+
+func (h *WebhookHandler) webhookError(c *fiber.Ctx, err error, requestID string) error {
+	if errors.Is(err, repository.ErrWebhookNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(
+			models.NewErrorResponse("NOT_FOUND", "Webhook subscription not found", "", requestID),
+		)
+	}
+	h.logger.WithError(err).Error("Webhook operation failed")
+	return c.Status(fiber.StatusInternalServerError).JSON(
+		models.NewErrorResponse("DATABASE_ERROR", "Webhook operation failed", err.Error(), requestID),
+	)
+}
+
+// parseWebhookID reads the :id parameter, writing the error response itself
+func parseWebhookID(c *fiber.Ctx, requestID string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		_ = c.Status(fiber.StatusBadRequest).JSON(
+			models.NewErrorResponse("INVALID_ID", "Invalid webhook subscription ID", err.Error(), requestID),
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// validateWebhook returns a description of the first problem, or "" when the subscription is valid
+func validateWebhook(sub *models.WebhookSubscription) string {
+	if sub.Name == "" {
+		return "name is required"
+	}
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "url must be an absolute http(s) URL"
+	}
+	for _, event := range sub.Events {
+		if !isWebhookEventType(event) {
+			return "unknown event type " + event + ", see /api/v1/admin/webhooks/events"
+		}
+	}
+	for _, bound := range []*float64{sub.MinSentiment, sub.MaxSentiment} {
+		if bound != nil && (*bound < -1 || *bound > 1) {
+			return "sentiment bounds must be between -1 and 1"
+		}
+	}
+	if sub.MinSentiment != nil && sub.MaxSentiment != nil && *sub.MinSentiment > *sub.MaxSentiment {
+		return "min_sentiment must not exceed max_sentiment"
+	}
+	return ""
+}
+
+func isWebhookEventType(event string) bool {
+	for _, e := range models.WebhookEventTypes {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanList trims values and drops empty ones; never returns nil so arrays are stored as '{}'
+func cleanList(values []string) []string {
+	cleaned := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			cleaned = append(cleaned, v)
+		}
+	}
+	return cleaned
+}
+
+func defaultActor(name string) string {
+	if name == "" {
+		return "administrator"
+	}
+	return name
+}
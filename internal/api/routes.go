@@ -11,10 +11,14 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/jeffrey/intellinieuws/internal/ai"
+	"github.com/jeffrey/intellinieuws/internal/analytics"
 	"github.com/jeffrey/intellinieuws/internal/api/handlers"
 	"github.com/jeffrey/intellinieuws/internal/cache"
+	"github.com/jeffrey/intellinieuws/internal/leader"
+	"github.com/jeffrey/intellinieuws/internal/metrics"
 	"github.com/jeffrey/intellinieuws/internal/models"
 	"github.com/jeffrey/intellinieuws/internal/scraper"
+	"github.com/jeffrey/intellinieuws/internal/tracing"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 	"github.com/jeffrey/intellinieuws/pkg/middleware"
 )
@@ -29,6 +33,14 @@ func SetupRoutes(
 	emailHandler *handlers.EmailHandler,
 	cacheHandler *handlers.CacheHandler,
 	configHandler *handlers.ConfigHandler,
+	entityHandler *handlers.EntityHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	auditHandler *handlers.AuditHandler,
+	streamHandler *handlers.StreamHandler,
+	webhookHandler *handlers.WebhookHandler,
+	savedSearchHandler *handlers.SavedSearchHandler,
+	feedHandler *handlers.FeedHandler,
+	exportHandler *handlers.ExportHandler,
 	rateLimiter *middleware.RateLimiter,
 	auth *middleware.APIKeyAuth,
 	log *logger.Logger,
@@ -37,45 +49,87 @@ func SetupRoutes(
 	cacheService *cache.Service,
 	scraperService *scraper.Service,
 	aiProcessor *ai.Processor,
+	refresher *analytics.RefreshCoordinator,
+	elector *leader.Elector,
 ) {
 	// Initialize analytics handler
-	analyticsHandler := handlers.NewAnalyticsHandler(db, log)
+	analyticsHandler := handlers.NewAnalyticsHandler(db, cacheService, refresher, log)
+
+	// requireScope enforces an API key scope; a no-op when authentication is disabled
+	requireScope := func(scopes ...string) fiber.Handler {
+		if auth == nil {
+			return func(c *fiber.Ctx) error { return c.Next() }
+		}
+		return auth.Require(scopes...)
+	}
 
 	// Global middleware
 	app.Use(recover.New())
 	app.Use(requestid.New())
+	app.Use(tracing.Middleware())
+	app.Use(metrics.HTTPMiddleware())
 
 	// Enhanced CORS configuration for frontend
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "*",
 		AllowMethods:     "GET,POST,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-API-Key, X-Request-ID",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-API-Key, X-Request-ID, Last-Event-ID, traceparent, tracestate",
 		AllowCredentials: false,
-		ExposeHeaders:    "X-Request-ID, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset",
+		ExposeHeaders:    "X-Request-ID, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, X-RateLimit-Cost, Retry-After, X-Quota-Limit, X-Quota-Remaining, X-Trace-ID",
 		MaxAge:           300,
 	}))
 
-	// Custom logger middleware
+	// Structured request log. The request-scoped logger is put in the user context so
+	// services log the request and trace IDs via logger.FromContext.
 	app.Use(func(c *fiber.Ctx) error {
 		start := time.Now()
 		requestID := c.Locals("requestid").(string)
 
+		reqLog := log.WithRequestID(requestID).WithTraceID(tracing.TraceID(c.UserContext()))
+		c.SetUserContext(logger.NewContext(c.UserContext(), reqLog))
+
 		err := c.Next()
 
-		duration := time.Since(start)
-		log.Infof("[%s] %s %s - %d - %v",
-			requestID,
-			c.Method(),
-			c.Path(),
-			c.Response().StatusCode(),
-			duration,
-		)
+		// Errors returned to the app error handler have not set the status yet
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if fiberErr, ok := err.(*fiber.Error); ok {
+				status = fiberErr.Code
+			}
+		}
+
+		event := reqLog.Logger.Info()
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			event = reqLog.Logger.Error()
+		case status >= fiber.StatusBadRequest:
+			event = reqLog.Logger.Warn()
+		}
+		event = event.
+			Str("method", c.Method()).
+			Str("route", c.Route().Path).
+			Str("path", c.Path()).
+			Int("status", status).
+			Float64("latency_ms", float64(time.Since(start).Microseconds())/1000).
+			Str("ip", c.IP())
+		// Reading a streamed body would drain it, so SSE and exports log no size
+		if !c.Response().IsBodyStream() {
+			event = event.Int("bytes", len(c.Response().Body()))
+		}
+		if principal := middleware.PrincipalFromContext(c); principal != nil && principal.KeyID > 0 {
+			event = event.Int64("key_id", principal.KeyID)
+		}
+		if err != nil {
+			event = event.Err(err)
+		}
+		event.Msg("request")
 
 		return err
 	})
 
 	// PHASE 4: Comprehensive health monitoring (no auth required)
-	healthHandler := handlers.NewHealthHandler(db, redis, cacheService, scraperService, aiProcessor, log)
+	healthHandler := handlers.NewHealthHandler(db, redis, cacheService, scraperService, aiProcessor, elector, log)
 
 	app.Get("/health", healthHandler.GetHealth)          // Comprehensive health
 	app.Get("/health/live", healthHandler.GetLiveness)   // Liveness probe
@@ -93,13 +147,18 @@ func SetupRoutes(
 	// Analytics routes (public, no auth) - Must be before auth middleware
 	analytics := api.Group("/analytics")
 	analytics.Get("/trending", analyticsHandler.GetTrendingKeywords)
+	analytics.Get("/bursts", analyticsHandler.GetBursts)
 	analytics.Get("/sentiment-trends", analyticsHandler.GetSentimentTrends)
 	analytics.Get("/hot-entities", analyticsHandler.GetHotEntities)
 	analytics.Get("/entity-sentiment", analyticsHandler.GetEntitySentiment)
+	analytics.Get("/entity-graph", analyticsHandler.GetEntityGraph)
+	analytics.Get("/sources", analyticsHandler.GetSourceComparison)
+	analytics.Get("/timeseries", analyticsHandler.GetTimeSeries)
 	analytics.Get("/overview", analyticsHandler.GetAnalyticsOverview)
 	analytics.Get("/article-stats", analyticsHandler.GetArticleStats)
 	analytics.Get("/maintenance-schedule", analyticsHandler.GetMaintenanceSchedule)
 	analytics.Get("/database-health", analyticsHandler.GetDatabaseHealth)
+	analytics.Get("/freshness", analyticsHandler.GetFreshness)
 	analytics.Post("/refresh", analyticsHandler.RefreshAnalytics)
 
 	// Configuration routes (public read, protected write) - Must be before auth middleware
@@ -123,20 +182,31 @@ func SetupRoutes(
 	articles.Get("/", articleHandler.ListArticles)
 	articles.Get("/stats", articleHandler.GetStats)
 	articles.Get("/search", articleHandler.SearchArticles)
+	articles.Get("/languages", articleHandler.GetLanguages)
 	articles.Get("/:id", articleHandler.GetArticle)
 
 	// Content extraction route (protected)
-	if auth != nil {
-		articles.Post("/:id/extract-content", auth.Handler(), articleHandler.ExtractContent)
-	} else {
-		articles.Post("/:id/extract-content", articleHandler.ExtractContent)
-	}
+	articles.Post("/:id/extract-content", requireScope(middleware.ScopeWriteArticles), articleHandler.ExtractContent)
 
 	// AI enrichment routes (public)
 	if aiHandler != nil {
 		articles.Get("/:id/enrichment", aiHandler.GetEnrichment)
 	}
 
+	// Live article stream (public, SSE or WebSocket upgrade)
+	if streamHandler != nil {
+		api.Get("/stream", streamHandler.Stream)
+		api.Get("/stream/stats", streamHandler.GetStats)
+	}
+
+	// Syndication feeds (public, RSS 2.0 / Atom / JSON Feed)
+	if feedHandler != nil {
+		feeds := api.Group("/feeds")
+		feeds.Get("/articles.:format", feedHandler.ArticlesFeed) // Any article query
+		feeds.Get("/entity/:name", feedHandler.EntityFeed)       // e.g. /feeds/entity/ING.rss
+		feeds.Get("/ticker/:symbol", feedHandler.TickerFeed)     // e.g. /feeds/ticker/INGA.atom
+	}
+
 	// Source routes
 	api.Get("/sources", scraperHandler.GetSources)
 	api.Get("/categories", articleHandler.GetCategories)
@@ -149,8 +219,16 @@ func SetupRoutes(
 		ai.Get("/entity/:name", aiHandler.GetArticlesByEntity)
 		ai.Get("/processor/stats", aiHandler.GetProcessorStats)
 
-		// Conversational AI chat endpoint (public)
-		ai.Post("/chat", aiHandler.Chat)
+		// Conversational AI chat endpoint (requires ai:chat when auth is enabled)
+		ai.Post("/chat", requireScope(middleware.ScopeAIChat), aiHandler.Chat)
+	}
+
+	// Canonical entity registry routes (public read)
+	if entityHandler != nil {
+		entities := api.Group("/entities")
+		entities.Get("/", entityHandler.ListEntities)
+		entities.Get("/resolve", entityHandler.ResolveEntity)
+		entities.Get("/:id<int>", entityHandler.GetEntity) // Numeric only, so admin routes such as /merge-candidates fall through
 	}
 
 	// Stock ticker routes (public) - FMP Free Tier Only
@@ -164,6 +242,11 @@ func SetupRoutes(
 		stocks.Get("/earnings", stockHandler.GetEarningsCalendar) // Earnings calendar
 		stocks.Get("/search", stockHandler.SearchSymbol)          // Symbol search
 		stocks.Get("/stats", stockHandler.GetStats)               // Cache stats
+		stocks.Get("/validate", stockHandler.ValidateTicker)      // Validate ticker against symbol master
+
+		// News sentiment versus daily returns; prices come from the historical endpoint
+		// below and are stored, so only stored prices are used without a premium plan
+		stocks.Get("/sentiment/:symbol", stockHandler.GetSentimentAnalysis)
 
 		// ⚠️ PREMIUM FEATURES - Disabled for free tier
 		// Uncomment these if you upgrade to FMP Starter plan ($14/month)
@@ -191,26 +274,57 @@ func SetupRoutes(
 		protected.Use(auth.Handler())
 	}
 
+	// Caller identity (any valid key)
+	if apiKeyHandler != nil {
+		protected.Get("/auth/whoami", apiKeyHandler.WhoAmI)
+	}
+
 	// Email integration routes (protected)
 	if emailHandler != nil {
-		emails := protected.Group("/email")
+		emails := protected.Group("/email", requireScope(middleware.ScopeWriteScrape))
 		emails.Post("/fetch-existing", emailHandler.FetchExistingEmails) // Manually fetch existing emails
 		emails.Get("/stats", emailHandler.GetStats)                      // Email processing stats
 	}
 
+	// Article language backfill (protected)
+	protected.Post("/articles/languages/backfill", requireScope(middleware.ScopeWriteArticles), articleHandler.BackfillLanguages)
+
+	// Bulk article export (protected, streamed)
+	if exportHandler != nil {
+		protected.Get("/export/articles", requireScope(middleware.ScopeExportArticles), exportHandler.ExportArticles)
+	}
+
 	// Scraper routes (protected)
-	protected.Post("/scrape", scraperHandler.TriggerScrape)
-	protected.Get("/scraper/stats", scraperHandler.GetScraperStats)
+	protected.Post("/scrape", requireScope(middleware.ScopeWriteScrape), scraperHandler.TriggerScrape)
+	protected.Get("/scraper/stats", requireScope(middleware.ScopeWriteScrape), scraperHandler.GetScraperStats)
 
 	// AI processing routes (protected)
 	if aiHandler != nil {
-		protected.Post("/articles/:id/process", aiHandler.ProcessArticle)
-		protected.Post("/ai/process/trigger", aiHandler.TriggerProcessing)
+		protected.Post("/articles/:id/process", requireScope(middleware.ScopeAIProcess), aiHandler.ProcessArticle)
+		protected.Post("/ai/process/trigger", requireScope(middleware.ScopeAIProcess), aiHandler.TriggerProcessing)
+		protected.Get("/ai/security/injections", requireScope(middleware.ScopeAdminConfig), aiHandler.GetInjectionStats)
+	}
+
+	// Symbol master refresh (protected)
+	if stockHandler != nil {
+		protected.Post("/stocks/symbols/refresh", requireScope(middleware.ScopeAdminConfig), stockHandler.RefreshSymbols)
+	}
+
+	// Entity registry admin routes (protected)
+	if entityHandler != nil {
+		entityAdmin := protected.Group("/entities", requireScope(middleware.ScopeAdminEntities))
+		entityAdmin.Post("/merge", entityHandler.MergeEntities)                          // Merge entities into a target
+		entityAdmin.Get("/merge-candidates", entityHandler.ListMergeCandidates)          // Pairs queued for merge review
+		entityAdmin.Delete("/merge-candidates/:id", entityHandler.DismissMergeCandidate) // Dismiss a queued pair
+		entityAdmin.Post("/:id/split", entityHandler.SplitEntity)                        // Split aliases into a new entity
+		entityAdmin.Post("/import", entityHandler.ImportDump)                            // Reload local entity dump
+		entityAdmin.Post("/backfill", entityHandler.Backfill)                            // Resolve previously processed articles (202)
+		entityAdmin.Get("/backfill/:id", entityHandler.GetBackfill)                      // Backfill job status
 	}
 
 	// Cache management routes (protected)
 	if cacheHandler != nil {
-		cacheRoutes := protected.Group("/cache")
+		cacheRoutes := protected.Group("/cache", requireScope(middleware.ScopeAdminConfig))
 		cacheRoutes.Get("/stats", cacheHandler.GetStatistics)         // Cache statistics
 		cacheRoutes.Get("/keys", cacheHandler.GetCacheKeys)           // List cache keys
 		cacheRoutes.Get("/size", cacheHandler.GetCacheSize)           // Total cache size
@@ -221,13 +335,53 @@ func SetupRoutes(
 
 	// Configuration write routes (protected)
 	if configHandler != nil {
-		configProtected := protected.Group("/config")
+		configProtected := protected.Group("/config", requireScope(middleware.ScopeAdminConfig))
 		configProtected.Post("/profile/:name", configHandler.SwitchProfile) // Switch profile
 		configProtected.Patch("/setting", configHandler.UpdateSetting)      // Update setting
 		configProtected.Post("/reset", configHandler.ResetToDefaults)       // Reset to defaults
 		configProtected.Post("/restart", configHandler.RestartServer)       // Restart server (graceful)
 	}
 
+	// API key management routes (protected)
+	if apiKeyHandler != nil {
+		keys := protected.Group("/admin/api-keys", requireScope(middleware.ScopeAdminKeys))
+		keys.Get("/", apiKeyHandler.ListKeys)             // List keys (never the raw keys)
+		keys.Post("/", apiKeyHandler.IssueKey)            // Issue a key (raw key returned once)
+		keys.Get("/scopes", apiKeyHandler.ListScopes)     // Assignable scopes
+		keys.Get("/:id", apiKeyHandler.GetKey)            // Key details
+		keys.Post("/:id/rotate", apiKeyHandler.RotateKey) // Replace key, old one expires after grace period
+		keys.Post("/:id/revoke", apiKeyHandler.RevokeKey) // Revoke immediately
+	}
+
+	// Saved searches and alerts (protected, owned by the calling user or key)
+	if savedSearchHandler != nil {
+		searches := protected.Group("/saved-searches", requireScope(middleware.ScopeSavedSearches))
+		searches.Get("/", savedSearchHandler.ListSearches)          // Own searches with new-result counts
+		searches.Post("/", savedSearchHandler.CreateSearch)         // Save a search
+		searches.Get("/:id", savedSearchHandler.GetSearch)          // Search details
+		searches.Patch("/:id", savedSearchHandler.UpdateSearch)     // Change query or alert channels
+		searches.Delete("/:id", savedSearchHandler.DeleteSearch)    // Remove search and alert history
+		searches.Get("/:id/results", savedSearchHandler.GetResults) // Run search, marks it viewed
+	}
+
+	// Webhook subscription management routes (protected)
+	if webhookHandler != nil {
+		hooks := protected.Group("/admin/webhooks", requireScope(middleware.ScopeAdminWebhooks))
+		hooks.Get("/", webhookHandler.ListSubscriptions)            // List subscriptions (never the secrets)
+		hooks.Post("/", webhookHandler.CreateSubscription)          // Register endpoint (secret returned once)
+		hooks.Get("/events", webhookHandler.ListEventTypes)         // Selectable event types
+		hooks.Get("/:id", webhookHandler.GetSubscription)           // Subscription details
+		hooks.Patch("/:id", webhookHandler.UpdateSubscription)      // Change filters, URL or re-enable
+		hooks.Delete("/:id", webhookHandler.DeleteSubscription)     // Remove subscription and delivery log
+		hooks.Post("/:id/test", webhookHandler.SendTestEvent)       // Deliver a test event now
+		hooks.Get("/:id/deliveries", webhookHandler.ListDeliveries) // Delivery log
+	}
+
+	// Audit log (protected)
+	if auditHandler != nil {
+		protected.Get("/admin/audit", requireScope(middleware.ScopeAdminAudit), auditHandler.ListEntries)
+	}
+
 	// 404 handler
 	app.Use(func(c *fiber.Ctx) error {
 		requestID := c.Locals("requestid").(string)
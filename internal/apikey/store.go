@@ -0,0 +1,398 @@
+// Package apikey issues, verifies and manages hashed per-client API keys.
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+	"github.com/jeffrey/intellinieuws/pkg/middleware"
+)
+
+const (
+	// keyPrefix marks IntelliNieuws keys so leaked keys are easy to recognize
+	keyPrefix = "inn_"
+
+	// cacheTTL bounds how long a revoked key may keep working on other instances
+	cacheTTL = 60 * time.Second
+	// negativeCacheTTL avoids a database lookup for every request with an invalid key
+	negativeCacheTTL = 30 * time.Second
+	maxNegativeCache = 10000
+
+	// usageFlushInterval controls how often last-used timestamps are written
+	usageFlushInterval = 30 * time.Second
+
+	// DefaultRotationGrace is how long a rotated key keeps working
+	DefaultRotationGrace = 24 * time.Hour
+)
+
+// Validation errors
+var (
+	ErrInvalidScope  = errors.New("invalid scope")
+	ErrInvalidKeyReq = errors.New("invalid key request")
+)
+
+type cachedPrincipal struct {
+	principal *middleware.Principal
+	expiresAt time.Time // cache entry expiry
+	keyExpiry *time.Time
+}
+
+// Store verifies API keys for the auth middleware and manages their lifecycle.
+// It implements middleware.KeyStore.
+type Store struct {
+	repo   *repository.APIKeyRepository
+	logger *logger.Logger
+
+	mu       sync.RWMutex
+	cache    map[string]cachedPrincipal // key hash -> principal
+	negative map[string]time.Time       // key hash -> negative cache expiry
+
+	usageMu sync.Mutex
+	usage   map[int64]repository.KeyUsage
+
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+	runMu    sync.Mutex
+}
+
+// NewStore creates a new API key store
+func NewStore(repo *repository.APIKeyRepository, log *logger.Logger) *Store {
+	return &Store{
+		repo:     repo,
+		logger:   log.WithComponent("api-keys"),
+		cache:    make(map[string]cachedPrincipal),
+		negative: make(map[string]time.Time),
+		usage:    make(map[int64]repository.KeyUsage),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start begins periodically flushing last-used timestamps
+func (s *Store) Start(ctx context.Context) {
+	s.runMu.Lock()
+	if s.running {
+		s.runMu.Unlock()
+		return
+	}
+	s.running = true
+	s.runMu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(usageFlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.flushUsage(ctx)
+			case <-ctx.Done():
+				return
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop flushes pending usage and stops the background worker
+func (s *Store) Stop() {
+	s.runMu.Lock()
+	if !s.running {
+		s.runMu.Unlock()
+		return
+	}
+	s.running = false
+	s.runMu.Unlock()
+
+	close(s.stopChan)
+	s.wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.flushUsage(ctx)
+}
+
+// Lookup implements middleware.KeyStore
+func (s *Store) Lookup(ctx context.Context, rawKey string) (*middleware.Principal, error) {
+	if !strings.HasPrefix(rawKey, keyPrefix) {
+		return nil, nil
+	}
+
+	hash := HashKey(rawKey)
+	now := time.Now()
+
+	s.mu.RLock()
+	cached, ok := s.cache[hash]
+	negUntil, neg := s.negative[hash]
+	s.mu.RUnlock()
+
+	if ok && now.Before(cached.expiresAt) {
+		if cached.keyExpiry != nil && !cached.keyExpiry.After(now) {
+			return nil, nil
+		}
+		return cached.principal, nil
+	}
+	if neg && now.Before(negUntil) {
+		return nil, nil
+	}
+
+	key, err := s.repo.GetByHash(ctx, hash)
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		s.rememberInvalid(hash, now)
+		return nil, nil
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to look up API key")
+		return nil, err
+	}
+
+	if key.ComputeStatus(now) != models.APIKeyStatusActive {
+		s.rememberInvalid(hash, now)
+		return nil, nil
+	}
+
+	principal := &middleware.Principal{
+		KeyID:      key.ID,
+		Name:       key.Name,
+		Owner:      key.Owner,
+		Scopes:     key.Scopes,
+		DailyQuota: key.DailyQuota,
+		RateTier:   key.RateTier,
+	}
+
+	s.mu.Lock()
+	s.cache[hash] = cachedPrincipal{principal: principal, expiresAt: now.Add(cacheTTL), keyExpiry: key.ExpiresAt}
+	s.mu.Unlock()
+
+	return principal, nil
+}
+
+func (s *Store) rememberInvalid(hash string, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.negative) >= maxNegativeCache {
+		s.negative = make(map[string]time.Time)
+	}
+	s.negative[hash] = now.Add(negativeCacheTTL)
+	delete(s.cache, hash)
+}
+
+// Touch implements middleware.KeyStore; usage is written in batches
+func (s *Store) Touch(keyID int64, ip string) {
+	s.usageMu.Lock()
+	s.usage[keyID] = repository.KeyUsage{At: time.Now(), IP: ip}
+	s.usageMu.Unlock()
+}
+
+func (s *Store) flushUsage(ctx context.Context) {
+	s.usageMu.Lock()
+	pending := s.usage
+	s.usage = make(map[int64]repository.KeyUsage)
+	s.usageMu.Unlock()
+
+	if err := s.repo.RecordUsage(ctx, pending); err != nil {
+		s.logger.WithError(err).Warnf("Failed to record usage for %d API keys", len(pending))
+	}
+}
+
+// invalidate drops all cached principals so changes take effect immediately on this instance
+func (s *Store) invalidate() {
+	s.mu.Lock()
+	s.cache = make(map[string]cachedPrincipal)
+	s.mu.Unlock()
+}
+
+// Issue creates a new key. The raw key is only returned here.
+func (s *Store) Issue(ctx context.Context, req models.APIKeyCreate, createdBy string) (*models.IssuedAPIKey, error) {
+	key, err := buildKey(req, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, prefix, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	key.Prefix = prefix
+
+	created, err := s.repo.Create(ctx, key, HashKey(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("🔑 Issued API key %s (%s) for %s with scopes %v", created.Prefix, created.Name, created.Owner, created.Scopes)
+	return &models.IssuedAPIKey{Key: raw, APIKey: created}, nil
+}
+
+// Rotate issues a replacement with the same name, owner, scopes, quota and rate tier.
+// The old key keeps working for the grace period (0 revokes it immediately).
+func (s *Store) Rotate(ctx context.Context, id int64, grace time.Duration, createdBy string) (*models.IssuedAPIKey, error) {
+	old, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if old.Status == models.APIKeyStatusRevoked {
+		return nil, fmt.Errorf("%w: key %d is revoked", ErrInvalidKeyReq, id)
+	}
+
+	raw, prefix, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	replacement := &models.APIKey{
+		Prefix:      prefix,
+		Name:        old.Name,
+		Owner:       old.Owner,
+		Scopes:      old.Scopes,
+		DailyQuota:  old.DailyQuota,
+		RateTier:    old.RateTier,
+		RotatedFrom: &old.ID,
+	}
+	if createdBy != "" {
+		replacement.CreatedBy = &createdBy
+	}
+	// Keep the original lifetime for keys that were issued with an expiry
+	if old.ExpiresAt != nil {
+		lifetime := old.ExpiresAt.Sub(old.CreatedAt)
+		expires := time.Now().Add(lifetime)
+		replacement.ExpiresAt = &expires
+	}
+
+	var oldExpiresAt *time.Time
+	if grace > 0 {
+		t := time.Now().Add(grace)
+		oldExpiresAt = &t
+	}
+
+	created, err := s.repo.Rotate(ctx, old.ID, replacement, HashKey(raw), oldExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.logger.Infof("🔄 Rotated API key %s -> %s (%s), grace period %v", old.Prefix, created.Prefix, created.Name, grace)
+	return &models.IssuedAPIKey{Key: raw, APIKey: created}, nil
+}
+
+// Revoke disables a key immediately on this instance (within cacheTTL on others)
+func (s *Store) Revoke(ctx context.Context, id int64, reason string) (*models.APIKey, error) {
+	if err := s.repo.Revoke(ctx, id, reason); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Infof("🚫 Revoked API key %s (%s): %s", key.Prefix, key.Name, reason)
+	return key, nil
+}
+
+// List returns issued keys
+func (s *Store) List(ctx context.Context, owner string, includeRevoked bool) ([]models.APIKey, error) {
+	return s.repo.List(ctx, owner, includeRevoked)
+}
+
+// Get returns a single key
+func (s *Store) Get(ctx context.Context, id int64) (*models.APIKey, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// buildKey validates an issue request
+func buildKey(req models.APIKeyCreate, createdBy string) (*models.APIKey, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Owner = strings.TrimSpace(req.Owner)
+	if req.Name == "" || len(req.Name) > 100 {
+		return nil, fmt.Errorf("%w: name is required (max 100 characters)", ErrInvalidKeyReq)
+	}
+	if req.Owner == "" || len(req.Owner) > 200 {
+		return nil, fmt.Errorf("%w: owner is required (max 200 characters)", ErrInvalidKeyReq)
+	}
+	if req.DailyQuota < 0 {
+		return nil, fmt.Errorf("%w: daily_quota must be >= 0", ErrInvalidKeyReq)
+	}
+
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[string]bool)
+	for _, scope := range req.Scopes {
+		scope = strings.TrimSpace(scope)
+		if !middleware.IsValidScope(scope) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		scopes = []string{middleware.ScopeReadArticles}
+	}
+
+	req.RateTier = strings.TrimSpace(req.RateTier)
+	if req.RateTier == "" {
+		req.RateTier = middleware.TierStandard
+	}
+	if !middleware.IsValidRateTier(req.RateTier) {
+		return nil, fmt.Errorf("%w: unknown rate_tier %q", ErrInvalidKeyReq, req.RateTier)
+	}
+
+	key := &models.APIKey{
+		Name:       req.Name,
+		Owner:      req.Owner,
+		Scopes:     scopes,
+		DailyQuota: req.DailyQuota,
+		RateTier:   req.RateTier,
+		ExpiresAt:  req.ExpiresAt,
+	}
+	if req.ExpiresInDays > 0 {
+		expires := time.Now().Add(time.Duration(req.ExpiresInDays) * 24 * time.Hour)
+		key.ExpiresAt = &expires
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expiry must be in the future", ErrInvalidKeyReq)
+	}
+	if createdBy != "" {
+		key.CreatedBy = &createdBy
+	}
+
+	return key, nil
+}
+
+// GenerateKey returns a new raw key ("inn_<prefix>_<secret>") and its non-secret prefix
+func GenerateKey() (raw, prefix string, err error) {
+	id := make([]byte, 4)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	prefix = keyPrefix + hex.EncodeToString(id)
+	raw = prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return raw, prefix, nil
+}
+
+// HashKey returns the hex SHA-256 of a raw key. Keys carry 256 bits of entropy,
+// so a fast hash is sufficient.
+func HashKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
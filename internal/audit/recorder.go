@@ -0,0 +1,117 @@
+// Package audit records administrative operations in the append-only audit log.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+	"github.com/jeffrey/intellinieuws/pkg/middleware"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionConfigProfileSwitch = "config.profile.switch"
+	ActionConfigSettingUpdate = "config.setting.update"
+	ActionConfigReset         = "config.reset"
+	ActionServerRestart       = "server.restart"
+	ActionCacheInvalidate     = "cache.invalidate"
+	ActionScrapeTrigger       = "scrape.trigger"
+	ActionAPIKeyIssue         = "api_key.issue"
+	ActionAPIKeyRotate        = "api_key.rotate"
+	ActionAPIKeyRevoke        = "api_key.revoke"
+	ActionWebhookCreate       = "webhook.create"
+	ActionWebhookUpdate       = "webhook.update"
+	ActionWebhookDelete       = "webhook.delete"
+)
+
+// writeTimeout bounds how long a request waits for its audit entry to be stored
+const writeTimeout = 5 * time.Second
+
+// Recorder writes audit entries for HTTP requests
+type Recorder struct {
+	repo   *repository.AuditRepository
+	logger *logger.Logger
+}
+
+// NewRecorder creates a new audit recorder
+func NewRecorder(repo *repository.AuditRepository, log *logger.Logger) *Recorder {
+	return &Recorder{
+		repo:   repo,
+		logger: log.WithComponent("audit"),
+	}
+}
+
+// Record stores who performed an action, with the state before and after it.
+// opErr marks the entry as failed. Failures to write the entry are logged, never returned,
+// so auditing cannot break the operation itself.
+func (r *Recorder) Record(c *fiber.Ctx, action, resource string, before, after interface{}, opErr error) {
+	entry := &models.AuditEntry{
+		Action:     action,
+		Resource:   resource,
+		Before:     marshal(before),
+		After:      marshal(after),
+		Status:     models.AuditStatusSuccess,
+		IPAddress:  c.IP(),
+		UserAgent:  c.Get(fiber.HeaderUserAgent),
+		HTTPMethod: c.Method(),
+		Path:       c.Path(),
+	}
+	if requestID, ok := c.Locals("requestid").(string); ok {
+		entry.RequestID = requestID
+	}
+	if opErr != nil {
+		entry.Status = models.AuditStatusFailure
+		entry.ErrorMessage = opErr.Error()
+	}
+	setActor(entry, middleware.PrincipalFromContext(c))
+
+	// The request context is recycled once the handler returns, so use a detached one
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	if err := r.repo.Insert(ctx, entry); err != nil {
+		r.logger.WithError(err).Errorf("Failed to record audit entry %s (%s) by %s", action, resource, entry.ActorName)
+		return
+	}
+	r.logger.Infof("📝 Audit: %s %s by %s %s (%s)", action, resource, entry.ActorType, entry.ActorName, entry.Status)
+}
+
+// List queries the audit log
+func (r *Recorder) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, int, error) {
+	return r.repo.List(ctx, filter)
+}
+
+func setActor(entry *models.AuditEntry, p *middleware.Principal) {
+	switch {
+	case p == nil:
+		entry.ActorType = models.AuditActorAnonymous
+	case p.Legacy:
+		entry.ActorType = models.AuditActorSharedKey
+		entry.ActorName = p.Name
+	case p.IsUser():
+		entry.ActorType = models.AuditActorUser
+		entry.ActorID = p.UserID
+		entry.ActorName = p.Name
+	default:
+		entry.ActorType = models.AuditActorAPIKey
+		entry.ActorID = fmt.Sprintf("%d", p.KeyID)
+		entry.ActorName = p.Name
+	}
+}
+
+func marshal(value interface{}) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/jeffrey/intellinieuws/internal/metrics"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -32,18 +34,23 @@ func (s *Service) Get(ctx context.Context, key string, dest interface{}) error {
 		return fmt.Errorf("cache not available")
 	}
 
+	prefix := keyPrefix(key)
 	val, err := s.client.Get(ctx, key).Result()
 	if err == redis.Nil {
+		metrics.ObserveCacheLookup(prefix, metrics.CacheMiss)
 		return fmt.Errorf("cache miss")
 	}
 	if err != nil {
+		metrics.ObserveCacheLookup(prefix, metrics.CacheError)
 		return fmt.Errorf("cache error: %w", err)
 	}
 
 	if err := json.Unmarshal([]byte(val), dest); err != nil {
+		metrics.ObserveCacheLookup(prefix, metrics.CacheError)
 		return fmt.Errorf("failed to unmarshal cached value: %w", err)
 	}
 
+	metrics.ObserveCacheLookup(prefix, metrics.CacheHit)
 	return nil
 }
 
@@ -122,6 +129,20 @@ func GenerateKey(prefix string, parts ...string) string {
 	return key
 }
 
+// keyPrefix returns the known prefix a key was generated with, or its first segment.
+// Metrics are labelled by prefix so per-key parts never become label values.
+func keyPrefix(key string) string {
+	for _, prefix := range knownPrefixes {
+		if key == prefix || strings.HasPrefix(key, prefix+":") {
+			return prefix
+		}
+	}
+	if i := strings.IndexByte(key, ':'); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
 // Cache key prefixes
 const (
 	PrefixArticle      = "article"
@@ -133,4 +154,13 @@ const (
 	PrefixAISentiment  = "ai:sentiment"
 	PrefixAIEntity     = "ai:entity"
 	PrefixAIEnrichment = "ai:enrichment"
+	PrefixFeeds        = "feeds"
+	PrefixAnalytics    = "analytics"
 )
+
+// knownPrefixes lists multi-segment prefixes before the single-segment ones they start with
+var knownPrefixes = []string{
+	PrefixAITrending, PrefixAISentiment, PrefixAIEntity, PrefixAIEnrichment,
+	PrefixArticle, PrefixArticles, PrefixStats, PrefixSources, PrefixScraperInfo, PrefixFeeds,
+	PrefixAnalytics,
+}
@@ -8,9 +8,12 @@ import (
 	"time"
 
 	"github.com/jeffrey/intellinieuws/internal/ai"
+	"github.com/jeffrey/intellinieuws/internal/metrics"
 	"github.com/jeffrey/intellinieuws/internal/models"
 	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/internal/tracing"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Processor handles email fetching and processing
@@ -66,6 +69,7 @@ func (p *Processor) Start(ctx context.Context) {
 	}
 	p.running = true
 	p.ticker = time.NewTicker(p.config.PollInterval)
+	p.stopChan = make(chan struct{})
 	p.mu.Unlock()
 
 	p.logger.Infof("Starting email processor with interval: %v", p.config.PollInterval)
@@ -73,7 +77,9 @@ func (p *Processor) Start(ctx context.Context) {
 	// Fetch existing emails on startup if configured
 	if p.emailService.config.FetchExisting {
 		p.logger.Info("Fetching existing emails on startup...")
+		p.wg.Add(1)
 		go func() {
+			defer p.wg.Done()
 			fetchCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 			defer cancel()
 
@@ -85,12 +91,12 @@ func (p *Processor) Start(ctx context.Context) {
 		}()
 	}
 
-	// Run initial fetch of new emails
-	go p.processCycle(ctx)
-
 	p.wg.Add(1)
 	go func() {
 		defer p.wg.Done()
+		// Run initial fetch of new emails
+		p.processCycle(ctx)
+
 		for {
 			select {
 			case <-p.ticker.C:
@@ -111,9 +117,15 @@ func (p *Processor) processCycle(ctx context.Context) {
 	p.logger.Info("Starting email processing cycle")
 	startTime := time.Now()
 
+	ctx, span := tracing.Start(ctx, "email.process_cycle")
+	defer span.End()
+
 	// Fetch new emails
 	emails, err := p.emailService.FetchNewEmails(ctx)
+	metrics.ObserveIMAPPoll(time.Since(startTime), len(emails), err)
+	span.SetAttributes(attribute.Int("email.fetched", len(emails)))
 	if err != nil {
+		tracing.RecordError(span, err)
 		p.logger.WithError(err).Error("Failed to fetch emails")
 		return
 	}
@@ -174,11 +186,15 @@ func (p *Processor) processCycle(ctx context.Context) {
 
 // processEmailToArticle converts an email into an article
 func (p *Processor) processEmailToArticle(ctx context.Context, email *models.Email) error {
-	p.logger.Infof("Processing email %d into article: %s", email.ID, email.Subject)
+	// The email ID correlates this email's logs, including those of its AI run
+	log := logger.FromContext(ctx, p.logger).WithCorrelationID(fmt.Sprintf("email-%d", email.ID))
+	ctx = logger.NewContext(ctx, log)
+
+	log.Infof("Processing email %d into article: %s", email.ID, email.Subject)
 
 	// Mark email as processing
 	if err := p.updateEmailStatus(ctx, email.ID, models.EmailStatusProcessing); err != nil {
-		p.logger.WithError(err).Warn("Failed to mark email as processing")
+		log.WithError(err).Warn("Failed to mark email as processing")
 	}
 
 	// Extract content (prefer text over HTML for now)
@@ -216,25 +232,40 @@ func (p *Processor) processEmailToArticle(ctx context.Context, email *models.Ema
 		return fmt.Errorf("failed to create article: %w", err)
 	}
 
-	p.logger.Infof("Created article %d from email %d", storedArticle.ID, email.ID)
+	log.Infof("Created article %d from email %d", storedArticle.ID, email.ID)
 
 	// Mark email as processed
 	articleID := storedArticle.ID
 	if err := p.emailRepo.MarkAsProcessed(ctx, email.ID, &articleID); err != nil {
-		p.logger.WithError(err).Warn("Failed to mark email as processed")
+		log.WithError(err).Warn("Failed to mark email as processed")
+	}
+
+	// Newsletters from unknown senders are a prompt-injection vector: scan them on ingest
+	// and skip the immediate AI run for suspicious ones. The background processor still
+	// enriches them later, with the content delimited and the detection flagged.
+	if !p.emailService.IsKnownSender(email.Sender) {
+		scan := ai.ScanForInjection(email.Subject + "\n" + content)
+		ai.RecordInjectionScan(log, ai.OriginUnknownSender, email.Sender, scan)
+		if scan.Suspicious {
+			log.Warnf("Suspicious email from unknown sender %s stored as article %d without immediate AI processing",
+				email.Sender, storedArticle.ID)
+			return nil
+		}
 	}
 
 	// Process with AI if enabled
 	if p.config.UseAI && p.aiService != nil {
 		go func() {
-			aiCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			// Keep the logger and trace of ctx, but not its cancellation: the cycle
+			// finishes before the AI run does
+			aiCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Minute)
 			defer cancel()
 
 			_, err := p.aiService.ProcessArticle(aiCtx, storedArticle.ID)
 			if err != nil {
-				p.logger.WithError(err).Warnf("Failed to AI process article %d", storedArticle.ID)
+				log.WithError(err).Warnf("Failed to AI process article %d", storedArticle.ID)
 			} else {
-				p.logger.Infof("Successfully AI processed article %d", storedArticle.ID)
+				log.Infof("Successfully AI processed article %d", storedArticle.ID)
 			}
 		}()
 	}
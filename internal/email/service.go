@@ -338,6 +338,15 @@ func (s *Service) isAllowedSender(sender string) bool {
 	return false
 }
 
+// IsKnownSender reports whether a sender is explicitly listed in the allowed senders.
+// With an empty allow list every sender is accepted but none is known.
+func (s *Service) IsKnownSender(sender string) bool {
+	if len(s.config.AllowedSenders) == 0 {
+		return false
+	}
+	return s.isAllowedSender(sender)
+}
+
 // markAsRead marks a message as read
 func (s *Service) markAsRead(client *imapclient.Client, seqNum uint32) error {
 	seqSet := imap.SeqSetNum(seqNum)
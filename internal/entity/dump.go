@@ -0,0 +1,187 @@
+package entity
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+)
+
+// DumpRecord is a single entity in a local Wikidata-style dump.
+// Both JSON Lines and a top-level JSON array are accepted.
+//
+//	{"id":"Q57792","label":"Mark Rutte","type":"person","aliases":["Rutte","premier Rutte"]}
+type DumpRecord struct {
+	ID          string   `json:"id"`
+	Label       string   `json:"label"`
+	Name        string   `json:"name"` // alternative to label
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Aliases     []string `json:"aliases"`
+}
+
+// ImportResult summarizes a dump import
+type ImportResult struct {
+	Entities int `json:"entities"`
+	Aliases  int `json:"aliases"`
+	Merged   int `json:"merged"`
+	Skipped  int `json:"skipped"`
+}
+
+// ImportDump loads a local entity dump file into the registry.
+// Auto-created entities that share an alias with a dump entity are merged into it.
+func (r *Resolver) ImportDump(ctx context.Context, path string) (*ImportResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open entity dump: %w", err)
+	}
+	defer f.Close()
+
+	records, err := decodeDump(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse entity dump %s: %w", path, err)
+	}
+
+	// Make sure auto entities created since startup are visible for merging
+	if err := r.Load(ctx); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	for _, rec := range records {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if err := r.importRecord(ctx, rec, result); err != nil {
+			r.logger.WithError(err).Warnf("Skipping dump record %s", rec.ID)
+			result.Skipped++
+		}
+	}
+
+	if err := r.Load(ctx); err != nil {
+		return result, err
+	}
+
+	r.logger.Infof("Imported entity dump %s: %d entities, %d aliases, %d merged, %d skipped",
+		path, result.Entities, result.Aliases, result.Merged, result.Skipped)
+	return result, nil
+}
+
+func (r *Resolver) importRecord(ctx context.Context, rec DumpRecord, result *ImportResult) error {
+	name := CleanName(rec.Label)
+	if name == "" {
+		name = CleanName(rec.Name)
+	}
+	entityType := NormalizeType(rec.Type)
+	if rec.ID == "" || name == "" || entityType == "" {
+		return fmt.Errorf("record requires id, label and a known type")
+	}
+
+	entity := &models.Entity{
+		CanonicalName: name,
+		EntityType:    entityType,
+		ExternalID:    &rec.ID,
+		Origin:        models.EntityOriginDump,
+	}
+	if rec.Description != "" {
+		entity.Description = &rec.Description
+	}
+
+	id, err := r.repo.UpsertExternal(ctx, entity)
+	if err != nil {
+		return err
+	}
+	result.Entities++
+
+	seen := make(map[string]bool)
+	toMerge := make([]int64, 0)
+	merging := make(map[int64]bool)
+	for _, alias := range append([]string{name}, rec.Aliases...) {
+		display := CleanName(alias)
+		normalized := Normalize(display, entityType)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+
+		// Only auto-created owners are taken over; manual and other dump entities win
+		override := true
+		if entry, method, ok := r.lookup(entityType, normalized); ok && method == "exact" && entry.entityID != id {
+			if entry.origin == models.EntityOriginAuto {
+				if !merging[entry.entityID] {
+					merging[entry.entityID] = true
+					toMerge = append(toMerge, entry.entityID)
+				}
+			} else {
+				override = false
+			}
+		}
+
+		input := repository.AliasInput{Alias: display, Normalized: normalized}
+		if err := r.repo.AddAlias(ctx, id, entityType, input, models.EntityOriginDump, override); err != nil {
+			return err
+		}
+		result.Aliases++
+	}
+
+	if len(toMerge) > 0 {
+		if err := r.repo.Merge(ctx, id, toMerge); err != nil {
+			return err
+		}
+		result.Merged += len(toMerge)
+	}
+
+	return nil
+}
+
+func decodeDump(reader io.Reader) ([]DumpRecord, error) {
+	br := bufio.NewReader(reader)
+
+	// Peek at the first non-space byte to pick the format
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			if err == io.EOF {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			break
+		}
+		br.ReadByte()
+	}
+
+	if b, _ := br.Peek(1); b[0] == '[' {
+		var records []DumpRecord
+		if err := json.NewDecoder(br).Decode(&records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	}
+
+	records := make([]DumpRecord, 0)
+	scanner := bufio.NewScanner(br)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var rec DumpRecord
+		if err := json.Unmarshal(text, &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+
+	return records, scanner.Err()
+}
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// personTitles are leading tokens dropped from person names ("premier Rutte" -> "rutte")
+var personTitles = map[string]bool{
+	"premier": true, "minister": true, "president": true, "staatssecretaris": true,
+	"burgemeester": true, "wethouder": true, "kamerlid": true, "fractievoorzitter": true,
+	"koning": true, "koningin": true, "prins": true, "prinses": true, "paus": true,
+	"bondskanselier": true, "topman": true, "topvrouw": true, "ceo": true, "oud": true,
+	"mevrouw": true, "mevr": true, "dhr": true, "mw": true,
+	"dr": true, "drs": true, "ir": true, "mr": true, "prof": true, "sir": true,
+	"mrs": true, "ms": true,
+}
+
+// organizationSuffixes are trailing legal-form tokens dropped from organization names
+var organizationSuffixes = map[string]bool{
+	"nv": true, "bv": true, "vof": true, "inc": true, "ltd": true, "plc": true,
+	"ag": true, "se": true, "sa": true, "llc": true, "corp": true, "corporation": true,
+	"co": true, "holding": true, "holdings": true, "group": true, "groep": true,
+}
+
+var accentFolder = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// NormalizeType maps AI entity keys ("persons") and singular forms to the registry type
+func NormalizeType(entityType string) string {
+	switch strings.ToLower(strings.TrimSpace(entityType)) {
+	case "person", "persons", "people", "per":
+		return models.EntityTypePerson
+	case "organization", "organizations", "organisation", "organisations", "org":
+		return models.EntityTypeOrganization
+	case "location", "locations", "place", "places", "loc":
+		return models.EntityTypeLocation
+	default:
+		return ""
+	}
+}
+
+// CleanName trims and collapses whitespace for display
+func CleanName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// Normalize returns the lookup key for a surface form: accents folded, lowercase,
+// punctuation removed, and titles (persons) or legal suffixes (organizations) stripped.
+func Normalize(name, entityType string) string {
+	folded, _, err := transform.String(accentFolder, name)
+	if err != nil {
+		folded = name
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(folded) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '.' || r == '\'' || r == '’':
+			// "N.V." -> "nv", "D'66" -> "d66"
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	tokens := strings.Fields(b.String())
+
+	switch entityType {
+	case models.EntityTypePerson:
+		for len(tokens) > 1 {
+			if len(tokens) > 2 && tokens[0] == "de" && tokens[1] == "heer" {
+				tokens = tokens[2:]
+				continue
+			}
+			if !personTitles[tokens[0]] {
+				break
+			}
+			tokens = tokens[1:]
+		}
+	case models.EntityTypeOrganization:
+		for len(tokens) > 1 && organizationSuffixes[tokens[len(tokens)-1]] {
+			tokens = tokens[:len(tokens)-1]
+		}
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+// lastToken returns the final token of a normalized name (the surname for persons)
+func lastToken(normalized string) string {
+	if i := strings.LastIndexByte(normalized, ' '); i >= 0 {
+		return normalized[i+1:]
+	}
+	return normalized
+}
@@ -0,0 +1,461 @@
+package entity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// indexReloadInterval bounds how stale the in-memory alias index may get
+// when other replicas create or merge entities
+const indexReloadInterval = 10 * time.Minute
+
+// indexEntry is the in-memory view of a resolved alias
+type indexEntry struct {
+	entityID      int64
+	canonicalName string
+	origin        string
+}
+
+// Resolver maps extracted entity mentions to canonical entity IDs
+type Resolver struct {
+	repo       *repository.EntityRepository
+	logger     *logger.Logger
+	autoCreate bool
+
+	mu       sync.RWMutex
+	aliases  map[string]map[string]indexEntry // type -> normalized alias -> entity
+	lastLoad time.Time
+
+	createMu sync.Mutex
+}
+
+// Resolution is the result of resolving a single mention
+type Resolution struct {
+	Mention       string `json:"mention"`
+	EntityType    string `json:"entity_type"`
+	EntityID      int64  `json:"entity_id"`
+	CanonicalName string `json:"canonical_name"`
+	Method        string `json:"method"` // exact, surname, created
+}
+
+// BackfillResult summarizes a backfill run
+type BackfillResult struct {
+	ArticlesProcessed int   `json:"articles_processed"`
+	MentionsResolved  int   `json:"mentions_resolved"`
+	Failed            int   `json:"failed"`
+	LastArticleID     int64 `json:"last_article_id"`
+	DurationMs        int64 `json:"duration_ms"`
+}
+
+// NewResolver creates a new entity resolver.
+// When autoCreate is false, unknown mentions are left unresolved instead of creating new entities.
+func NewResolver(repo *repository.EntityRepository, autoCreate bool, log *logger.Logger) *Resolver {
+	return &Resolver{
+		repo:       repo,
+		logger:     log.WithComponent("entity-resolver"),
+		autoCreate: autoCreate,
+		aliases:    make(map[string]map[string]indexEntry),
+	}
+}
+
+// Load (re)builds the in-memory alias index from the database
+func (r *Resolver) Load(ctx context.Context) error {
+	entries, err := r.repo.LoadAliasIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	aliases := make(map[string]map[string]indexEntry)
+	for _, e := range entries {
+		if aliases[e.EntityType] == nil {
+			aliases[e.EntityType] = make(map[string]indexEntry)
+		}
+		aliases[e.EntityType][e.AliasNormalized] = indexEntry{
+			entityID:      e.EntityID,
+			canonicalName: e.CanonicalName,
+			origin:        e.Origin,
+		}
+	}
+
+	r.mu.Lock()
+	r.aliases = aliases
+	r.lastLoad = time.Now()
+	r.mu.Unlock()
+
+	r.logger.Infof("Loaded entity alias index: %d aliases", len(entries))
+	return nil
+}
+
+func (r *Resolver) ensureFresh(ctx context.Context) {
+	r.mu.RLock()
+	stale := time.Since(r.lastLoad) > indexReloadInterval
+	r.mu.RUnlock()
+
+	if stale {
+		if err := r.Load(ctx); err != nil {
+			r.logger.WithError(err).Warn("Failed to reload entity alias index")
+		}
+	}
+}
+
+func (r *Resolver) remember(entityType, normalized string, entry indexEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.aliases[entityType] == nil {
+		r.aliases[entityType] = make(map[string]indexEntry)
+	}
+	r.aliases[entityType][normalized] = entry
+}
+
+// lookup resolves a normalized alias from the index. A bare surname of a person falls back
+// to the one known person with that surname; full names only match exactly, so two people
+// sharing a surname are never linked.
+func (r *Resolver) lookup(entityType, normalized string) (indexEntry, string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byAlias := r.aliases[entityType]
+	if entry, ok := byAlias[normalized]; ok {
+		return entry, "exact", true
+	}
+
+	if entityType != models.EntityTypePerson {
+		return indexEntry{}, "", false
+	}
+
+	surname := lastToken(normalized)
+	if surname != normalized {
+		return indexEntry{}, "", false
+	}
+
+	// "rutte" -> exactly one known person whose name ends in "rutte"
+	var match indexEntry
+	found := false
+	for alias, entry := range byAlias {
+		if lastToken(alias) != surname {
+			continue
+		}
+		if found && entry.entityID != match.entityID {
+			return indexEntry{}, "", false // ambiguous
+		}
+		match, found = entry, true
+	}
+
+	return match, "surname", found
+}
+
+// surnameEntity returns the entity known by the bare surname of a multi-token person name
+func (r *Resolver) surnameEntity(entityType, normalized string) (indexEntry, bool) {
+	if entityType != models.EntityTypePerson {
+		return indexEntry{}, false
+	}
+	surname := lastToken(normalized)
+	if surname == normalized {
+		return indexEntry{}, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.aliases[entityType][surname]
+	return entry, ok
+}
+
+// isBareSurname reports whether entry is an auto-created person still named only by the
+// surname of normalized, so a full name may be attached to it
+func isBareSurname(entry indexEntry, normalized string) bool {
+	return entry.origin == models.EntityOriginAuto &&
+		Normalize(entry.canonicalName, models.EntityTypePerson) == lastToken(normalized)
+}
+
+// Lookup returns the canonical entity ID for a name without creating anything
+func (r *Resolver) Lookup(ctx context.Context, name, entityType string) (int64, bool) {
+	r.ensureFresh(ctx)
+
+	types := []string{NormalizeType(entityType)}
+	if types[0] == "" {
+		types = []string{models.EntityTypePerson, models.EntityTypeOrganization, models.EntityTypeLocation}
+	}
+
+	for _, t := range types {
+		if entry, _, ok := r.lookup(t, Normalize(name, t)); ok {
+			return entry.entityID, true
+		}
+	}
+
+	return 0, false
+}
+
+// Resolve maps a single mention to its canonical entity, creating one if allowed
+func (r *Resolver) Resolve(ctx context.Context, mention, entityType string) (*Resolution, error) {
+	entityType = NormalizeType(entityType)
+	if entityType == "" {
+		return nil, fmt.Errorf("unknown entity type")
+	}
+
+	display := CleanName(mention)
+	normalized := Normalize(display, entityType)
+	if normalized == "" {
+		return nil, nil
+	}
+
+	// A surname match is not learned as an alias: the next person with that surname
+	// would be bound to this entity for good
+	if entry, method, ok := r.lookup(entityType, normalized); ok {
+		return &Resolution{display, entityType, entry.entityID, entry.canonicalName, method}, nil
+	}
+
+	if !r.autoCreate {
+		return nil, nil
+	}
+
+	// Serialize creation so concurrent workers don't race on the same new alias
+	r.createMu.Lock()
+	defer r.createMu.Unlock()
+
+	if entry, method, ok := r.lookup(entityType, normalized); ok {
+		return &Resolution{display, entityType, entry.entityID, entry.canonicalName, method}, nil
+	}
+
+	alias := repository.AliasInput{Alias: display, Normalized: normalized}
+
+	// "Rutte" may have been created before "Mark Rutte" arrives: the full name joins that
+	// entity instead of splitting the person in two
+	surnameEntry, hasSurname := r.surnameEntity(entityType, normalized)
+	if hasSurname && isBareSurname(surnameEntry, normalized) {
+		adopted, err := r.repo.AdoptFullName(ctx, surnameEntry.entityID, entityType, alias)
+		if err != nil {
+			return nil, err
+		}
+		if adopted {
+			entry := indexEntry{entityID: surnameEntry.entityID, canonicalName: display, origin: surnameEntry.origin}
+			r.remember(entityType, normalized, entry)
+			r.remember(entityType, lastToken(normalized), entry)
+			return &Resolution{display, entityType, entry.entityID, display, "surname"}, nil
+		}
+	}
+
+	id, created, err := r.repo.CreateEntity(ctx, &models.Entity{
+		CanonicalName: display,
+		EntityType:    entityType,
+		Origin:        models.EntityOriginAuto,
+	}, []repository.AliasInput{alias})
+	if err != nil {
+		return nil, err
+	}
+
+	entry := indexEntry{entityID: id, canonicalName: display, origin: models.EntityOriginAuto}
+	r.remember(entityType, normalized, entry)
+
+	// The surname belongs to an entity with another full name: leave it to a reviewer
+	if created && hasSurname && surnameEntry.entityID != id {
+		if err := r.repo.QueueMergeCandidate(ctx, id, surnameEntry.entityID, "surname"); err != nil {
+			r.logger.WithError(err).Warnf("Failed to queue %q for merge review", display)
+		}
+	}
+
+	method := "exact"
+	if created {
+		method = "created"
+	}
+	return &Resolution{display, entityType, id, display, method}, nil
+}
+
+// ResolveArticleEntities resolves all mentions of an article and stores the links.
+// Implements ai.EntityResolver.
+func (r *Resolver) ResolveArticleEntities(ctx context.Context, articleID int64, persons, organizations, locations []string) error {
+	r.ensureFresh(ctx)
+
+	links := make([]repository.ArticleEntityLink, 0, len(persons)+len(organizations)+len(locations))
+	groups := map[string][]string{
+		models.EntityTypePerson:       persons,
+		models.EntityTypeOrganization: organizations,
+		models.EntityTypeLocation:     locations,
+	}
+
+	for entityType, mentions := range groups {
+		for _, mention := range mentions {
+			res, err := r.Resolve(ctx, mention, entityType)
+			if err != nil {
+				r.logger.WithError(err).Warnf("Failed to resolve %s %q for article %d", entityType, mention, articleID)
+				continue
+			}
+			if res == nil {
+				continue
+			}
+			links = append(links, repository.ArticleEntityLink{EntityID: res.EntityID, Mention: res.Mention})
+		}
+	}
+
+	return r.repo.ReplaceArticleLinks(ctx, articleID, links)
+}
+
+// Merge folds source entities into the target and reloads the index
+func (r *Resolver) Merge(ctx context.Context, targetID int64, sourceIDs []int64) error {
+	filtered := make([]int64, 0, len(sourceIDs))
+	for _, id := range sourceIDs {
+		if id != targetID {
+			filtered = append(filtered, id)
+		}
+	}
+	if len(filtered) == 0 {
+		return fmt.Errorf("no source entities to merge")
+	}
+
+	if err := r.repo.Merge(ctx, targetID, filtered); err != nil {
+		return err
+	}
+
+	return r.Load(ctx)
+}
+
+// Split moves the given aliases (and the article mentions using them) to a new entity
+func (r *Resolver) Split(ctx context.Context, sourceID int64, req models.EntitySplitRequest) (int64, error) {
+	source, err := r.repo.GetByID(ctx, sourceID)
+	if err != nil {
+		return 0, err
+	}
+	if source.MergedInto != nil {
+		return 0, fmt.Errorf("entity %d is merged into %d", sourceID, *source.MergedInto)
+	}
+
+	entityType := source.EntityType
+	if req.EntityType != "" {
+		if entityType = NormalizeType(req.EntityType); entityType == "" {
+			return 0, fmt.Errorf("unknown entity type %q", req.EntityType)
+		}
+	}
+
+	normalized := make(map[string]bool, len(req.Aliases))
+	keys := make([]string, 0, len(req.Aliases))
+	for _, alias := range req.Aliases {
+		n := Normalize(alias, source.EntityType)
+		if n != "" && !normalized[n] {
+			normalized[n] = true
+			keys = append(keys, n)
+		}
+	}
+	if len(keys) == 0 {
+		return 0, fmt.Errorf("at least one alias is required")
+	}
+
+	stored, err := r.repo.GetMentions(ctx, sourceID)
+	if err != nil {
+		return 0, err
+	}
+	moved := make([]repository.ArticleMention, 0)
+	for _, m := range stored {
+		if normalized[Normalize(m.Mention, source.EntityType)] {
+			moved = append(moved, m)
+		}
+	}
+
+	name := CleanName(req.CanonicalName)
+	if name == "" {
+		name = CleanName(req.Aliases[0])
+	}
+
+	entity := &models.Entity{
+		CanonicalName: name,
+		EntityType:    entityType,
+		Origin:        models.EntityOriginManual,
+	}
+	if req.ExternalID != "" {
+		entity.ExternalID = &req.ExternalID
+	}
+
+	newID, err := r.repo.Split(ctx, sourceID, entity, keys, moved)
+	if err != nil {
+		return 0, err
+	}
+
+	return newID, r.Load(ctx)
+}
+
+// Backfill resolves entities for already processed articles, starting after the given article ID
+func (r *Resolver) Backfill(ctx context.Context, afterID int64, batchSize int) (*BackfillResult, error) {
+	start := time.Now()
+	result := &BackfillResult{LastArticleID: afterID}
+
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		rows, err := r.repo.GetProcessedArticleEntities(ctx, result.LastArticleID, batchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			result.LastArticleID = row.ArticleID
+
+			var extracted struct {
+				Persons       []string `json:"persons"`
+				Organizations []string `json:"organizations"`
+				Locations     []string `json:"locations"`
+			}
+			if err := json.Unmarshal(row.Entities, &extracted); err != nil {
+				result.Failed++
+				continue
+			}
+
+			if err := r.ResolveArticleEntities(ctx, row.ArticleID, extracted.Persons, extracted.Organizations, extracted.Locations); err != nil {
+				r.logger.WithError(err).Warnf("Backfill failed for article %d", row.ArticleID)
+				result.Failed++
+				continue
+			}
+
+			result.ArticlesProcessed++
+			result.MentionsResolved += len(extracted.Persons) + len(extracted.Organizations) + len(extracted.Locations)
+		}
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	r.logger.Infof("Entity backfill completed: %d articles, %d mentions, %d failed in %dms",
+		result.ArticlesProcessed, result.MentionsResolved, result.Failed, result.DurationMs)
+
+	return result, nil
+}
+
+// GetEntity returns an entity by ID
+func (r *Resolver) GetEntity(ctx context.Context, id int64) (*models.Entity, error) {
+	return r.repo.GetByID(ctx, id)
+}
+
+// ListEntities returns canonical entities
+func (r *Resolver) ListEntities(ctx context.Context, filter models.EntityFilter) ([]models.Entity, int, error) {
+	if filter.EntityType != "" {
+		filter.EntityType = NormalizeType(filter.EntityType)
+	}
+	return r.repo.List(ctx, filter)
+}
+
+// ListMergeCandidates returns entity pairs queued for merge review
+func (r *Resolver) ListMergeCandidates(ctx context.Context, limit, offset int) ([]models.EntityMergeCandidate, int, error) {
+	return r.repo.ListMergeCandidates(ctx, limit, offset)
+}
+
+// DismissMergeCandidate removes a queued pair that should not be merged
+func (r *Resolver) DismissMergeCandidate(ctx context.Context, id int64) error {
+	return r.repo.DismissMergeCandidate(ctx, id)
+}
+
+// IsNotFound reports whether err means the entity or merge candidate does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, repository.ErrEntityNotFound) || errors.Is(err, repository.ErrMergeCandidateNotFound)
+}
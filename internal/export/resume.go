@@ -0,0 +1,97 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+)
+
+// LastExportedID scans an interrupted NDJSON or CSV export and returns the id of its last
+// complete row, which is the watermark to resume from, and the size of the file up to
+// that row. A trailing partial row should be truncated before appending.
+func LastExportedID(format string, r io.Reader) (int64, int64, error) {
+	switch format {
+	case models.ExportFormatNDJSON:
+		return lastNDJSONID(r)
+	case models.ExportFormatCSV:
+		return lastCSVID(r)
+	}
+	return 0, 0, fmt.Errorf("%s exports cannot be resumed into the same file", format)
+}
+
+func lastNDJSONID(r io.Reader) (int64, int64, error) {
+	reader := bufio.NewReader(r)
+	var lastID, offset, size int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		size += int64(len(line))
+		if err == io.EOF {
+			return lastID, offset, nil // A line without newline is partial
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+
+		var row struct {
+			ID int64 `json:"id"`
+		}
+		if json.Unmarshal(line, &row) != nil {
+			return lastID, offset, nil
+		}
+		lastID, offset = row.ID, size
+	}
+}
+
+func lastCSVID(r io.Reader) (int64, int64, error) {
+	tail := &tailReader{r: r}
+	reader := csv.NewReader(tail)
+	reader.FieldsPerRecord = len(csvHeader)
+
+	var lastID, offset, prevID, prevOffset int64
+	for line := 0; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			// A last record without newline may have been cut inside its final field
+			if tail.last != '\n' && offset == tail.n {
+				return prevID, prevOffset, nil
+			}
+			return lastID, offset, nil
+		}
+		if err != nil {
+			return lastID, offset, nil // Partial last record
+		}
+		prevID, prevOffset = lastID, offset
+		if line == 0 {
+			offset = reader.InputOffset() // Header
+			continue
+		}
+
+		id, err := strconv.ParseInt(record[0], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid id on CSV line %d: %w", line+1, err)
+		}
+		lastID, offset = id, reader.InputOffset()
+	}
+}
+
+// tailReader remembers the size and last byte of everything read
+type tailReader struct {
+	r    io.Reader
+	n    int64
+	last byte
+}
+
+func (t *tailReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if n > 0 {
+		t.n += int64(n)
+		t.last = p[n-1]
+	}
+	return n, err
+}
@@ -0,0 +1,197 @@
+// Package export writes bulk article exports as NDJSON, CSV or Parquet.
+//
+// Writers consume rows one at a time so exports stream from the database cursor to the
+// client or file without holding the result set in memory. Parquet buffers at most one
+// row group before flushing it.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetRowGroupSize bounds the rows a Parquet writer buffers in memory
+const parquetRowGroupSize = 10000
+
+// listSeparator joins list columns in CSV cells
+const listSeparator = "|"
+
+// Writer encodes export rows
+type Writer interface {
+	Write(row *models.ExportRow) error
+	// Flush hands buffered rows to the underlying writer; Parquet keeps filling its row group
+	Flush() error
+	// Close flushes buffered rows and writes any trailer; it does not close the underlying writer
+	Close() error
+}
+
+// NewWriter returns a writer for format (ndjson, csv or parquet)
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case models.ExportFormatNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	case models.ExportFormatCSV:
+		return newCSVWriter(w), nil
+	case models.ExportFormatParquet:
+		return &parquetWriter{w: parquet.NewGenericWriter[models.ExportRow](w,
+			parquet.Compression(&parquet.Zstd),
+			parquet.MaxRowsPerRowGroup(parquetRowGroupSize),
+		)}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format: %s", format)
+}
+
+// NewAppendWriter returns a writer that continues an earlier NDJSON or CSV export; the CSV
+// header is not repeated. Parquet files end with a footer and cannot be appended to.
+func NewAppendWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case models.ExportFormatNDJSON:
+		return NewWriter(format, w)
+	case models.ExportFormatCSV:
+		cw := newCSVWriter(w)
+		cw.headerWritten = true
+		return cw, nil
+	}
+	return nil, fmt.Errorf("%s exports cannot be resumed into the same file", format)
+}
+
+// ContentType returns the MIME type of an export format
+func ContentType(format string) string {
+	switch format {
+	case models.ExportFormatNDJSON:
+		return "application/x-ndjson"
+	case models.ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case models.ExportFormatParquet:
+		return "application/vnd.apache.parquet"
+	}
+	return "application/octet-stream"
+}
+
+// IsValidFormat reports whether format is a supported export format
+func IsValidFormat(format string) bool {
+	for _, f := range models.ExportFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(row *models.ExportRow) error {
+	return w.enc.Encode(row)
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return nil
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+// csvHeader matches the column order of csvWriter.Write
+var csvHeader = []string{
+	"id", "title", "summary", "url", "published", "source", "category", "author", "language",
+	"keywords", "created_at", "ai_processed", "ai_sentiment", "ai_sentiment_label",
+	"ai_categories", "ai_persons", "ai_organizations", "ai_locations", "ai_keywords",
+	"ai_stock_tickers", "ai_summary", "ai_processed_at",
+}
+
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (w *csvWriter) Write(row *models.ExportRow) error {
+	if !w.headerWritten {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	sentiment := ""
+	if row.AISentiment != nil {
+		sentiment = strconv.FormatFloat(*row.AISentiment, 'f', -1, 64)
+	}
+	processedAt := ""
+	if row.AIProcessedAt != nil {
+		processedAt = row.AIProcessedAt.UTC().Format(time.RFC3339)
+	}
+
+	return w.w.Write([]string{
+		strconv.FormatInt(row.ID, 10),
+		row.Title,
+		row.Summary,
+		row.URL,
+		row.Published.UTC().Format(time.RFC3339),
+		row.Source,
+		row.Category,
+		row.Author,
+		row.Language,
+		strings.Join(row.Keywords, listSeparator),
+		row.CreatedAt.UTC().Format(time.RFC3339),
+		strconv.FormatBool(row.AIProcessed),
+		sentiment,
+		row.AISentimentLabel,
+		strings.Join(row.AICategories, listSeparator),
+		strings.Join(row.AIPersons, listSeparator),
+		strings.Join(row.AIOrganizations, listSeparator),
+		strings.Join(row.AILocations, listSeparator),
+		strings.Join(row.AIKeywords, listSeparator),
+		strings.Join(row.AIStockTickers, listSeparator),
+		row.AISummary,
+		processedAt,
+	})
+}
+
+func (w *csvWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Close writes the header of an empty export and flushes buffered records
+func (w *csvWriter) Close() error {
+	if !w.headerWritten {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type parquetWriter struct {
+	w *parquet.GenericWriter[models.ExportRow]
+}
+
+func (w *parquetWriter) Write(row *models.ExportRow) error {
+	_, err := w.w.Write([]models.ExportRow{*row})
+	return err
+}
+
+func (w *parquetWriter) Flush() error {
+	return nil
+}
+
+func (w *parquetWriter) Close() error {
+	return w.w.Close()
+}
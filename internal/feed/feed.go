@@ -0,0 +1,338 @@
+// Package feed renders article queries as RSS 2.0, Atom and JSON Feed documents.
+//
+// AI enrichment is published as extension elements in the ai namespace for RSS and Atom,
+// and as the _intellinieuws extension object in JSON Feed, so feed readers that ignore
+// them still show the plain articles.
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+)
+
+// Namespace is the XML namespace of the ai: extension elements
+const Namespace = "https://github.com/jeffrey/intellinieuws/ns/ai/1.0"
+
+// Feed is a rendered article query
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // Human-readable page for the feed
+	SelfURL     string // URL the feed was requested from
+	Updated     time.Time
+	Items       []models.FeedItem
+}
+
+// ContentType returns the MIME type of a feed format
+func ContentType(format string) string {
+	switch format {
+	case models.FeedFormatRSS:
+		return "application/rss+xml; charset=utf-8"
+	case models.FeedFormatAtom:
+		return "application/atom+xml; charset=utf-8"
+	case models.FeedFormatJSON:
+		return "application/feed+json; charset=utf-8"
+	}
+	return ""
+}
+
+// Render encodes the feed in format (rss, atom or json)
+func Render(format string, f *Feed) ([]byte, error) {
+	switch format {
+	case models.FeedFormatRSS:
+		return renderXML(rssDocument(f))
+	case models.FeedFormatAtom:
+		return renderXML(atomDocument(f))
+	case models.FeedFormatJSON:
+		return json.MarshalIndent(jsonFeedDocument(f), "", "  ")
+	}
+	return nil, fmt.Errorf("unsupported feed format: %s", format)
+}
+
+func renderXML(doc interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// guid is the stable identifier of an article across all feeds
+func guid(item *models.FeedItem) string {
+	return "tag:intellinieuws,2024:article:" + strconv.FormatInt(item.ID, 10)
+}
+
+// aiExtension holds the ai: elements shared by RSS items and Atom entries
+type aiExtension struct {
+	Summary        string `xml:"ai:summary,omitempty"`
+	Sentiment      string `xml:"ai:sentiment,omitempty"`
+	SentimentLabel string `xml:"ai:sentimentLabel,omitempty"`
+}
+
+func newAIExtension(item *models.FeedItem) aiExtension {
+	ext := aiExtension{Summary: item.AISummary, SentimentLabel: item.AISentimentLabel}
+	if item.AISentiment != nil {
+		ext.Sentiment = strconv.FormatFloat(*item.AISentiment, 'f', 3, 64)
+	}
+	return ext
+}
+
+// RSS 2.0
+
+type rssRoot struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	AINS    string     `xml:"xmlns:ai,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      rssLink   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Creator     string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	Source      string   `xml:"dc:publisher,omitempty"`
+	aiExtension
+}
+
+func rssDocument(f *Feed) *rssRoot {
+	channel := rssChannel{
+		Title:       f.Title,
+		Link:        f.Link,
+		Description: f.Description,
+		AtomLink:    rssLink{Href: f.SelfURL, Rel: "self", Type: ContentType(models.FeedFormatRSS)},
+		Generator:   "IntelliNieuws",
+		Items:       make([]rssItem, 0, len(f.Items)),
+	}
+	if !f.Updated.IsZero() {
+		channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for i := range f.Items {
+		item := &f.Items[i]
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			Description: item.Summary,
+			GUID:        rssGUID{Value: guid(item)},
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+			Creator:     item.Author,
+			Source:      item.Source,
+			aiExtension: newAIExtension(item),
+		}
+		if item.Category != "" {
+			entry.Categories = append(entry.Categories, item.Category)
+		}
+		channel.Items = append(channel.Items, entry)
+	}
+
+	return &rssRoot{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		AINS:    Namespace,
+		Channel: channel,
+	}
+}
+
+// Atom
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	NS      string      `xml:"xmlns,attr"`
+	AINS    string      `xml:"xmlns:ai,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Tagline string      `xml:"subtitle,omitempty"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary,omitempty"`
+	Authors    []atomAuthor   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	aiExtension
+}
+
+func atomDocument(f *Feed) *atomFeed {
+	updated := f.Updated
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	doc := &atomFeed{
+		NS:      "http://www.w3.org/2005/Atom",
+		AINS:    Namespace,
+		ID:      f.SelfURL,
+		Title:   f.Title,
+		Tagline: f.Description,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.SelfURL, Rel: "self", Type: ContentType(models.FeedFormatAtom)},
+			{Href: f.Link, Rel: "alternate"},
+		},
+		Entries: make([]atomEntry, 0, len(f.Items)),
+	}
+
+	for i := range f.Items {
+		item := &f.Items[i]
+		entry := atomEntry{
+			ID:          guid(item),
+			Title:       item.Title,
+			Link:        atomLink{Href: item.URL, Rel: "alternate"},
+			Published:   item.Published.UTC().Format(time.RFC3339),
+			Updated:     item.ModifiedAt.UTC().Format(time.RFC3339),
+			Summary:     item.Summary,
+			aiExtension: newAIExtension(item),
+		}
+		// Atom requires an author per entry when the feed has none
+		author := item.Author
+		if author == "" {
+			author = item.Source
+		}
+		entry.Authors = []atomAuthor{{Name: author}}
+		if item.Category != "" {
+			entry.Categories = []atomCategory{{Term: item.Category}}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return doc
+}
+
+// JSON Feed 1.1
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentText   string           `json:"content_text"`
+	Summary       string           `json:"summary,omitempty"`
+	Image         string           `json:"image,omitempty"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+	Language      string           `json:"language,omitempty"`
+	Extension     jsonFeedExt      `json:"_intellinieuws"`
+}
+
+// jsonFeedExt is the _intellinieuws extension object
+type jsonFeedExt struct {
+	About          string   `json:"about"`
+	ArticleID      int64    `json:"article_id"`
+	Source         string   `json:"source"`
+	Category       string   `json:"category,omitempty"`
+	AISummary      string   `json:"ai_summary,omitempty"`
+	Sentiment      *float64 `json:"sentiment,omitempty"`
+	SentimentLabel string   `json:"sentiment_label,omitempty"`
+}
+
+func jsonFeedDocument(f *Feed) *jsonFeed {
+	doc := &jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.SelfURL,
+		Description: f.Description,
+		Items:       make([]jsonFeedItem, 0, len(f.Items)),
+	}
+
+	for i := range f.Items {
+		item := &f.Items[i]
+		entry := jsonFeedItem{
+			ID:            guid(item),
+			URL:           item.URL,
+			Title:         item.Title,
+			ContentText:   item.Summary,
+			Summary:       item.AISummary,
+			Image:         item.ImageURL,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+			DateModified:  item.ModifiedAt.UTC().Format(time.RFC3339),
+			Tags:          item.Keywords,
+			Language:      item.Language,
+			Extension: jsonFeedExt{
+				About:          Namespace,
+				ArticleID:      item.ID,
+				Source:         item.Source,
+				Category:       item.Category,
+				AISummary:      item.AISummary,
+				Sentiment:      item.AISentiment,
+				SentimentLabel: item.AISentimentLabel,
+			},
+		}
+		if item.Author != "" {
+			entry.Authors = []jsonFeedAuthor{{Name: item.Author}}
+		}
+		doc.Items = append(doc.Items, entry)
+	}
+	return doc
+}
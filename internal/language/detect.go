@@ -0,0 +1,197 @@
+// Package language detects the language of article text and maps languages to
+// PostgreSQL text-search configurations.
+package language
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Language codes (ISO 639-1)
+const (
+	Dutch   = "nl"
+	English = "en"
+	German  = "de"
+	French  = "fr"
+	Spanish = "es"
+)
+
+// Default is assumed when text is too short or ambiguous to classify.
+// Most sources are Dutch.
+const Default = Dutch
+
+// minHits is the number of stopword hits required before trusting a detection
+const minHits = 2
+
+// searchConfigs maps language codes to PostgreSQL text-search configurations.
+// Must match article_search_config() in migrations/V005__add_article_language.sql.
+var searchConfigs = map[string]string{
+	Dutch:   "dutch",
+	English: "english",
+	German:  "german",
+	French:  "french",
+	Spanish: "spanish",
+}
+
+// names are human-readable language names, used in translation prompts
+var names = map[string]string{
+	Dutch:   "Dutch",
+	English: "English",
+	German:  "German",
+	French:  "French",
+	Spanish: "Spanish",
+}
+
+// stopwords are frequent function words that rarely occur in other languages.
+// Words shared between languages (e.g. "in", "is", "die") are left out.
+var stopwords = map[string][]string{
+	Dutch: {
+		"de", "het", "een", "en", "van", "voor", "niet", "zijn", "werd", "wordt",
+		"ook", "maar", "bij", "naar", "dat", "met", "om", "aan", "ze", "hij",
+		"nog", "wel", "geen", "heeft", "hebben", "worden", "deze", "dit", "uit", "tot",
+		"over", "zich", "meer", "al", "kan", "moet", "zou", "jaar", "volgens", "tegen",
+	},
+	English: {
+		"the", "and", "of", "to", "for", "that", "with", "was", "were", "has",
+		"have", "from", "this", "by", "are", "at", "but", "not", "be", "been",
+		"which", "their", "they", "will", "would", "after", "about", "more", "said", "its",
+		"than", "who", "into", "could", "new", "also", "over", "says", "year", "what",
+	},
+	German: {
+		"der", "und", "den", "das", "nicht", "mit", "sich", "des", "auf", "ist",
+		"ein", "eine", "dem", "auch", "wird", "werden", "nach", "bei", "einer", "um",
+		"noch", "wie", "über", "aus", "sind", "hat", "zum", "zur", "durch", "wurde",
+		"gegen", "vom", "soll", "jahr", "ihre", "sie", "sagte", "mehr", "aber", "seine",
+	},
+	French: {
+		"le", "la", "les", "des", "du", "et", "est", "une", "pour", "dans",
+		"que", "qui", "sur", "pas", "par", "au", "aux", "avec", "ce", "cette",
+		"sont", "ont", "été", "mais", "plus", "il", "elle", "ils", "selon", "leur",
+		"son", "ses", "entre", "après", "contre", "sera", "fait", "deux", "année", "comme",
+	},
+	Spanish: {
+		"el", "los", "las", "del", "y", "que", "una", "por", "para", "con",
+		"es", "se", "su", "sus", "al", "lo", "como", "más", "pero", "fue",
+		"ha", "han", "este", "esta", "sobre", "entre", "según", "también", "año", "contra",
+		"muy", "sin", "ya", "hasta", "desde", "cuando", "durante", "porque", "tras", "donde",
+	},
+}
+
+// stopwordIndex maps each stopword to the languages it belongs to
+var stopwordIndex = buildIndex()
+
+func buildIndex() map[string][]string {
+	index := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, w := range words {
+			index[w] = append(index[w], lang)
+		}
+	}
+	return index
+}
+
+// Result is a language detection outcome
+type Result struct {
+	Language   string  `json:"language"`
+	Confidence float64 `json:"confidence"` // share of stopword hits for the winning language
+	Detected   bool    `json:"detected"`   // false when Default was used as fallback
+}
+
+// Detect returns the most likely language code of the text, or Default
+func Detect(text string) string {
+	return DetectWithConfidence(text).Language
+}
+
+// DetectWithConfidence scores the text against the stopword lists of every
+// supported language
+func DetectWithConfidence(text string) Result {
+	scores := make(map[string]int)
+	total := 0
+
+	for _, token := range tokenize(text) {
+		for _, lang := range stopwordIndex[token] {
+			scores[lang]++
+			total++
+		}
+	}
+
+	best, bestScore := "", 0
+	langs := make([]string, 0, len(scores))
+	for lang := range scores {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs) // deterministic tie-breaking
+	for _, lang := range langs {
+		if scores[lang] > bestScore {
+			best, bestScore = lang, scores[lang]
+		}
+	}
+
+	// Ties with the default language go to the default
+	if best != Default && bestScore > 0 && scores[Default] == bestScore {
+		best = Default
+	}
+
+	if bestScore < minHits {
+		return Result{Language: Default, Confidence: 0, Detected: false}
+	}
+
+	return Result{
+		Language:   best,
+		Confidence: float64(bestScore) / float64(total),
+		Detected:   true,
+	}
+}
+
+// tokenize lowercases the text and splits it into words
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+// Normalize lowercases a language code and strips region suffixes ("en-GB" -> "en").
+// Unsupported codes return "".
+func Normalize(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i]
+	}
+	if _, ok := searchConfigs[code]; !ok {
+		return ""
+	}
+	return code
+}
+
+// IsSupported reports whether a language code is supported
+func IsSupported(code string) bool {
+	return Normalize(code) != ""
+}
+
+// SearchConfig returns the PostgreSQL text-search configuration for a language.
+// Unsupported languages use "simple" (no stemming, no stopwords).
+func SearchConfig(code string) string {
+	if cfg, ok := searchConfigs[Normalize(code)]; ok {
+		return cfg
+	}
+	return "simple"
+}
+
+// Name returns the English name of a language ("nl" -> "Dutch")
+func Name(code string) string {
+	if name, ok := names[Normalize(code)]; ok {
+		return name
+	}
+	return code
+}
+
+// Supported returns all supported language codes in sorted order
+func Supported() []string {
+	codes := make([]string, 0, len(searchConfigs))
+	for code := range searchConfigs {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
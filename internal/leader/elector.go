@@ -0,0 +1,260 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/config"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// defaultRenewInterval applies when no renew interval is configured
+const defaultRenewInterval = 5 * time.Second
+
+// Elector elects the one instance among the replicas that runs the background workers,
+// such as the scraping scheduler, the AI, content and email processors and the alert
+// digests. Leadership is a Postgres session-level advisory lock: PostgreSQL releases it
+// as soon as the leader exits or loses its connection.
+//
+// The leader checks its lock session every renew interval and stops its workers when
+// the session is gone; followers retry the lock on the same interval, so another
+// instance takes over within one interval of the leader disappearing.
+type Elector struct {
+	repo     *repository.LeaderRepository
+	id       string
+	enabled  bool
+	interval time.Duration
+	logger   *logger.Logger
+
+	workers []worker
+
+	mu            sync.Mutex
+	leader        bool
+	lock          *repository.LeaderLock
+	leaderSince   *time.Time
+	cancelWorkers context.CancelFunc
+	running       bool
+	stopChan      chan struct{}
+	wg            sync.WaitGroup
+}
+
+// worker is a background worker that runs on the leader only
+type worker struct {
+	name  string
+	start func(ctx context.Context)
+	stop  func()
+}
+
+// Status is the leader election state as seen by this instance
+type Status struct {
+	Enabled     bool       `json:"enabled"`
+	InstanceID  string     `json:"instance_id"`
+	IsLeader    bool       `json:"is_leader"`
+	Leader      *string    `json:"leader"`                 // Instance ID of the leader, nil while there is none
+	LeaderSince *time.Time `json:"leader_since,omitempty"` // Only known on the leader itself
+	Workers     []string   `json:"workers"`
+}
+
+// NewElector creates a new leader elector; the instance ID defaults to hostname-pid
+func NewElector(repo *repository.LeaderRepository, cfg config.LeaderConfig, log *logger.Logger) *Elector {
+	id := cfg.InstanceID
+	if id == "" {
+		host, _ := os.Hostname()
+		id = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	interval := cfg.RenewInterval
+	if interval <= 0 {
+		interval = defaultRenewInterval
+	}
+
+	return &Elector{
+		repo:     repo,
+		id:       id,
+		enabled:  cfg.Enabled,
+		interval: interval,
+		logger:   log.WithComponent("leader"),
+	}
+}
+
+// Register adds a worker that runs while this instance leads. start must return once the
+// worker runs in the background and stop must wait until it has finished; the worker is
+// started again when this instance regains leadership, which is why the workers create
+// their stop channel in Start rather than in their constructor. Register before Start.
+func (e *Elector) Register(name string, start func(ctx context.Context), stop func()) {
+	e.workers = append(e.workers, worker{name: name, start: start, stop: stop})
+}
+
+// Start begins the election. Without leader election this instance leads right away.
+func (e *Elector) Start(ctx context.Context) {
+	e.mu.Lock()
+	if e.running {
+		e.mu.Unlock()
+		return
+	}
+	e.running = true
+	e.stopChan = make(chan struct{})
+	e.mu.Unlock()
+
+	if !e.enabled {
+		e.logger.Infof("Leader election disabled, %s runs %d background workers", e.id, len(e.workers))
+		e.becomeLeader(ctx, nil)
+		return
+	}
+
+	e.logger.Infof("Starting leader election as %s (renew interval: %v)", e.id, e.interval)
+	e.wg.Add(1)
+	go e.run(ctx)
+}
+
+// Stop stops the workers if this instance leads and releases leadership
+func (e *Elector) Stop() {
+	e.mu.Lock()
+	if !e.running {
+		e.mu.Unlock()
+		return
+	}
+	e.running = false
+	e.mu.Unlock()
+
+	close(e.stopChan)
+	e.wg.Wait()
+	e.stepDown()
+	e.logger.Info("Leader election stopped")
+}
+
+// IsLeader reports whether this instance currently runs the background workers
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader
+}
+
+// InstanceID returns the ID this instance is reported under
+func (e *Elector) InstanceID() string {
+	return e.id
+}
+
+// Status returns the election state; the leader is looked up in the database when this
+// instance does not lead
+func (e *Elector) Status(ctx context.Context) (Status, error) {
+	e.mu.Lock()
+	status := Status{
+		Enabled:     e.enabled,
+		InstanceID:  e.id,
+		IsLeader:    e.leader,
+		LeaderSince: e.leaderSince,
+		Workers:     make([]string, 0, len(e.workers)),
+	}
+	e.mu.Unlock()
+	for _, w := range e.workers {
+		status.Workers = append(status.Workers, w.name)
+	}
+
+	if status.IsLeader {
+		status.Leader = &status.InstanceID
+		return status, nil
+	}
+	leader, err := e.repo.GetLeader(ctx)
+	if err != nil {
+		return status, err
+	}
+	status.Leader = leader
+	return status, nil
+}
+
+func (e *Elector) run(ctx context.Context) {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	e.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.stopChan:
+			return
+		case <-ticker.C:
+			e.tick(ctx)
+		}
+	}
+}
+
+// tick renews leadership on the leader and tries to take it on a follower
+func (e *Elector) tick(ctx context.Context) {
+	tickCtx, cancel := context.WithTimeout(ctx, e.interval)
+	defer cancel()
+
+	e.mu.Lock()
+	held := e.lock
+	e.mu.Unlock()
+
+	if held != nil {
+		if err := held.Check(tickCtx); err != nil {
+			e.logger.WithError(err).Warn("Lost leadership, stopping background workers")
+			e.stepDown()
+		}
+		return
+	}
+
+	lock, acquired, err := e.repo.TryLock(tickCtx, e.id)
+	if err != nil {
+		e.logger.WithError(err).Warn("Leader election failed")
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	e.logger.Infof("Elected leader as %s, starting %d background workers", e.id, len(e.workers))
+	e.becomeLeader(ctx, lock)
+}
+
+// becomeLeader starts the workers with a context that is cancelled on step down
+func (e *Elector) becomeLeader(ctx context.Context, lock *repository.LeaderLock) {
+	workerCtx, cancel := context.WithCancel(ctx)
+	now := time.Now()
+
+	e.mu.Lock()
+	e.leader = true
+	e.lock = lock
+	e.leaderSince = &now
+	e.cancelWorkers = cancel
+	e.mu.Unlock()
+
+	for _, w := range e.workers {
+		e.logger.Debugf("Starting %s", w.name)
+		w.start(workerCtx)
+	}
+}
+
+// stepDown stops the workers, in registration order, before the lock is released, so a
+// new leader does not overlap with them. After a lost session another instance may
+// already lead; the cancelled context stops the workers' current work.
+func (e *Elector) stepDown() {
+	e.mu.Lock()
+	if !e.leader {
+		e.mu.Unlock()
+		return
+	}
+	lock, cancel := e.lock, e.cancelWorkers
+	e.leader = false
+	e.lock = nil
+	e.leaderSince = nil
+	e.cancelWorkers = nil
+	e.mu.Unlock()
+
+	cancel()
+	for _, w := range e.workers {
+		e.logger.Debugf("Stopping %s", w.name)
+		w.stop()
+	}
+	if lock != nil {
+		lock.Release()
+	}
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// queueDepthTimeout bounds the AI queue query run on every Prometheus scrape
+const queueDepthTimeout = 2 * time.Second
+
+// circuitStates are exported as one series per state so dashboards can sum by state
+var circuitStates = []utils.CircuitState{utils.CircuitClosed, utils.CircuitOpen, utils.CircuitHalfOpen}
+
+var (
+	circuitBreakerStateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "circuit_breaker", "state"),
+		"Circuit breaker state by breaker name; 1 for the current state, 0 otherwise.",
+		[]string{"name", "state"}, nil,
+	)
+	browserPoolSizeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "browser_pool", "size"),
+		"Number of headless browsers in the pool.",
+		nil, nil,
+	)
+	browserPoolInUseDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "browser_pool", "in_use"),
+		"Number of pooled browsers currently acquired.",
+		nil, nil,
+	)
+	aiQueueDepthDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ai", "queue_depth"),
+		"Articles waiting for AI processing.",
+		nil, nil,
+	)
+)
+
+// RegisterCircuitBreakers exports the states of the breakers held by manager
+func RegisterCircuitBreakers(manager *utils.CircuitBreakerManager) {
+	registry.MustRegister(&circuitBreakerCollector{manager: manager})
+}
+
+// RegisterBrowserPool exports browser pool utilization. stats reports ok=false while no
+// pool is running, in which case nothing is exported.
+func RegisterBrowserPool(stats func() (size, inUse int, ok bool)) {
+	registry.MustRegister(&browserPoolCollector{stats: stats})
+}
+
+// RegisterAIQueue exports the AI processing backlog counted by depth
+func RegisterAIQueue(depth func(ctx context.Context) (int, error)) {
+	registry.MustRegister(&aiQueueCollector{depth: depth})
+}
+
+type circuitBreakerCollector struct {
+	manager *utils.CircuitBreakerManager
+}
+
+func (c *circuitBreakerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- circuitBreakerStateDesc
+}
+
+func (c *circuitBreakerCollector) Collect(ch chan<- prometheus.Metric) {
+	for name, current := range c.manager.States() {
+		for _, state := range circuitStates {
+			value := 0.0
+			if state == current {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(circuitBreakerStateDesc, prometheus.GaugeValue, value, name, state.String())
+		}
+	}
+}
+
+type browserPoolCollector struct {
+	stats func() (size, inUse int, ok bool)
+}
+
+func (c *browserPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- browserPoolSizeDesc
+	ch <- browserPoolInUseDesc
+}
+
+func (c *browserPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	size, inUse, ok := c.stats()
+	if !ok {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(browserPoolSizeDesc, prometheus.GaugeValue, float64(size))
+	ch <- prometheus.MustNewConstMetric(browserPoolInUseDesc, prometheus.GaugeValue, float64(inUse))
+}
+
+type aiQueueCollector struct {
+	depth func(ctx context.Context) (int, error)
+}
+
+func (c *aiQueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- aiQueueDepthDesc
+}
+
+func (c *aiQueueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueDepthTimeout)
+	defer cancel()
+
+	depth, err := c.depth(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(aiQueueDepthDesc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(aiQueueDepthDesc, prometheus.GaugeValue, float64(depth))
+}
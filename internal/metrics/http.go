@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// HTTPMiddleware records request latency labelled by the matched route template
+// (e.g. /api/v1/articles/:id) rather than the raw path, which keeps cardinality bounded.
+func HTTPMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		// Errors returned to the app error handler have not set the status yet
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		// Fiber strings point into reused buffers; label values are retained by the vector
+		httpRequestDuration.WithLabelValues(utils.CopyString(c.Method()), c.Route().Path, strconv.Itoa(status)).
+			Observe(time.Since(start).Seconds())
+		return err
+	}
+}
@@ -0,0 +1,212 @@
+// Package metrics exposes Prometheus metrics for the HTTP API, scraper, AI processor,
+// cache, stock and email layers.
+//
+// Event metrics are recorded through the Observe functions from the instrumented code.
+// State that already lives elsewhere (circuit breakers, browser pool, AI queue) is read
+// when Prometheus scrapes, through the Register functions wired up in cmd/api.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "intellinieuws"
+
+// Cache lookup results
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error"
+)
+
+// Outcome labels shared by AI, stock and IMAP metrics
+const (
+	outcomeSuccess = "success"
+	outcomeError   = "error"
+)
+
+// registry is used instead of the global default so only these collectors are exposed
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	scrapeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scraper",
+		Name:      "job_duration_seconds",
+		Help:      "Duration of scrape jobs by source and status.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"source", "status"})
+
+	scrapeJobs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scraper",
+		Name:      "jobs_total",
+		Help:      "Scrape jobs by source, status and error code (empty on success).",
+	}, []string{"source", "status", "error_code"})
+
+	scrapeArticles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scraper",
+		Name:      "articles_total",
+		Help:      "Articles seen by scrape jobs by source and result (found, stored, skipped).",
+	}, []string{"source", "result"})
+
+	aiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "ai",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of AI provider requests by operation and outcome.",
+		Buckets:   []float64{0.25, 0.5, 1, 2, 4, 8, 15, 30, 60},
+	}, []string{"operation", "outcome"})
+
+	aiTokens = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ai",
+		Name:      "tokens_total",
+		Help:      "Tokens consumed by AI provider requests by model and type (prompt, completion).",
+	}, []string{"model", "type"})
+
+	aiArticleDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "ai",
+		Name:      "article_processing_seconds",
+		Help:      "Time the AI processor spent enriching one article by outcome.",
+		Buckets:   []float64{0.5, 1, 2, 4, 8, 15, 30, 60, 120},
+	}, []string{"outcome"})
+
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "lookups_total",
+		Help:      "Cache lookups by key prefix and result (hit, miss, error).",
+	}, []string{"prefix", "result"})
+
+	stockRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "stock",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of stock data provider requests by provider, endpoint and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"provider", "endpoint", "status"})
+
+	imapPolls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "email",
+		Name:      "imap_polls_total",
+		Help:      "IMAP mailbox polls by outcome.",
+	}, []string{"outcome"})
+
+	imapPollDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "email",
+		Name:      "imap_poll_duration_seconds",
+		Help:      "Duration of IMAP mailbox polls.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	})
+
+	imapMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "email",
+		Name:      "imap_messages_fetched_total",
+		Help:      "Messages from allowed senders fetched by IMAP polls.",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		scrapeDuration,
+		scrapeJobs,
+		scrapeArticles,
+		aiRequestDuration,
+		aiTokens,
+		aiArticleDuration,
+		cacheLookups,
+		stockRequestDuration,
+		imapPolls,
+		imapPollDuration,
+		imapMessages,
+	)
+}
+
+// Handler serves the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// NewServer returns an HTTP server exposing /metrics on port; the caller starts and shuts it down
+func NewServer(port int) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	return &http.Server{
+		Addr:              ":" + strconv.Itoa(port),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
+
+// ObserveScrape records a finished scrape job. errorCode is empty for successful jobs.
+func ObserveScrape(source, status, errorCode string, duration time.Duration, found, stored, skipped int) {
+	scrapeDuration.WithLabelValues(source, status).Observe(duration.Seconds())
+	scrapeJobs.WithLabelValues(source, status, errorCode).Inc()
+	scrapeArticles.WithLabelValues(source, "found").Add(float64(found))
+	scrapeArticles.WithLabelValues(source, "stored").Add(float64(stored))
+	scrapeArticles.WithLabelValues(source, "skipped").Add(float64(skipped))
+}
+
+// ObserveAIRequest records the latency of one AI provider request
+func ObserveAIRequest(operation string, duration time.Duration, err error) {
+	aiRequestDuration.WithLabelValues(operation, outcome(err)).Observe(duration.Seconds())
+}
+
+// AddAITokens records the token usage reported by an AI provider response
+func AddAITokens(model string, prompt, completion int) {
+	aiTokens.WithLabelValues(model, "prompt").Add(float64(prompt))
+	aiTokens.WithLabelValues(model, "completion").Add(float64(completion))
+}
+
+// ObserveAIArticle records how long enriching one article took
+func ObserveAIArticle(duration time.Duration, err error) {
+	aiArticleDuration.WithLabelValues(outcome(err)).Observe(duration.Seconds())
+}
+
+// ObserveCacheLookup records a cache hit, miss or error for a key prefix
+func ObserveCacheLookup(prefix, result string) {
+	cacheLookups.WithLabelValues(prefix, result).Inc()
+}
+
+// ObserveStockRequest records a stock provider request. status is the HTTP status code,
+// or "error" when no response was received.
+func ObserveStockRequest(provider, endpoint, status string, duration time.Duration) {
+	stockRequestDuration.WithLabelValues(provider, endpoint, status).Observe(duration.Seconds())
+}
+
+// ObserveIMAPPoll records one IMAP mailbox poll and the messages it fetched
+func ObserveIMAPPoll(duration time.Duration, fetched int, err error) {
+	imapPolls.WithLabelValues(outcome(err)).Inc()
+	imapPollDuration.Observe(duration.Seconds())
+	imapMessages.Add(float64(fetched))
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return outcomeError
+	}
+	return outcomeSuccess
+}
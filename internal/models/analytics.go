@@ -0,0 +1,338 @@
+package models
+
+import "time"
+
+// Burst term kinds
+const (
+	BurstKindKeyword = "keyword"
+	BurstKindEntity  = "entity"
+)
+
+// BurstQuery holds the parameters of a burst detection run
+type BurstQuery struct {
+	Kind         string // keyword, entity or "" for both
+	EntityType   string // Only for entities
+	WindowHours  []int  // Current windows compared against their baseline, at most 24 hours
+	BaselineDays int    // Days of history the baseline is built from
+	MinZScore    float64
+	MinArticles  int // Minimum articles in the current window
+	Limit        int
+	ArticleLimit int // Contributing articles per burst
+}
+
+// TermHourlyCounts is the article count of a keyword or entity per hour, where
+// Counts[i] counts the articles published between i+1 and i hours before the query time
+type TermHourlyCounts struct {
+	Kind       string
+	Term       string
+	EntityID   int64
+	EntityType string
+	Counts     []int
+}
+
+// Burst is a keyword or entity mentioned far more often than its own baseline
+type Burst struct {
+	Kind         string         `json:"kind"`
+	Term         string         `json:"term"`
+	EntityID     int64          `json:"entity_id,omitempty"`
+	EntityType   string         `json:"entity_type,omitempty"`
+	BurstScore   float64        `json:"burst_score"` // Highest z-score over the windows
+	WindowHours  int            `json:"window_hours"`
+	CurrentCount int            `json:"current_count"`
+	Onset        time.Time      `json:"onset"`
+	Windows      []BurstWindow  `json:"windows"`
+	Articles     []BurstArticle `json:"articles"`
+}
+
+// BurstWindow compares the article count in one window with the same window on previous days
+type BurstWindow struct {
+	Hours          int     `json:"hours"`
+	Count          int     `json:"count"`
+	BaselineMean   float64 `json:"baseline_mean"`
+	BaselineStdDev float64 `json:"baseline_stddev"`
+	ZScore         float64 `json:"z_score"`
+}
+
+// BurstArticle is an article contributing to a burst
+type BurstArticle struct {
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Source    string    `json:"source"`
+	Published time.Time `json:"published"`
+}
+
+// Entity graph output formats
+const (
+	GraphFormatJSON = "json"
+	GraphFormatGEXF = "gexf"
+)
+
+// EntityGraphQuery holds the parameters of an entity co-occurrence graph
+type EntityGraphQuery struct {
+	Since      time.Time
+	Until      time.Time
+	EntityType string // Restricts the nodes, "" for all types
+	CenterID   int64  // Ego network of this entity when > 0
+	MinWeight  int    // Minimum articles mentioning both entities of an edge
+	Limit      int    // Maximum nodes, the center included
+}
+
+// EntityGraph is a co-occurrence graph of canonical entities. The node/edge layout with
+// id, source and target maps directly onto d3, vis-network, Cytoscape and Graphology.
+type EntityGraph struct {
+	Nodes []EntityGraphNode `json:"nodes"`
+	Edges []EntityGraphEdge `json:"edges"`
+}
+
+// EntityGraphNode is an entity with its mentions in the graph window
+type EntityGraphNode struct {
+	ID           int64    `json:"id"`
+	Label        string   `json:"label"`
+	EntityType   string   `json:"entity_type"`
+	Mentions     int      `json:"mentions"` // Articles mentioning the entity
+	AvgSentiment *float64 `json:"avg_sentiment"`
+	Center       bool     `json:"center,omitempty"`
+}
+
+// EntityGraphEdge links two entities mentioned in the same articles
+type EntityGraphEdge struct {
+	Source       int64    `json:"source"`
+	Target       int64    `json:"target"`
+	Weight       int      `json:"weight"` // Articles mentioning both entities
+	AvgSentiment *float64 `json:"avg_sentiment"`
+}
+
+// TickerSentimentQuery holds the parameters of a news sentiment versus price analysis
+type TickerSentimentQuery struct {
+	Symbol         string
+	Days           int     // Calendar days of history
+	MaxLag         int     // Trading days the return may lead or follow the news
+	EventWindow    int     // Trading days before and after an event day
+	EventThreshold float64 // Minimum absolute average sentiment of an event day
+	MinArticles    int     // Minimum articles on an event day
+}
+
+// TickerDay is one UTC day of a ticker's news volume, sentiment and price series
+type TickerDay struct {
+	Day           time.Time `json:"day"`
+	ArticleCount  int       `json:"article_count"`
+	PositiveCount int       `json:"positive_count"`
+	NegativeCount int       `json:"negative_count"`
+	AvgSentiment  *float64  `json:"avg_sentiment"`
+	Close         *float64  `json:"close"`  // Adjusted close, nil on non-trading days
+	Return        *float64  `json:"return"` // Versus the previous trading day
+}
+
+// TickerPrice is an adjusted close and its return, persisted in the ticker series
+type TickerPrice struct {
+	Day    time.Time
+	Close  float64
+	Return *float64 // nil for the first day of a fetched range
+}
+
+// LagCorrelation correlates news on trading day t with the return on day t+lag
+type LagCorrelation struct {
+	Lag             int      `json:"lag"`
+	SentimentReturn *float64 `json:"sentiment_return"`  // Pearson r of average sentiment and return
+	VolumeAbsReturn *float64 `json:"volume_abs_return"` // Pearson r of article count and absolute return
+	N               int      `json:"n"`                 // Days with sentiment used for SentimentReturn
+}
+
+// EventStudy averages returns around strongly positive and negative news days
+type EventStudy struct {
+	Threshold   float64         `json:"threshold"`
+	MinArticles int             `json:"min_articles"`
+	Window      int             `json:"window"`
+	Positive    EventStudyGroup `json:"positive"`
+	Negative    EventStudyGroup `json:"negative"`
+}
+
+// EventStudyGroup holds the event days of one sign and their average return path
+type EventStudyGroup struct {
+	Events []time.Time     `json:"events"`
+	Days   []EventStudyDay `json:"days"`
+}
+
+// EventStudyDay is the average return at a trading-day offset from the events
+type EventStudyDay struct {
+	Offset           int      `json:"offset"`
+	AvgReturn        *float64 `json:"avg_return"`
+	CumulativeReturn *float64 `json:"cumulative_return"` // Sum of average returns from -window
+	N                int      `json:"n"`
+}
+
+// TickerSentimentAnalysis relates a ticker's daily news sentiment to its price returns
+type TickerSentimentAnalysis struct {
+	Symbol       string           `json:"symbol"`
+	From         time.Time        `json:"from"`
+	To           time.Time        `json:"to"`
+	PricesStale  bool             `json:"prices_stale,omitempty"` // Prices could not be refreshed
+	Series       []TickerDay      `json:"series"`
+	Correlations []LagCorrelation `json:"correlations"`
+	EventStudy   EventStudy       `json:"event_study"`
+}
+
+// SourceComparisonQuery holds the parameters of a per-source comparison
+type SourceComparisonQuery struct {
+	Since              time.Time
+	Until              time.Time
+	StoryWindowHours   int     // Maximum time between two articles of the same story
+	MinSharedEntities  int     // Canonical entities two articles must share to form a story
+	MinTitleSimilarity float64 // Title word overlap (Jaccard) that links articles without shared entities
+	TopN               int     // Categories and entities per source
+}
+
+// SourceArticle is an article with the fields the source comparison needs
+type SourceArticle struct {
+	ID             int64
+	Source         string
+	Title          string
+	Published      time.Time
+	Category       string
+	Sentiment      *float64
+	SentimentLabel string
+	EntityIDs      []int64 // Canonical entities
+}
+
+// SourceComparison compares the coverage, speed and tone of the sources in a window
+type SourceComparison struct {
+	Since              time.Time       `json:"since"`
+	Until              time.Time       `json:"until"`
+	Articles           int             `json:"articles"`
+	Stories            int             `json:"stories"`
+	MultiSourceStories int             `json:"multi_source_stories"`
+	Overall            SourceSentiment `json:"overall_sentiment"`
+	Sources            []SourceStats   `json:"sources"`
+}
+
+// SourceStats are the analytics of one source
+type SourceStats struct {
+	Source             string           `json:"source"`
+	Name               string           `json:"name,omitempty"`
+	Articles           int              `json:"articles"`
+	ArticleShare       float64          `json:"article_share"` // Of all articles in the window
+	Stories            int              `json:"stories"`
+	Exclusives         int              `json:"exclusives"` // Stories no other source covered
+	ExclusiveShare     float64          `json:"exclusive_share"`
+	SharedStories      int              `json:"shared_stories"`       // Stories covered by several sources
+	FirstReports       int              `json:"first_reports"`        // Shared stories this source published first
+	MedianDelayMinutes *float64         `json:"median_delay_minutes"` // Behind the first source, over shared stories
+	Sentiment          SourceSentiment  `json:"sentiment"`
+	SentimentDelta     *float64         `json:"sentiment_delta"` // Average sentiment minus the overall average
+	Categories         []SourceCoverage `json:"categories"`
+	Entities           []SourceCoverage `json:"entities"`
+}
+
+// SourceSentiment is the sentiment distribution of AI processed articles
+type SourceSentiment struct {
+	Processed int      `json:"processed"`
+	Average   *float64 `json:"average"`
+	Positive  float64  `json:"positive"` // Shares of the processed articles
+	Neutral   float64  `json:"neutral"`
+	Negative  float64  `json:"negative"`
+}
+
+// SourceCoverage is a source's share of the coverage of a category or entity
+type SourceCoverage struct {
+	ID       int64   `json:"id,omitempty"` // Entity ID
+	Name     string  `json:"name"`
+	Articles int     `json:"articles"`
+	Share    float64 `json:"share"` // Of all articles in the category or mentioning the entity
+}
+
+// Time series metrics
+const (
+	TimeSeriesMetricArticles       = "articles"
+	TimeSeriesMetricAvgSentiment   = "avg_sentiment"
+	TimeSeriesMetricEntityMentions = "entity_mentions"
+)
+
+// Time series group-by dimensions; "" returns a single series
+const (
+	TimeSeriesGroupSource    = "source"
+	TimeSeriesGroupCategory  = "category"
+	TimeSeriesGroupEntity    = "entity"
+	TimeSeriesGroupTicker    = "ticker"
+	TimeSeriesGroupSentiment = "sentiment"
+)
+
+// Time series buckets
+const (
+	TimeSeriesBucketHour = "hour"
+	TimeSeriesBucketDay  = "day"
+	TimeSeriesBucketWeek = "week"
+)
+
+// TimeSeriesQuery is a validated aggregation over articles in [Since, Until)
+type TimeSeriesQuery struct {
+	Metric  string            `json:"metric"`
+	GroupBy string            `json:"group_by,omitempty"`
+	Bucket  string            `json:"bucket"`
+	Since   time.Time         `json:"since"`
+	Until   time.Time         `json:"until"`
+	Filters TimeSeriesFilters `json:"filters"`
+	Limit   int               `json:"limit"` // Maximum series, the groups with most articles
+}
+
+// TimeSeriesFilters restrict the articles of a time series query; empty fields match all
+type TimeSeriesFilters struct {
+	Sources    []string `json:"sources,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Sentiment  string   `json:"sentiment,omitempty"` // positive, neutral or negative
+	Tickers    []string `json:"tickers,omitempty"`
+	EntityID   int64    `json:"entity_id,omitempty"`   // Articles mentioning this canonical entity
+	EntityType string   `json:"entity_type,omitempty"` // Counted entities, with the entity metric or group
+}
+
+// TimeSeriesRow is one bucket of one group as returned by the database
+type TimeSeriesRow struct {
+	Bucket   time.Time
+	Group    string
+	Label    string
+	Value    *float64
+	Articles int
+}
+
+// TimeSeries is the metric of one group per bucket, every bucket of the range included
+type TimeSeries struct {
+	Group    string            `json:"group,omitempty"`
+	Label    string            `json:"label,omitempty"`
+	Articles int               `json:"articles"`
+	Points   []TimeSeriesPoint `json:"points"`
+}
+
+// TimeSeriesPoint is the metric value of one bucket; Value is nil for an average without articles
+type TimeSeriesPoint struct {
+	Bucket   time.Time `json:"bucket"`
+	Value    *float64  `json:"value"`
+	Articles int       `json:"articles"`
+}
+
+// TimeSeriesResult is the answer to a time series query
+type TimeSeriesResult struct {
+	Query    TimeSeriesQuery `json:"query"`
+	Series   []TimeSeries    `json:"series"`
+	DataAsOf time.Time       `json:"data_as_of"` // When the result was computed, cached results keep it
+}
+
+// ViewRefresh is the refresh state of an analytics materialized view
+type ViewRefresh struct {
+	ViewName        string     `json:"view_name"`
+	LastRefreshedAt *time.Time `json:"last_refreshed_at"` // Data is as of this time
+	LastDurationMs  *int       `json:"last_duration_ms"`
+	LastRowCount    *int64     `json:"last_row_count"`
+	RefreshCount    int64      `json:"refresh_count"`
+	LastAttemptAt   *time.Time `json:"last_attempt_at"`
+	LastError       *string    `json:"last_error,omitempty"`
+	RefreshedBy     *string    `json:"refreshed_by,omitempty"`
+}
+
+// ViewRefreshResult is the outcome of refreshing one materialized view
+type ViewRefreshResult struct {
+	ViewName      string `json:"view_name"`
+	RefreshTimeMs int    `json:"refresh_time_ms"`
+	RowsAffected  int64  `json:"rows_affected"`
+	Concurrent    bool   `json:"concurrent"`
+	Error         string `json:"error,omitempty"`
+}
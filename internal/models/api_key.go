@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// API key statuses (derived, not stored)
+const (
+	APIKeyStatusActive  = "active"
+	APIKeyStatusExpired = "expired"
+	APIKeyStatusRevoked = "revoked"
+)
+
+// APIKey represents an issued per-client API key. The raw key is never stored.
+type APIKey struct {
+	ID            int64      `json:"id" db:"id"`
+	Prefix        string     `json:"prefix" db:"key_prefix"`
+	Name          string     `json:"name" db:"name"`
+	Owner         string     `json:"owner" db:"owner"`
+	Scopes        []string   `json:"scopes" db:"scopes"`
+	DailyQuota    int        `json:"daily_quota" db:"daily_quota"` // 0 = unlimited
+	RateTier      string     `json:"rate_tier" db:"rate_tier"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	LastUsedIP    *string    `json:"last_used_ip,omitempty" db:"last_used_ip"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	RevokedReason *string    `json:"revoked_reason,omitempty" db:"revoked_reason"`
+	RotatedFrom   *int64     `json:"rotated_from,omitempty" db:"rotated_from"`
+	CreatedBy     *string    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+	Status        string     `json:"status"`
+}
+
+// ComputeStatus derives the key status at the given time
+func (k *APIKey) ComputeStatus(now time.Time) string {
+	switch {
+	case k.RevokedAt != nil:
+		return APIKeyStatusRevoked
+	case k.ExpiresAt != nil && !k.ExpiresAt.After(now):
+		return APIKeyStatusExpired
+	default:
+		return APIKeyStatusActive
+	}
+}
+
+// APIKeyCreate represents a request to issue a new API key
+type APIKeyCreate struct {
+	Name          string     `json:"name"`
+	Owner         string     `json:"owner"`
+	Scopes        []string   `json:"scopes"`
+	DailyQuota    int        `json:"daily_quota"`
+	RateTier      string     `json:"rate_tier,omitempty"` // standard (default), premium or unlimited
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	ExpiresInDays int        `json:"expires_in_days,omitempty"` // Alternative to expires_at
+}
+
+// APIKeyRotateRequest represents a request to rotate an API key
+type APIKeyRotateRequest struct {
+	// Hours the old key keeps working so clients can switch over (default 24, 0 = revoke immediately)
+	GracePeriodHours *int `json:"grace_period_hours,omitempty"`
+}
+
+// APIKeyRevokeRequest represents a request to revoke an API key
+type APIKeyRevokeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// IssuedAPIKey is returned once when a key is issued or rotated
+type IssuedAPIKey struct {
+	Key    string  `json:"key"` // Raw key, only shown once
+	APIKey *APIKey `json:"api_key"`
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/pagination"
 )
 
 // Article represents a news article
@@ -16,13 +18,26 @@ type Article struct {
 	ImageURL    string    `json:"image_url" db:"image_url"`
 	Author      string    `json:"author" db:"author"`
 	Category    string    `json:"category" db:"category"`
-	ContentHash string    `json:"-" db:"content_hash"` // For duplicate detection
+	Language    string    `json:"language,omitempty" db:"language"` // ISO 639-1, empty until detected
+	ContentHash string    `json:"-" db:"content_hash"`              // For duplicate detection
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 	// Full content extraction fields
 	Content            string     `json:"content,omitempty" db:"content"`
 	ContentExtracted   bool       `json:"content_extracted" db:"content_extracted"`
 	ContentExtractedAt *time.Time `json:"content_extracted_at,omitempty" db:"content_extracted_at"`
+	// Machine translation, only set when requested with ?lang=
+	Translation *ArticleTranslation `json:"translation,omitempty" db:"-"`
+}
+
+// ArticleTranslation is a machine translation of an article's title and summary
+type ArticleTranslation struct {
+	Language       string    `json:"language"`
+	SourceLanguage string    `json:"source_language"`
+	Title          string    `json:"title"`
+	Summary        string    `json:"summary,omitempty"`
+	Model          string    `json:"model,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
 }
 
 // ArticleFilter represents filters for querying articles
@@ -31,12 +46,15 @@ type ArticleFilter struct {
 	Category  string
 	Keyword   string
 	Search    string
+	Language  string // ISO 639-1 code
 	StartDate *time.Time
 	EndDate   *time.Time
 	SortBy    string
 	SortOrder string
 	Limit     int
-	Offset    int
+	Offset    int // Ignored when Cursor is set
+	Cursor    *pagination.Cursor
+	SkipCount bool // Skip the COUNT(*) query; Total is then unknown
 }
 
 // ScrapingJob represents a scraping job
@@ -105,7 +123,9 @@ type ArticleCreate struct {
 	ImageURL    string    `json:"image_url" validate:"omitempty,url"`
 	Author      string    `json:"author" validate:"max=200"`
 	Category    string    `json:"category" validate:"max=100"`
+	Language    string    `json:"language,omitempty" validate:"omitempty,len=2"` // Detected when empty
 	ContentHash string    `json:"-"`
+	ScrapeJobID string    `json:"-"` // UUID of the scrape job storing the article, if any
 }
 
 // ArticleResponse represents the API response for an article
@@ -121,9 +141,18 @@ type ArticleListResponse struct {
 
 // PaginationResponse represents pagination metadata
 type PaginationResponse struct {
-	Total  int `json:"total"`
-	Limit  int `json:"limit"`
-	Offset int `json:"offset"`
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// ArticlePage is one page of a keyset-paginated article list
+type ArticlePage struct {
+	Articles []Article
+	Total    *int // nil when the count was skipped
+	Page     pagination.PageInfo
 }
 
 // ErrorResponse represents an API error response
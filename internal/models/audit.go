@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audit actor types
+const (
+	AuditActorAPIKey    = "api_key"
+	AuditActorUser      = "user"
+	AuditActorSharedKey = "shared_key"
+	AuditActorAnonymous = "anonymous"
+)
+
+// Audit outcome statuses
+const (
+	AuditStatusSuccess = "success"
+	AuditStatusFailure = "failure"
+)
+
+// AuditEntry is an append-only record of an administrative operation
+type AuditEntry struct {
+	ID           int64           `json:"id" db:"id"`
+	OccurredAt   time.Time       `json:"occurred_at" db:"occurred_at"`
+	ActorType    string          `json:"actor_type" db:"actor_type"`
+	ActorID      string          `json:"actor_id,omitempty" db:"actor_id"`
+	ActorName    string          `json:"actor_name,omitempty" db:"actor_name"`
+	IPAddress    string          `json:"ip_address,omitempty" db:"ip_address"`
+	UserAgent    string          `json:"user_agent,omitempty" db:"user_agent"`
+	Action       string          `json:"action" db:"action"`
+	Resource     string          `json:"resource,omitempty" db:"resource"`
+	Before       json.RawMessage `json:"before,omitempty" db:"before_value"`
+	After        json.RawMessage `json:"after,omitempty" db:"after_value"`
+	Status       string          `json:"status" db:"status"`
+	ErrorMessage string          `json:"error_message,omitempty" db:"error_message"`
+	RequestID    string          `json:"request_id,omitempty" db:"request_id"`
+	HTTPMethod   string          `json:"http_method,omitempty" db:"http_method"`
+	Path         string          `json:"path,omitempty" db:"path"`
+}
+
+// AuditFilter represents filters for querying the audit log
+type AuditFilter struct {
+	Actor     string // Matches actor ID or name
+	ActorType string
+	Action    string // Exact action, or a prefix when ending in "*" (e.g. "config.*")
+	Resource  string
+	RequestID string
+	Status    string
+	Since     *time.Time
+	Until     *time.Time
+	Limit     int
+	Offset    int
+}
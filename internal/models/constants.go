@@ -62,6 +62,47 @@ const (
 	DefaultTrendingMinArticles = 3
 	DefaultTrendingLimit       = 20
 
+	// Burst detection defaults
+	DefaultBurstBaselineDays = 14
+	MaxBurstBaselineDays     = 30
+	DefaultBurstMinZScore    = 3.0
+	DefaultBurstMinArticles  = 3
+	DefaultBurstLimit        = 20
+	DefaultBurstArticleLimit = 5
+	MaxBurstWindowHours      = 24
+
+	// Entity graph defaults
+	DefaultEntityGraphDays      = 7
+	MaxEntityGraphDays          = 90
+	DefaultEntityGraphMinWeight = 2
+	DefaultEntityGraphLimit     = 100
+	MaxEntityGraphLimit         = 500
+
+	// Ticker sentiment analysis defaults
+	DefaultTickerSentimentDays  = 180
+	MaxTickerSentimentDays      = 730
+	DefaultTickerMaxLag         = 5
+	MaxTickerMaxLag             = 10
+	DefaultTickerEventWindow    = 5
+	MaxTickerEventWindow        = 10
+	DefaultTickerEventThreshold = 0.3
+	DefaultTickerEventArticles  = 2
+
+	// Source comparison defaults
+	DefaultSourceComparisonDays = 7
+	MaxSourceComparisonDays     = 31
+	DefaultStoryWindowHours     = 36
+	DefaultStorySharedEntities  = 2
+	DefaultStoryTitleSimilarity = 0.5
+	DefaultSourceComparisonTopN = 5
+
+	// Time series query defaults
+	DefaultTimeSeriesDays  = 7
+	DefaultTimeSeriesLimit = 10
+	MaxTimeSeriesLimit     = 50
+	MaxTimeSeriesBuckets   = 1000
+	MaxTimeSeriesFilters   = 20 // Values per list filter
+
 	// Pagination defaults
 	DefaultPageLimit  = 50
 	DefaultPageOffset = 0
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// Entity types (matches database CHECK constraint)
+const (
+	EntityTypePerson       = "person"
+	EntityTypeOrganization = "organization"
+	EntityTypeLocation     = "location"
+)
+
+// Entity origins (matches database CHECK constraint)
+const (
+	EntityOriginAuto   = "auto"
+	EntityOriginDump   = "dump"
+	EntityOriginManual = "manual"
+)
+
+// Entity represents a canonical entity in the registry
+type Entity struct {
+	ID            int64         `json:"id" db:"id"`
+	CanonicalName string        `json:"canonical_name" db:"canonical_name"`
+	EntityType    string        `json:"entity_type" db:"entity_type"`
+	ExternalID    *string       `json:"external_id,omitempty" db:"external_id"`
+	Description   *string       `json:"description,omitempty" db:"description"`
+	MergedInto    *int64        `json:"merged_into,omitempty" db:"merged_into"`
+	Origin        string        `json:"origin" db:"origin"`
+	Aliases       []EntityAlias `json:"aliases,omitempty"`
+	ArticleCount  int           `json:"article_count"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// EntityAlias represents a surface form that resolves to an entity
+type EntityAlias struct {
+	ID              int64     `json:"id" db:"id"`
+	EntityID        int64     `json:"entity_id" db:"entity_id"`
+	Alias           string    `json:"alias" db:"alias"`
+	AliasNormalized string    `json:"alias_normalized" db:"alias_normalized"`
+	EntityType      string    `json:"entity_type" db:"entity_type"`
+	Origin          string    `json:"origin" db:"origin"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// EntityFilter represents filters for listing entities
+type EntityFilter struct {
+	EntityType string
+	Search     string
+	Limit      int
+	Offset     int
+}
+
+// EntityMergeRequest represents a request to merge entities into a target
+type EntityMergeRequest struct {
+	TargetID  int64   `json:"target_id"`
+	SourceIDs []int64 `json:"source_ids"`
+}
+
+// EntitySplitRequest represents a request to split aliases off an entity
+type EntitySplitRequest struct {
+	Aliases       []string `json:"aliases"`
+	CanonicalName string   `json:"canonical_name"`
+	EntityType    string   `json:"entity_type,omitempty"`
+	ExternalID    string   `json:"external_id,omitempty"`
+}
+
+// EntityMergeCandidate is a pair of entities that may be the same, queued for review
+type EntityMergeCandidate struct {
+	ID            int64     `json:"id" db:"id"`
+	EntityID      int64     `json:"entity_id" db:"entity_id"`
+	EntityName    string    `json:"entity_name"`
+	CandidateID   int64     `json:"candidate_id" db:"candidate_id"`
+	CandidateName string    `json:"candidate_name"`
+	Reason        string    `json:"reason" db:"reason"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Bulk export formats
+const (
+	ExportFormatNDJSON  = "ndjson"
+	ExportFormatCSV     = "csv"
+	ExportFormatParquet = "parquet"
+)
+
+// ExportFormats lists the supported bulk export formats
+var ExportFormats = []string{ExportFormatNDJSON, ExportFormatCSV, ExportFormatParquet}
+
+// ExportRow is one article with its AI columns flattened for datasets
+type ExportRow struct {
+	ID               int64      `json:"id" parquet:"id"`
+	Title            string     `json:"title" parquet:"title"`
+	Summary          string     `json:"summary" parquet:"summary"`
+	URL              string     `json:"url" parquet:"url"`
+	Published        time.Time  `json:"published" parquet:"published,timestamp(millisecond)"`
+	Source           string     `json:"source" parquet:"source"`
+	Category         string     `json:"category" parquet:"category"`
+	Author           string     `json:"author" parquet:"author"`
+	Language         string     `json:"language" parquet:"language"`
+	Keywords         []string   `json:"keywords" parquet:"keywords,list"`
+	CreatedAt        time.Time  `json:"created_at" parquet:"created_at,timestamp(millisecond)"`
+	AIProcessed      bool       `json:"ai_processed" parquet:"ai_processed"`
+	AISentiment      *float64   `json:"ai_sentiment" parquet:"ai_sentiment,optional"`
+	AISentimentLabel string     `json:"ai_sentiment_label" parquet:"ai_sentiment_label"`
+	AICategories     []string   `json:"ai_categories" parquet:"ai_categories,list"` // Highest score first
+	AIPersons        []string   `json:"ai_persons" parquet:"ai_persons,list"`
+	AIOrganizations  []string   `json:"ai_organizations" parquet:"ai_organizations,list"`
+	AILocations      []string   `json:"ai_locations" parquet:"ai_locations,list"`
+	AIKeywords       []string   `json:"ai_keywords" parquet:"ai_keywords,list"`
+	AIStockTickers   []string   `json:"ai_stock_tickers" parquet:"ai_stock_tickers,list"`
+	AISummary        string     `json:"ai_summary" parquet:"ai_summary"`
+	AIProcessedAt    *time.Time `json:"ai_processed_at" parquet:"ai_processed_at,optional"`
+}
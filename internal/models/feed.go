@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Syndication feed formats
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+	FeedFormatJSON = "json"
+)
+
+// FeedItem is an article with the AI enrichment published in syndication feeds
+type FeedItem struct {
+	Article
+	AISummary        string    `json:"ai_summary,omitempty"`
+	AISentiment      *float64  `json:"ai_sentiment,omitempty"`
+	AISentimentLabel string    `json:"ai_sentiment_label,omitempty"`
+	ModifiedAt       time.Time `json:"modified_at"` // Latest of updated_at and ai_processed_at
+}
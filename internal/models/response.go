@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/pagination"
 )
 
 // APIResponse is a standardized wrapper for all API responses
@@ -23,20 +25,33 @@ type APIError struct {
 
 // Meta contains metadata for paginated responses
 type Meta struct {
-	Pagination *PaginationMeta `json:"pagination,omitempty"`
-	Sorting    *SortingMeta    `json:"sorting,omitempty"`
-	Filtering  *FilteringMeta  `json:"filtering,omitempty"`
+	Pagination *PaginationMeta  `json:"pagination,omitempty"`
+	Sorting    *SortingMeta     `json:"sorting,omitempty"`
+	Filtering  *FilteringMeta   `json:"filtering,omitempty"`
+	Search     *SavedSearchMeta `json:"saved_search,omitempty"`
+}
+
+// SavedSearchMeta describes the saved search behind a result list
+type SavedSearchMeta struct {
+	ID                 int64     `json:"id"`
+	Name               string    `json:"name"`
+	NewSinceLastViewed int       `json:"new_since_last_viewed"`
+	LastViewedAt       time.Time `json:"last_viewed_at"` // Before this request marked the search viewed
 }
 
-// PaginationMeta contains enhanced pagination metadata
+// PaginationMeta contains enhanced pagination metadata.
+// Total, CurrentPage and TotalPages are omitted when the total count was skipped;
+// NextCursor/PrevCursor are set for keyset-paginated lists.
 type PaginationMeta struct {
-	Total       int  `json:"total"`
-	Limit       int  `json:"limit"`
-	Offset      int  `json:"offset"`
-	CurrentPage int  `json:"current_page"`
-	TotalPages  int  `json:"total_pages"`
-	HasNext     bool `json:"has_next"`
-	HasPrev     bool `json:"has_prev"`
+	Total       *int   `json:"total,omitempty"`
+	Limit       int    `json:"limit"`
+	Offset      int    `json:"offset"`
+	CurrentPage int    `json:"current_page,omitempty"`
+	TotalPages  int    `json:"total_pages,omitempty"`
+	HasNext     bool   `json:"has_next"`
+	HasPrev     bool   `json:"has_prev"`
+	NextCursor  string `json:"next_cursor,omitempty"`
+	PrevCursor  string `json:"prev_cursor,omitempty"`
 }
 
 // SortingMeta contains sorting information
@@ -51,6 +66,7 @@ type FilteringMeta struct {
 	Category  string `json:"category,omitempty"`
 	Keyword   string `json:"keyword,omitempty"`
 	Search    string `json:"search,omitempty"`
+	Language  string `json:"language,omitempty"`
 	StartDate string `json:"start_date,omitempty"`
 	EndDate   string `json:"end_date,omitempty"`
 }
@@ -143,7 +159,7 @@ func CalculatePaginationMeta(total, limit, offset int) *PaginationMeta {
 	}
 
 	return &PaginationMeta{
-		Total:       total,
+		Total:       &total,
 		Limit:       limit,
 		Offset:      offset,
 		CurrentPage: currentPage,
@@ -152,3 +168,25 @@ func CalculatePaginationMeta(total, limit, offset int) *PaginationMeta {
 		HasPrev:     offset > 0,
 	}
 }
+
+// CalculateCursorPaginationMeta builds pagination metadata for a keyset-paginated page.
+// total is nil when the count was skipped.
+func CalculateCursorPaginationMeta(total *int, limit, offset int, page pagination.PageInfo) *PaginationMeta {
+	var meta *PaginationMeta
+	if total != nil {
+		meta = CalculatePaginationMeta(*total, limit, offset)
+	} else {
+		meta = &PaginationMeta{Limit: limit, Offset: offset}
+	}
+
+	meta.HasNext = page.HasNext
+	meta.HasPrev = page.HasPrev
+	meta.NextCursor = page.NextCursor
+	meta.PrevCursor = page.PrevCursor
+	if page.NextCursor != "" || page.PrevCursor != "" {
+		// Page numbers are meaningless once the client follows cursors
+		meta.CurrentPage = 0
+		meta.TotalPages = 0
+	}
+	return meta
+}
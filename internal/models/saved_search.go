@@ -0,0 +1,100 @@
+package models
+
+import "time"
+
+// Saved search alert channels
+const (
+	AlertChannelWebhook = "webhook"
+	AlertChannelEmail   = "email"
+	AlertChannelStream  = "stream"
+)
+
+// AlertChannels lists the channels a saved search can alert through
+var AlertChannels = []string{AlertChannelWebhook, AlertChannelEmail, AlertChannelStream}
+
+// SavedSearchQuery is the stored query: the ArticleFilter fields plus AI enrichment filters.
+// Feeds use the same query.
+type SavedSearchQuery struct {
+	Source         string     `json:"source,omitempty"`
+	Category       string     `json:"category,omitempty"`
+	Keyword        string     `json:"keyword,omitempty"`
+	Search         string     `json:"search,omitempty"` // Full-text query
+	Language       string     `json:"language,omitempty"`
+	StartDate      *time.Time `json:"start_date,omitempty"`
+	EndDate        *time.Time `json:"end_date,omitempty"`
+	Entity         string     `json:"entity,omitempty"` // Person, organization or location
+	Ticker         string     `json:"ticker,omitempty"`
+	SentimentLabel string     `json:"sentiment,omitempty"` // positive, neutral or negative
+	MinSentiment   *float64   `json:"min_sentiment,omitempty"`
+	MaxSentiment   *float64   `json:"max_sentiment,omitempty"`
+}
+
+// NeedsEnrichment reports whether the query filters on AI enrichment, which new
+// articles only have once they are processed
+func (q SavedSearchQuery) NeedsEnrichment() bool {
+	return q.Entity != "" || q.Ticker != "" || q.SentimentLabel != "" || q.MinSentiment != nil || q.MaxSentiment != nil
+}
+
+// SavedSearch is a named query owned by a user or API key
+type SavedSearch struct {
+	ID                    int64            `json:"id" db:"id"`
+	Owner                 string           `json:"owner,omitempty" db:"owner"`
+	Name                  string           `json:"name" db:"name"`
+	Query                 SavedSearchQuery `json:"query" db:"query"`
+	AlertChannels         []string         `json:"alert_channels" db:"alert_channels"`
+	WebhookSubscriptionID *int64           `json:"webhook_subscription_id,omitempty" db:"webhook_subscription_id"`
+	Email                 string           `json:"email,omitempty" db:"email"`
+	LastViewedAt          time.Time        `json:"last_viewed_at" db:"last_viewed_at"`
+	LastMatchedAt         *time.Time       `json:"last_matched_at,omitempty" db:"last_matched_at"`
+	CreatedAt             time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time        `json:"updated_at" db:"updated_at"`
+	NewSinceLastViewed    *int             `json:"new_since_last_viewed,omitempty" db:"-"`
+}
+
+// HasChannel reports whether the search alerts through channel
+func (s *SavedSearch) HasChannel(channel string) bool {
+	for _, c := range s.AlertChannels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// SavedSearchCreate is the request body for saving a search
+type SavedSearchCreate struct {
+	Name                  string           `json:"name"`
+	Query                 SavedSearchQuery `json:"query"`
+	AlertChannels         []string         `json:"alert_channels"`
+	WebhookSubscriptionID *int64           `json:"webhook_subscription_id"`
+	Email                 string           `json:"email"`
+}
+
+// SavedSearchUpdate is the request body for changing a saved search; nil fields are kept
+type SavedSearchUpdate struct {
+	Name                  *string           `json:"name"`
+	Query                 *SavedSearchQuery `json:"query"`
+	AlertChannels         *[]string         `json:"alert_channels"`
+	WebhookSubscriptionID *int64            `json:"webhook_subscription_id"`
+	Email                 *string           `json:"email"`
+}
+
+// SavedSearchAlert is sent to webhook and stream channels when an article matches
+type SavedSearchAlert struct {
+	SavedSearchID   int64             `json:"saved_search_id"`
+	SavedSearchName string            `json:"saved_search_name"`
+	Article         Article           `json:"article"`
+	Enrichment      *StreamEnrichment `json:"enrichment,omitempty"`
+}
+
+// SavedSearchDigestItem is one pending match for an email digest
+type SavedSearchDigestItem struct {
+	SavedSearchID   int64
+	SavedSearchName string
+	Email           string
+	ArticleID       int64
+	Title           string
+	URL             string
+	Source          string
+	Published       time.Time
+}
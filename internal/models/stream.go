@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Live stream event types
+const (
+	StreamEventArticleCreated  = "article.created"
+	StreamEventArticleEnriched = "article.enriched"
+	StreamEventSavedSearchHit  = "saved_search.matched"
+)
+
+// StreamEvent is pushed to live stream clients (SSE and WebSocket)
+type StreamEvent struct {
+	ID         int64             `json:"id"` // Monotonic; clients resume with Last-Event-ID
+	Type       string            `json:"type"`
+	Article    Article           `json:"article"`
+	Enrichment *StreamEnrichment `json:"enrichment,omitempty"`      // Only for article.enriched
+	SearchID   int64             `json:"saved_search_id,omitempty"` // Only for saved_search.matched
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// StreamEnrichment is the compact AI enrichment sent with article.enriched events
+type StreamEnrichment struct {
+	Sentiment      *float64 `json:"sentiment,omitempty"`
+	SentimentLabel string   `json:"sentiment_label,omitempty"`
+	Categories     []string `json:"categories,omitempty"`
+	Persons        []string `json:"persons,omitempty"`
+	Organizations  []string `json:"organizations,omitempty"`
+	Locations      []string `json:"locations,omitempty"`
+	StockTickers   []string `json:"stock_tickers,omitempty"`
+	Summary        string   `json:"summary,omitempty"`
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook event types
+const (
+	WebhookEventArticleCreated  = "article.created"
+	WebhookEventArticleEnriched = "article.enriched"
+	WebhookEventScrapeFailed    = "scrape.failed"
+	WebhookEventTest            = "webhook.test"
+	WebhookEventSavedSearchHit  = "saved_search.matched" // Sent to the subscription a saved search alerts through
+)
+
+// WebhookEventTypes lists the events a subscription can select
+var WebhookEventTypes = []string{
+	WebhookEventArticleCreated,
+	WebhookEventArticleEnriched,
+	WebhookEventScrapeFailed,
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending    = "pending"
+	WebhookDeliveryDelivering = "delivering"
+	WebhookDeliveryRetrying   = "retrying"
+	WebhookDeliverySucceeded  = "succeeded"
+	WebhookDeliveryFailed     = "failed"
+	WebhookDeliveryCancelled  = "cancelled"
+)
+
+// WebhookSubscription is a partner endpoint notified of events
+type WebhookSubscription struct {
+	ID                  int64      `json:"id" db:"id"`
+	Name                string     `json:"name" db:"name"`
+	Owner               string     `json:"owner,omitempty" db:"owner"`
+	URL                 string     `json:"url" db:"url"`
+	Secret              string     `json:"-" db:"secret"` // Only returned on creation
+	Events              []string   `json:"events" db:"events"`
+	Sources             []string   `json:"sources" db:"sources"`
+	Keywords            []string   `json:"keywords" db:"keywords"`
+	Entities            []string   `json:"entities" db:"entities"`
+	Tickers             []string   `json:"tickers" db:"tickers"`
+	MinSentiment        *float64   `json:"min_sentiment,omitempty" db:"min_sentiment"`
+	MaxSentiment        *float64   `json:"max_sentiment,omitempty" db:"max_sentiment"`
+	Active              bool       `json:"active" db:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures" db:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty" db:"disabled_at"`
+	DisabledReason      string     `json:"disabled_reason,omitempty" db:"disabled_reason"`
+	LastDeliveryAt      *time.Time `json:"last_delivery_at,omitempty" db:"last_delivery_at"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty" db:"last_success_at"`
+	CreatedBy           string     `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookSubscriptionCreate is the request body for creating a subscription
+type WebhookSubscriptionCreate struct {
+	Name         string   `json:"name"`
+	Owner        string   `json:"owner"`
+	URL          string   `json:"url"`
+	Events       []string `json:"events"` // Empty = all event types
+	Sources      []string `json:"sources"`
+	Keywords     []string `json:"keywords"`
+	Entities     []string `json:"entities"`
+	Tickers      []string `json:"tickers"`
+	MinSentiment *float64 `json:"min_sentiment"`
+	MaxSentiment *float64 `json:"max_sentiment"`
+}
+
+// WebhookSubscriptionUpdate is the request body for changing a subscription; nil fields are kept
+type WebhookSubscriptionUpdate struct {
+	Name           *string   `json:"name"`
+	Owner          *string   `json:"owner"`
+	URL            *string   `json:"url"`
+	Events         *[]string `json:"events"`
+	Sources        *[]string `json:"sources"`
+	Keywords       *[]string `json:"keywords"`
+	Entities       *[]string `json:"entities"`
+	Tickers        *[]string `json:"tickers"`
+	MinSentiment   *float64  `json:"min_sentiment"`
+	MaxSentiment   *float64  `json:"max_sentiment"`
+	ClearSentiment bool      `json:"clear_sentiment"` // Remove both sentiment bounds
+	Active         *bool     `json:"active"`          // Re-enabling resets the failure counter
+}
+
+// WebhookSubscriptionCreated is returned once on creation and includes the signing secret
+type WebhookSubscriptionCreated struct {
+	Subscription *WebhookSubscription `json:"subscription"`
+	Secret       string               `json:"secret"`
+}
+
+// WebhookDelivery is one event queued for, or delivered to, a subscription
+type WebhookDelivery struct {
+	ID             int64           `json:"id" db:"id"`
+	SubscriptionID int64           `json:"subscription_id" db:"subscription_id"`
+	EventID        string          `json:"event_id" db:"event_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload,omitempty" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	ResponseStatus *int            `json:"response_status,omitempty" db:"response_status"`
+	ResponseBody   string          `json:"response_body,omitempty" db:"response_body"`
+	ErrorMessage   string          `json:"error_message,omitempty" db:"error_message"`
+	DurationMs     *int            `json:"duration_ms,omitempty" db:"duration_ms"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+// WebhookPayload is the JSON body posted to subscribers
+type WebhookPayload struct {
+	ID        string      `json:"id"` // Event ID, identical across retries
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// ScrapeFailureEvent describes a failed or partially failed scrape
+type ScrapeFailureEvent struct {
+	Source        string    `json:"source"`
+	Status        string    `json:"status"`
+	Error         string    `json:"error"`
+	ArticlesFound int       `json:"articles_found"`
+	StartedAt     time.Time `json:"started_at"`
+	DurationMs    int64     `json:"duration_ms"`
+}
@@ -0,0 +1,153 @@
+// Package pagination implements opaque keyset cursors for time-ordered article lists.
+//
+// A cursor encodes the sort position (timestamp, id) of the first or last row of a page,
+// so the next page is fetched with "WHERE (published, id) < (...)" instead of OFFSET.
+// Pages stay stable when new articles arrive and deep pages are as fast as the first.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidCursor is returned for cursors that cannot be decoded or do not match the query
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// keysetColumns are the timestamp columns that support cursors; other sorts use offsets
+var keysetColumns = map[string]bool{
+	"published":  true,
+	"created_at": true,
+}
+
+// SupportsCursor reports whether a sort column can be paginated with cursors
+func SupportsCursor(sortBy string) bool {
+	return keysetColumns[sortBy]
+}
+
+// Cursor is a position in a list ordered by (SortBy, id)
+type Cursor struct {
+	SortBy    string    `json:"s"`
+	SortOrder string    `json:"o"` // asc or desc
+	Time      time.Time `json:"t"`
+	ID        int64     `json:"i"`
+	Backward  bool      `json:"b,omitempty"` // true for prev_cursor
+}
+
+// Encode returns the opaque cursor token
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses a cursor token and checks it belongs to a query with the given ordering
+func Decode(token, sortBy, sortOrder string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 || !SupportsCursor(c.SortBy) {
+		return nil, ErrInvalidCursor
+	}
+	if c.SortBy != sortBy || c.SortOrder != sortOrder {
+		return nil, fmt.Errorf("%w: cursor was issued for sort %s %s", ErrInvalidCursor, c.SortBy, c.SortOrder)
+	}
+	return &c, nil
+}
+
+// Keyset describes how to paginate one query
+type Keyset struct {
+	SortBy    string // published or created_at
+	SortOrder string // asc or desc
+	Limit     int
+	Cursor    *Cursor // nil for the first page
+}
+
+// scanDescending reports whether rows are read in descending order for this request
+func (k Keyset) scanDescending() bool {
+	desc := k.SortOrder != "asc"
+	if k.Cursor != nil && k.Cursor.Backward {
+		return !desc
+	}
+	return desc
+}
+
+// Condition returns the SQL condition selecting rows after the cursor (empty without cursor)
+// and its arguments, numbered from argPos.
+func (k Keyset) Condition(argPos int) (string, []interface{}) {
+	if k.Cursor == nil {
+		return "", nil
+	}
+	op := ">"
+	if k.scanDescending() {
+		op = "<"
+	}
+	return fmt.Sprintf(" AND (%s, id) %s ($%d, $%d)", k.SortBy, op, argPos, argPos+1),
+		[]interface{}{k.Cursor.Time, k.Cursor.ID}
+}
+
+// OrderBy returns the ORDER BY clause including the id tie-breaker
+func (k Keyset) OrderBy() string {
+	dir := "ASC"
+	if k.scanDescending() {
+		dir = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, id %s", k.SortBy, dir, dir)
+}
+
+// FetchLimit is the number of rows to query: one extra row reveals whether another page exists
+func (k Keyset) FetchLimit() int {
+	return k.Limit + 1
+}
+
+// PageInfo describes the position of a page
+type PageInfo struct {
+	HasNext    bool
+	HasPrev    bool
+	NextCursor string
+	PrevCursor string
+}
+
+// Finish trims the extra row, restores display order for backward pages and builds the
+// cursors. position returns the sort timestamp and id of an item. offset is only used to
+// detect a previous page for offset-based first requests.
+func Finish[T any](items []T, k Keyset, offset int, position func(T) (time.Time, int64)) ([]T, PageInfo) {
+	more := len(items) > k.Limit
+	if more {
+		items = items[:k.Limit]
+	}
+
+	var info PageInfo
+	switch {
+	case k.Cursor != nil && k.Cursor.Backward:
+		// Rows were read towards the start of the list; flip them back
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+		info.HasPrev = more
+		info.HasNext = true
+	case k.Cursor != nil:
+		info.HasPrev = true
+		info.HasNext = more
+	default:
+		info.HasPrev = offset > 0
+		info.HasNext = more
+	}
+
+	if len(items) == 0 {
+		return items, info
+	}
+	if info.HasNext {
+		t, id := position(items[len(items)-1])
+		info.NextCursor = Cursor{SortBy: k.SortBy, SortOrder: k.SortOrder, Time: t, ID: id}.Encode()
+	}
+	if info.HasPrev {
+		t, id := position(items[0])
+		info.PrevCursor = Cursor{SortBy: k.SortBy, SortOrder: k.SortOrder, Time: t, ID: id, Backward: true}.Encode()
+	}
+	return items, info
+}
@@ -0,0 +1,702 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// AnalyticsRepository runs analytics queries directly on articles and entities, for
+// analyses that need fresher or more detailed data than the materialized views hold
+type AnalyticsRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+// NewAnalyticsRepository creates a new analytics repository
+func NewAnalyticsRepository(db *pgxpool.Pool, log *logger.Logger) *AnalyticsRepository {
+	return &AnalyticsRepository{
+		db:     db,
+		logger: log.WithComponent("analytics-repo"),
+	}
+}
+
+// GetKeywordHourlyCounts returns hourly article counts over the last spanHours hours
+// before now for every keyword (lowercased) with at least minArticles articles in the
+// last currentHours hours
+func (r *AnalyticsRepository) GetKeywordHourlyCounts(ctx context.Context, now time.Time, spanHours, currentHours, minArticles int) ([]models.TermHourlyCounts, error) {
+	query := `
+		WITH mentions AS (
+			SELECT DISTINCT
+				LOWER(TRIM(kw.value->>'word')) AS term,
+				a.id,
+				FLOOR(EXTRACT(EPOCH FROM ($1::timestamptz - a.published)) / 3600)::int AS hours_ago
+			FROM articles a
+			CROSS JOIN LATERAL jsonb_array_elements(a.ai_keywords) AS kw(value)
+			WHERE a.ai_processed = TRUE
+			  AND jsonb_typeof(a.ai_keywords) = 'array'
+			  AND kw.value->>'word' IS NOT NULL
+			  AND a.published > $1::timestamptz - make_interval(hours => $2)
+			  AND a.published <= $1
+		),
+		candidates AS (
+			SELECT term
+			FROM mentions
+			WHERE hours_ago < $3 AND term <> ''
+			GROUP BY term
+			HAVING COUNT(*) >= $4
+		)
+		SELECT m.term, m.hours_ago, COUNT(*)
+		FROM mentions m
+		JOIN candidates c ON c.term = m.term
+		GROUP BY m.term, m.hours_ago
+		ORDER BY m.term
+	`
+
+	rows, err := r.db.Query(ctx, query, now, spanHours, currentHours, minArticles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keyword hourly counts: %w", err)
+	}
+	defer rows.Close()
+
+	var series []models.TermHourlyCounts
+	for rows.Next() {
+		var term string
+		var hoursAgo, count int
+		if err := rows.Scan(&term, &hoursAgo, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan keyword hourly count: %w", err)
+		}
+		if len(series) == 0 || series[len(series)-1].Term != term {
+			series = append(series, models.TermHourlyCounts{
+				Kind:   models.BurstKindKeyword,
+				Term:   term,
+				Counts: make([]int, spanHours),
+			})
+		}
+		if hoursAgo >= 0 && hoursAgo < spanHours {
+			series[len(series)-1].Counts[hoursAgo] = count
+		}
+	}
+
+	return series, rows.Err()
+}
+
+// GetEntityHourlyCounts returns hourly article counts over the last spanHours hours
+// before now for every canonical entity with at least minArticles articles in the last
+// currentHours hours. Mentions of merged entities count for their target.
+func (r *AnalyticsRepository) GetEntityHourlyCounts(ctx context.Context, now time.Time, spanHours, currentHours, minArticles int, entityType string) ([]models.TermHourlyCounts, error) {
+	query := `
+		WITH mentions AS (
+			SELECT DISTINCT
+				COALESCE(e.merged_into, e.id) AS entity_id,
+				a.id,
+				FLOOR(EXTRACT(EPOCH FROM ($1::timestamptz - a.published)) / 3600)::int AS hours_ago
+			FROM article_entities ae
+			JOIN articles a ON a.id = ae.article_id
+			JOIN entities e ON e.id = ae.entity_id
+			WHERE a.published > $1::timestamptz - make_interval(hours => $2)
+			  AND a.published <= $1
+			  AND ($5 = '' OR e.entity_type = $5)
+		),
+		candidates AS (
+			SELECT entity_id
+			FROM mentions
+			WHERE hours_ago < $3
+			GROUP BY entity_id
+			HAVING COUNT(*) >= $4
+		)
+		SELECT e.id, e.canonical_name, e.entity_type, m.hours_ago, COUNT(*)
+		FROM mentions m
+		JOIN candidates c ON c.entity_id = m.entity_id
+		JOIN entities e ON e.id = m.entity_id
+		GROUP BY e.id, e.canonical_name, e.entity_type, m.hours_ago
+		ORDER BY e.id
+	`
+
+	rows, err := r.db.Query(ctx, query, now, spanHours, currentHours, minArticles, entityType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity hourly counts: %w", err)
+	}
+	defer rows.Close()
+
+	var series []models.TermHourlyCounts
+	for rows.Next() {
+		var entityID int64
+		var name, typ string
+		var hoursAgo, count int
+		if err := rows.Scan(&entityID, &name, &typ, &hoursAgo, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan entity hourly count: %w", err)
+		}
+		if len(series) == 0 || series[len(series)-1].EntityID != entityID {
+			series = append(series, models.TermHourlyCounts{
+				Kind:       models.BurstKindEntity,
+				Term:       name,
+				EntityID:   entityID,
+				EntityType: typ,
+				Counts:     make([]int, spanHours),
+			})
+		}
+		if hoursAgo >= 0 && hoursAgo < spanHours {
+			series[len(series)-1].Counts[hoursAgo] = count
+		}
+	}
+
+	return series, rows.Err()
+}
+
+// GetKeywordArticles returns the newest articles published in (since, until] with the
+// keyword (case-insensitive)
+func (r *AnalyticsRepository) GetKeywordArticles(ctx context.Context, keyword string, since, until time.Time, limit int) ([]models.BurstArticle, error) {
+	query := `
+		SELECT a.id, a.title, a.url, a.source, a.published
+		FROM articles a
+		WHERE a.ai_processed = TRUE
+		  AND jsonb_typeof(a.ai_keywords) = 'array'
+		  AND a.published > $2 AND a.published <= $3
+		  AND EXISTS (
+			SELECT 1 FROM jsonb_array_elements(a.ai_keywords) AS kw(value)
+			WHERE LOWER(TRIM(kw.value->>'word')) = $1
+		  )
+		ORDER BY a.published DESC
+		LIMIT $4
+	`
+	return r.queryBurstArticles(ctx, query, keyword, since, until, limit)
+}
+
+// GetEntityArticles returns the newest articles published in (since, until] mentioning
+// the entity or an entity merged into it
+func (r *AnalyticsRepository) GetEntityArticles(ctx context.Context, entityID int64, since, until time.Time, limit int) ([]models.BurstArticle, error) {
+	query := `
+		SELECT a.id, a.title, a.url, a.source, a.published
+		FROM articles a
+		WHERE a.published > $2 AND a.published <= $3
+		  AND EXISTS (
+			SELECT 1
+			FROM article_entities ae
+			JOIN entities e ON e.id = ae.entity_id
+			WHERE ae.article_id = a.id AND COALESCE(e.merged_into, e.id) = $1
+		  )
+		ORDER BY a.published DESC
+		LIMIT $4
+	`
+	return r.queryBurstArticles(ctx, query, entityID, since, until, limit)
+}
+
+func (r *AnalyticsRepository) queryBurstArticles(ctx context.Context, query string, term interface{}, since, until time.Time, limit int) ([]models.BurstArticle, error) {
+	rows, err := r.db.Query(ctx, query, term, since, until, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get burst articles: %w", err)
+	}
+	defer rows.Close()
+
+	articles := make([]models.BurstArticle, 0, limit)
+	for rows.Next() {
+		var a models.BurstArticle
+		if err := rows.Scan(&a.ID, &a.Title, &a.URL, &a.Source, &a.Published); err != nil {
+			return nil, fmt.Errorf("failed to scan burst article: %w", err)
+		}
+		articles = append(articles, a)
+	}
+
+	return articles, rows.Err()
+}
+
+// entityMentionsCTE selects the distinct (article, canonical entity) pairs of articles
+// published in ($1, $2]; mentions of merged entities count for their target
+const entityMentionsCTE = `
+	mentions AS (
+		SELECT DISTINCT ae.article_id, COALESCE(e.merged_into, e.id) AS entity_id, a.ai_sentiment
+		FROM article_entities ae
+		JOIN articles a ON a.id = ae.article_id
+		JOIN entities e ON e.id = ae.entity_id
+		WHERE a.published > $1 AND a.published <= $2
+	)`
+
+// ResolveEntityID resolves a canonical entity ID, alias, canonical name or external ID to
+// the ID of its canonical entity
+func (r *AnalyticsRepository) ResolveEntityID(ctx context.Context, entity string) (int64, error) {
+	var id *int64
+	var err error
+	if n, convErr := strconv.ParseInt(entity, 10, 64); convErr == nil {
+		err = r.db.QueryRow(ctx, `SELECT COALESCE(merged_into, id) FROM entities WHERE id = $1`, n).Scan(&id)
+	} else {
+		err = r.db.QueryRow(ctx, `SELECT resolve_entity_id($1)`, entity).Scan(&id)
+	}
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && id == nil) {
+		return 0, ErrEntityNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve entity: %w", err)
+	}
+	return *id, nil
+}
+
+// GetTopEntityIDs returns the most mentioned entities in the query window
+func (r *AnalyticsRepository) GetTopEntityIDs(ctx context.Context, q models.EntityGraphQuery) ([]int64, error) {
+	query := `
+		WITH ` + entityMentionsCTE + `
+		SELECT m.entity_id
+		FROM mentions m
+		JOIN entities e ON e.id = m.entity_id
+		WHERE ($3 = '' OR e.entity_type = $3)
+		GROUP BY m.entity_id
+		ORDER BY COUNT(*) DESC, m.entity_id
+		LIMIT $4
+	`
+	return r.queryEntityIDs(ctx, query, q.Since, q.Until, q.EntityType, q.Limit)
+}
+
+// GetCoMentionedEntityIDs returns the entities most often mentioned together with the
+// query's center entity, in at least MinWeight articles
+func (r *AnalyticsRepository) GetCoMentionedEntityIDs(ctx context.Context, q models.EntityGraphQuery) ([]int64, error) {
+	query := `
+		WITH ` + entityMentionsCTE + `
+		SELECT other.entity_id
+		FROM mentions center
+		JOIN mentions other ON other.article_id = center.article_id AND other.entity_id <> center.entity_id
+		JOIN entities e ON e.id = other.entity_id
+		WHERE center.entity_id = $3
+		  AND ($4 = '' OR e.entity_type = $4)
+		GROUP BY other.entity_id
+		HAVING COUNT(*) >= $5
+		ORDER BY COUNT(*) DESC, other.entity_id
+		LIMIT $6
+	`
+	return r.queryEntityIDs(ctx, query, q.Since, q.Until, q.CenterID, q.EntityType, q.MinWeight, q.Limit-1)
+}
+
+func (r *AnalyticsRepository) queryEntityIDs(ctx context.Context, query string, args ...interface{}) ([]int64, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph entities: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan graph entity: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetEntityGraphNodes returns the mention counts and average sentiment of the entities
+// in the query window, most mentioned first
+func (r *AnalyticsRepository) GetEntityGraphNodes(ctx context.Context, q models.EntityGraphQuery, ids []int64) ([]models.EntityGraphNode, error) {
+	query := `
+		WITH ` + entityMentionsCTE + `
+		SELECT e.id, e.canonical_name, e.entity_type, COUNT(*), AVG(m.ai_sentiment)
+		FROM mentions m
+		JOIN entities e ON e.id = m.entity_id
+		WHERE m.entity_id = ANY($3)
+		GROUP BY e.id, e.canonical_name, e.entity_type
+		ORDER BY COUNT(*) DESC, e.id
+	`
+
+	rows, err := r.db.Query(ctx, query, q.Since, q.Until, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph nodes: %w", err)
+	}
+	defer rows.Close()
+
+	nodes := make([]models.EntityGraphNode, 0, len(ids))
+	for rows.Next() {
+		var n models.EntityGraphNode
+		if err := rows.Scan(&n.ID, &n.Label, &n.EntityType, &n.Mentions, &n.AvgSentiment); err != nil {
+			return nil, fmt.Errorf("failed to scan graph node: %w", err)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, rows.Err()
+}
+
+// GetEntityGraphEdges returns the co-mention counts of every pair of the entities
+// mentioned together in at least MinWeight articles, heaviest first. Source < target.
+func (r *AnalyticsRepository) GetEntityGraphEdges(ctx context.Context, q models.EntityGraphQuery, ids []int64) ([]models.EntityGraphEdge, error) {
+	query := `
+		WITH ` + entityMentionsCTE + `
+		SELECT m1.entity_id, m2.entity_id, COUNT(*), AVG(m1.ai_sentiment)
+		FROM mentions m1
+		JOIN mentions m2 ON m2.article_id = m1.article_id AND m2.entity_id > m1.entity_id
+		WHERE m1.entity_id = ANY($3) AND m2.entity_id = ANY($3)
+		GROUP BY m1.entity_id, m2.entity_id
+		HAVING COUNT(*) >= $4
+		ORDER BY COUNT(*) DESC, m1.entity_id, m2.entity_id
+	`
+
+	rows, err := r.db.Query(ctx, query, q.Since, q.Until, ids, q.MinWeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph edges: %w", err)
+	}
+	defer rows.Close()
+
+	edges := make([]models.EntityGraphEdge, 0)
+	for rows.Next() {
+		var e models.EntityGraphEdge
+		if err := rows.Scan(&e.Source, &e.Target, &e.Weight, &e.AvgSentiment); err != nil {
+			return nil, fmt.Errorf("failed to scan graph edge: %w", err)
+		}
+		edges = append(edges, e)
+	}
+	return edges, rows.Err()
+}
+
+// RefreshTickerNews recomputes the news columns of the ticker series for the UTC days in
+// [from, to] from the articles whose ai_stock_tickers mention the symbol
+func (r *AnalyticsRepository) RefreshTickerNews(ctx context.Context, symbol string, from, to time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Days whose articles were reprocessed without the ticker drop back to zero
+	if _, err := tx.Exec(ctx, `
+		UPDATE ticker_daily_stats
+		SET article_count = 0, positive_count = 0, negative_count = 0, avg_sentiment = NULL, news_updated_at = NOW()
+		WHERE symbol = $1 AND day BETWEEN $2 AND $3 AND article_count > 0
+	`, symbol, from, to); err != nil {
+		return fmt.Errorf("failed to reset ticker news: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO ticker_daily_stats (symbol, day, article_count, positive_count, negative_count, avg_sentiment, news_updated_at)
+		SELECT $1,
+		       (a.published AT TIME ZONE 'UTC')::date,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE a.ai_sentiment_label = 'positive'),
+		       COUNT(*) FILTER (WHERE a.ai_sentiment_label = 'negative'),
+		       AVG(a.ai_sentiment)::double precision,
+		       NOW()
+		FROM articles a
+		WHERE a.ai_processed = TRUE
+		  AND jsonb_typeof(a.ai_stock_tickers) = 'array'
+		  AND a.published >= $2::date
+		  AND a.published < $3::date + 1
+		  AND EXISTS (
+			SELECT 1 FROM jsonb_array_elements(a.ai_stock_tickers) AS t(value)
+			WHERE UPPER(t.value->>'symbol') = $1
+		  )
+		GROUP BY 2
+		ON CONFLICT (symbol, day) DO UPDATE SET
+			article_count = EXCLUDED.article_count,
+			positive_count = EXCLUDED.positive_count,
+			negative_count = EXCLUDED.negative_count,
+			avg_sentiment = EXCLUDED.avg_sentiment,
+			news_updated_at = EXCLUDED.news_updated_at
+	`, symbol, from, to); err != nil {
+		return fmt.Errorf("failed to aggregate ticker news: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit ticker news: %w", err)
+	}
+	return nil
+}
+
+// GetTickerPriceCoverage returns the first UTC day with a price in the ticker series and
+// when prices were last stored; ok is false when the series has no prices
+func (r *AnalyticsRepository) GetTickerPriceCoverage(ctx context.Context, symbol string) (firstDay, updatedAt time.Time, ok bool, err error) {
+	var first, updated *time.Time
+	err = r.db.QueryRow(ctx, `
+		SELECT MIN(day)::timestamptz, MAX(price_updated_at)
+		FROM ticker_daily_stats
+		WHERE symbol = $1 AND close_price IS NOT NULL
+	`, symbol).Scan(&first, &updated)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("failed to get ticker price coverage: %w", err)
+	}
+	if first == nil || updated == nil {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	return *first, *updated, true, nil
+}
+
+// UpsertTickerPrices stores adjusted closes and returns in the ticker series
+func (r *AnalyticsRepository) UpsertTickerPrices(ctx context.Context, symbol string, prices []models.TickerPrice) error {
+	if len(prices) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, p := range prices {
+		// A nil return keeps a previously computed one, the first day of a range has none
+		batch.Queue(`
+			INSERT INTO ticker_daily_stats (symbol, day, close_price, daily_return, price_updated_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			ON CONFLICT (symbol, day) DO UPDATE SET
+				close_price = EXCLUDED.close_price,
+				daily_return = COALESCE(EXCLUDED.daily_return, ticker_daily_stats.daily_return),
+				price_updated_at = EXCLUDED.price_updated_at
+		`, symbol, p.Day, p.Close, p.Return)
+	}
+
+	results := r.db.SendBatch(ctx, batch)
+	for range prices {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("failed to store ticker price: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("failed to store ticker prices: %w", err)
+	}
+	return nil
+}
+
+// GetTickerDailySeries returns the ticker series for the UTC days in [from, to], oldest first
+func (r *AnalyticsRepository) GetTickerDailySeries(ctx context.Context, symbol string, from, to time.Time) ([]models.TickerDay, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT day::timestamptz, article_count, positive_count, negative_count, avg_sentiment, close_price, daily_return
+		FROM ticker_daily_stats
+		WHERE symbol = $1 AND day BETWEEN $2 AND $3
+		ORDER BY day
+	`, symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticker series: %w", err)
+	}
+	defer rows.Close()
+
+	series := make([]models.TickerDay, 0)
+	for rows.Next() {
+		var d models.TickerDay
+		if err := rows.Scan(&d.Day, &d.ArticleCount, &d.PositiveCount, &d.NegativeCount,
+			&d.AvgSentiment, &d.Close, &d.Return); err != nil {
+			return nil, fmt.Errorf("failed to scan ticker day: %w", err)
+		}
+		series = append(series, d)
+	}
+	return series, rows.Err()
+}
+
+// GetSourceArticles returns the articles published in (since, until] with the canonical
+// entities they mention, oldest first
+func (r *AnalyticsRepository) GetSourceArticles(ctx context.Context, since, until time.Time) ([]models.SourceArticle, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.source, a.title, a.published, COALESCE(a.category, ''),
+		       a.ai_sentiment::double precision, COALESCE(a.ai_sentiment_label, ''),
+		       COALESCE((
+				SELECT array_agg(DISTINCT COALESCE(e.merged_into, e.id))
+				FROM article_entities ae
+				JOIN entities e ON e.id = ae.entity_id
+				WHERE ae.article_id = a.id
+		       ), '{}')
+		FROM articles a
+		WHERE a.published > $1 AND a.published <= $2
+		ORDER BY a.published, a.id
+	`, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source articles: %w", err)
+	}
+	defer rows.Close()
+
+	articles := make([]models.SourceArticle, 0)
+	for rows.Next() {
+		var a models.SourceArticle
+		if err := rows.Scan(&a.ID, &a.Source, &a.Title, &a.Published, &a.Category,
+			&a.Sentiment, &a.SentimentLabel, &a.EntityIDs); err != nil {
+			return nil, fmt.Errorf("failed to scan source article: %w", err)
+		}
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
+// GetSourceNames returns the configured names of the sources by domain, the value
+// articles store in their source column
+func (r *AnalyticsRepository) GetSourceNames(ctx context.Context) (map[string]string, error) {
+	rows, err := r.db.Query(ctx, `SELECT domain, name FROM sources`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source names: %w", err)
+	}
+	defer rows.Close()
+
+	names := make(map[string]string)
+	for rows.Next() {
+		var domain, name string
+		if err := rows.Scan(&domain, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan source name: %w", err)
+		}
+		names[domain] = name
+	}
+	return names, rows.Err()
+}
+
+// GetEntityNames returns the canonical names of the given entities
+func (r *AnalyticsRepository) GetEntityNames(ctx context.Context, ids []int64) (map[int64]string, error) {
+	names := make(map[int64]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT id, canonical_name FROM entities WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity names: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan entity name: %w", err)
+		}
+		names[id] = name
+	}
+	return names, rows.Err()
+}
+
+// Time series SQL fragments. Query parameters only ever select one of these fragments,
+// user input reaches the database as bind parameters.
+var (
+	timeSeriesMetricSQL = map[string]string{
+		models.TimeSeriesMetricArticles:       "COUNT(DISTINCT a.id)::double precision",
+		models.TimeSeriesMetricAvgSentiment:   "AVG(a.ai_sentiment)::double precision",
+		models.TimeSeriesMetricEntityMentions: "COUNT(*)::double precision", // Rows are distinct (article, entity) pairs
+	}
+	timeSeriesGroupSQL = map[string]string{
+		"":                              "''",
+		models.TimeSeriesGroupSource:    "a.source",
+		models.TimeSeriesGroupCategory:  "COALESCE(a.category, '')",
+		models.TimeSeriesGroupEntity:    "m.entity_id::text",
+		models.TimeSeriesGroupTicker:    "tk.symbol",
+		models.TimeSeriesGroupSentiment: "COALESCE(a.ai_sentiment_label, '')",
+	}
+	timeSeriesBucketSQL = map[string]string{
+		models.TimeSeriesBucketHour: "date_trunc('hour', a.published AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'",
+		models.TimeSeriesBucketDay:  "date_trunc('day', a.published AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'",
+		models.TimeSeriesBucketWeek: "date_trunc('week', a.published AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'",
+	}
+)
+
+// GetTimeSeries runs a time series query and returns the buckets of the q.Limit groups
+// with most articles, ordered by group and bucket. Buckets without articles are absent.
+func (r *AnalyticsRepository) GetTimeSeries(ctx context.Context, q models.TimeSeriesQuery) ([]models.TimeSeriesRow, error) {
+	metric, ok := timeSeriesMetricSQL[q.Metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown time series metric %q", q.Metric)
+	}
+	group, ok := timeSeriesGroupSQL[q.GroupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown time series group %q", q.GroupBy)
+	}
+	bucket, ok := timeSeriesBucketSQL[q.Bucket]
+	if !ok {
+		return nil, fmt.Errorf("unknown time series bucket %q", q.Bucket)
+	}
+
+	args := []interface{}{q.Since, q.Until, q.Limit}
+	argPos := 4
+	joins := ""
+	where := "a.published >= $1 AND a.published < $2"
+
+	if q.Metric == models.TimeSeriesMetricEntityMentions || q.GroupBy == models.TimeSeriesGroupEntity {
+		typeFilter := ""
+		if q.Filters.EntityType != "" {
+			typeFilter = fmt.Sprintf(" AND ce.entity_type = $%d", argPos)
+			args = append(args, q.Filters.EntityType)
+			argPos++
+		}
+		joins += `
+			JOIN (
+				SELECT DISTINCT ae.article_id, ce.id AS entity_id
+				FROM article_entities ae
+				JOIN entities e ON e.id = ae.entity_id
+				JOIN entities ce ON ce.id = COALESCE(e.merged_into, e.id)
+				WHERE TRUE` + typeFilter + `
+			) m ON m.article_id = a.id`
+	}
+	if q.GroupBy == models.TimeSeriesGroupTicker {
+		joins += `
+			CROSS JOIN LATERAL (
+				SELECT DISTINCT UPPER(t.value->>'symbol') AS symbol
+				FROM jsonb_array_elements(CASE WHEN jsonb_typeof(a.ai_stock_tickers) = 'array'
+					THEN a.ai_stock_tickers ELSE '[]'::jsonb END) AS t(value)
+				WHERE t.value->>'symbol' <> ''
+			) tk`
+	}
+
+	if len(q.Filters.Sources) > 0 {
+		where += fmt.Sprintf(" AND a.source = ANY($%d)", argPos)
+		args = append(args, q.Filters.Sources)
+		argPos++
+	}
+	if len(q.Filters.Categories) > 0 {
+		where += fmt.Sprintf(" AND a.category = ANY($%d)", argPos)
+		args = append(args, q.Filters.Categories)
+		argPos++
+	}
+	if q.Filters.Sentiment != "" {
+		where += fmt.Sprintf(" AND a.ai_sentiment_label = $%d", argPos)
+		args = append(args, q.Filters.Sentiment)
+		argPos++
+	}
+	if len(q.Filters.Tickers) > 0 {
+		where += fmt.Sprintf(` AND jsonb_typeof(a.ai_stock_tickers) = 'array' AND EXISTS (
+				SELECT 1 FROM jsonb_array_elements(a.ai_stock_tickers) AS ft(value)
+				WHERE UPPER(ft.value->>'symbol') = ANY($%d)
+			)`, argPos)
+		args = append(args, q.Filters.Tickers)
+		argPos++
+	}
+	if q.Filters.EntityID > 0 {
+		where += fmt.Sprintf(` AND EXISTS (
+				SELECT 1 FROM article_entities fae
+				JOIN entities fe ON fe.id = fae.entity_id
+				WHERE fae.article_id = a.id AND COALESCE(fe.merged_into, fe.id) = $%d
+			)`, argPos)
+		args = append(args, q.Filters.EntityID)
+	}
+
+	label := "s.group_key"
+	if q.GroupBy == models.TimeSeriesGroupEntity {
+		label = "COALESCE((SELECT canonical_name FROM entities WHERE id = s.group_key::bigint), s.group_key)"
+	}
+
+	query := `
+		WITH series AS (
+			SELECT ` + bucket + ` AS bucket, ` + group + ` AS group_key,
+			       ` + metric + ` AS value, COUNT(DISTINCT a.id) AS articles
+			FROM articles a` + joins + `
+			WHERE ` + where + `
+			GROUP BY 1, 2
+		),
+		top_groups AS (
+			SELECT group_key
+			FROM series
+			GROUP BY group_key
+			ORDER BY SUM(articles) DESC, group_key
+			LIMIT $3
+		)
+		SELECT s.bucket, s.group_key, ` + label + `, s.value, s.articles
+		FROM series s
+		JOIN top_groups USING (group_key)
+		ORDER BY s.group_key, s.bucket
+	`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time series: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]models.TimeSeriesRow, 0)
+	for rows.Next() {
+		var row models.TimeSeriesRow
+		if err := rows.Scan(&row.Bucket, &row.Group, &row.Label, &row.Value, &row.Articles); err != nil {
+			return nil, fmt.Errorf("failed to scan time series row: %w", err)
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
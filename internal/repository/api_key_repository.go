@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// ErrAPIKeyNotFound is returned when an API key does not exist
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// APIKeyRepository handles database operations for per-client API keys
+type APIKeyRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *pgxpool.Pool, log *logger.Logger) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:     db,
+		logger: log.WithComponent("api-key-repo"),
+	}
+}
+
+const apiKeyColumns = `
+	id, key_prefix, name, owner, scopes, daily_quota, rate_tier, expires_at, last_used_at, last_used_ip,
+	revoked_at, revoked_reason, rotated_from, created_by, created_at, updated_at
+`
+
+func scanAPIKey(row pgx.Row) (*models.APIKey, error) {
+	var k models.APIKey
+	err := row.Scan(
+		&k.ID,
+		&k.Prefix,
+		&k.Name,
+		&k.Owner,
+		&k.Scopes,
+		&k.DailyQuota,
+		&k.RateTier,
+		&k.ExpiresAt,
+		&k.LastUsedAt,
+		&k.LastUsedIP,
+		&k.RevokedAt,
+		&k.RevokedReason,
+		&k.RotatedFrom,
+		&k.CreatedBy,
+		&k.CreatedAt,
+		&k.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	k.Status = k.ComputeStatus(time.Now())
+	return &k, nil
+}
+
+// Create stores a new key; only the hash of the raw key is persisted
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey, keyHash string) (*models.APIKey, error) {
+	return r.create(ctx, r.db, key, keyHash)
+}
+
+func (r *APIKeyRepository) create(ctx context.Context, q interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}, key *models.APIKey, keyHash string) (*models.APIKey, error) {
+	row := q.QueryRow(ctx, `
+		INSERT INTO api_keys (key_prefix, key_hash, name, owner, scopes, daily_quota, rate_tier, expires_at, rotated_from, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING `+apiKeyColumns,
+		key.Prefix, keyHash, key.Name, key.Owner, key.Scopes, key.DailyQuota, key.RateTier, key.ExpiresAt, key.RotatedFrom, key.CreatedBy,
+	)
+	created, err := scanAPIKey(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+	return created, nil
+}
+
+// GetByHash retrieves a key by the SHA-256 hash of the raw key
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRow(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = $1", keyHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return key, nil
+}
+
+// GetByID retrieves a key by ID
+func (r *APIKeyRepository) GetByID(ctx context.Context, id int64) (*models.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRow(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return key, nil
+}
+
+// List returns keys, newest first
+func (r *APIKeyRepository) List(ctx context.Context, owner string, includeRevoked bool) ([]models.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE 1=1"
+	args := []interface{}{}
+	argPos := 1
+
+	if owner != "" {
+		query += fmt.Sprintf(" AND owner = $%d", argPos)
+		args = append(args, owner)
+		argPos++
+	}
+	if !includeRevoked {
+		query += " AND revoked_at IS NULL"
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Revoke marks a key as revoked. Revoking an already revoked key keeps the original timestamp.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int64, reason string) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, NOW()),
+		    revoked_reason = COALESCE(revoked_reason, NULLIF($2, ''))
+		WHERE id = $1
+	`, id, reason)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// Rotate creates the replacement key and retires the old one in a single transaction.
+// The old key expires at oldExpiresAt, or is revoked immediately when oldExpiresAt is nil.
+// Saved searches owned by the old key move to the replacement in the same transaction.
+func (r *APIKeyRepository) Rotate(ctx context.Context, oldID int64, replacement *models.APIKey, keyHash string, oldExpiresAt *time.Time) (*models.APIKey, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var tag interface{ RowsAffected() int64 }
+	if oldExpiresAt == nil {
+		tag, err = tx.Exec(ctx, `
+			UPDATE api_keys SET revoked_at = NOW(), revoked_reason = 'rotated'
+			WHERE id = $1 AND revoked_at IS NULL
+		`, oldID)
+	} else {
+		tag, err = tx.Exec(ctx, `
+			UPDATE api_keys SET expires_at = LEAST(COALESCE(expires_at, $2), $2)
+			WHERE id = $1 AND revoked_at IS NULL
+		`, oldID, *oldExpiresAt)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retire rotated api key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	created, err := r.create(ctx, tx, replacement, keyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE saved_searches SET owner = $2, updated_at = NOW() WHERE owner = $1`,
+		KeySearchOwner(oldID), KeySearchOwner(created.ID)); err != nil {
+		return nil, fmt.Errorf("failed to move saved searches to rotated api key: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit key rotation: %w", err)
+	}
+	return created, nil
+}
+
+// KeyUsage is the last recorded use of a key
+type KeyUsage struct {
+	At time.Time
+	IP string
+}
+
+// RecordUsage stores last-used timestamps for a batch of keys
+func (r *APIKeyRepository) RecordUsage(ctx context.Context, usage map[int64]KeyUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(usage))
+	times := make([]time.Time, 0, len(usage))
+	ips := make([]string, 0, len(usage))
+	for id, u := range usage {
+		ids = append(ids, id)
+		times = append(times, u.At)
+		ips = append(ips, u.IP)
+	}
+
+	_, err := r.db.Exec(ctx, `
+		UPDATE api_keys k
+		SET last_used_at = u.at, last_used_ip = u.ip
+		FROM UNNEST($1::bigint[], $2::timestamptz[], $3::text[]) AS u(id, at, ip)
+		WHERE k.id = u.id AND (k.last_used_at IS NULL OR k.last_used_at < u.at)
+	`, ids, times, ips)
+	if err != nil {
+		return fmt.Errorf("failed to record api key usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jeffrey/intellinieuws/internal/models"
+)
+
+// exportFetchSize is the number of rows fetched from the export cursor per round trip
+const exportFetchSize = 1000
+
+// Export streams articles matching q with id > afterID in id order to fn, reading them
+// from a server-side cursor so the result set is never held in memory. limit <= 0 exports
+// everything. The id of the last exported row is the watermark to resume from.
+func (r *ArticleRepository) Export(ctx context.Context, q models.SavedSearchQuery, afterID int64, limit int, fn func(*models.ExportRow) error) (int, error) {
+	where, args, argPos := articleQueryWhere(q)
+	where += fmt.Sprintf(" AND id > $%d", argPos)
+	args = append(args, afterID)
+	argPos++
+
+	query := `
+		SELECT id, title, COALESCE(summary, ''), url, published, source, COALESCE(category, ''),
+		       COALESCE(author, ''), COALESCE(language, ''), keywords, created_at,
+		       COALESCE(ai_processed, FALSE), ai_sentiment, COALESCE(ai_sentiment_label, ''),
+		       ai_categories, ai_entities, ai_keywords, ai_stock_tickers,
+		       COALESCE(ai_summary, ''), ai_processed_at
+		FROM articles` + where + " ORDER BY id"
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argPos)
+		args = append(args, limit)
+	}
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return 0, fmt.Errorf("failed to start export: %w", err)
+	}
+	defer tx.Rollback(ctx) // Read-only; closes the cursor
+
+	if _, err := tx.Exec(ctx, "DECLARE article_export NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return 0, fmt.Errorf("failed to open export cursor: %w", err)
+	}
+
+	exported := 0
+	for {
+		rows, err := tx.Query(ctx, fmt.Sprintf("FETCH %d FROM article_export", exportFetchSize))
+		if err != nil {
+			return exported, fmt.Errorf("failed to fetch export rows: %w", err)
+		}
+
+		fetched := 0
+		for rows.Next() {
+			row, err := scanExportRow(rows)
+			if err != nil {
+				rows.Close()
+				return exported, err
+			}
+			if err := fn(row); err != nil {
+				rows.Close()
+				return exported, err
+			}
+			fetched++
+			exported++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return exported, fmt.Errorf("failed to fetch export rows: %w", err)
+		}
+		if fetched < exportFetchSize {
+			return exported, nil
+		}
+	}
+}
+
+// exportEntities is the stored shape of ai_entities
+type exportEntities struct {
+	Persons       []string `json:"persons"`
+	Organizations []string `json:"organizations"`
+	Locations     []string `json:"locations"`
+}
+
+func scanExportRow(rows pgx.Rows) (*models.ExportRow, error) {
+	var row models.ExportRow
+	var categoriesJSON, entitiesJSON, keywordsJSON, tickersJSON []byte
+
+	err := rows.Scan(
+		&row.ID,
+		&row.Title,
+		&row.Summary,
+		&row.URL,
+		&row.Published,
+		&row.Source,
+		&row.Category,
+		&row.Author,
+		&row.Language,
+		&row.Keywords,
+		&row.CreatedAt,
+		&row.AIProcessed,
+		&row.AISentiment,
+		&row.AISentimentLabel,
+		&categoriesJSON,
+		&entitiesJSON,
+		&keywordsJSON,
+		&tickersJSON,
+		&row.AISummary,
+		&row.AIProcessedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan export row: %w", err)
+	}
+
+	// Malformed AI columns are exported as empty rather than failing the whole export
+	if len(categoriesJSON) > 0 {
+		var categories map[string]float64
+		if json.Unmarshal(categoriesJSON, &categories) == nil {
+			for name := range categories {
+				row.AICategories = append(row.AICategories, name)
+			}
+			sort.Slice(row.AICategories, func(i, j int) bool {
+				a, b := row.AICategories[i], row.AICategories[j]
+				if categories[a] != categories[b] {
+					return categories[a] > categories[b]
+				}
+				return a < b
+			})
+		}
+	}
+	if len(entitiesJSON) > 0 {
+		var entities exportEntities
+		if json.Unmarshal(entitiesJSON, &entities) == nil {
+			row.AIPersons = entities.Persons
+			row.AIOrganizations = entities.Organizations
+			row.AILocations = entities.Locations
+		}
+	}
+	if len(keywordsJSON) > 0 {
+		var keywords []struct {
+			Word string `json:"word"`
+		}
+		if json.Unmarshal(keywordsJSON, &keywords) == nil {
+			for _, k := range keywords {
+				row.AIKeywords = append(row.AIKeywords, k.Word)
+			}
+		}
+	}
+	if len(tickersJSON) > 0 {
+		var tickers []struct {
+			Symbol string `json:"symbol"`
+		}
+		if json.Unmarshal(tickersJSON, &tickers) == nil {
+			for _, t := range tickers {
+				row.AIStockTickers = append(row.AIStockTickers, t.Symbol)
+			}
+		}
+	}
+	return &row, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/pagination"
+)
+
+// articlePaging is the pagination plan for one article query
+type articlePaging struct {
+	keyset pagination.Keyset
+	cursor bool // keyset cursors are supported for the sort column
+	limit  bool // a LIMIT (plus one look-ahead row) was applied
+}
+
+// applyArticlePagination appends the cursor condition, ORDER BY and LIMIT/OFFSET clauses.
+// Time-ordered sorts use keyset pagination with id as tie-breaker; other sorts fall back
+// to offsets. One extra row is fetched to detect whether a next page exists.
+func applyArticlePagination(query string, args []interface{}, argPos int, filter models.ArticleFilter) (string, []interface{}, articlePaging) {
+	orderBy := "published"
+	if filter.SortBy != "" {
+		orderBy = filter.SortBy
+	}
+	orderDir := "desc"
+	if filter.SortOrder == "asc" {
+		orderDir = "asc"
+	}
+
+	paging := articlePaging{
+		keyset: pagination.Keyset{SortBy: orderBy, SortOrder: orderDir, Limit: filter.Limit},
+		cursor: pagination.SupportsCursor(orderBy),
+		limit:  filter.Limit > 0,
+	}
+
+	if paging.cursor {
+		paging.keyset.Cursor = filter.Cursor
+		condition, condArgs := paging.keyset.Condition(argPos)
+		query += condition
+		args = append(args, condArgs...)
+		argPos += len(condArgs)
+		query += paging.keyset.OrderBy()
+	} else {
+		query += fmt.Sprintf(" ORDER BY %s %s, id %s", orderBy, orderDir, orderDir)
+	}
+
+	if paging.limit {
+		query += fmt.Sprintf(" LIMIT $%d", argPos)
+		args = append(args, paging.keyset.FetchLimit())
+		argPos++
+	}
+	if filter.Offset > 0 && filter.Cursor == nil {
+		query += fmt.Sprintf(" OFFSET $%d", argPos)
+		args = append(args, filter.Offset)
+	}
+
+	return query, args, paging
+}
+
+// finishArticlePage trims the look-ahead row and builds the page cursors
+func finishArticlePage(articles []models.Article, total *int, paging articlePaging, filter models.ArticleFilter) *models.ArticlePage {
+	page := &models.ArticlePage{Total: total}
+
+	if !paging.limit {
+		page.Articles = articles
+		page.Page.HasPrev = filter.Offset > 0
+		return page
+	}
+
+	if !paging.cursor {
+		if len(articles) > filter.Limit {
+			articles = articles[:filter.Limit]
+			page.Page.HasNext = true
+		}
+		page.Articles = articles
+		page.Page.HasPrev = filter.Offset > 0
+		return page
+	}
+
+	position := func(a models.Article) (time.Time, int64) {
+		if paging.keyset.SortBy == "created_at" {
+			return a.CreatedAt, a.ID
+		}
+		return a.Published, a.ID
+	}
+	page.Articles, page.Page = pagination.Finish(articles, paging.keyset, filter.Offset, position)
+	return page
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+)
+
+// articleQueryWhere builds the WHERE clause for an article query with enrichment filters
+// (saved searches, feeds). The full-text condition uses $1/$2, so it must come first.
+func articleQueryWhere(q models.SavedSearchQuery) (string, []interface{}, int) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	argPos := 1
+
+	if q.Search != "" {
+		where = " WHERE " + searchCondition(q.Language)
+		args = append(args, q.Search, "%"+q.Search+"%")
+		argPos = 3
+	}
+
+	add := func(condition string, value interface{}) {
+		where += fmt.Sprintf(condition, argPos)
+		args = append(args, value)
+		argPos++
+	}
+
+	if q.Source != "" {
+		add(" AND source = $%d", q.Source)
+	}
+	if q.Category != "" {
+		add(" AND category = $%d", q.Category)
+	}
+	if q.Language != "" {
+		add(" AND language = $%d", q.Language)
+	}
+	if q.Keyword != "" {
+		add(" AND $%d = ANY(keywords)", q.Keyword)
+	}
+	if q.StartDate != nil {
+		add(" AND published >= $%d", *q.StartDate)
+	}
+	if q.EndDate != nil {
+		add(" AND published <= $%d", *q.EndDate)
+	}
+	if q.Entity != "" {
+		// The name or an alias of the canonical entity, following merges; never a substring
+		add(` AND EXISTS (
+				SELECT 1 FROM article_entities fae
+				JOIN entities fe ON fe.id = fae.entity_id
+				WHERE fae.article_id = articles.id AND COALESCE(fe.merged_into, fe.id) IN (
+					SELECT COALESCE(ne.merged_into, ne.id)
+					FROM entities ne
+					LEFT JOIN entity_aliases na ON na.entity_id = ne.id
+					WHERE LOWER(ne.canonical_name) = LOWER($%[1]d)
+					   OR LOWER(na.alias) = LOWER($%[1]d)
+					   OR na.alias_normalized = LOWER($%[1]d)
+				)
+			)`, strings.TrimSpace(q.Entity))
+	}
+	if q.Ticker != "" {
+		add(` AND jsonb_typeof(ai_stock_tickers) = 'array' AND EXISTS (
+				SELECT 1 FROM jsonb_array_elements(ai_stock_tickers) AS ft(value)
+				WHERE UPPER(ft.value->>'symbol') = $%d
+			)`, strings.ToUpper(strings.TrimSpace(q.Ticker)))
+	}
+	if q.SentimentLabel != "" {
+		add(" AND LOWER(ai_sentiment_label) = $%d", strings.ToLower(q.SentimentLabel))
+	}
+	if q.MinSentiment != nil {
+		add(" AND ai_sentiment >= $%d", *q.MinSentiment)
+	}
+	if q.MaxSentiment != nil {
+		add(" AND ai_sentiment <= $%d", *q.MaxSentiment)
+	}
+
+	return where, args, argPos
+}
+
+// ListFeed returns the newest articles matching q with their AI summary and sentiment
+func (r *ArticleRepository) ListFeed(ctx context.Context, q models.SavedSearchQuery, limit int) ([]models.FeedItem, error) {
+	where, args, argPos := articleQueryWhere(q)
+	query := `
+		SELECT id, title, summary, url, published, source, keywords, image_url,
+		       author, category, COALESCE(language, '') as language, created_at, updated_at,
+		       COALESCE(ai_summary, ''), ai_sentiment, COALESCE(ai_sentiment_label, ''),
+		       GREATEST(updated_at, COALESCE(ai_processed_at, updated_at))
+		FROM articles` + where + fmt.Sprintf(" ORDER BY published DESC, id DESC LIMIT $%d", argPos)
+	args = append(args, limit)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed articles: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.FeedItem{}
+	for rows.Next() {
+		var item models.FeedItem
+		err := rows.Scan(
+			&item.ID,
+			&item.Title,
+			&item.Summary,
+			&item.URL,
+			&item.Published,
+			&item.Source,
+			&item.Keywords,
+			&item.ImageURL,
+			&item.Author,
+			&item.Category,
+			&item.Language,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&item.AISummary,
+			&item.AISentiment,
+			&item.AISentimentLabel,
+			&item.ModifiedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan feed article: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list feed articles: %w", err)
+	}
+	return items, nil
+}
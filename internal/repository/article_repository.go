@@ -10,6 +10,7 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeffrey/intellinieuws/internal/language"
 	"github.com/jeffrey/intellinieuws/internal/models"
 )
 
@@ -33,10 +34,11 @@ func (r *ArticleRepository) Create(ctx context.Context, article *models.ArticleC
 	article.Summary = sanitizeUTF8(article.Summary)
 	article.Author = sanitizeUTF8(article.Author)
 	article.Category = sanitizeUTF8(article.Category)
+	article.Language = articleLanguage(article)
 
 	query := `
-		INSERT INTO articles (title, summary, url, published, source, keywords, image_url, author, category, content_hash)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO articles (title, summary, url, published, source, keywords, image_url, author, category, content_hash, language)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -52,6 +54,7 @@ func (r *ArticleRepository) Create(ctx context.Context, article *models.ArticleC
 		article.Author,
 		article.Category,
 		article.ContentHash,
+		article.Language,
 	).Scan(&result.ID, &result.CreatedAt, &result.UpdatedAt)
 
 	if err != nil {
@@ -69,28 +72,37 @@ func (r *ArticleRepository) Create(ctx context.Context, article *models.ArticleC
 	result.Author = article.Author
 	result.Category = article.Category
 	result.ContentHash = article.ContentHash
+	result.Language = article.Language
 
 	return &result, nil
 }
 
 // CreateBatch inserts multiple articles in a single transaction for better performance
 func (r *ArticleRepository) CreateBatch(ctx context.Context, articles []*models.ArticleCreate) (int, error) {
+	stored, err := r.CreateBatchReturning(ctx, articles)
+	return len(stored), err
+}
+
+// CreateBatchReturning inserts multiple articles and returns the ones that were actually
+// stored (duplicates skipped by ON CONFLICT are left out), without content
+func (r *ArticleRepository) CreateBatchReturning(ctx context.Context, articles []*models.ArticleCreate) ([]models.Article, error) {
 	if len(articles) == 0 {
-		return 0, nil
+		return nil, nil
 	}
 
 	// Use batch without explicit transaction for better concurrency
 	batch := &pgx.Batch{}
 	query := `
-		INSERT INTO articles (title, summary, url, published, source, keywords, image_url, author, category, content_hash)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO articles (title, summary, url, published, source, keywords, image_url, author, category, content_hash, language, scrape_job_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, '')::uuid)
 		ON CONFLICT (url) DO NOTHING
-		RETURNING id
+		RETURNING id, created_at, updated_at
 	`
 
 	for _, article := range articles {
 		// Generate content hash
 		article.ContentHash = generateContentHash(article.Title, article.URL)
+		article.Language = articleLanguage(article)
 
 		batch.Queue(query,
 			article.Title,
@@ -103,6 +115,8 @@ func (r *ArticleRepository) CreateBatch(ctx context.Context, articles []*models.
 			article.Author,
 			article.Category,
 			article.ContentHash,
+			article.Language,
+			article.ScrapeJobID,
 		)
 	}
 
@@ -110,25 +124,37 @@ func (r *ArticleRepository) CreateBatch(ctx context.Context, articles []*models.
 	results := r.db.SendBatch(ctx, batch)
 	defer results.Close()
 
-	// Count successful inserts (those that return an ID)
-	inserted := 0
-	for i := 0; i < len(articles); i++ {
-		var id int64
-		err := results.QueryRow().Scan(&id)
+	// Collect successful inserts (those that return an ID)
+	stored := make([]models.Article, 0, len(articles))
+	for _, article := range articles {
+		created := models.Article{
+			Title:       article.Title,
+			Summary:     article.Summary,
+			URL:         article.URL,
+			Published:   article.Published,
+			Source:      article.Source,
+			Keywords:    article.Keywords,
+			ImageURL:    article.ImageURL,
+			Author:      article.Author,
+			Category:    article.Category,
+			Language:    article.Language,
+			ContentHash: article.ContentHash,
+		}
+		err := results.QueryRow().Scan(&created.ID, &created.CreatedAt, &created.UpdatedAt)
 		if err == nil {
-			inserted++
+			stored = append(stored, created)
 		}
 		// ON CONFLICT returns no rows, that's OK
 	}
 
-	return inserted, nil
+	return stored, nil
 }
 
 // GetByID retrieves an article by ID (includes content if extracted)
 func (r *ArticleRepository) GetByID(ctx context.Context, id int64) (*models.Article, error) {
 	query := `
 		SELECT id, title, summary, url, published, source, keywords, image_url,
-		       author, category, content_hash, created_at, updated_at,
+		       author, category, COALESCE(language, '') as language, content_hash, created_at, updated_at,
 		       COALESCE(content, '') as content,
 		       COALESCE(content_extracted, FALSE) as content_extracted,
 		       content_extracted_at
@@ -152,6 +178,7 @@ func (r *ArticleRepository) GetByID(ctx context.Context, id int64) (*models.Arti
 		&article.ImageURL,
 		&article.Author,
 		&article.Category,
+		&article.Language,
 		&article.ContentHash,
 		&article.CreatedAt,
 		&article.UpdatedAt,
@@ -177,11 +204,11 @@ func (r *ArticleRepository) GetByID(ctx context.Context, id int64) (*models.Arti
 
 // ListLight retrieves articles WITHOUT full content (optimized for list views)
 // Use this for API list endpoints to avoid transferring large content fields
-func (r *ArticleRepository) ListLight(ctx context.Context, filter models.ArticleFilter) ([]models.Article, int, error) {
+func (r *ArticleRepository) ListLight(ctx context.Context, filter models.ArticleFilter) (*models.ArticlePage, error) {
 	// Lightweight query - exclude content field for performance
 	query := `
 		SELECT id, title, summary, url, published, source, keywords, image_url,
-		       author, category, content_hash, created_at, updated_at,
+		       author, category, COALESCE(language, '') as language, content_hash, created_at, updated_at,
 		       COALESCE(content_extracted, FALSE) as content_extracted,
 		       content_extracted_at
 		FROM articles
@@ -206,6 +233,13 @@ func (r *ArticleRepository) ListLight(ctx context.Context, filter models.Article
 		argPos++
 	}
 
+	if filter.Language != "" {
+		query += fmt.Sprintf(" AND language = $%d", argPos)
+		countQuery += fmt.Sprintf(" AND language = $%d", argPos)
+		args = append(args, filter.Language)
+		argPos++
+	}
+
 	if filter.Keyword != "" {
 		query += fmt.Sprintf(" AND $%d = ANY(keywords)", argPos)
 		countQuery += fmt.Sprintf(" AND $%d = ANY(keywords)", argPos)
@@ -227,40 +261,23 @@ func (r *ArticleRepository) ListLight(ctx context.Context, filter models.Article
 		argPos++
 	}
 
-	// Get total count
-	var total int
-	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count articles: %w", err)
-	}
-
-	// Apply ordering
-	orderBy := "published"
-	if filter.SortBy != "" {
-		orderBy = filter.SortBy
-	}
-	orderDir := "DESC"
-	if filter.SortOrder == "asc" {
-		orderDir = "ASC"
+	// Get total count (optional: it dominates the cost of large listings)
+	var total *int
+	if !filter.SkipCount {
+		var count int
+		if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count articles: %w", err)
+		}
+		total = &count
 	}
-	query += fmt.Sprintf(" ORDER BY %s %s", orderBy, orderDir)
 
-	// Apply pagination
-	if filter.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", argPos)
-		args = append(args, filter.Limit)
-		argPos++
-	}
-	if filter.Offset > 0 {
-		query += fmt.Sprintf(" OFFSET $%d", argPos)
-		args = append(args, filter.Offset)
-		argPos++
-	}
+	// Apply ordering and pagination (keyset cursor or offset)
+	query, args, paging := applyArticlePagination(query, args, argPos, filter)
 
 	// Execute query
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list articles: %w", err)
+		return nil, fmt.Errorf("failed to list articles: %w", err)
 	}
 	defer rows.Close()
 
@@ -282,6 +299,7 @@ func (r *ArticleRepository) ListLight(ctx context.Context, filter models.Article
 			&article.ImageURL,
 			&article.Author,
 			&article.Category,
+			&article.Language,
 			&article.ContentHash,
 			&article.CreatedAt,
 			&article.UpdatedAt,
@@ -289,7 +307,7 @@ func (r *ArticleRepository) ListLight(ctx context.Context, filter models.Article
 			&contentExtractedAt,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan article: %w", err)
+			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
 
 		// Set content fields (no content in lightweight query)
@@ -299,16 +317,16 @@ func (r *ArticleRepository) ListLight(ctx context.Context, filter models.Article
 		articles = append(articles, article)
 	}
 
-	return articles, total, nil
+	return finishArticlePage(articles, total, paging, filter), nil
 }
 
 // List retrieves articles with filters and sorting (includes full content)
 // For list views, prefer ListLight() for better performance
-func (r *ArticleRepository) List(ctx context.Context, filter models.ArticleFilter) ([]models.Article, int, error) {
+func (r *ArticleRepository) List(ctx context.Context, filter models.ArticleFilter) (*models.ArticlePage, error) {
 	// Build dynamic query (include content fields)
 	query := `
 		SELECT id, title, summary, url, published, source, keywords, image_url,
-		       author, category, content_hash, created_at, updated_at,
+		       author, category, COALESCE(language, '') as language, content_hash, created_at, updated_at,
 		       COALESCE(content, '') as content,
 		       COALESCE(content_extracted, FALSE) as content_extracted,
 		       content_extracted_at
@@ -334,6 +352,13 @@ func (r *ArticleRepository) List(ctx context.Context, filter models.ArticleFilte
 		argPos++
 	}
 
+	if filter.Language != "" {
+		query += fmt.Sprintf(" AND language = $%d", argPos)
+		countQuery += fmt.Sprintf(" AND language = $%d", argPos)
+		args = append(args, filter.Language)
+		argPos++
+	}
+
 	if filter.Keyword != "" {
 		query += fmt.Sprintf(" AND $%d = ANY(keywords)", argPos)
 		countQuery += fmt.Sprintf(" AND $%d = ANY(keywords)", argPos)
@@ -355,40 +380,23 @@ func (r *ArticleRepository) List(ctx context.Context, filter models.ArticleFilte
 		argPos++
 	}
 
-	// Get total count
-	var total int
-	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count articles: %w", err)
-	}
-
-	// Apply ordering
-	orderBy := "published"
-	if filter.SortBy != "" {
-		orderBy = filter.SortBy
-	}
-	orderDir := "DESC"
-	if filter.SortOrder == "asc" {
-		orderDir = "ASC"
+	// Get total count (optional: it dominates the cost of large listings)
+	var total *int
+	if !filter.SkipCount {
+		var count int
+		if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count articles: %w", err)
+		}
+		total = &count
 	}
-	query += fmt.Sprintf(" ORDER BY %s %s", orderBy, orderDir)
 
-	// Apply pagination
-	if filter.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", argPos)
-		args = append(args, filter.Limit)
-		argPos++
-	}
-	if filter.Offset > 0 {
-		query += fmt.Sprintf(" OFFSET $%d", argPos)
-		args = append(args, filter.Offset)
-		argPos++
-	}
+	// Apply ordering and pagination (keyset cursor or offset)
+	query, args, paging := applyArticlePagination(query, args, argPos, filter)
 
 	// Execute query
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list articles: %w", err)
+		return nil, fmt.Errorf("failed to list articles: %w", err)
 	}
 	defer rows.Close()
 
@@ -411,6 +419,7 @@ func (r *ArticleRepository) List(ctx context.Context, filter models.ArticleFilte
 			&article.ImageURL,
 			&article.Author,
 			&article.Category,
+			&article.Language,
 			&article.ContentHash,
 			&article.CreatedAt,
 			&article.UpdatedAt,
@@ -419,7 +428,7 @@ func (r *ArticleRepository) List(ctx context.Context, filter models.ArticleFilte
 			&contentExtractedAt,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan article: %w", err)
+			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
 
 		// Set content fields
@@ -430,35 +439,23 @@ func (r *ArticleRepository) List(ctx context.Context, filter models.ArticleFilte
 		articles = append(articles, article)
 	}
 
-	return articles, total, nil
+	return finishArticlePage(articles, total, paging, filter), nil
 }
 
 // SearchLight performs full-text search WITHOUT full content (optimized for list views)
-func (r *ArticleRepository) SearchLight(ctx context.Context, filter models.ArticleFilter) ([]models.Article, int, error) {
+func (r *ArticleRepository) SearchLight(ctx context.Context, filter models.ArticleFilter) (*models.ArticlePage, error) {
 	// Lightweight search query - exclude content field for performance
 	query := `
 		SELECT id, title, summary, url, published, source, keywords, image_url,
-		       author, category, content_hash, created_at, updated_at,
+		       author, category, COALESCE(language, '') as language, content_hash, created_at, updated_at,
 		       COALESCE(content_extracted, FALSE) as content_extracted,
 		       content_extracted_at
 		FROM articles
-		WHERE (
-			to_tsvector('english', title || ' ' || COALESCE(summary, ''))
-			@@ plainto_tsquery('english', $1)
-			OR title ILIKE $2
-			OR summary ILIKE $2
-		)
-	`
+		WHERE ` + searchCondition(filter.Language)
 	countQuery := `
 		SELECT COUNT(*)
 		FROM articles
-		WHERE (
-			to_tsvector('english', title || ' ' || COALESCE(summary, ''))
-			@@ plainto_tsquery('english', $1)
-			OR title ILIKE $2
-			OR summary ILIKE $2
-		)
-	`
+		WHERE ` + searchCondition(filter.Language)
 
 	searchPattern := "%" + filter.Search + "%"
 	args := []interface{}{filter.Search, searchPattern}
@@ -479,39 +476,30 @@ func (r *ArticleRepository) SearchLight(ctx context.Context, filter models.Artic
 		argPos++
 	}
 
-	// Get total count
-	var total int
-	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	if filter.Language != "" {
+		query += fmt.Sprintf(" AND language = $%d", argPos)
+		countQuery += fmt.Sprintf(" AND language = $%d", argPos)
+		args = append(args, filter.Language)
+		argPos++
 	}
 
-	// Apply ordering
-	orderBy := "published"
-	if filter.SortBy != "" {
-		orderBy = filter.SortBy
-	}
-	orderDir := "DESC"
-	if filter.SortOrder == "asc" {
-		orderDir = "ASC"
+	// Get total count (optional: it dominates the cost of large listings)
+	var total *int
+	if !filter.SkipCount {
+		var count int
+		if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count search results: %w", err)
+		}
+		total = &count
 	}
-	query += fmt.Sprintf(" ORDER BY %s %s", orderBy, orderDir)
 
-	// Apply pagination
-	if filter.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", argPos)
-		args = append(args, filter.Limit)
-		argPos++
-	}
-	if filter.Offset > 0 {
-		query += fmt.Sprintf(" OFFSET $%d", argPos)
-		args = append(args, filter.Offset)
-	}
+	// Apply ordering and pagination (keyset cursor or offset)
+	query, args, paging := applyArticlePagination(query, args, argPos, filter)
 
 	// Execute query
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to search articles: %w", err)
+		return nil, fmt.Errorf("failed to search articles: %w", err)
 	}
 	defer rows.Close()
 
@@ -533,6 +521,7 @@ func (r *ArticleRepository) SearchLight(ctx context.Context, filter models.Artic
 			&article.ImageURL,
 			&article.Author,
 			&article.Category,
+			&article.Language,
 			&article.ContentHash,
 			&article.CreatedAt,
 			&article.UpdatedAt,
@@ -540,7 +529,7 @@ func (r *ArticleRepository) SearchLight(ctx context.Context, filter models.Artic
 			&contentExtractedAt,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan article: %w", err)
+			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
 
 		// Set content fields (no content in lightweight query)
@@ -550,36 +539,24 @@ func (r *ArticleRepository) SearchLight(ctx context.Context, filter models.Artic
 		articles = append(articles, article)
 	}
 
-	return articles, total, nil
+	return finishArticlePage(articles, total, paging, filter), nil
 }
 
 // Search performs full-text search on articles
-func (r *ArticleRepository) Search(ctx context.Context, filter models.ArticleFilter) ([]models.Article, int, error) {
+func (r *ArticleRepository) Search(ctx context.Context, filter models.ArticleFilter) (*models.ArticlePage, error) {
 	// Build search query using PostgreSQL full-text search (include content fields)
 	query := `
 		SELECT id, title, summary, url, published, source, keywords, image_url,
-		       author, category, content_hash, created_at, updated_at,
+		       author, category, COALESCE(language, '') as language, content_hash, created_at, updated_at,
 		       COALESCE(content, '') as content,
 		       COALESCE(content_extracted, FALSE) as content_extracted,
 		       content_extracted_at
 		FROM articles
-		WHERE (
-			to_tsvector('english', title || ' ' || COALESCE(summary, ''))
-			@@ plainto_tsquery('english', $1)
-			OR title ILIKE $2
-			OR summary ILIKE $2
-		)
-	`
+		WHERE ` + searchCondition(filter.Language)
 	countQuery := `
 		SELECT COUNT(*)
 		FROM articles
-		WHERE (
-			to_tsvector('english', title || ' ' || COALESCE(summary, ''))
-			@@ plainto_tsquery('english', $1)
-			OR title ILIKE $2
-			OR summary ILIKE $2
-		)
-	`
+		WHERE ` + searchCondition(filter.Language)
 
 	searchPattern := "%" + filter.Search + "%"
 	args := []interface{}{filter.Search, searchPattern}
@@ -600,39 +577,30 @@ func (r *ArticleRepository) Search(ctx context.Context, filter models.ArticleFil
 		argPos++
 	}
 
-	// Get total count
-	var total int
-	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	if filter.Language != "" {
+		query += fmt.Sprintf(" AND language = $%d", argPos)
+		countQuery += fmt.Sprintf(" AND language = $%d", argPos)
+		args = append(args, filter.Language)
+		argPos++
 	}
 
-	// Apply ordering
-	orderBy := "published"
-	if filter.SortBy != "" {
-		orderBy = filter.SortBy
-	}
-	orderDir := "DESC"
-	if filter.SortOrder == "asc" {
-		orderDir = "ASC"
+	// Get total count (optional: it dominates the cost of large listings)
+	var total *int
+	if !filter.SkipCount {
+		var count int
+		if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count search results: %w", err)
+		}
+		total = &count
 	}
-	query += fmt.Sprintf(" ORDER BY %s %s", orderBy, orderDir)
 
-	// Apply pagination
-	if filter.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", argPos)
-		args = append(args, filter.Limit)
-		argPos++
-	}
-	if filter.Offset > 0 {
-		query += fmt.Sprintf(" OFFSET $%d", argPos)
-		args = append(args, filter.Offset)
-	}
+	// Apply ordering and pagination (keyset cursor or offset)
+	query, args, paging := applyArticlePagination(query, args, argPos, filter)
 
 	// Execute query
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to search articles: %w", err)
+		return nil, fmt.Errorf("failed to search articles: %w", err)
 	}
 	defer rows.Close()
 
@@ -655,6 +623,7 @@ func (r *ArticleRepository) Search(ctx context.Context, filter models.ArticleFil
 			&article.ImageURL,
 			&article.Author,
 			&article.Category,
+			&article.Language,
 			&article.ContentHash,
 			&article.CreatedAt,
 			&article.UpdatedAt,
@@ -663,7 +632,7 @@ func (r *ArticleRepository) Search(ctx context.Context, filter models.ArticleFil
 			&contentExtractedAt,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan article: %w", err)
+			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
 
 		// Set content fields
@@ -674,7 +643,7 @@ func (r *ArticleRepository) Search(ctx context.Context, filter models.ArticleFil
 		articles = append(articles, article)
 	}
 
-	return articles, total, nil
+	return finishArticlePage(articles, total, paging, filter), nil
 }
 
 // ExistsByURL checks if an article with the given URL already exists
@@ -965,7 +934,7 @@ func (r *ArticleRepository) GetContentExtractionStats(ctx context.Context) (map[
 func (r *ArticleRepository) GetArticleWithContent(ctx context.Context, id int64) (*models.Article, error) {
 	query := `
 		SELECT id, title, summary, url, published, source, keywords, image_url,
-		       author, category, content_hash, created_at, updated_at,
+		       author, category, COALESCE(language, '') as language, content_hash, created_at, updated_at,
 		       content, content_extracted, content_extracted_at
 		FROM articles
 		WHERE id = $1
@@ -987,6 +956,7 @@ func (r *ArticleRepository) GetArticleWithContent(ctx context.Context, id int64)
 		&article.ImageURL,
 		&article.Author,
 		&article.Category,
+		&article.Language,
 		&article.ContentHash,
 		&article.CreatedAt,
 		&article.UpdatedAt,
@@ -1015,3 +985,107 @@ func (r *ArticleRepository) GetArticleWithContent(ctx context.Context, id int64)
 
 	return &article, nil
 }
+
+// searchCondition builds the full-text WHERE clause for $1 (query) and $2 (ILIKE pattern).
+// Every language is matched against search_vector with its own text-search configuration,
+// so each branch can use idx_articles_search_vector. Undetected (NULL) languages are
+// treated as Dutch, matching article_search_config() in V005.
+func searchCondition(lang string) string {
+	branches := make([]string, 0, len(language.Supported())+1)
+	for _, code := range language.Supported() {
+		if lang != "" && code != lang {
+			continue
+		}
+		guard := fmt.Sprintf("language = '%s'", code)
+		if code == language.Default {
+			guard = fmt.Sprintf("(language = '%s' OR language IS NULL)", code)
+		}
+		branches = append(branches, fmt.Sprintf(
+			"(%s AND search_vector @@ plainto_tsquery('%s', $1))", guard, language.SearchConfig(code)))
+	}
+	if lang == "" || !language.IsSupported(lang) {
+		branches = append(branches, fmt.Sprintf(
+			"(language NOT IN ('%s') AND search_vector @@ plainto_tsquery('simple', $1))",
+			strings.Join(language.Supported(), "', '")))
+	}
+
+	return "(\n\t\t\t" + strings.Join(branches, "\n\t\t\tOR ") +
+		"\n\t\t\tOR title ILIKE $2\n\t\t\tOR summary ILIKE $2\n\t\t)\n\t"
+}
+
+// articleLanguage returns the given language if supported, otherwise detects it from title and summary
+func articleLanguage(article *models.ArticleCreate) string {
+	if code := language.Normalize(article.Language); code != "" {
+		return code
+	}
+	return language.Detect(article.Title + "\n" + article.Summary)
+}
+
+// DetectMissingLanguages detects and stores the language of up to limit articles
+// that were ingested before language detection existed. Returns the number updated.
+func (r *ArticleRepository) DetectMissingLanguages(ctx context.Context, limit int) (int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, title, COALESCE(summary, '')
+		FROM articles
+		WHERE language IS NULL
+		ORDER BY id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get articles without language: %w", err)
+	}
+
+	ids := make([]int64, 0, limit)
+	langs := make([]string, 0, limit)
+	for rows.Next() {
+		var id int64
+		var title, summary string
+		if err := rows.Scan(&id, &title, &summary); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan article: %w", err)
+		}
+		ids = append(ids, id)
+		langs = append(langs, language.Detect(title+"\n"+summary))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read articles without language: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	tag, err := r.db.Exec(ctx, `
+		UPDATE articles a
+		SET language = d.language
+		FROM UNNEST($1::bigint[], $2::text[]) AS d(id, language)
+		WHERE a.id = d.id AND a.language IS NULL
+	`, ids, langs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update article languages: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// GetLanguageStats returns the number of articles per language ("" = not yet detected)
+func (r *ArticleRepository) GetLanguageStats(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.Query(ctx, "SELECT COALESCE(language, ''), COUNT(*) FROM articles GROUP BY 1")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get language stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[string]int)
+	for rows.Next() {
+		var lang string
+		var count int
+		if err := rows.Scan(&lang, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan language stats: %w", err)
+		}
+		stats[lang] = count
+	}
+
+	return stats, rows.Err()
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// AuditRepository handles database operations for the append-only audit log
+type AuditRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *pgxpool.Pool, log *logger.Logger) *AuditRepository {
+	return &AuditRepository{
+		db:     db,
+		logger: log.WithComponent("audit-repo"),
+	}
+}
+
+// Insert appends an entry to the audit log
+func (r *AuditRepository) Insert(ctx context.Context, entry *models.AuditEntry) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO audit_log (
+			actor_type, actor_id, actor_name, ip_address, user_agent,
+			action, resource, before_value, after_value, status, error_message,
+			request_id, http_method, path
+		) VALUES (
+			$1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''),
+			$6, NULLIF($7, ''), $8, $9, $10, NULLIF($11, ''),
+			NULLIF($12, ''), NULLIF($13, ''), NULLIF($14, '')
+		)
+		RETURNING id, occurred_at
+	`,
+		entry.ActorType, entry.ActorID, entry.ActorName, entry.IPAddress, entry.UserAgent,
+		entry.Action, entry.Resource, nullJSON(entry.Before), nullJSON(entry.After), entry.Status, entry.ErrorMessage,
+		entry.RequestID, entry.HTTPMethod, entry.Path,
+	).Scan(&entry.ID, &entry.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+	return nil
+}
+
+// List returns audit entries matching the filter, newest first, with the total count
+func (r *AuditRepository) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, int, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	argPos := 1
+
+	if filter.Actor != "" {
+		where += fmt.Sprintf(" AND (actor_id = $%d OR actor_name = $%d)", argPos, argPos)
+		args = append(args, filter.Actor)
+		argPos++
+	}
+	if filter.ActorType != "" {
+		where += fmt.Sprintf(" AND actor_type = $%d", argPos)
+		args = append(args, filter.ActorType)
+		argPos++
+	}
+	if filter.Action != "" {
+		if prefix, ok := strings.CutSuffix(filter.Action, "*"); ok {
+			where += fmt.Sprintf(" AND action LIKE $%d", argPos)
+			args = append(args, escapeLike(prefix)+"%")
+		} else {
+			where += fmt.Sprintf(" AND action = $%d", argPos)
+			args = append(args, filter.Action)
+		}
+		argPos++
+	}
+	if filter.Resource != "" {
+		where += fmt.Sprintf(" AND resource = $%d", argPos)
+		args = append(args, filter.Resource)
+		argPos++
+	}
+	if filter.RequestID != "" {
+		where += fmt.Sprintf(" AND request_id = $%d", argPos)
+		args = append(args, filter.RequestID)
+		argPos++
+	}
+	if filter.Status != "" {
+		where += fmt.Sprintf(" AND status = $%d", argPos)
+		args = append(args, filter.Status)
+		argPos++
+	}
+	if filter.Since != nil {
+		where += fmt.Sprintf(" AND occurred_at >= $%d", argPos)
+		args = append(args, *filter.Since)
+		argPos++
+	}
+	if filter.Until != nil {
+		where += fmt.Sprintf(" AND occurred_at < $%d", argPos)
+		args = append(args, *filter.Until)
+		argPos++
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM audit_log"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	query := `
+		SELECT id, occurred_at, actor_type, COALESCE(actor_id, ''), COALESCE(actor_name, ''),
+		       COALESCE(ip_address, ''), COALESCE(user_agent, ''), action, COALESCE(resource, ''),
+		       before_value, after_value, status, COALESCE(error_message, ''),
+		       COALESCE(request_id, ''), COALESCE(http_method, ''), COALESCE(path, '')
+		FROM audit_log` + where +
+		fmt.Sprintf(" ORDER BY occurred_at DESC, id DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var e models.AuditEntry
+		var before, after []byte
+		if err := rows.Scan(
+			&e.ID, &e.OccurredAt, &e.ActorType, &e.ActorID, &e.ActorName,
+			&e.IPAddress, &e.UserAgent, &e.Action, &e.Resource,
+			&before, &after, &e.Status, &e.ErrorMessage,
+			&e.RequestID, &e.HTTPMethod, &e.Path,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		e.Before = before
+		e.After = after
+		entries = append(entries, e)
+	}
+
+	return entries, total, rows.Err()
+}
+
+// nullJSON maps an empty JSON value to SQL NULL
+func nullJSON(value []byte) interface{} {
+	if len(value) == 0 {
+		return nil
+	}
+	return string(value)
+}
+
+// escapeLike escapes LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,632 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+var (
+	// ErrEntityNotFound is returned when an entity does not exist
+	ErrEntityNotFound = errors.New("entity not found")
+	// ErrMergeCandidateNotFound is returned when a queued merge candidate does not exist
+	ErrMergeCandidateNotFound = errors.New("merge candidate not found")
+)
+
+// EntityRepository handles database operations for the canonical entity registry
+type EntityRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+// NewEntityRepository creates a new entity repository
+func NewEntityRepository(db *pgxpool.Pool, log *logger.Logger) *EntityRepository {
+	return &EntityRepository{
+		db:     db,
+		logger: log.WithComponent("entity-repo"),
+	}
+}
+
+// AliasIndexEntry is a single alias row resolved to its canonical (unmerged) entity
+type AliasIndexEntry struct {
+	AliasNormalized string
+	EntityType      string
+	EntityID        int64
+	CanonicalName   string
+	Origin          string
+}
+
+// AliasInput describes an alias to attach to an entity
+type AliasInput struct {
+	Alias      string
+	Normalized string
+}
+
+// ArticleEntityLink is a resolved mention of an entity in an article
+type ArticleEntityLink struct {
+	EntityID int64
+	Mention  string
+}
+
+// ArticleEntitiesRow holds the raw AI entities of a processed article
+type ArticleEntitiesRow struct {
+	ArticleID int64
+	Entities  []byte
+}
+
+// ArticleMention is a stored mention used when splitting entities
+type ArticleMention struct {
+	ArticleID int64
+	Mention   string
+}
+
+// LoadAliasIndex returns every alias mapped to its canonical entity, following merges
+func (r *EntityRepository) LoadAliasIndex(ctx context.Context) ([]AliasIndexEntry, error) {
+	query := `
+		SELECT ea.alias_normalized, ea.entity_type,
+		       COALESCE(target.id, e.id), COALESCE(target.canonical_name, e.canonical_name),
+		       COALESCE(target.origin, e.origin)
+		FROM entity_aliases ea
+		JOIN entities e ON e.id = ea.entity_id
+		LEFT JOIN entities target ON target.id = e.merged_into
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alias index: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]AliasIndexEntry, 0)
+	for rows.Next() {
+		var e AliasIndexEntry
+		if err := rows.Scan(&e.AliasNormalized, &e.EntityType, &e.EntityID, &e.CanonicalName, &e.Origin); err != nil {
+			return nil, fmt.Errorf("failed to scan alias: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// FindByAlias returns the canonical entity ID for a normalized alias
+func (r *EntityRepository) FindByAlias(ctx context.Context, normalized, entityType string) (int64, error) {
+	query := `
+		SELECT COALESCE(e.merged_into, e.id)
+		FROM entity_aliases ea
+		JOIN entities e ON e.id = ea.entity_id
+		WHERE ea.alias_normalized = $1 AND ea.entity_type = $2
+	`
+
+	var id int64
+	err := r.db.QueryRow(ctx, query, normalized, entityType).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrEntityNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find entity by alias: %w", err)
+	}
+
+	return id, nil
+}
+
+// CreateEntity creates a new entity with its aliases in a single transaction.
+// If the first alias is already claimed (concurrent creation), the existing entity ID is returned.
+func (r *EntityRepository) CreateEntity(ctx context.Context, entity *models.Entity, aliases []AliasInput) (int64, bool, error) {
+	if len(aliases) == 0 {
+		return 0, false, fmt.Errorf("entity requires at least one alias")
+	}
+
+	if id, err := r.FindByAlias(ctx, aliases[0].Normalized, entity.EntityType); err == nil {
+		return id, false, nil
+	} else if !errors.Is(err, ErrEntityNotFound) {
+		return 0, false, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO entities (canonical_name, entity_type, external_id, description, origin)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, entity.CanonicalName, entity.EntityType, entity.ExternalID, entity.Description, entity.Origin).Scan(&id)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create entity: %w", err)
+	}
+
+	for i, alias := range aliases {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO entity_aliases (entity_id, alias, alias_normalized, entity_type, origin)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (alias_normalized, entity_type) DO NOTHING
+		`, id, alias.Alias, alias.Normalized, entity.EntityType, entity.Origin)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to create alias: %w", err)
+		}
+		if i == 0 && tag.RowsAffected() == 0 {
+			// Lost the race for the primary alias - use the winner
+			tx.Rollback(ctx)
+			existing, err := r.FindByAlias(ctx, alias.Normalized, entity.EntityType)
+			return existing, false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, false, fmt.Errorf("failed to commit entity: %w", err)
+	}
+
+	r.logger.Debugf("Created entity %d (%s: %s)", id, entity.EntityType, entity.CanonicalName)
+	return id, true, nil
+}
+
+// AdoptFullName attaches a full name to an auto-created entity that was only known by a
+// bare surname: the name becomes an alias and the canonical name. Returns false when the
+// alias already belongs to another entity.
+func (r *EntityRepository) AdoptFullName(ctx context.Context, entityID int64, entityType string, alias AliasInput) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO entity_aliases (entity_id, alias, alias_normalized, entity_type, origin)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (alias_normalized, entity_type) DO NOTHING
+	`, entityID, alias.Alias, alias.Normalized, entityType, models.EntityOriginAuto)
+	if err != nil {
+		return false, fmt.Errorf("failed to add alias %q: %w", alias.Alias, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE entities SET canonical_name = $2, updated_at = NOW()
+		WHERE id = $1 AND origin = $3
+	`, entityID, alias.Alias, models.EntityOriginAuto); err != nil {
+		return false, fmt.Errorf("failed to rename entity: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit entity name: %w", err)
+	}
+
+	r.logger.Debugf("Entity %d is now known as %s", entityID, alias.Alias)
+	return true, nil
+}
+
+// QueueMergeCandidate records that two entities may be the same, for manual review
+func (r *EntityRepository) QueueMergeCandidate(ctx context.Context, entityID, candidateID int64, reason string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO entity_merge_candidates (entity_id, candidate_id, reason)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (entity_id, candidate_id) DO NOTHING
+	`, entityID, candidateID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to queue merge candidate: %w", err)
+	}
+	return nil
+}
+
+// ListMergeCandidates returns queued pairs whose entities have not been merged, oldest first
+func (r *EntityRepository) ListMergeCandidates(ctx context.Context, limit, offset int) ([]models.EntityMergeCandidate, int, error) {
+	const where = `
+		FROM entity_merge_candidates mc
+		JOIN entities e ON e.id = mc.entity_id
+		JOIN entities c ON c.id = mc.candidate_id
+		WHERE e.merged_into IS NULL AND c.merged_into IS NULL
+	`
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*)`+where).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count merge candidates: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT mc.id, mc.entity_id, e.canonical_name, mc.candidate_id, c.canonical_name, mc.reason, mc.created_at
+	`+where+`
+		ORDER BY mc.created_at, mc.id
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list merge candidates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := make([]models.EntityMergeCandidate, 0)
+	for rows.Next() {
+		var mc models.EntityMergeCandidate
+		if err := rows.Scan(&mc.ID, &mc.EntityID, &mc.EntityName, &mc.CandidateID, &mc.CandidateName, &mc.Reason, &mc.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan merge candidate: %w", err)
+		}
+		candidates = append(candidates, mc)
+	}
+
+	return candidates, total, rows.Err()
+}
+
+// DismissMergeCandidate removes a queued pair that turned out to be different entities
+func (r *EntityRepository) DismissMergeCandidate(ctx context.Context, id int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM entity_merge_candidates WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to dismiss merge candidate: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrMergeCandidateNotFound
+	}
+	return nil
+}
+
+// UpsertExternal creates or updates an entity identified by its external ID
+func (r *EntityRepository) UpsertExternal(ctx context.Context, entity *models.Entity) (int64, error) {
+	query := `
+		INSERT INTO entities (canonical_name, entity_type, external_id, description, origin)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (external_id) WHERE external_id IS NOT NULL
+		DO UPDATE SET canonical_name = EXCLUDED.canonical_name,
+		              entity_type = EXCLUDED.entity_type,
+		              description = COALESCE(EXCLUDED.description, entities.description)
+		RETURNING id
+	`
+
+	var id int64
+	err := r.db.QueryRow(ctx, query,
+		entity.CanonicalName,
+		entity.EntityType,
+		entity.ExternalID,
+		entity.Description,
+		entity.Origin,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to upsert entity %v: %w", entity.ExternalID, err)
+	}
+
+	return id, nil
+}
+
+// AddAlias attaches an alias to an entity. Existing aliases are only reassigned when override is set.
+func (r *EntityRepository) AddAlias(ctx context.Context, entityID int64, entityType string, alias AliasInput, origin string, override bool) error {
+	query := `
+		INSERT INTO entity_aliases (entity_id, alias, alias_normalized, entity_type, origin)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (alias_normalized, entity_type) DO NOTHING
+	`
+	if override {
+		query = `
+			INSERT INTO entity_aliases (entity_id, alias, alias_normalized, entity_type, origin)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (alias_normalized, entity_type)
+			DO UPDATE SET entity_id = EXCLUDED.entity_id, alias = EXCLUDED.alias, origin = EXCLUDED.origin
+		`
+	}
+
+	if _, err := r.db.Exec(ctx, query, entityID, alias.Alias, alias.Normalized, entityType, origin); err != nil {
+		return fmt.Errorf("failed to add alias %q: %w", alias.Alias, err)
+	}
+
+	return nil
+}
+
+// ReplaceArticleLinks replaces the resolved entity mentions of an article
+func (r *EntityRepository) ReplaceArticleLinks(ctx context.Context, articleID int64, links []ArticleEntityLink) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM article_entities WHERE article_id = $1`, articleID); err != nil {
+		return fmt.Errorf("failed to clear article entities: %w", err)
+	}
+
+	if len(links) > 0 {
+		batch := &pgx.Batch{}
+		for _, link := range links {
+			batch.Queue(`
+				INSERT INTO article_entities (article_id, entity_id, mention)
+				VALUES ($1, $2, $3)
+				ON CONFLICT DO NOTHING
+			`, articleID, link.EntityID, link.Mention)
+		}
+
+		results := tx.SendBatch(ctx, batch)
+		for range links {
+			if _, err := results.Exec(); err != nil {
+				results.Close()
+				return fmt.Errorf("failed to link article entity: %w", err)
+			}
+		}
+		if err := results.Close(); err != nil {
+			return fmt.Errorf("failed to link article entities: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit article entities: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an entity with its aliases and article count
+func (r *EntityRepository) GetByID(ctx context.Context, id int64) (*models.Entity, error) {
+	query := `
+		SELECT e.id, e.canonical_name, e.entity_type, e.external_id, e.description,
+		       e.merged_into, e.origin, e.created_at, e.updated_at,
+		       (SELECT COUNT(DISTINCT article_id) FROM article_entities WHERE entity_id = e.id)
+		FROM entities e
+		WHERE e.id = $1
+	`
+
+	var entity models.Entity
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&entity.ID,
+		&entity.CanonicalName,
+		&entity.EntityType,
+		&entity.ExternalID,
+		&entity.Description,
+		&entity.MergedInto,
+		&entity.Origin,
+		&entity.CreatedAt,
+		&entity.UpdatedAt,
+		&entity.ArticleCount,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrEntityNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity: %w", err)
+	}
+
+	aliases, err := r.getAliases(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	entity.Aliases = aliases
+
+	return &entity, nil
+}
+
+func (r *EntityRepository) getAliases(ctx context.Context, entityID int64) ([]models.EntityAlias, error) {
+	query := `
+		SELECT id, entity_id, alias, alias_normalized, entity_type, origin, created_at
+		FROM entity_aliases
+		WHERE entity_id = $1
+		ORDER BY alias
+	`
+
+	rows, err := r.db.Query(ctx, query, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aliases: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := make([]models.EntityAlias, 0)
+	for rows.Next() {
+		var a models.EntityAlias
+		if err := rows.Scan(&a.ID, &a.EntityID, &a.Alias, &a.AliasNormalized, &a.EntityType, &a.Origin, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alias: %w", err)
+		}
+		aliases = append(aliases, a)
+	}
+
+	return aliases, rows.Err()
+}
+
+// List retrieves canonical (unmerged) entities with optional filtering
+func (r *EntityRepository) List(ctx context.Context, filter models.EntityFilter) ([]models.Entity, int, error) {
+	where := " WHERE e.merged_into IS NULL"
+	args := []interface{}{}
+	argPos := 1
+
+	if filter.EntityType != "" {
+		where += fmt.Sprintf(" AND e.entity_type = $%d", argPos)
+		args = append(args, filter.EntityType)
+		argPos++
+	}
+
+	if filter.Search != "" {
+		where += fmt.Sprintf(` AND (e.canonical_name ILIKE $%d OR EXISTS (
+			SELECT 1 FROM entity_aliases ea WHERE ea.entity_id = e.id AND ea.alias ILIKE $%d))`, argPos, argPos)
+		args = append(args, "%"+filter.Search+"%")
+		argPos++
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM entities e"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count entities: %w", err)
+	}
+
+	query := `
+		SELECT e.id, e.canonical_name, e.entity_type, e.external_id, e.description,
+		       e.merged_into, e.origin, e.created_at, e.updated_at,
+		       (SELECT COUNT(DISTINCT article_id) FROM article_entities WHERE entity_id = e.id) AS article_count
+		FROM entities e` + where +
+		fmt.Sprintf(" ORDER BY article_count DESC, e.canonical_name LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list entities: %w", err)
+	}
+	defer rows.Close()
+
+	entities := make([]models.Entity, 0)
+	for rows.Next() {
+		var entity models.Entity
+		if err := rows.Scan(
+			&entity.ID,
+			&entity.CanonicalName,
+			&entity.EntityType,
+			&entity.ExternalID,
+			&entity.Description,
+			&entity.MergedInto,
+			&entity.Origin,
+			&entity.CreatedAt,
+			&entity.UpdatedAt,
+			&entity.ArticleCount,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan entity: %w", err)
+		}
+		entities = append(entities, entity)
+	}
+
+	return entities, total, rows.Err()
+}
+
+// Merge folds the source entities into the target: aliases and article links move,
+// and the sources are marked as merged so old IDs keep resolving.
+func (r *EntityRepository) Merge(ctx context.Context, targetID int64, sourceIDs []int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var targetMerged *int64
+	err = tx.QueryRow(ctx, `SELECT merged_into FROM entities WHERE id = $1 FOR UPDATE`, targetID).Scan(&targetMerged)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrEntityNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock target entity: %w", err)
+	}
+	if targetMerged != nil {
+		return fmt.Errorf("target entity %d is itself merged into %d", targetID, *targetMerged)
+	}
+
+	statements := []string{
+		`UPDATE entity_aliases SET entity_id = $1 WHERE entity_id = ANY($2)`,
+		`INSERT INTO article_entities (article_id, entity_id, mention)
+		 SELECT article_id, $1, mention FROM article_entities WHERE entity_id = ANY($2)
+		 ON CONFLICT DO NOTHING`,
+		`DELETE FROM article_entities WHERE entity_id = ANY($2)`,
+		`UPDATE entities SET merged_into = $1 WHERE id = ANY($2) OR merged_into = ANY($2)`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(ctx, stmt, targetID, sourceIDs); err != nil {
+			return fmt.Errorf("failed to merge entities: %w", err)
+		}
+	}
+	// Merged pairs leave the review queue
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM entity_merge_candidates WHERE entity_id = ANY($1) OR candidate_id = ANY($1)
+	`, sourceIDs); err != nil {
+		return fmt.Errorf("failed to merge entities: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit merge: %w", err)
+	}
+
+	r.logger.Infof("Merged entities %v into %d", sourceIDs, targetID)
+	return nil
+}
+
+// GetMentions returns the stored mentions linked to an entity
+func (r *EntityRepository) GetMentions(ctx context.Context, entityID int64) ([]ArticleMention, error) {
+	rows, err := r.db.Query(ctx, `SELECT article_id, mention FROM article_entities WHERE entity_id = $1`, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity mentions: %w", err)
+	}
+	defer rows.Close()
+
+	mentions := make([]ArticleMention, 0)
+	for rows.Next() {
+		var m ArticleMention
+		if err := rows.Scan(&m.ArticleID, &m.Mention); err != nil {
+			return nil, fmt.Errorf("failed to scan mention: %w", err)
+		}
+		mentions = append(mentions, m)
+	}
+
+	return mentions, rows.Err()
+}
+
+// Split moves the given aliases and mentions from the source entity to a newly created entity
+func (r *EntityRepository) Split(ctx context.Context, sourceID int64, entity *models.Entity, normalizedAliases []string, mentions []ArticleMention) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var newID int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO entities (canonical_name, entity_type, external_id, description, origin)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, entity.CanonicalName, entity.EntityType, entity.ExternalID, entity.Description, entity.Origin).Scan(&newID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create split entity: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE entity_aliases SET entity_id = $1, entity_type = $3, origin = $4
+		WHERE entity_id = $2 AND alias_normalized = ANY($5)
+	`, newID, sourceID, entity.EntityType, models.EntityOriginManual, normalizedAliases)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move aliases: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, fmt.Errorf("none of the given aliases belong to entity %d", sourceID)
+	}
+
+	for _, m := range mentions {
+		if _, err := tx.Exec(ctx, `
+			UPDATE article_entities SET entity_id = $1
+			WHERE entity_id = $2 AND article_id = $3 AND mention = $4
+		`, newID, sourceID, m.ArticleID, m.Mention); err != nil {
+			return 0, fmt.Errorf("failed to move mention: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit split: %w", err)
+	}
+
+	r.logger.Infof("Split %d aliases and %d mentions from entity %d into %d",
+		tag.RowsAffected(), len(mentions), sourceID, newID)
+	return newID, nil
+}
+
+// GetProcessedArticleEntities returns raw AI entities for processed articles after a watermark ID
+func (r *EntityRepository) GetProcessedArticleEntities(ctx context.Context, afterID int64, limit int) ([]ArticleEntitiesRow, error) {
+	query := `
+		SELECT id, ai_entities
+		FROM articles
+		WHERE ai_processed = TRUE
+		  AND ai_entities IS NOT NULL
+		  AND id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get processed articles: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]ArticleEntitiesRow, 0, limit)
+	for rows.Next() {
+		var row ArticleEntitiesRow
+		if err := rows.Scan(&row.ArticleID, &row.Entities); err != nil {
+			return nil, fmt.Errorf("failed to scan article entities: %w", err)
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
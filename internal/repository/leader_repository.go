@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// leaderLockKey is the advisory lock key held by the instance that runs the background workers
+const leaderLockKey int64 = 0x494e4e_0002 // "INN" + 2
+
+// leaderApplicationPrefix marks the session holding the leader lock in pg_stat_activity,
+// so every instance can tell which one leads
+const leaderApplicationPrefix = "intellinieuws-leader:"
+
+// LeaderRepository takes and inspects the leader election lock
+type LeaderRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+// NewLeaderRepository creates a new leader repository
+func NewLeaderRepository(db *pgxpool.Pool, log *logger.Logger) *LeaderRepository {
+	return &LeaderRepository{
+		db:     db,
+		logger: log.WithComponent("leader-repo"),
+	}
+}
+
+// LeaderLock is a held leader lock. The lock lives as long as its database session:
+// when the holder exits or loses its connection PostgreSQL releases it.
+type LeaderLock struct {
+	conn *pgx.Conn
+}
+
+// TryLock takes the session-level leader lock without waiting. The lock session is taken
+// out of the pool and named after instanceID.
+func (r *LeaderRepository) TryLock(ctx context.Context, instanceID string) (*LeaderLock, bool, error) {
+	pooled, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	var acquired bool
+	if err := pooled.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, leaderLockKey).Scan(&acquired); err != nil {
+		pooled.Release()
+		return nil, false, fmt.Errorf("failed to take leader lock: %w", err)
+	}
+	if !acquired {
+		pooled.Release()
+		return nil, false, nil
+	}
+
+	// The session now belongs to the lock, it must not be handed out by the pool again
+	lock := &LeaderLock{conn: pooled.Hijack()}
+	if _, err := lock.conn.Exec(ctx, `SELECT set_config('application_name', $1, false)`,
+		leaderApplicationPrefix+instanceID); err != nil {
+		lock.Release()
+		return nil, false, fmt.Errorf("failed to name leader session: %w", err)
+	}
+	return lock, true, nil
+}
+
+// Check verifies that the lock session is still alive, and with it the lock
+func (l *LeaderLock) Check(ctx context.Context) error {
+	if err := l.conn.Ping(ctx); err != nil {
+		return fmt.Errorf("leader session lost: %w", err)
+	}
+	return nil
+}
+
+// Release releases the lock by closing its session
+func (l *LeaderLock) Release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = l.conn.Close(ctx)
+}
+
+// GetLeader returns the instance ID of the current lock holder, nil when no instance holds it
+func (r *LeaderRepository) GetLeader(ctx context.Context) (*string, error) {
+	var name string
+	err := r.db.QueryRow(ctx, `
+		SELECT a.application_name
+		FROM pg_locks l
+		JOIN pg_stat_activity a ON a.pid = l.pid
+		WHERE l.locktype = 'advisory' AND l.granted
+		  AND l.classid::bigint = $1 AND l.objid::bigint = $2 AND l.objsubid = 1
+		LIMIT 1
+	`, leaderLockKey>>32, leaderLockKey&0xffffffff).Scan(&name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leader: %w", err)
+	}
+
+	leader := strings.TrimPrefix(name, leaderApplicationPrefix)
+	return &leader, nil
+}
@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+var (
+	// ErrSavedSearchNotFound is returned when a saved search does not exist
+	ErrSavedSearchNotFound = errors.New("saved search not found")
+	// ErrSavedSearchExists is returned when the owner already has a search with that name
+	ErrSavedSearchExists = errors.New("saved search with this name already exists")
+)
+
+// SavedSearchRepository handles database operations for saved searches and their matches
+type SavedSearchRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+// NewSavedSearchRepository creates a new saved search repository
+func NewSavedSearchRepository(db *pgxpool.Pool, log *logger.Logger) *SavedSearchRepository {
+	return &SavedSearchRepository{
+		db:     db,
+		logger: log.WithComponent("saved-search-repo"),
+	}
+}
+
+// KeySearchOwner is the saved search owner for searches created with an API key. Key
+// rotation moves the searches to the replacement key.
+func KeySearchOwner(keyID int64) string {
+	return "key:" + strconv.FormatInt(keyID, 10)
+}
+
+const savedSearchColumns = `
+	id, owner, name, query, alert_channels, webhook_subscription_id, COALESCE(email, ''),
+	last_viewed_at, last_matched_at, created_at, updated_at
+`
+
+func scanSavedSearch(row pgx.Row) (*models.SavedSearch, error) {
+	var s models.SavedSearch
+	err := row.Scan(
+		&s.ID,
+		&s.Owner,
+		&s.Name,
+		&s.Query,
+		&s.AlertChannels,
+		&s.WebhookSubscriptionID,
+		&s.Email,
+		&s.LastViewedAt,
+		&s.LastMatchedAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Create stores a new saved search
+func (r *SavedSearchRepository) Create(ctx context.Context, s *models.SavedSearch) (*models.SavedSearch, error) {
+	row := r.db.QueryRow(ctx, `
+		INSERT INTO saved_searches (owner, name, query, alert_channels, webhook_subscription_id, email)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		RETURNING `+savedSearchColumns,
+		s.Owner, s.Name, s.Query, s.AlertChannels, s.WebhookSubscriptionID, s.Email,
+	)
+	created, err := scanSavedSearch(row)
+	if isUniqueViolation(err) {
+		return nil, ErrSavedSearchExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create saved search: %w", err)
+	}
+	return created, nil
+}
+
+// GetByID returns a saved search
+func (r *SavedSearchRepository) GetByID(ctx context.Context, id int64) (*models.SavedSearch, error) {
+	s, err := scanSavedSearch(r.db.QueryRow(ctx, `SELECT `+savedSearchColumns+` FROM saved_searches WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSavedSearchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+	return s, nil
+}
+
+// List returns the saved searches of an owner
+func (r *SavedSearchRepository) List(ctx context.Context, owner string) ([]models.SavedSearch, error) {
+	return r.query(ctx, `SELECT `+savedSearchColumns+` FROM saved_searches WHERE owner = $1 ORDER BY name`, owner)
+}
+
+// ListAlerting returns all saved searches with at least one alert channel
+func (r *SavedSearchRepository) ListAlerting(ctx context.Context) ([]models.SavedSearch, error) {
+	return r.query(ctx, `SELECT `+savedSearchColumns+` FROM saved_searches WHERE alert_channels <> '{}' ORDER BY id`)
+}
+
+func (r *SavedSearchRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.SavedSearch, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	searches := []models.SavedSearch{}
+	for rows.Next() {
+		s, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved search: %w", err)
+		}
+		searches = append(searches, *s)
+	}
+	return searches, rows.Err()
+}
+
+// Update stores the mutable fields of a saved search
+func (r *SavedSearchRepository) Update(ctx context.Context, s *models.SavedSearch) (*models.SavedSearch, error) {
+	row := r.db.QueryRow(ctx, `
+		UPDATE saved_searches
+		SET name = $2, query = $3, alert_channels = $4, webhook_subscription_id = $5, email = NULLIF($6, ''),
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+savedSearchColumns,
+		s.ID, s.Name, s.Query, s.AlertChannels, s.WebhookSubscriptionID, s.Email,
+	)
+	updated, err := scanSavedSearch(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSavedSearchNotFound
+	}
+	if isUniqueViolation(err) {
+		return nil, ErrSavedSearchExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update saved search: %w", err)
+	}
+	return updated, nil
+}
+
+// Delete removes a saved search and its matches
+func (r *SavedSearchRepository) Delete(ctx context.Context, id int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM saved_searches WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrSavedSearchNotFound
+	}
+	return nil
+}
+
+// MarkViewed resets the "new since last viewed" counter
+func (r *SavedSearchRepository) MarkViewed(ctx context.Context, id int64) error {
+	_, err := r.db.Exec(ctx, `UPDATE saved_searches SET last_viewed_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark saved search viewed: %w", err)
+	}
+	return nil
+}
+
+// Results runs a saved search query. Only the pagination fields of filter are used.
+func (r *SavedSearchRepository) Results(ctx context.Context, q models.SavedSearchQuery, filter models.ArticleFilter) (*models.ArticlePage, error) {
+	where, args, argPos := articleQueryWhere(q)
+
+	var total *int
+	if !filter.SkipCount {
+		var count int
+		if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM articles"+where, args...).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count saved search results: %w", err)
+		}
+		total = &count
+	}
+
+	query := `
+		SELECT id, title, summary, url, published, source, keywords, image_url,
+		       author, category, COALESCE(language, '') as language, content_hash, created_at, updated_at,
+		       COALESCE(content_extracted, FALSE) as content_extracted,
+		       content_extracted_at
+		FROM articles` + where
+	query, args, paging := applyArticlePagination(query, args, argPos, filter)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run saved search: %w", err)
+	}
+	defer rows.Close()
+
+	articles := []models.Article{}
+	for rows.Next() {
+		var article models.Article
+		err := rows.Scan(
+			&article.ID,
+			&article.Title,
+			&article.Summary,
+			&article.URL,
+			&article.Published,
+			&article.Source,
+			&article.Keywords,
+			&article.ImageURL,
+			&article.Author,
+			&article.Category,
+			&article.Language,
+			&article.ContentHash,
+			&article.CreatedAt,
+			&article.UpdatedAt,
+			&article.ContentExtracted,
+			&article.ContentExtractedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		articles = append(articles, article)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to run saved search: %w", err)
+	}
+
+	return finishArticlePage(articles, total, paging, filter), nil
+}
+
+// CountSince counts results stored after since
+func (r *SavedSearchRepository) CountSince(ctx context.Context, q models.SavedSearchQuery, since time.Time) (int, error) {
+	where, args, argPos := articleQueryWhere(q)
+	where += fmt.Sprintf(" AND created_at > $%d", argPos)
+	args = append(args, since)
+
+	var count int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM articles"+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count new saved search results: %w", err)
+	}
+	return count, nil
+}
+
+// RecordMatch stores that an article matched a search. Returns false when it already
+// matched before, so each article raises one alert per search.
+func (r *SavedSearchRepository) RecordMatch(ctx context.Context, searchID, articleID int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		WITH inserted AS (
+			INSERT INTO saved_search_matches (saved_search_id, article_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+			RETURNING saved_search_id
+		)
+		UPDATE saved_searches SET last_matched_at = NOW()
+		WHERE id IN (SELECT saved_search_id FROM inserted)
+	`, searchID, articleID)
+	if err != nil {
+		return false, fmt.Errorf("failed to record saved search match: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ClaimDigest claims up to limit matches not yet emailed for searches alerting by email,
+// oldest first. Claimed matches are marked emailed right away, so concurrent digests never
+// send the same match; ReleaseDigest hands them back when the email could not be sent.
+func (r *SavedSearchRepository) ClaimDigest(ctx context.Context, limit int) ([]models.SavedSearchDigestItem, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH due AS (
+			SELECT m.saved_search_id, m.article_id
+			FROM saved_search_matches m
+			JOIN saved_searches s ON s.id = m.saved_search_id
+			WHERE m.emailed_at IS NULL
+			  AND 'email' = ANY(s.alert_channels)
+			  AND s.email IS NOT NULL
+			ORDER BY m.matched_at
+			LIMIT $1
+			FOR UPDATE OF m SKIP LOCKED
+		), claimed AS (
+			UPDATE saved_search_matches m SET emailed_at = NOW()
+			FROM due
+			WHERE m.saved_search_id = due.saved_search_id AND m.article_id = due.article_id
+			RETURNING m.saved_search_id, m.article_id, m.matched_at
+		)
+		SELECT s.id, s.name, s.email, a.id, a.title, a.url, a.source, a.published
+		FROM claimed c
+		JOIN saved_searches s ON s.id = c.saved_search_id
+		JOIN articles a ON a.id = c.article_id
+		ORDER BY c.matched_at
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim saved search digest: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.SavedSearchDigestItem{}
+	for rows.Next() {
+		var item models.SavedSearchDigestItem
+		if err := rows.Scan(
+			&item.SavedSearchID, &item.SavedSearchName, &item.Email,
+			&item.ArticleID, &item.Title, &item.URL, &item.Source, &item.Published,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan saved search digest: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// ReleaseDigest returns claimed matches to the queue so the next digest includes them
+func (r *SavedSearchRepository) ReleaseDigest(ctx context.Context, searchID int64, articleIDs []int64) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE saved_search_matches SET emailed_at = NULL
+		WHERE saved_search_id = $1 AND article_id = ANY($2)
+	`, searchID, articleIDs)
+	if err != nil {
+		return fmt.Errorf("failed to release saved search digest: %w", err)
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
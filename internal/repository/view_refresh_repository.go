@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// analyticsRefreshLockKey is the advisory lock key held while analytics views refresh
+const analyticsRefreshLockKey int64 = 0x494e4e_0001 // "INN" + 1
+
+// ViewRefreshRepository refreshes the analytics materialized views and tracks their
+// refresh state in analytics_view_refreshes
+type ViewRefreshRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+// NewViewRefreshRepository creates a new view refresh repository
+func NewViewRefreshRepository(db *pgxpool.Pool, log *logger.Logger) *ViewRefreshRepository {
+	return &ViewRefreshRepository{
+		db:     db,
+		logger: log.WithComponent("view-refresh-repo"),
+	}
+}
+
+// TryLock takes the session-level advisory lock that serializes analytics refreshes across
+// replicas, without waiting. When acquired is true the caller must call unlock, which
+// releases the lock and its connection.
+func (r *ViewRefreshRepository) TryLock(ctx context.Context) (unlock func(), acquired bool, err error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, analyticsRefreshLockKey).Scan(&acquired); err != nil {
+		conn.Release()
+		return nil, false, fmt.Errorf("failed to take refresh lock: %w", err)
+	}
+	if !acquired {
+		conn.Release()
+		return nil, false, nil
+	}
+
+	unlock = func() {
+		// The refresh context may be cancelled by now, the lock must still be released
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, analyticsRefreshLockKey); err != nil {
+			// Closing the session is the only other way to drop a session-level lock
+			r.logger.WithError(err).Warn("Failed to release refresh lock, closing connection")
+			conn.Conn().Close(ctx)
+		}
+		conn.Release()
+	}
+	return unlock, true, nil
+}
+
+// List returns the refresh state of every managed view, by view name
+func (r *ViewRefreshRepository) List(ctx context.Context) ([]models.ViewRefresh, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT view_name, last_refreshed_at, last_duration_ms, last_row_count, refresh_count,
+		       last_attempt_at, last_error, refreshed_by
+		FROM analytics_view_refreshes
+		ORDER BY view_name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list view refreshes: %w", err)
+	}
+	defer rows.Close()
+
+	views := make([]models.ViewRefresh, 0)
+	for rows.Next() {
+		var v models.ViewRefresh
+		if err := rows.Scan(&v.ViewName, &v.LastRefreshedAt, &v.LastDurationMs, &v.LastRowCount,
+			&v.RefreshCount, &v.LastAttemptAt, &v.LastError, &v.RefreshedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan view refresh: %w", err)
+		}
+		views = append(views, v)
+	}
+	return views, rows.Err()
+}
+
+// IsPopulated reports whether a materialized view holds data; unpopulated views cannot be
+// refreshed concurrently
+func (r *ViewRefreshRepository) IsPopulated(ctx context.Context, view string) (bool, error) {
+	var populated bool
+	err := r.db.QueryRow(ctx, `SELECT ispopulated FROM pg_matviews WHERE matviewname = $1`, view).Scan(&populated)
+	if err != nil {
+		return false, fmt.Errorf("failed to check view %s: %w", view, err)
+	}
+	return populated, nil
+}
+
+// Refresh refreshes a materialized view, updates its planner statistics and returns its
+// row count
+func (r *ViewRefreshRepository) Refresh(ctx context.Context, view string, concurrent bool) (int64, error) {
+	name := pgx.Identifier{view}.Sanitize()
+	refresh := "REFRESH MATERIALIZED VIEW " + name
+	if concurrent {
+		refresh = "REFRESH MATERIALIZED VIEW CONCURRENTLY " + name
+	}
+	if _, err := r.db.Exec(ctx, refresh); err != nil {
+		return 0, fmt.Errorf("failed to refresh %s: %w", view, err)
+	}
+	if _, err := r.db.Exec(ctx, "ANALYZE "+name); err != nil {
+		return 0, fmt.Errorf("failed to analyze %s: %w", view, err)
+	}
+
+	var count int64
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM "+name).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count %s: %w", view, err)
+	}
+	return count, nil
+}
+
+// RecordSuccess stores a successful refresh that started at startedAt
+func (r *ViewRefreshRepository) RecordSuccess(ctx context.Context, view string, startedAt time.Time, duration time.Duration, rowCount int64, host string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO analytics_view_refreshes (view_name, last_refreshed_at, last_duration_ms, last_row_count,
+			refresh_count, last_attempt_at, last_error, refreshed_by, updated_at)
+		VALUES ($1, $2, $3, $4, 1, $2, NULL, $5, NOW())
+		ON CONFLICT (view_name) DO UPDATE SET
+			last_refreshed_at = EXCLUDED.last_refreshed_at,
+			last_duration_ms = EXCLUDED.last_duration_ms,
+			last_row_count = EXCLUDED.last_row_count,
+			refresh_count = analytics_view_refreshes.refresh_count + 1,
+			last_attempt_at = EXCLUDED.last_attempt_at,
+			last_error = NULL,
+			refreshed_by = EXCLUDED.refreshed_by,
+			updated_at = NOW()
+	`, view, startedAt, duration.Milliseconds(), rowCount, host)
+	if err != nil {
+		return fmt.Errorf("failed to record refresh of %s: %w", view, err)
+	}
+	return nil
+}
+
+// RecordFailure stores a failed refresh attempt; the last successful refresh is kept
+func (r *ViewRefreshRepository) RecordFailure(ctx context.Context, view string, attemptedAt time.Time, refreshErr error, host string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO analytics_view_refreshes (view_name, last_attempt_at, last_error, refreshed_by, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (view_name) DO UPDATE SET
+			last_attempt_at = EXCLUDED.last_attempt_at,
+			last_error = EXCLUDED.last_error,
+			refreshed_by = EXCLUDED.refreshed_by,
+			updated_at = NOW()
+	`, view, attemptedAt, refreshErr.Error(), host)
+	if err != nil {
+		return fmt.Errorf("failed to record refresh failure of %s: %w", view, err)
+	}
+	return nil
+}
+
+// GetDataAsOf returns the oldest last refresh of the given views, nil when one of them has
+// never been refreshed or is not tracked
+func (r *ViewRefreshRepository) GetDataAsOf(ctx context.Context, views []string) (*time.Time, error) {
+	var asOf *time.Time
+	err := r.db.QueryRow(ctx, `
+		SELECT CASE WHEN COUNT(last_refreshed_at) = cardinality($1::text[]) THEN MIN(last_refreshed_at) END
+		FROM analytics_view_refreshes
+		WHERE view_name = ANY($1)
+	`, views).Scan(&asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data as of: %w", err)
+	}
+	return asOf, nil
+}
@@ -0,0 +1,403 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// ErrWebhookNotFound is returned when a webhook subscription does not exist
+var ErrWebhookNotFound = errors.New("webhook subscription not found")
+
+// WebhookRepository handles database operations for webhook subscriptions and deliveries
+type WebhookRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *pgxpool.Pool, log *logger.Logger) *WebhookRepository {
+	return &WebhookRepository{
+		db:     db,
+		logger: log.WithComponent("webhook-repo"),
+	}
+}
+
+const webhookColumns = `
+	id, name, COALESCE(owner, ''), url, secret, events, sources, keywords, entities, tickers,
+	min_sentiment::float8, max_sentiment::float8, active, consecutive_failures, disabled_at,
+	COALESCE(disabled_reason, ''), last_delivery_at, last_success_at, COALESCE(created_by, ''),
+	created_at, updated_at
+`
+
+func scanWebhook(row pgx.Row) (*models.WebhookSubscription, error) {
+	var w models.WebhookSubscription
+	err := row.Scan(
+		&w.ID,
+		&w.Name,
+		&w.Owner,
+		&w.URL,
+		&w.Secret,
+		&w.Events,
+		&w.Sources,
+		&w.Keywords,
+		&w.Entities,
+		&w.Tickers,
+		&w.MinSentiment,
+		&w.MaxSentiment,
+		&w.Active,
+		&w.ConsecutiveFailures,
+		&w.DisabledAt,
+		&w.DisabledReason,
+		&w.LastDeliveryAt,
+		&w.LastSuccessAt,
+		&w.CreatedBy,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Create stores a new subscription
+func (r *WebhookRepository) Create(ctx context.Context, w *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	row := r.db.QueryRow(ctx, `
+		INSERT INTO webhook_subscriptions
+			(name, owner, url, secret, events, sources, keywords, entities, tickers, min_sentiment, max_sentiment, created_by)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''))
+		RETURNING `+webhookColumns,
+		w.Name, w.Owner, w.URL, w.Secret, w.Events, w.Sources, w.Keywords, w.Entities, w.Tickers,
+		w.MinSentiment, w.MaxSentiment, w.CreatedBy,
+	)
+	created, err := scanWebhook(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return created, nil
+}
+
+// GetByID returns a subscription
+func (r *WebhookRepository) GetByID(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	w, err := scanWebhook(r.db.QueryRow(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return w, nil
+}
+
+// List returns subscriptions, optionally for one owner and including disabled ones
+func (r *WebhookRepository) List(ctx context.Context, owner string, includeInactive bool) ([]models.WebhookSubscription, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhook_subscriptions WHERE 1=1`
+	args := []interface{}{}
+	argPos := 1
+
+	if owner != "" {
+		query += fmt.Sprintf(" AND owner = $%d", argPos)
+		args = append(args, owner)
+		argPos++
+	}
+	if !includeInactive {
+		query += " AND active = TRUE"
+	}
+	query += " ORDER BY created_at DESC"
+
+	return r.query(ctx, query, args...)
+}
+
+// ListActive returns all active subscriptions
+func (r *WebhookRepository) ListActive(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return r.query(ctx, `SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE active = TRUE`)
+}
+
+func (r *WebhookRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.WebhookSubscription, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []models.WebhookSubscription{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subs = append(subs, *w)
+	}
+	return subs, rows.Err()
+}
+
+// Update stores the mutable fields of a subscription
+func (r *WebhookRepository) Update(ctx context.Context, w *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	row := r.db.QueryRow(ctx, `
+		UPDATE webhook_subscriptions
+		SET name = $2, owner = NULLIF($3, ''), url = $4, events = $5, sources = $6, keywords = $7,
+		    entities = $8, tickers = $9, min_sentiment = $10, max_sentiment = $11, active = $12,
+		    consecutive_failures = $13, disabled_at = $14, disabled_reason = NULLIF($15, ''),
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+webhookColumns,
+		w.ID, w.Name, w.Owner, w.URL, w.Events, w.Sources, w.Keywords, w.Entities, w.Tickers,
+		w.MinSentiment, w.MaxSentiment, w.Active, w.ConsecutiveFailures, w.DisabledAt, w.DisabledReason,
+	)
+	updated, err := scanWebhook(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	if !updated.Active {
+		if err := r.cancelPending(ctx, updated.ID); err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
+}
+
+// Delete removes a subscription and its delivery log
+func (r *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// RecordSuccess resets the failure counter after a successful delivery
+func (r *WebhookRepository) RecordSuccess(ctx context.Context, id int64) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE webhook_subscriptions
+		SET consecutive_failures = 0, last_delivery_at = NOW(), last_success_at = NOW()
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook success: %w", err)
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt and disables the subscription once disableAfter
+// consecutive attempts have failed (0 = never). Returns true when it was disabled now.
+func (r *WebhookRepository) RecordFailure(ctx context.Context, id int64, disableAfter int) (bool, error) {
+	var disabled bool
+	err := r.db.QueryRow(ctx, `
+		WITH prev AS (
+			SELECT active FROM webhook_subscriptions WHERE id = $1 FOR UPDATE
+		)
+		UPDATE webhook_subscriptions
+		SET consecutive_failures = consecutive_failures + 1,
+		    last_delivery_at = NOW(),
+		    active = CASE WHEN $2 > 0 AND consecutive_failures + 1 >= $2 THEN FALSE ELSE active END,
+		    disabled_at = CASE WHEN $2 > 0 AND consecutive_failures + 1 >= $2 AND active THEN NOW() ELSE disabled_at END,
+		    disabled_reason = CASE WHEN $2 > 0 AND consecutive_failures + 1 >= $2 AND active
+		        THEN format('disabled after %s consecutive failed deliveries', consecutive_failures + 1)
+		        ELSE disabled_reason END
+		WHERE id = $1
+		RETURNING (SELECT active FROM prev) AND NOT webhook_subscriptions.active
+	`, id, disableAfter).Scan(&disabled)
+	if err != nil {
+		return false, fmt.Errorf("failed to record webhook failure: %w", err)
+	}
+
+	if disabled {
+		if err := r.cancelPending(ctx, id); err != nil {
+			return true, err
+		}
+	}
+	return disabled, nil
+}
+
+// cancelPending cancels queued deliveries of a disabled subscription
+func (r *WebhookRepository) cancelPending(ctx context.Context, subscriptionID int64) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'cancelled', error_message = COALESCE(error_message, 'subscription disabled')
+		WHERE subscription_id = $1 AND status IN ('pending', 'retrying')
+	`, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel pending webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// Enqueue stores pending deliveries
+func (r *WebhookRepository) Enqueue(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, d := range deliveries {
+		batch.Queue(`
+			INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+			VALUES ($1, $2, $3, $4)
+		`, d.SubscriptionID, d.EventID, d.EventType, d.Payload)
+	}
+
+	results := r.db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for range deliveries {
+		if _, err := results.Exec(); err != nil {
+			return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateDelivering stores a delivery that is sent immediately (test events). ClaimDue
+// never claims test events, so the caller records the outcome with RecordAttempt.
+func (r *WebhookRepository) CreateDelivering(ctx context.Context, d *models.WebhookDelivery) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status)
+		VALUES ($1, $2, $3, $4, 'delivering')
+		RETURNING id, created_at
+	`, d.SubscriptionID, d.EventID, d.EventType, d.Payload).Scan(&d.ID, &d.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	d.Status = models.WebhookDeliveryDelivering
+	return nil
+}
+
+// ClaimedDelivery is a due delivery with the endpoint it goes to
+type ClaimedDelivery struct {
+	models.WebhookDelivery
+	URL    string
+	Secret string
+}
+
+// ClaimDue leases up to limit due deliveries of active subscriptions. Claimed rows are
+// marked delivering until lease expires, so a crashed worker's deliveries are retried and
+// several instances can work the queue without sending twice. Test events are sent
+// synchronously by their caller and never claimed.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]ClaimedDelivery, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhook_subscriptions s ON s.id = d.subscription_id
+			WHERE d.status IN ('pending', 'delivering', 'retrying')
+			  AND d.next_attempt_at <= NOW()
+			  AND d.event_type <> $3
+			  AND s.active = TRUE
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET status = 'delivering', next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+		FROM due, webhook_subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING d.id, d.subscription_id, d.event_id::text, d.event_type, d.payload, d.attempts, d.created_at,
+		          s.url, s.secret
+	`, limit, lease.Milliseconds(), models.WebhookEventTest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	claimed := []ClaimedDelivery{}
+	for rows.Next() {
+		var c ClaimedDelivery
+		if err := rows.Scan(
+			&c.ID, &c.SubscriptionID, &c.EventID, &c.EventType, &c.Payload, &c.Attempts, &c.CreatedAt,
+			&c.URL, &c.Secret,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		c.Status = models.WebhookDeliveryDelivering
+		claimed = append(claimed, c)
+	}
+	return claimed, rows.Err()
+}
+
+// RecordAttempt stores the outcome of a delivery attempt
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, d *models.WebhookDelivery) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = COALESCE($4, next_attempt_at),
+		    response_status = $5, response_body = NULLIF($6, ''), error_message = NULLIF($7, ''),
+		    duration_ms = $8, delivered_at = $9
+		WHERE id = $1
+	`, d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.ResponseStatus, d.ResponseBody, d.ErrorMessage,
+		d.DurationMs, d.DeliveredAt)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID int64, status string, limit, offset int) ([]models.WebhookDelivery, int, error) {
+	where := " WHERE subscription_id = $1"
+	args := []interface{}{subscriptionID}
+	argPos := 2
+
+	if status != "" {
+		where += fmt.Sprintf(" AND status = $%d", argPos)
+		args = append(args, status)
+		argPos++
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM webhook_deliveries"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	query := `
+		SELECT id, subscription_id, event_id::text, event_type, payload, status, attempts, next_attempt_at,
+		       response_status, COALESCE(response_body, ''), COALESCE(error_message, ''), duration_ms,
+		       created_at, delivered_at
+		FROM webhook_deliveries` + where +
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := rows.Scan(
+			&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &d.ResponseStatus, &d.ResponseBody, &d.ErrorMessage, &d.DurationMs,
+			&d.CreatedAt, &d.DeliveredAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, total, rows.Err()
+}
+
+// DeleteDeliveriesBefore removes finished deliveries older than cutoff
+func (r *WebhookRepository) DeleteDeliveriesBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM webhook_deliveries
+		WHERE created_at < $1 AND status IN ('succeeded', 'failed', 'cancelled')
+	`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old webhook deliveries: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
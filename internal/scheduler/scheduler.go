@@ -2,18 +2,24 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jeffrey/intellinieuws/internal/analytics"
 	"github.com/jeffrey/intellinieuws/internal/scraper"
+	"github.com/jeffrey/intellinieuws/internal/tracing"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 )
 
+// analyticsRefreshCheckInterval is how often the scheduler asks the refresh coordinator
+// to refresh the views whose own interval has passed
+const analyticsRefreshCheckInterval = time.Minute
+
 // Scheduler manages periodic scraping tasks and analytics refresh
 type Scheduler struct {
 	scraperService         *scraper.Service
-	db                     *pgxpool.Pool
+	refresher              *analytics.RefreshCoordinator
 	logger                 *logger.Logger
 	interval               time.Duration
 	analyticsRefreshTicker *time.Ticker
@@ -27,13 +33,13 @@ type Scheduler struct {
 // NewScheduler creates a new scheduler
 func NewScheduler(
 	scraperService *scraper.Service,
-	db *pgxpool.Pool,
+	refresher *analytics.RefreshCoordinator,
 	interval time.Duration,
 	log *logger.Logger,
 ) *Scheduler {
 	return &Scheduler{
 		scraperService: scraperService,
-		db:             db,
+		refresher:      refresher,
 		logger:         log.WithComponent("scheduler"),
 		interval:       interval,
 		stopChan:       make(chan struct{}),
@@ -50,13 +56,14 @@ func (s *Scheduler) Start(ctx context.Context) {
 	}
 	s.running = true
 	s.ticker = time.NewTicker(s.interval)
+	s.stopChan = make(chan struct{})
 	s.mu.Unlock()
 
 	s.logger.Infof("Starting scheduler with interval: %v", s.interval)
 
-	// Start analytics refresh ticker (every 15 minutes)
-	if s.db != nil {
-		s.analyticsRefreshTicker = time.NewTicker(15 * time.Minute)
+	// Start analytics refresh ticker; each view is refreshed on its own interval
+	if s.refresher != nil {
+		s.analyticsRefreshTicker = time.NewTicker(analyticsRefreshCheckInterval)
 		s.wg.Add(1)
 		go func() {
 			defer s.wg.Done()
@@ -76,12 +83,12 @@ func (s *Scheduler) Start(ctx context.Context) {
 		}()
 	}
 
-	// Run initial scrape
-	go s.runScrape(ctx)
-
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
+		// Run initial scrape
+		s.runScrape(ctx)
+
 		for {
 			select {
 			case <-s.ticker.C:
@@ -102,7 +109,9 @@ func (s *Scheduler) runScrape(ctx context.Context) {
 	s.logger.Info("Running scheduled scrape")
 	startTime := time.Now()
 
+	ctx, span := tracing.Start(ctx, "scheduler.scrape")
 	results, err := s.scraperService.ScrapeAllSources(ctx)
+	tracing.End(span, err)
 	if err != nil {
 		s.logger.WithError(err).Error("Scheduled scrape failed")
 		return
@@ -125,43 +134,42 @@ func (s *Scheduler) runScrape(ctx context.Context) {
 		totalStored, totalSkipped, duration)
 }
 
-// refreshAnalytics refreshes materialized views
+// refreshAnalytics refreshes the materialized views that are due
 func (s *Scheduler) refreshAnalytics(ctx context.Context) {
-	if s.db == nil {
+	if s.refresher == nil {
 		return
 	}
 
-	s.logger.Info("Refreshing analytics materialized views...")
 	startTime := time.Now()
 
-	query := `SELECT * FROM refresh_analytics_views(TRUE)`
-	rows, err := s.db.Query(ctx, query)
+	ctx, span := tracing.Start(ctx, "scheduler.refresh_analytics")
+	results, err := s.refresher.RefreshDue(ctx)
+	if errors.Is(err, analytics.ErrRefreshInProgress) {
+		tracing.End(span, nil)
+		s.logger.Debug("Analytics refresh skipped, another instance is refreshing")
+		return
+	}
+	tracing.End(span, err)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to refresh analytics views")
 		return
 	}
-	defer rows.Close()
+	if len(results) == 0 {
+		return
+	}
 
 	totalRows := int64(0)
-	viewCount := 0
-	for rows.Next() {
-		var viewName string
-		var refreshTimeMs int
-		var rowsAffected int64
-
-		if err := rows.Scan(&viewName, &refreshTimeMs, &rowsAffected); err != nil {
-			s.logger.WithError(err).Warn("Failed to scan refresh result")
-			continue
+	failed := 0
+	for _, result := range results {
+		totalRows += result.RowsAffected
+		if result.Error != "" {
+			failed++
 		}
-
-		totalRows += rowsAffected
-		viewCount++
-		s.logger.Debugf("Refreshed %s: %d rows in %dms", viewName, rowsAffected, refreshTimeMs)
 	}
 
 	duration := time.Since(startTime)
-	s.logger.Infof("Analytics refresh completed: %d views, %d rows, duration=%v",
-		viewCount, totalRows, duration)
+	s.logger.Infof("Analytics refresh completed: %d views, %d failed, %d rows, duration=%v",
+		len(results), failed, totalRows, duration)
 }
 
 // Stop stops the scheduler
@@ -166,6 +166,13 @@ func (p *BrowserPool) GetStats() map[string]interface{} {
 	}
 }
 
+// Utilization returns the pool size and the browsers currently acquired; ok is false once closed
+func (p *BrowserPool) Utilization() (size, inUse int, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.size, p.size - len(p.available), !p.closed
+}
+
 // IsAvailable checks if pool has available browsers
 func (p *BrowserPool) IsAvailable() bool {
 	p.mu.Lock()
@@ -39,6 +39,7 @@ func (p *ContentProcessor) Start(ctx context.Context) error {
 		return nil
 	}
 	p.isRunning = true
+	p.stopChan = make(chan struct{})
 	p.mu.Unlock()
 
 	if !p.enabled {
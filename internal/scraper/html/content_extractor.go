@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/jeffrey/intellinieuws/internal/tracing"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 	"github.com/jeffrey/intellinieuws/pkg/utils"
 	"github.com/microcosm-cc/bluemonday"
@@ -38,11 +39,11 @@ func NewContentExtractor(userAgent string, log *logger.Logger) *ContentExtractor
 	return &ContentExtractor{
 		client: &http.Client{
 			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
+			Transport: tracing.Transport(&http.Transport{
 				MaxIdleConns:        100,
 				MaxIdleConnsPerHost: 10,
 				IdleConnTimeout:     90 * time.Second,
-			},
+			}),
 		},
 		sanitizer:        bluemonday.StrictPolicy(), // Only text, no HTML
 		logger:           log.WithComponent("html-extractor"),
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"html"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/tracing"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 	"github.com/jeffrey/intellinieuws/pkg/utils"
 	"github.com/mmcdole/gofeed"
@@ -25,6 +27,7 @@ type Scraper struct {
 func NewScraper(userAgent string, log *logger.Logger) *Scraper {
 	parser := gofeed.NewParser()
 	parser.UserAgent = userAgent
+	parser.Client = &http.Client{Transport: tracing.Transport(nil)}
 
 	return &Scraper{
 		parser:        parser,
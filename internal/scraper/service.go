@@ -9,14 +9,17 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jeffrey/intellinieuws/internal/metrics"
 	"github.com/jeffrey/intellinieuws/internal/models"
 	"github.com/jeffrey/intellinieuws/internal/repository"
 	"github.com/jeffrey/intellinieuws/internal/scraper/browser"
 	"github.com/jeffrey/intellinieuws/internal/scraper/html"
 	"github.com/jeffrey/intellinieuws/internal/scraper/rss"
+	"github.com/jeffrey/intellinieuws/internal/tracing"
 	"github.com/jeffrey/intellinieuws/pkg/config"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 	"github.com/jeffrey/intellinieuws/pkg/utils"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Service manages all scraping operations
@@ -32,6 +35,23 @@ type Service struct {
 	logger           *logger.Logger
 	config           *config.ScraperConfig
 	circuitBreaker   *utils.CircuitBreakerManager // PHASE 4: Resilience
+	publishers       []ArticlePublisher           // Optional live stream and webhook publishers
+}
+
+// ArticlePublisher interface for optional publishing of newly stored articles
+type ArticlePublisher interface {
+	PublishArticles(ctx context.Context, articles []models.Article)
+}
+
+// ScrapeFailurePublisher is optionally implemented by publishers that want failed scrapes
+type ScrapeFailurePublisher interface {
+	PublishScrapeFailure(ctx context.Context, failure models.ScrapeFailureEvent)
+}
+
+// AddPublisher adds a publisher notified of newly stored articles
+// (and of failed scrapes when it implements ScrapeFailurePublisher)
+func (s *Service) AddPublisher(publisher ArticlePublisher) {
+	s.publishers = append(s.publishers, publisher)
 }
 
 // NewService creates a new scraper service
@@ -92,47 +112,119 @@ var ScrapeSources = map[string]string{
 	"nos.nl": "https://feeds.nos.nl/nosnieuwsalgemeen",
 }
 
-// ScrapeSource scrapes a single news source with comprehensive error handling
+// ScrapeSource scrapes a single news source with comprehensive error handling.
+// Failed and partially failed scrapes are reported to failure publishers.
 func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL string) (*ScrapingResult, error) {
-	s.logger.Infof("Starting scrape for source: %s", source)
+	startTime := time.Now()
+	ctx, span := tracing.Start(ctx, "scraper.scrape_source", attribute.String("scraper.source", source))
+	result, err := s.scrapeSource(ctx, source, feedURL)
+	if result != nil {
+		span.SetAttributes(
+			attribute.String("scraper.status", result.Status),
+			attribute.Int("scraper.articles_stored", result.ArticlesStored),
+		)
+	}
+	tracing.End(span, err)
+	observeScrape(source, result, err, time.Since(startTime))
+	if err != nil || (result != nil && result.Status == StatusPartialSuccess) {
+		s.publishScrapeFailure(ctx, source, result, err)
+	}
+	return result, err
+}
+
+// observeScrape records the outcome of a scrape job in the Prometheus metrics
+func observeScrape(source string, result *ScrapingResult, err error, duration time.Duration) {
+	status := StatusFailed
+	var errorCode string
+	var found, stored, skipped int
+	if result != nil {
+		errorCode = result.ErrorCode
+		found, stored, skipped = result.ArticlesFound, result.ArticlesStored, result.ArticlesSkipped
+		if err == nil {
+			switch result.Status {
+			case models.JobStatusCompleted:
+				status = StatusSuccess
+			case StatusPartialSuccess:
+				status = StatusPartialSuccess
+			}
+		}
+	}
+	metrics.ObserveScrape(source, status, errorCode, duration, found, stored, skipped)
+}
+
+// publishScrapeFailure notifies publishers interested in failed scrapes
+func (s *Service) publishScrapeFailure(ctx context.Context, source string, result *ScrapingResult, scrapeErr error) {
+	failure := models.ScrapeFailureEvent{
+		Source: source,
+		Status: StatusFailed,
+	}
+	if result != nil {
+		if result.Status == StatusPartialSuccess {
+			failure.Status = StatusPartialSuccess
+		}
+		failure.Error = result.Error
+		failure.ArticlesFound = result.ArticlesFound
+		failure.StartedAt = result.StartTime
+		failure.DurationMs = time.Since(result.StartTime).Milliseconds()
+	}
+	if scrapeErr != nil {
+		failure.Error = scrapeErr.Error()
+	}
+
+	for _, publisher := range s.publishers {
+		if fp, ok := publisher.(ScrapeFailurePublisher); ok {
+			fp.PublishScrapeFailure(ctx, failure)
+		}
+	}
+}
+
+func (s *Service) scrapeSource(ctx context.Context, source string, feedURL string) (result *ScrapingResult, err error) {
+	// The job UUID is logged with everything the scrape does and stored on its articles,
+	// so the AI enrichment of those articles can be traced back to this job
+	jobUUID := uuid.New().String()
+	log := logger.FromContext(ctx, s.logger).WithJobID(jobUUID)
+	ctx = logger.NewContext(ctx, log)
+
+	log.Infof("Starting scrape for source: %s", source)
 	startTime := time.Now()
 
-	result := &ScrapingResult{
+	result = &ScrapingResult{
 		Source:    source,
 		StartTime: startTime,
 		Status:    models.JobStatusRunning,
 	}
 
 	// Create job record with UUID and method
-	jobUUID := uuid.New().String()
 	scrapingMethod := models.ScrapingMethodRSS // Default to RSS
 
-	jobID, err := s.jobRepo.CreateJobWithDetails(ctx, source, jobUUID, scrapingMethod)
-	if err != nil {
-		s.logger.WithError(err).Warn("Failed to create job record, continuing anyway")
+	jobID, jobErr := s.jobRepo.CreateJobWithDetails(ctx, source, jobUUID, scrapingMethod)
+	if jobErr != nil {
+		log.WithError(jobErr).Warn("Failed to create job record, continuing anyway")
 		jobID = 0 // Continue without job tracking
 	}
 
 	// Mark job as started
 	if jobID > 0 {
 		if err := s.jobRepo.StartJob(ctx, jobID); err != nil {
-			s.logger.WithError(err).Warn("Failed to start job record")
+			log.WithError(err).Warn("Failed to start job record")
 		}
 	}
 
 	// Defer panic recovery and job completion
 	defer func() {
 		if r := recover(); r != nil {
-			s.logger.Errorf("Panic recovered in scrape for %s: %v", source, r)
+			log.Errorf("Panic recovered in scrape for %s: %v", source, r)
 			result.Error = fmt.Sprintf("panic: %v", r)
+			result.ErrorCode = "PANIC_RECOVERED"
 			result.Status = models.JobStatusFailed
 			result.EndTime = time.Now()
+			err = fmt.Errorf("panic while scraping %s: %v", source, r)
 
 			// Mark job as failed with error code
 			if jobID > 0 {
 				executionMs := int(time.Since(startTime).Milliseconds())
-				if err := s.jobRepo.FailJobWithDetails(ctx, jobID, result.Error, "PANIC_RECOVERED", executionMs); err != nil {
-					s.logger.WithError(err).Warn("Failed to mark job as failed")
+				if err := s.jobRepo.FailJobWithDetails(ctx, jobID, result.Error, result.ErrorCode, executionMs); err != nil {
+					log.WithError(err).Warn("Failed to mark job as failed")
 				}
 			}
 		}
@@ -141,14 +233,15 @@ func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL strin
 	// Check context cancellation
 	if ctx.Err() != nil {
 		result.Error = "context cancelled"
+		result.ErrorCode = "CONTEXT_CANCELLED"
 		result.Status = models.JobStatusFailed
 		result.EndTime = time.Now()
 
 		// Mark job as failed with error code
 		if jobID > 0 {
 			executionMs := int(time.Since(startTime).Milliseconds())
-			if err := s.jobRepo.FailJobWithDetails(ctx, jobID, result.Error, "CONTEXT_CANCELLED", executionMs); err != nil {
-				s.logger.WithError(err).Warn("Failed to mark job as failed")
+			if err := s.jobRepo.FailJobWithDetails(ctx, jobID, result.Error, result.ErrorCode, executionMs); err != nil {
+				log.WithError(err).Warn("Failed to mark job as failed")
 			}
 		}
 		return result, ctx.Err()
@@ -158,19 +251,20 @@ func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL strin
 	if s.config.EnableRobotsTxtCheck {
 		allowed, err := s.robotsChecker.IsAllowed(feedURL)
 		if err != nil {
-			s.logger.WithError(err).Warnf("Error checking robots.txt for %s, continuing anyway", source)
+			log.WithError(err).Warnf("Error checking robots.txt for %s, continuing anyway", source)
 			// Continue scraping even if robots.txt check fails
 		} else if !allowed {
 			result.Error = "robots.txt disallows scraping"
+			result.ErrorCode = "ROBOTS_TXT_DISALLOW"
 			result.Status = models.JobStatusFailed
 			result.EndTime = time.Now()
-			s.logger.Warnf("Robots.txt disallows scraping of %s", source)
+			log.Warnf("Robots.txt disallows scraping of %s", source)
 
 			// Mark job as failed with error code
 			if jobID > 0 {
 				executionMs := int(time.Since(startTime).Milliseconds())
-				if err := s.jobRepo.FailJobWithDetails(ctx, jobID, result.Error, "ROBOTS_TXT_DISALLOW", executionMs); err != nil {
-					s.logger.WithError(err).Warn("Failed to mark job as failed")
+				if err := s.jobRepo.FailJobWithDetails(ctx, jobID, result.Error, result.ErrorCode, executionMs); err != nil {
+					log.WithError(err).Warn("Failed to mark job as failed")
 				}
 			}
 			return result, fmt.Errorf("robots.txt disallows scraping of %s", feedURL)
@@ -181,6 +275,7 @@ func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL strin
 	domain, err := utils.GetDomain(feedURL)
 	if err != nil {
 		result.Error = fmt.Sprintf("invalid URL: %v", err)
+		result.ErrorCode = "INVALID_URL"
 		result.Status = models.JobStatusFailed
 		result.EndTime = time.Now()
 		return result, fmt.Errorf("invalid URL for %s: %w", source, err)
@@ -191,14 +286,15 @@ func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL strin
 
 	if err := s.rateLimiter.Wait(rateLimitCtx, domain); err != nil {
 		result.Error = fmt.Sprintf("rate limit error: %v", err)
+		result.ErrorCode = "RATE_LIMIT_ERROR"
 		result.Status = models.JobStatusFailed
 		result.EndTime = time.Now()
 
 		// Mark job as failed with error code
 		if jobID > 0 {
 			executionMs := int(time.Since(startTime).Milliseconds())
-			if err := s.jobRepo.FailJobWithDetails(ctx, jobID, result.Error, "RATE_LIMIT_ERROR", executionMs); err != nil {
-				s.logger.WithError(err).Warn("Failed to mark job as failed")
+			if err := s.jobRepo.FailJobWithDetails(ctx, jobID, result.Error, result.ErrorCode, executionMs); err != nil {
+				log.WithError(err).Warn("Failed to mark job as failed")
 			}
 		}
 		return result, fmt.Errorf("rate limit error for %s: %w", source, err)
@@ -219,11 +315,11 @@ func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL strin
 	})
 
 	if err != nil {
-		errorCode := "SCRAPING_FAILED"
+		result.ErrorCode = "SCRAPING_FAILED"
 		if cb.IsOpen() {
 			result.Error = fmt.Sprintf("circuit breaker open (too many failures)")
-			errorCode = "CIRCUIT_BREAKER_OPEN"
-			s.logger.Warnf("Circuit breaker OPEN for %s - blocking requests", source)
+			result.ErrorCode = "CIRCUIT_BREAKER_OPEN"
+			log.Warnf("Circuit breaker OPEN for %s - blocking requests", source)
 		} else {
 			result.Error = fmt.Sprintf("scraping failed: %v", err)
 		}
@@ -233,21 +329,21 @@ func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL strin
 		// Mark job as failed with error code
 		if jobID > 0 {
 			executionMs := int(time.Since(startTime).Milliseconds())
-			if err := s.jobRepo.FailJobWithDetails(ctx, jobID, result.Error, errorCode, executionMs); err != nil {
-				s.logger.WithError(err).Warn("Failed to mark job as failed")
+			if err := s.jobRepo.FailJobWithDetails(ctx, jobID, result.Error, result.ErrorCode, executionMs); err != nil {
+				log.WithError(err).Warn("Failed to mark job as failed")
 			}
 			// Update source metadata on scraping failure
 			if err := s.jobRepo.UpdateSourceError(ctx, source, result.Error); err != nil {
-				s.logger.WithError(err).Warn("Failed to update source error")
+				log.WithError(err).Warn("Failed to update source error")
 			}
 		}
 		return result, fmt.Errorf("scraping failed for %s: %w", source, err)
 	}
 
-	s.logger.Infof("Found %d articles from %s", len(articles), source)
+	log.Infof("Found %d articles from %s", len(articles), source)
 
 	if len(articles) == 0 {
-		s.logger.Warnf("No articles found for %s", source)
+		log.Warnf("No articles found for %s", source)
 		result.Status = models.JobStatusCompleted
 		result.EndTime = time.Now()
 		result.Duration = time.Since(startTime)
@@ -256,11 +352,11 @@ func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL strin
 		if jobID > 0 {
 			executionMs := int(time.Since(startTime).Milliseconds())
 			if err := s.jobRepo.CompleteJobWithDetails(ctx, jobID, 0, 0, 0, 0, executionMs); err != nil {
-				s.logger.WithError(err).Warn("Failed to complete job record")
+				log.WithError(err).Warn("Failed to complete job record")
 			}
 			// Update source metadata even when no articles found (still a success)
 			if err := s.jobRepo.UpdateSourceMetadata(ctx, source, 0, true); err != nil {
-				s.logger.WithError(err).Warn("Failed to update source metadata")
+				log.WithError(err).Warn("Failed to update source metadata")
 			}
 		}
 		return result, nil
@@ -285,24 +381,24 @@ func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL strin
 		var err error
 		existsMap, err = s.articleRepo.ExistsByURLBatch(ctx, urls)
 		if err != nil {
-			s.logger.WithError(err).Warn("Batch duplicate check failed, continuing with all articles...")
+			log.WithError(err).Warn("Batch duplicate check failed, continuing with all articles...")
 			// Create empty map so we don't skip any articles on error
 			existsMap = make(map[string]bool)
 		}
-		s.logger.Debugf("Batch duplicate check completed for %d URLs", len(urls))
+		log.Debugf("Batch duplicate check completed for %d URLs", len(urls))
 	}
 
 	// Filter articles based on batch duplicate check results
 	for _, article := range articles {
 		// Check context
 		if ctx.Err() != nil {
-			s.logger.Warn("Context cancelled during article filtering")
+			log.Warn("Context cancelled during article filtering")
 			break
 		}
 
 		// Validate article data
 		if article.URL == "" {
-			s.logger.Warnf("Skipping article with empty URL: %s", article.Title)
+			log.Warnf("Skipping article with empty URL: %s", article.Title)
 			skipped++
 			continue
 		}
@@ -313,6 +409,7 @@ func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL strin
 			continue
 		}
 
+		article.ScrapeJobID = jobUUID
 		validArticles = append(validArticles, article)
 	}
 
@@ -324,12 +421,19 @@ func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL strin
 		storeCtx, storeCancel := context.WithTimeout(ctx, 30*time.Second)
 		defer storeCancel()
 
-		inserted, err := s.articleRepo.CreateBatch(storeCtx, validArticles)
+		insertedArticles, err := s.articleRepo.CreateBatchReturning(storeCtx, validArticles)
+		inserted := len(insertedArticles)
 		stored = inserted
 
+		if inserted > 0 {
+			for _, publisher := range s.publishers {
+				publisher.PublishArticles(ctx, insertedArticles)
+			}
+		}
+
 		if err != nil {
 			errMsg := fmt.Sprintf("Batch insert error: %v", err)
-			s.logger.Error(errMsg)
+			log.Error(errMsg)
 			storageErrors = append(storageErrors, errMsg)
 		}
 
@@ -348,6 +452,7 @@ func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL strin
 
 	if len(storageErrors) > 0 {
 		result.Error = fmt.Sprintf("%d storage errors occurred", len(storageErrors))
+		result.ErrorCode = "STORAGE_ERROR"
 		result.Status = StatusPartialSuccess
 	}
 
@@ -357,24 +462,24 @@ func (s *Service) ScrapeSource(ctx context.Context, source string, feedURL strin
 		if result.Status == models.JobStatusCompleted {
 			if err := s.jobRepo.CompleteJobWithDetails(ctx, jobID,
 				len(articles), stored, 0, skipped, executionMs); err != nil {
-				s.logger.WithError(err).Warn("Failed to complete job record")
+				log.WithError(err).Warn("Failed to complete job record")
 			}
 			// Update source metadata on success
 			if err := s.jobRepo.UpdateSourceMetadata(ctx, source, stored, true); err != nil {
-				s.logger.WithError(err).Warn("Failed to update source metadata")
+				log.WithError(err).Warn("Failed to update source metadata")
 			}
 		} else {
-			if err := s.jobRepo.FailJobWithDetails(ctx, jobID, result.Error, "STORAGE_ERROR", executionMs); err != nil {
-				s.logger.WithError(err).Warn("Failed to mark job as failed")
+			if err := s.jobRepo.FailJobWithDetails(ctx, jobID, result.Error, result.ErrorCode, executionMs); err != nil {
+				log.WithError(err).Warn("Failed to mark job as failed")
 			}
 			// Update source metadata on failure
 			if err := s.jobRepo.UpdateSourceError(ctx, source, result.Error); err != nil {
-				s.logger.WithError(err).Warn("Failed to update source error")
+				log.WithError(err).Warn("Failed to update source error")
 			}
 		}
 	}
 
-	s.logger.Infof("Completed scrape for %s: stored=%d, skipped=%d, errors=%d, duration=%v",
+	log.Infof("Completed scrape for %s: stored=%d, skipped=%d, errors=%d, duration=%v",
 		source, stored, skipped, len(storageErrors), result.Duration)
 
 	return result, nil
@@ -515,6 +620,7 @@ type ScrapingResult struct {
 	ArticlesStored  int
 	ArticlesSkipped int
 	Error           string
+	ErrorCode       string // Matches the error code recorded on the job; empty on success
 }
 
 // Success statuses
@@ -692,6 +798,20 @@ func (s *Service) getBrowserPoolStats() map[string]interface{} {
 	return stats
 }
 
+// BrowserPoolUtilization returns the pool size and browsers in use; ok is false when
+// browser scraping is disabled or the pool is closed
+func (s *Service) BrowserPoolUtilization() (size, inUse int, ok bool) {
+	if s.browserPool == nil {
+		return 0, 0, false
+	}
+	return s.browserPool.Utilization()
+}
+
+// CircuitBreakers returns the per-source circuit breakers
+func (s *Service) CircuitBreakers() *utils.CircuitBreakerManager {
+	return s.circuitBreaker
+}
+
 // Cleanup closes browser pool and other resources
 func (s *Service) Cleanup() {
 	if s.browserPool != nil {
@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/jeffrey/intellinieuws/internal/metrics"
+	"github.com/jeffrey/intellinieuws/internal/tracing"
 	"github.com/jeffrey/intellinieuws/pkg/logger"
 	"github.com/redis/go-redis/v9"
 )
@@ -52,7 +55,8 @@ func NewService(cfg *Config, redisClient *redis.Client, log *logger.Logger) *Ser
 	return &Service{
 		config: cfg,
 		httpClient: &http.Client{
-			Timeout: cfg.Timeout,
+			Timeout:   cfg.Timeout,
+			Transport: tracing.Transport(nil),
 		},
 		redis:       redisClient,
 		logger:      log.WithComponent("stock-service"),
@@ -67,13 +71,10 @@ func (s *Service) GetQuote(ctx context.Context, symbol string) (*StockQuote, err
 	// Check cache first
 	if s.config.EnableCache && s.redis != nil {
 		cacheKey := cachePrefix + cacheQuote + symbol
-		cached, err := s.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var quote StockQuote
-			if err := json.Unmarshal([]byte(cached), &quote); err == nil {
-				s.logger.Debugf("Cache HIT for stock quote: %s", symbol)
-				return &quote, nil
-			}
+		var quote StockQuote
+		if s.getCached(ctx, cacheKey, &quote) {
+			s.logger.Debugf("Cache HIT for stock quote: %s", symbol)
+			return &quote, nil
 		}
 	}
 
@@ -115,13 +116,10 @@ func (s *Service) GetProfile(ctx context.Context, symbol string) (*StockProfile,
 	// Check cache first
 	if s.config.EnableCache && s.redis != nil {
 		cacheKey := cachePrefix + cacheProfile + symbol
-		cached, err := s.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var profile StockProfile
-			if err := json.Unmarshal([]byte(cached), &profile); err == nil {
-				s.logger.Debugf("Cache HIT for stock profile: %s", symbol)
-				return &profile, nil
-			}
+		var profile StockProfile
+		if s.getCached(ctx, cacheKey, &profile) {
+			s.logger.Debugf("Cache HIT for stock profile: %s", symbol)
+			return &profile, nil
 		}
 	}
 
@@ -192,13 +190,10 @@ func (s *Service) GetMultipleQuotes(ctx context.Context, symbols []string) (map[
 	if s.config.EnableCache && s.redis != nil {
 		for symbol := range uniqueSymbols {
 			cacheKey := cachePrefix + cacheQuote + symbol
-			cached, err := s.redis.Get(ctx, cacheKey).Result()
-			if err == nil {
-				var quote StockQuote
-				if err := json.Unmarshal([]byte(cached), &quote); err == nil {
-					results[symbol] = &quote
-					continue
-				}
+			var quote StockQuote
+			if s.getCached(ctx, cacheKey, &quote) {
+				results[symbol] = &quote
+				continue
 			}
 			uncachedSymbols = append(uncachedSymbols, symbol)
 		}
@@ -276,7 +271,7 @@ func (s *Service) fetchQuotesBatchFMP(ctx context.Context, symbols []string) (ma
 		return nil, fmt.Errorf("failed to create batch request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "quote_batch")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch batch quotes: %w", err)
 	}
@@ -383,7 +378,7 @@ func (s *Service) fetchQuoteFMP(ctx context.Context, symbol string) (*StockQuote
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "quote")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch quote: %w", err)
 	}
@@ -440,7 +435,7 @@ func (s *Service) fetchProfileFMP(ctx context.Context, symbol string) (*StockPro
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "profile")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch profile: %w", err)
 	}
@@ -487,7 +482,7 @@ func (s *Service) fetchQuoteAlphaVantage(ctx context.Context, symbol string) (*S
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "quote")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch quote: %w", err)
 	}
@@ -524,6 +519,45 @@ func (s *Service) fetchQuoteAlphaVantage(ctx context.Context, symbol string) (*S
 	return quote, nil
 }
 
+// do sends a provider request, recording its latency and status code in the metrics
+func (s *Service) do(req *http.Request, endpoint string) (*http.Response, error) {
+	provider := "fmp"
+	if strings.Contains(req.URL.Host, "alphavantage") {
+		provider = "alphavantage"
+	}
+
+	start := time.Now()
+	resp, err := s.httpClient.Do(req)
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.ObserveStockRequest(provider, endpoint, status, time.Since(start))
+	return resp, err
+}
+
+// getCached decodes a cached value into dest and reports whether it was found. The lookup
+// is recorded in the cache metrics under the key's data type, e.g. stock:quote.
+func (s *Service) getCached(ctx context.Context, key string, dest interface{}) bool {
+	prefix := strings.TrimPrefix(key, cachePrefix)
+	if i := strings.IndexByte(prefix, ':'); i >= 0 {
+		prefix = prefix[:i]
+	}
+	prefix = cachePrefix + prefix
+
+	cached, err := s.redis.Get(ctx, key).Result()
+	switch {
+	case err == redis.Nil:
+		metrics.ObserveCacheLookup(prefix, metrics.CacheMiss)
+		return false
+	case err != nil, json.Unmarshal([]byte(cached), dest) != nil:
+		metrics.ObserveCacheLookup(prefix, metrics.CacheError)
+		return false
+	}
+	metrics.ObserveCacheLookup(prefix, metrics.CacheHit)
+	return true
+}
+
 // Close stops the rate limiter
 func (s *Service) Close() {
 	if s.rateLimiter != nil {
@@ -560,13 +594,10 @@ func (s *Service) GetStockNews(ctx context.Context, symbol string, limit int) ([
 	// Check cache first
 	cacheKey := cachePrefix + "news:" + symbol
 	if s.config.EnableCache && s.redis != nil {
-		cached, err := s.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var news []StockNews
-			if err := json.Unmarshal([]byte(cached), &news); err == nil {
-				s.logger.Debugf("Cache HIT for stock news: %s", symbol)
-				return news, nil
-			}
+		var news []StockNews
+		if s.getCached(ctx, cacheKey, &news) {
+			s.logger.Debugf("Cache HIT for stock news: %s", symbol)
+			return news, nil
 		}
 	}
 
@@ -583,7 +614,7 @@ func (s *Service) GetStockNews(ctx context.Context, symbol string, limit int) ([
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "news")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch news: %w", err)
 	}
@@ -635,13 +666,10 @@ func (s *Service) GetHistoricalPrices(ctx context.Context, symbol string, from,
 	// Check cache
 	cacheKey := fmt.Sprintf("%shistorical:%s:%s:%s", cachePrefix, symbol, fromStr, toStr)
 	if s.config.EnableCache && s.redis != nil {
-		cached, err := s.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var prices []HistoricalPrice
-			if err := json.Unmarshal([]byte(cached), &prices); err == nil {
-				s.logger.Debugf("Cache HIT for historical prices: %s", symbol)
-				return prices, nil
-			}
+		var prices []HistoricalPrice
+		if s.getCached(ctx, cacheKey, &prices) {
+			s.logger.Debugf("Cache HIT for historical prices: %s", symbol)
+			return prices, nil
 		}
 	}
 
@@ -659,7 +687,7 @@ func (s *Service) GetHistoricalPrices(ctx context.Context, symbol string, from,
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "historical")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch historical prices: %w", err)
 	}
@@ -709,13 +737,10 @@ func (s *Service) GetKeyMetrics(ctx context.Context, symbol string) (*KeyMetrics
 	// Check cache (longer TTL for metrics)
 	cacheKey := cachePrefix + "metrics:" + symbol
 	if s.config.EnableCache && s.redis != nil {
-		cached, err := s.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var metrics KeyMetrics
-			if err := json.Unmarshal([]byte(cached), &metrics); err == nil {
-				s.logger.Debugf("Cache HIT for key metrics: %s", symbol)
-				return &metrics, nil
-			}
+		var metrics KeyMetrics
+		if s.getCached(ctx, cacheKey, &metrics) {
+			s.logger.Debugf("Cache HIT for key metrics: %s", symbol)
+			return &metrics, nil
 		}
 	}
 
@@ -732,7 +757,7 @@ func (s *Service) GetKeyMetrics(ctx context.Context, symbol string) (*KeyMetrics
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "key_metrics")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch key metrics: %w", err)
 	}
@@ -789,13 +814,10 @@ func (s *Service) GetEarningsCalendar(ctx context.Context, from, to time.Time) (
 	// Check cache
 	cacheKey := fmt.Sprintf("%searnings:%s:%s", cachePrefix, fromStr, toStr)
 	if s.config.EnableCache && s.redis != nil {
-		cached, err := s.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var calendar []EarningsCalendar
-			if err := json.Unmarshal([]byte(cached), &calendar); err == nil {
-				s.logger.Debugf("Cache HIT for earnings calendar")
-				return calendar, nil
-			}
+		var calendar []EarningsCalendar
+		if s.getCached(ctx, cacheKey, &calendar) {
+			s.logger.Debugf("Cache HIT for earnings calendar")
+			return calendar, nil
 		}
 	}
 
@@ -813,7 +835,7 @@ func (s *Service) GetEarningsCalendar(ctx context.Context, from, to time.Time) (
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "earnings_calendar")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch earnings calendar: %w", err)
 	}
@@ -874,7 +896,7 @@ func (s *Service) SearchSymbol(ctx context.Context, query string, limit int) ([]
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "search")
 	if err != nil {
 		return nil, fmt.Errorf("failed to search symbols: %w", err)
 	}
@@ -916,13 +938,10 @@ func (s *Service) GetMarketGainers(ctx context.Context) ([]MarketMover, error) {
 	// Check cache (5 min TTL for market data)
 	cacheKey := cachePrefix + "gainers"
 	if s.config.EnableCache && s.redis != nil {
-		cached, err := s.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var gainers []MarketMover
-			if err := json.Unmarshal([]byte(cached), &gainers); err == nil {
-				s.logger.Debug("Cache HIT for market gainers")
-				return gainers, nil
-			}
+		var gainers []MarketMover
+		if s.getCached(ctx, cacheKey, &gainers) {
+			s.logger.Debug("Cache HIT for market gainers")
+			return gainers, nil
 		}
 	}
 
@@ -938,7 +957,7 @@ func (s *Service) GetMarketGainers(ctx context.Context) ([]MarketMover, error) {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "gainers")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch gainers: %w", err)
 	}
@@ -987,13 +1006,10 @@ func (s *Service) GetMarketGainers(ctx context.Context) ([]MarketMover, error) {
 func (s *Service) GetMarketLosers(ctx context.Context) ([]MarketMover, error) {
 	cacheKey := cachePrefix + "losers"
 	if s.config.EnableCache && s.redis != nil {
-		cached, err := s.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var losers []MarketMover
-			if err := json.Unmarshal([]byte(cached), &losers); err == nil {
-				s.logger.Debug("Cache HIT for market losers")
-				return losers, nil
-			}
+		var losers []MarketMover
+		if s.getCached(ctx, cacheKey, &losers) {
+			s.logger.Debug("Cache HIT for market losers")
+			return losers, nil
 		}
 	}
 
@@ -1008,7 +1024,7 @@ func (s *Service) GetMarketLosers(ctx context.Context) ([]MarketMover, error) {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "losers")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch losers: %w", err)
 	}
@@ -1055,13 +1071,10 @@ func (s *Service) GetMarketLosers(ctx context.Context) ([]MarketMover, error) {
 func (s *Service) GetMostActives(ctx context.Context) ([]MarketMover, error) {
 	cacheKey := cachePrefix + "actives"
 	if s.config.EnableCache && s.redis != nil {
-		cached, err := s.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var actives []MarketMover
-			if err := json.Unmarshal([]byte(cached), &actives); err == nil {
-				s.logger.Debug("Cache HIT for most actives")
-				return actives, nil
-			}
+		var actives []MarketMover
+		if s.getCached(ctx, cacheKey, &actives) {
+			s.logger.Debug("Cache HIT for most actives")
+			return actives, nil
 		}
 	}
 
@@ -1076,7 +1089,7 @@ func (s *Service) GetMostActives(ctx context.Context) ([]MarketMover, error) {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "actives")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch actives: %w", err)
 	}
@@ -1123,13 +1136,10 @@ func (s *Service) GetMostActives(ctx context.Context) ([]MarketMover, error) {
 func (s *Service) GetSectorPerformance(ctx context.Context) ([]SectorPerformance, error) {
 	cacheKey := cachePrefix + "sectors"
 	if s.config.EnableCache && s.redis != nil {
-		cached, err := s.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var sectors []SectorPerformance
-			if err := json.Unmarshal([]byte(cached), &sectors); err == nil {
-				s.logger.Debug("Cache HIT for sector performance")
-				return sectors, nil
-			}
+		var sectors []SectorPerformance
+		if s.getCached(ctx, cacheKey, &sectors) {
+			s.logger.Debug("Cache HIT for sector performance")
+			return sectors, nil
 		}
 	}
 
@@ -1144,7 +1154,7 @@ func (s *Service) GetSectorPerformance(ctx context.Context) ([]SectorPerformance
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "sector_performance")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch sector performance: %w", err)
 	}
@@ -1191,13 +1201,10 @@ func (s *Service) GetAnalystRatings(ctx context.Context, symbol string, limit in
 
 	cacheKey := fmt.Sprintf("%sratings:%s", cachePrefix, symbol)
 	if s.config.EnableCache && s.redis != nil {
-		cached, err := s.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var ratings []AnalystRating
-			if err := json.Unmarshal([]byte(cached), &ratings); err == nil {
-				s.logger.Debugf("Cache HIT for analyst ratings: %s", symbol)
-				return ratings, nil
-			}
+		var ratings []AnalystRating
+		if s.getCached(ctx, cacheKey, &ratings) {
+			s.logger.Debugf("Cache HIT for analyst ratings: %s", symbol)
+			return ratings, nil
 		}
 	}
 
@@ -1212,7 +1219,7 @@ func (s *Service) GetAnalystRatings(ctx context.Context, symbol string, limit in
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "analyst_ratings")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch ratings: %w", err)
 	}
@@ -1259,13 +1266,10 @@ func (s *Service) GetPriceTarget(ctx context.Context, symbol string) (*PriceTarg
 
 	cacheKey := fmt.Sprintf("%starget:%s", cachePrefix, symbol)
 	if s.config.EnableCache && s.redis != nil {
-		cached, err := s.redis.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var target PriceTarget
-			if err := json.Unmarshal([]byte(cached), &target); err == nil {
-				s.logger.Debugf("Cache HIT for price target: %s", symbol)
-				return &target, nil
-			}
+		var target PriceTarget
+		if s.getCached(ctx, cacheKey, &target) {
+			s.logger.Debugf("Cache HIT for price target: %s", symbol)
+			return &target, nil
 		}
 	}
 
@@ -1280,7 +1284,7 @@ func (s *Service) GetPriceTarget(ctx context.Context, symbol string) (*PriceTarg
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := s.httpClient.Do(req)
+	resp, err := s.do(req, "price_target")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch price target: %w", err)
 	}
@@ -0,0 +1,401 @@
+package stock
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// Listing is a single exchange listing in the symbol master
+type Listing struct {
+	Symbol   string   `json:"symbol"`   // Full listing symbol, e.g. "ASML.AS"
+	Name     string   `json:"name"`     // Company name
+	Exchange string   `json:"exchange"` // Canonical exchange code, e.g. "AMS", "NASDAQ"
+	Country  string   `json:"country,omitempty"`
+	Currency string   `json:"currency,omitempty"`
+	Primary  bool     `json:"primary"` // Home listing of the company
+	Aliases  []string `json:"aliases,omitempty"`
+}
+
+// BaseSymbol returns the symbol without exchange suffix ("ASML.AS" -> "ASML")
+func (l Listing) BaseSymbol() string {
+	return baseSymbol(l.Symbol)
+}
+
+// exchangeAliases maps the many ways exchanges are written to a canonical code
+var exchangeAliases = map[string]string{
+	"AEX": "AMS", "AMS": "AMS", "AMSTERDAM": "AMS", "EURONEXT AMSTERDAM": "AMS", "XAMS": "AMS",
+	"EURONEXT": "AMS", "AMX": "AMS", "ASCX": "AMS",
+	"NASDAQ": "NASDAQ", "NMS": "NASDAQ", "NGS": "NASDAQ", "NCM": "NASDAQ", "XNAS": "NASDAQ",
+	"NYSE": "NYSE", "XNYS": "NYSE", "NEW YORK STOCK EXCHANGE": "NYSE", "NYQ": "NYSE",
+	"XETRA": "XETRA", "XETR": "XETRA", "FRANKFURT": "XETRA", "FWB": "XETRA", "GER": "XETRA", "DAX": "XETRA",
+	"PAR": "PAR", "PARIS": "PAR", "EURONEXT PARIS": "PAR", "XPAR": "PAR", "CAC": "PAR",
+	"BRU": "BRU", "BRUSSEL": "BRU", "BRUSSELS": "BRU", "EURONEXT BRUSSELS": "BRU", "XBRU": "BRU", "BEL20": "BRU",
+	"LSE": "LSE", "LONDON": "LSE", "XLON": "LSE", "FTSE": "LSE",
+}
+
+// companySuffixes are legal-form tokens ignored when matching company names
+var companySuffixes = map[string]bool{
+	"nv": true, "bv": true, "se": true, "sa": true, "ag": true, "plc": true, "inc": true,
+	"corp": true, "corporation": true, "co": true, "ltd": true, "llc": true, "holding": true,
+	"holdings": true, "group": true, "groep": true, "the": true, "company": true, "class": true,
+}
+
+// NormalizeExchange maps an exchange name to its canonical code ("AEX" -> "AMS")
+func NormalizeExchange(exchange string) string {
+	key := strings.ToUpper(strings.Join(strings.Fields(exchange), " "))
+	if code, ok := exchangeAliases[key]; ok {
+		return code
+	}
+	return key
+}
+
+// normalizeCompany returns the matching key for a company name
+func normalizeCompany(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '.':
+			// "N.V." -> "nv"
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	tokens := strings.Fields(b.String())
+	kept := tokens[:0]
+	for _, t := range tokens {
+		if !companySuffixes[t] {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) == 0 {
+		return strings.Join(tokens, " ")
+	}
+	return strings.Join(kept, " ")
+}
+
+func baseSymbol(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if i := strings.LastIndexByte(symbol, '.'); i > 0 {
+		return symbol[:i]
+	}
+	return symbol
+}
+
+// SymbolSearcher looks up listings at the quote provider (implemented by Service)
+type SymbolSearcher interface {
+	SearchSymbol(ctx context.Context, query string, limit int) ([]StockProfile, error)
+}
+
+// SymbolMaster is an in-memory index of known listings backed by a local CSV file.
+//
+// CSV columns: symbol,name,exchange,country,currency,primary,aliases (aliases separated by "|")
+//
+// Refreshed listings are saved to path; the checked-in seed file is only read while path
+// does not exist yet, so runtime refreshes never rewrite it.
+type SymbolMaster struct {
+	path     string
+	seedPath string
+	logger   *logger.Logger
+
+	saveMu sync.Mutex // Serializes Save so concurrent refreshes do not interleave writes
+
+	mu        sync.RWMutex
+	listings  map[string]Listing  // full symbol -> listing
+	byBase    map[string][]string // base symbol -> full symbols
+	byCompany map[string][]string // normalized company name/alias -> full symbols
+}
+
+// NewSymbolMaster creates an empty symbol master bound to a file path
+func NewSymbolMaster(path string, log *logger.Logger) *SymbolMaster {
+	return &SymbolMaster{
+		path:      path,
+		logger:    log.WithComponent("symbol-master"),
+		listings:  make(map[string]Listing),
+		byBase:    make(map[string][]string),
+		byCompany: make(map[string][]string),
+	}
+}
+
+// SetSeedPath sets the file loaded while the symbol master file does not exist yet
+func (m *SymbolMaster) SetSeedPath(path string) {
+	m.seedPath = path
+}
+
+// Load reads the symbol master file, or the seed file when it has not been saved yet.
+// A missing file yields an empty master.
+func (m *SymbolMaster) Load() error {
+	path := m.path
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && m.seedPath != "" {
+		path = m.seedPath
+		f, err = os.Open(path)
+	}
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			m.logger.Warnf("Symbol master %s not found, starting empty", m.path)
+			return nil
+		}
+		return fmt.Errorf("failed to open symbol master: %w", err)
+	}
+	defer f.Close()
+
+	listings, err := readListings(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse symbol master %s: %w", path, err)
+	}
+
+	m.mu.Lock()
+	m.listings = make(map[string]Listing, len(listings))
+	m.byBase = make(map[string][]string)
+	m.byCompany = make(map[string][]string)
+	for _, l := range listings {
+		m.addLocked(l)
+	}
+	m.mu.Unlock()
+
+	m.logger.Infof("Loaded symbol master: %d listings from %s", len(listings), path)
+	return nil
+}
+
+func readListings(r io.Reader) ([]Listing, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	listings := make([]Listing, 0, len(records))
+	for i, rec := range records {
+		if i == 0 && strings.EqualFold(rec[0], "symbol") {
+			continue // header
+		}
+		if len(rec) < 3 {
+			return nil, fmt.Errorf("line %d: expected at least symbol,name,exchange", i+1)
+		}
+
+		l := Listing{
+			Symbol:   strings.ToUpper(strings.TrimSpace(rec[0])),
+			Name:     strings.TrimSpace(rec[1]),
+			Exchange: NormalizeExchange(rec[2]),
+		}
+		if len(rec) > 3 {
+			l.Country = strings.ToUpper(strings.TrimSpace(rec[3]))
+		}
+		if len(rec) > 4 {
+			l.Currency = strings.ToUpper(strings.TrimSpace(rec[4]))
+		}
+		if len(rec) > 5 {
+			l.Primary, _ = strconv.ParseBool(strings.TrimSpace(rec[5]))
+		}
+		if len(rec) > 6 && strings.TrimSpace(rec[6]) != "" {
+			for _, alias := range strings.Split(rec[6], "|") {
+				if alias = strings.TrimSpace(alias); alias != "" {
+					l.Aliases = append(l.Aliases, alias)
+				}
+			}
+		}
+		listings = append(listings, l)
+	}
+
+	return listings, nil
+}
+
+// addLocked indexes a listing; caller must hold the write lock
+func (m *SymbolMaster) addLocked(l Listing) {
+	if _, exists := m.listings[l.Symbol]; !exists {
+		base := l.BaseSymbol()
+		m.byBase[base] = append(m.byBase[base], l.Symbol)
+
+		for _, name := range append([]string{l.Name}, l.Aliases...) {
+			key := normalizeCompany(name)
+			if key != "" && !containsString(m.byCompany[key], l.Symbol) {
+				m.byCompany[key] = append(m.byCompany[key], l.Symbol)
+			}
+		}
+	}
+	m.listings[l.Symbol] = l
+}
+
+// Save writes the symbol master to its file through a temporary file in the same
+// directory, so readers never see a partially written master
+func (m *SymbolMaster) Save() error {
+	m.saveMu.Lock()
+	defer m.saveMu.Unlock()
+
+	m.mu.RLock()
+	listings := make([]Listing, 0, len(m.listings))
+	for _, l := range m.listings {
+		listings = append(listings, l)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(listings, func(i, j int) bool {
+		if listings[i].Name != listings[j].Name {
+			return listings[i].Name < listings[j].Name
+		}
+		return listings[i].Symbol < listings[j].Symbol
+	})
+
+	dir := filepath.Dir(m.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to write symbol master: %w", err)
+	}
+	f, err := os.CreateTemp(dir, ".symbol_master-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write symbol master: %w", err)
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp) // No-op after the rename
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"symbol", "name", "exchange", "country", "currency", "primary", "aliases"})
+	for _, l := range listings {
+		w.Write([]string{
+			l.Symbol, l.Name, l.Exchange, l.Country, l.Currency,
+			strconv.FormatBool(l.Primary), strings.Join(l.Aliases, "|"),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write symbol master: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write symbol master: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write symbol master: %w", err)
+	}
+
+	if err := os.Rename(tmp, m.path); err != nil {
+		return fmt.Errorf("failed to write symbol master: %w", err)
+	}
+	return nil
+}
+
+// Count returns the number of known listings
+func (m *SymbolMaster) Count() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.listings)
+}
+
+// Get returns a listing by its full symbol
+func (m *SymbolMaster) Get(symbol string) (Listing, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	l, ok := m.listings[strings.ToUpper(strings.TrimSpace(symbol))]
+	return l, ok
+}
+
+// ListingsForSymbol returns all listings sharing a base symbol ("ASML" -> ASML, ASML.AS)
+func (m *SymbolMaster) ListingsForSymbol(symbol string) []Listing {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.collectLocked(m.byBase[baseSymbol(symbol)])
+}
+
+// ListingsForCompany returns all listings of a company by name or alias
+func (m *SymbolMaster) ListingsForCompany(name string) []Listing {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.collectLocked(m.byCompany[normalizeCompany(name)])
+}
+
+// MatchesCompany reports whether a company name refers to the listing's company
+func (m *SymbolMaster) MatchesCompany(l Listing, name string) bool {
+	key := normalizeCompany(name)
+	if key == "" {
+		return false
+	}
+	for _, candidate := range append([]string{l.Name}, l.Aliases...) {
+		n := normalizeCompany(candidate)
+		if n == key || strings.HasPrefix(n, key+" ") || strings.HasPrefix(key, n+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *SymbolMaster) collectLocked(symbols []string) []Listing {
+	result := make([]Listing, 0, len(symbols))
+	for _, s := range symbols {
+		result = append(result, m.listings[s])
+	}
+	return result
+}
+
+// Refresh searches the provider for a query and adds unknown listings to the master.
+// Returns the number of listings added.
+func (m *SymbolMaster) Refresh(ctx context.Context, searcher SymbolSearcher, query string) (int, error) {
+	if searcher == nil {
+		return 0, fmt.Errorf("no symbol searcher configured")
+	}
+
+	profiles, err := searcher.SearchSymbol(ctx, query, 10)
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	m.mu.Lock()
+	for _, p := range profiles {
+		symbol := strings.ToUpper(strings.TrimSpace(p.Symbol))
+		if symbol == "" || p.CompanyName == "" {
+			continue
+		}
+		if _, exists := m.listings[symbol]; exists {
+			continue
+		}
+
+		// First listing seen for a company is assumed primary until curated
+		primary := len(m.byCompany[normalizeCompany(p.CompanyName)]) == 0
+		m.addLocked(Listing{
+			Symbol:   symbol,
+			Name:     p.CompanyName,
+			Exchange: NormalizeExchange(p.Exchange),
+			Currency: strings.ToUpper(p.Currency),
+			Primary:  primary,
+		})
+		added++
+	}
+	m.mu.Unlock()
+
+	if added > 0 {
+		m.logger.Infof("Symbol master refresh for %q added %d listings", query, added)
+		if err := m.Save(); err != nil {
+			m.logger.WithError(err).Warn("Failed to persist symbol master")
+		}
+	}
+
+	return added, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
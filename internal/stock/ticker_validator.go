@@ -0,0 +1,296 @@
+package stock
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+// Ticker validation policies
+const (
+	TickerPolicyFlag = "flag" // keep unknown tickers, marked unverified
+	TickerPolicyDrop = "drop" // remove tickers below the minimum confidence
+)
+
+// TickerCandidate is a ticker as extracted from article text
+type TickerCandidate struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name,omitempty"`
+	Exchange string `json:"exchange,omitempty"`
+}
+
+// TickerValidation is the outcome of validating a candidate against the symbol master
+type TickerValidation struct {
+	Input      TickerCandidate `json:"input"`
+	Symbol     string          `json:"symbol"` // Resolved listing symbol (input symbol when unknown)
+	Name       string          `json:"name,omitempty"`
+	Exchange   string          `json:"exchange,omitempty"`
+	Verified   bool            `json:"verified"`
+	Confidence float64         `json:"confidence"`
+	Reason     string          `json:"reason"`
+	Keep       bool            `json:"keep"` // Whether the ticker survives the configured policy
+}
+
+// maxTickerMisses bounds the negative cache; once full, unknown tickers are not looked
+// up at the provider until failed lookups expire
+const maxTickerMisses = 10000
+
+// TickerValidatorConfig configures ticker validation
+type TickerValidatorConfig struct {
+	Policy        string        // "flag" or "drop"
+	MinConfidence float64       // below this a ticker is unverified
+	AutoRefresh   bool          // query the provider for unknown tickers
+	RefreshTTL    time.Duration // how long a failed lookup is not retried
+}
+
+// TickerValidator validates extracted tickers against a SymbolMaster
+type TickerValidator struct {
+	master   *SymbolMaster
+	searcher SymbolSearcher
+	config   TickerValidatorConfig
+	logger   *logger.Logger
+
+	missMu sync.Mutex
+	misses map[string]time.Time // negative cache for provider lookups
+}
+
+// NewTickerValidator creates a new ticker validator
+func NewTickerValidator(master *SymbolMaster, config TickerValidatorConfig, log *logger.Logger) *TickerValidator {
+	if config.Policy != TickerPolicyDrop {
+		config.Policy = TickerPolicyFlag
+	}
+	if config.MinConfidence <= 0 {
+		config.MinConfidence = 0.5
+	}
+	if config.RefreshTTL <= 0 {
+		config.RefreshTTL = 24 * time.Hour
+	}
+
+	return &TickerValidator{
+		master: master,
+		config: config,
+		logger: log.WithComponent("ticker-validator"),
+		misses: make(map[string]time.Time),
+	}
+}
+
+// SetSearcher enables refreshing the symbol master from the quote provider
+func (v *TickerValidator) SetSearcher(searcher SymbolSearcher) {
+	v.searcher = searcher
+}
+
+// Master returns the underlying symbol master
+func (v *TickerValidator) Master() *SymbolMaster {
+	return v.master
+}
+
+// Policy returns the configured validation policy
+func (v *TickerValidator) Policy() string {
+	return v.config.Policy
+}
+
+// Validate resolves a single ticker candidate to a listing, asking the provider about
+// unknown tickers when auto refresh is enabled
+func (v *TickerValidator) Validate(ctx context.Context, candidate TickerCandidate) TickerValidation {
+	candidate = cleanCandidate(candidate)
+
+	result := v.resolve(candidate)
+	if !result.Verified && v.config.AutoRefresh && v.searcher != nil && v.refreshFor(ctx, candidate) {
+		result = v.resolve(candidate)
+	}
+
+	result.Keep = result.Verified || v.config.Policy == TickerPolicyFlag
+	return result
+}
+
+// ValidateLocal resolves a ticker candidate against the symbol master only, never
+// spending provider quota or changing the master
+func (v *TickerValidator) ValidateLocal(candidate TickerCandidate) TickerValidation {
+	result := v.resolve(cleanCandidate(candidate))
+	result.Keep = result.Verified || v.config.Policy == TickerPolicyFlag
+	return result
+}
+
+func cleanCandidate(candidate TickerCandidate) TickerCandidate {
+	candidate.Symbol = strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(candidate.Symbol, "$")))
+	candidate.Name = strings.TrimSpace(candidate.Name)
+	return candidate
+}
+
+// resolve matches a candidate against the master without touching the provider
+func (v *TickerValidator) resolve(candidate TickerCandidate) TickerValidation {
+	result := TickerValidation{
+		Input:    candidate,
+		Symbol:   candidate.Symbol,
+		Name:     candidate.Name,
+		Exchange: candidate.Exchange,
+	}
+	exchange := ""
+	if candidate.Exchange != "" {
+		exchange = NormalizeExchange(candidate.Exchange)
+	}
+
+	var listing Listing
+	switch {
+	case candidate.Symbol != "":
+		listings := v.master.ListingsForSymbol(candidate.Symbol)
+		if len(listings) == 0 {
+			// Symbol unknown; the company name may still identify the listing
+			if l, ok := v.pickListing(v.master.ListingsForCompany(candidate.Name), exchange); ok {
+				listing = l
+				result.Confidence = 0.7
+				result.Reason = "symbol unknown, resolved by company name"
+				break
+			}
+			result.Confidence = 0.1
+			result.Reason = "unknown symbol"
+			return v.finish(result, Listing{})
+		}
+
+		if exact, ok := v.master.Get(candidate.Symbol); ok && (exchange == "" || exact.Exchange == exchange) && len(listings) == 1 {
+			listing = exact
+			result.Confidence = 0.95
+			result.Reason = "unique symbol"
+			if exchange != "" {
+				result.Confidence = 1.0
+				result.Reason = "exact symbol and exchange"
+			}
+		} else {
+			listing, _ = v.pickListing(listings, exchange)
+			result.Confidence = 0.9
+			result.Reason = "primary listing"
+			if exchange != "" && listing.Exchange == exchange {
+				result.Confidence = 1.0
+				result.Reason = "listing matched by exchange"
+			} else if exchange != "" {
+				// e.g. "ING" on AEX: the company trades there under another symbol (INGA.AS)
+				if l, ok := v.pickListing(v.master.ListingsForCompany(listing.Name), exchange); ok && l.Exchange == exchange {
+					listing = l
+					result.Reason = "company listing on requested exchange"
+				}
+			}
+		}
+
+		// A name that contradicts the symbol suggests a hallucinated or reused ticker
+		if candidate.Name != "" && !v.master.MatchesCompany(listing, candidate.Name) {
+			if l, ok := v.pickListing(v.master.ListingsForCompany(candidate.Name), exchange); ok {
+				listing = l
+				result.Confidence = 0.75
+				result.Reason = "symbol conflicts with company name, resolved by name"
+			} else {
+				result.Confidence = 0.6
+				result.Reason = "symbol known but company name does not match"
+			}
+		}
+
+	case candidate.Name != "":
+		l, ok := v.pickListing(v.master.ListingsForCompany(candidate.Name), exchange)
+		if !ok {
+			result.Confidence = 0.1
+			result.Reason = "unknown company"
+			return v.finish(result, Listing{})
+		}
+		listing = l
+		result.Confidence = 0.85
+		result.Reason = "resolved by company name"
+
+	default:
+		result.Reason = "empty ticker"
+		return v.finish(result, Listing{})
+	}
+
+	return v.finish(result, listing)
+}
+
+// pickListing chooses the listing on the requested exchange, else the primary listing
+func (v *TickerValidator) pickListing(listings []Listing, exchange string) (Listing, bool) {
+	if len(listings) == 0 {
+		return Listing{}, false
+	}
+	if exchange != "" {
+		for _, l := range listings {
+			if l.Exchange == exchange {
+				return l, true
+			}
+		}
+	}
+	for _, l := range listings {
+		if l.Primary {
+			return l, true
+		}
+	}
+	return listings[0], true
+}
+
+func (v *TickerValidator) finish(result TickerValidation, listing Listing) TickerValidation {
+	if listing.Symbol != "" {
+		result.Symbol = listing.Symbol
+		result.Name = listing.Name
+		result.Exchange = listing.Exchange
+	}
+	result.Verified = listing.Symbol != "" && result.Confidence >= v.config.MinConfidence
+	return result
+}
+
+// refreshFor asks the provider about an unknown ticker, at most once per RefreshTTL
+func (v *TickerValidator) refreshFor(ctx context.Context, candidate TickerCandidate) bool {
+	query := candidate.Symbol
+	if query == "" {
+		query = candidate.Name
+	}
+	if query == "" {
+		return false
+	}
+
+	v.missMu.Lock()
+	if last, ok := v.misses[query]; ok && time.Since(last) < v.config.RefreshTTL {
+		v.missMu.Unlock()
+		return false
+	}
+	if len(v.misses) >= maxTickerMisses {
+		v.pruneMisses()
+	}
+	if len(v.misses) >= maxTickerMisses {
+		v.missMu.Unlock()
+		v.logger.Debugf("Too many failed symbol lookups, not looking up %s", query)
+		return false
+	}
+	v.misses[query] = time.Now()
+	v.missMu.Unlock()
+
+	added, err := v.master.Refresh(ctx, v.searcher, query)
+	if err != nil {
+		v.logger.WithError(err).Debugf("Symbol lookup for %s failed", query)
+		return false
+	}
+	return added > 0
+}
+
+// pruneMisses drops failed lookups whose TTL has expired; missMu must be held
+func (v *TickerValidator) pruneMisses() {
+	for query, last := range v.misses {
+		if time.Since(last) >= v.config.RefreshTTL {
+			delete(v.misses, query)
+		}
+	}
+}
+
+// RefreshSymbols queries the provider for each query and persists new listings
+func (v *TickerValidator) RefreshSymbols(ctx context.Context, queries []string) (int, error) {
+	total := 0
+	for _, query := range queries {
+		query = strings.TrimSpace(query)
+		if query == "" {
+			continue
+		}
+		added, err := v.master.Refresh(ctx, v.searcher, query)
+		if err != nil {
+			return total, err
+		}
+		total += added
+	}
+	return total, nil
+}
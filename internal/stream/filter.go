@@ -0,0 +1,117 @@
+package stream
+
+import (
+	"strings"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+)
+
+// Filter selects which events a subscriber receives. Values within a field are OR-ed,
+// fields are AND-ed. Entity and ticker filters only match article.enriched events,
+// since those are only known once AI enrichment has landed. Saved search alerts are
+// only delivered to subscribers that name the saved search, and bypass the other fields.
+type Filter struct {
+	Sources       []string
+	Categories    []string
+	Keywords      []string
+	Entities      []string
+	Tickers       []string
+	Enrichment    bool    // Include article.enriched events
+	SavedSearches []int64 // Include saved_search.matched alerts of these searches
+}
+
+// NewFilter builds a filter from comma-separated lists
+func NewFilter(sources, categories, keywords, entities, tickers string, enrichment bool, savedSearches []int64) Filter {
+	f := Filter{
+		Sources:       splitLower(sources),
+		Categories:    splitLower(categories),
+		Keywords:      splitLower(keywords),
+		Entities:      splitLower(entities),
+		Tickers:       splitLower(tickers),
+		Enrichment:    enrichment,
+		SavedSearches: savedSearches,
+	}
+	// Entity and ticker filters are meaningless without enrichment events
+	if len(f.Entities) > 0 || len(f.Tickers) > 0 {
+		f.Enrichment = true
+	}
+	return f
+}
+
+// Match reports whether an event passes the filter
+func (f Filter) Match(event *models.StreamEvent) bool {
+	if event.Type == models.StreamEventSavedSearchHit {
+		for _, id := range f.SavedSearches {
+			if id == event.SearchID {
+				return true
+			}
+		}
+		return false
+	}
+	if event.Type == models.StreamEventArticleEnriched && !f.Enrichment {
+		return false
+	}
+
+	if len(f.Sources) > 0 && !containsFold(f.Sources, event.Article.Source) {
+		return false
+	}
+	if len(f.Categories) > 0 && !containsFold(f.Categories, event.Article.Category) {
+		return false
+	}
+	if len(f.Keywords) > 0 && !anyFold(f.Keywords, event.Article.Keywords) {
+		return false
+	}
+
+	if len(f.Entities) == 0 && len(f.Tickers) == 0 {
+		return true
+	}
+	enrichment := event.Enrichment
+	if enrichment == nil {
+		return false
+	}
+	if len(f.Entities) > 0 {
+		names := make([]string, 0, len(enrichment.Persons)+len(enrichment.Organizations)+len(enrichment.Locations))
+		names = append(names, enrichment.Persons...)
+		names = append(names, enrichment.Organizations...)
+		names = append(names, enrichment.Locations...)
+		if !anyFold(f.Entities, names) {
+			return false
+		}
+	}
+	if len(f.Tickers) > 0 && !anyFold(f.Tickers, enrichment.StockTickers) {
+		return false
+	}
+	return true
+}
+
+// splitLower splits a comma-separated list into trimmed, lowercased values
+func splitLower(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// containsFold reports whether value (case-insensitive) is in the lowercased wanted list
+func containsFold(wanted []string, value string) bool {
+	value = strings.ToLower(value)
+	for _, w := range wanted {
+		if w == value {
+			return true
+		}
+	}
+	return false
+}
+
+// anyFold reports whether any of values is in the lowercased wanted list
+func anyFold(wanted []string, values []string) bool {
+	for _, value := range values {
+		if containsFold(wanted, value) {
+			return true
+		}
+	}
+	return false
+}
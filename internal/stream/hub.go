@@ -0,0 +1,321 @@
+// Package stream pushes newly stored and enriched articles to live clients.
+//
+// Events are published by the scraper and the AI service. With Redis they get a
+// cluster-wide sequence number, are kept in a short replay history for Last-Event-ID
+// resume and fan out to every replica over pub/sub; without Redis the hub works per instance.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisChannel    = "stream:articles"
+	redisSequence   = "stream:articles:seq"
+	redisHistory    = "stream:articles:history"
+	subscriberQueue = 256 // Buffered events per client before it is dropped as too slow
+)
+
+// ErrTooManySubscribers is returned when the hub is at its client limit
+var ErrTooManySubscribers = errors.New("too many stream subscribers")
+
+// Config configures the hub
+type Config struct {
+	HistorySize int // Events kept for Last-Event-ID resume
+	MaxClients  int // Concurrent subscribers per instance, 0 = unlimited
+}
+
+// Hub distributes stream events to subscribers
+type Hub struct {
+	redis  *redis.Client
+	config Config
+	logger *logger.Logger
+
+	mu          sync.RWMutex
+	subscribers map[*Subscriber]struct{}
+	history     []models.StreamEvent // Local replay history when Redis is unavailable
+	seq         int64
+
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+	runMu    sync.Mutex
+}
+
+// NewHub creates a stream hub; redisClient may be nil for single-instance deployments
+func NewHub(redisClient *redis.Client, cfg Config, log *logger.Logger) *Hub {
+	if cfg.HistorySize <= 0 {
+		cfg.HistorySize = 1000
+	}
+	return &Hub{
+		redis:       redisClient,
+		config:      cfg,
+		logger:      log.WithComponent("stream-hub"),
+		subscribers: make(map[*Subscriber]struct{}),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start subscribes to the Redis channel so events from other replicas reach local clients
+func (h *Hub) Start(ctx context.Context) error {
+	h.runMu.Lock()
+	defer h.runMu.Unlock()
+	if h.running {
+		return fmt.Errorf("stream hub already running")
+	}
+	h.running = true
+
+	if h.redis == nil {
+		h.logger.Info("Stream hub started (local only, no Redis)")
+		return nil
+	}
+
+	pubsub := h.redis.Subscribe(ctx, redisChannel)
+	h.wg.Add(1)
+	go h.receive(pubsub)
+
+	h.logger.Infof("Stream hub started (Redis pub/sub, history=%d)", h.config.HistorySize)
+	return nil
+}
+
+// Stop closes the Redis subscription and disconnects all clients
+func (h *Hub) Stop() {
+	h.runMu.Lock()
+	if !h.running {
+		h.runMu.Unlock()
+		return
+	}
+	h.running = false
+	h.runMu.Unlock()
+
+	close(h.stopChan)
+	h.wg.Wait()
+
+	h.mu.Lock()
+	for sub := range h.subscribers {
+		sub.close()
+		delete(h.subscribers, sub)
+	}
+	h.mu.Unlock()
+
+	h.logger.Info("Stream hub stopped")
+}
+
+// receive forwards events from Redis pub/sub to local subscribers
+func (h *Hub) receive(pubsub *redis.PubSub) {
+	defer h.wg.Done()
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-h.stopChan:
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var event models.StreamEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				h.logger.WithError(err).Warn("Discarding malformed stream event")
+				continue
+			}
+			h.dispatch(event)
+		}
+	}
+}
+
+// PublishArticles publishes article.created events for newly stored articles
+func (h *Hub) PublishArticles(ctx context.Context, articles []models.Article) {
+	for _, article := range articles {
+		h.publish(ctx, models.StreamEvent{
+			Type:    models.StreamEventArticleCreated,
+			Article: article,
+		})
+	}
+}
+
+// PublishEnrichment publishes an article.enriched event
+func (h *Hub) PublishEnrichment(ctx context.Context, article models.Article, enrichment *models.StreamEnrichment) {
+	h.publish(ctx, models.StreamEvent{
+		Type:       models.StreamEventArticleEnriched,
+		Article:    article,
+		Enrichment: enrichment,
+	})
+}
+
+// PublishSavedSearchMatch publishes a saved_search.matched alert; only clients that
+// subscribed to the saved search receive it
+func (h *Hub) PublishSavedSearchMatch(ctx context.Context, alert models.SavedSearchAlert) {
+	h.publish(ctx, models.StreamEvent{
+		Type:       models.StreamEventSavedSearchHit,
+		Article:    alert.Article,
+		Enrichment: alert.Enrichment,
+		SearchID:   alert.SavedSearchID,
+	})
+}
+
+// publish assigns an ID, records the event for replay and fans it out.
+// Publishing never fails the caller: on Redis errors the event is delivered locally
+// without an ID, so it cannot be resumed but live clients still see it.
+func (h *Hub) publish(ctx context.Context, event models.StreamEvent) {
+	event.Timestamp = time.Now().UTC()
+	// Content is never streamed; clients fetch it from /articles/:id when needed
+	event.Article.Content = ""
+
+	if h.redis == nil {
+		h.mu.Lock()
+		h.seq++
+		event.ID = h.seq
+		h.history = append(h.history, event)
+		if len(h.history) > h.config.HistorySize {
+			h.history = h.history[len(h.history)-h.config.HistorySize:]
+		}
+		h.mu.Unlock()
+		h.dispatch(event)
+		return
+	}
+
+	redisCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+	defer cancel()
+
+	id, err := h.redis.Incr(redisCtx, redisSequence).Result()
+	if err == nil {
+		event.ID = id
+		payload, _ := json.Marshal(event)
+
+		pipe := h.redis.TxPipeline()
+		pipe.ZAdd(redisCtx, redisHistory, redis.Z{Score: float64(id), Member: payload})
+		pipe.ZRemRangeByRank(redisCtx, redisHistory, 0, int64(-h.config.HistorySize-1))
+		pipe.Publish(redisCtx, redisChannel, payload)
+		if _, err = pipe.Exec(redisCtx); err == nil {
+			return
+		}
+	}
+
+	h.logger.WithError(err).Warn("Failed to publish stream event to Redis, delivering locally")
+	event.ID = 0
+	h.dispatch(event)
+}
+
+// Replay returns stored events with an ID greater than afterID, oldest first
+func (h *Hub) Replay(ctx context.Context, afterID int64) ([]models.StreamEvent, error) {
+	if h.redis == nil {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+		events := []models.StreamEvent{}
+		for _, event := range h.history {
+			if event.ID > afterID {
+				events = append(events, event)
+			}
+		}
+		return events, nil
+	}
+
+	payloads, err := h.redis.ZRangeByScore(ctx, redisHistory, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(afterID, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream history: %w", err)
+	}
+
+	events := make([]models.StreamEvent, 0, len(payloads))
+	for _, payload := range payloads {
+		var event models.StreamEvent
+		if err := json.Unmarshal([]byte(payload), &event); err == nil {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// Subscribe registers a client. Call Unsubscribe when the client disconnects.
+func (h *Hub) Subscribe(filter Filter) (*Subscriber, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.config.MaxClients > 0 && len(h.subscribers) >= h.config.MaxClients {
+		return nil, ErrTooManySubscribers
+	}
+
+	sub := &Subscriber{
+		filter: filter,
+		events: make(chan models.StreamEvent, subscriberQueue),
+		done:   make(chan struct{}),
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// Unsubscribe removes a client
+func (h *Hub) Unsubscribe(sub *Subscriber) {
+	h.mu.Lock()
+	delete(h.subscribers, sub)
+	h.mu.Unlock()
+	sub.close()
+}
+
+// SubscriberCount returns the number of connected clients on this instance
+func (h *Hub) SubscriberCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers)
+}
+
+// dispatch delivers an event to matching local subscribers.
+// Clients that cannot keep up are disconnected and resume with Last-Event-ID.
+func (h *Hub) dispatch(event models.StreamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		if !sub.filter.Match(&event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			h.logger.Warn("Dropping slow stream subscriber")
+			delete(h.subscribers, sub)
+			sub.close()
+		}
+	}
+}
+
+// Subscriber is a connected stream client
+type Subscriber struct {
+	filter Filter
+	events chan models.StreamEvent
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Events returns the channel of matching events
+func (s *Subscriber) Events() <-chan models.StreamEvent {
+	return s.events
+}
+
+// Done is closed when the subscriber was dropped (too slow or hub stopped)
+func (s *Subscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+// Matches reports whether an event passes the subscriber's filter
+func (s *Subscriber) Matches(event *models.StreamEvent) bool {
+	return s.filter.Match(event)
+}
+
+func (s *Subscriber) close() {
+	s.once.Do(func() { close(s.done) })
+}
@@ -0,0 +1,183 @@
+package stream
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Minimal server-side WebSocket (RFC 6455) for the push-only stream: text frames out,
+// control frames in. Fragmented and large client messages are rejected.
+
+const (
+	websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+
+	maxClientPayload = 4096
+	writeTimeout     = 10 * time.Second
+)
+
+// ErrBadHandshake is returned for requests that are not valid WebSocket upgrades
+var ErrBadHandshake = errors.New("invalid websocket handshake")
+
+// IsWebSocketUpgrade reports whether the request asks for a WebSocket upgrade
+func IsWebSocketUpgrade(c *fiber.Ctx) bool {
+	return strings.EqualFold(c.Get(fiber.HeaderUpgrade), "websocket") &&
+		strings.Contains(strings.ToLower(c.Get(fiber.HeaderConnection)), "upgrade")
+}
+
+// UpgradeWebSocket completes the handshake and runs handler on the hijacked connection
+// once the 101 response has been written. The connection is closed when handler returns.
+func UpgradeWebSocket(c *fiber.Ctx, handler func(*WebSocketConn)) error {
+	key := c.Get("Sec-WebSocket-Key")
+	if !IsWebSocketUpgrade(c) || key == "" || c.Get("Sec-WebSocket-Version") != "13" {
+		return ErrBadHandshake
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	c.Status(fiber.StatusSwitchingProtocols)
+	c.Set(fiber.HeaderUpgrade, "websocket")
+	c.Set(fiber.HeaderConnection, "Upgrade")
+	c.Set("Sec-WebSocket-Accept", base64.StdEncoding.EncodeToString(sum[:]))
+
+	c.Context().Hijack(func(conn net.Conn) {
+		ws := &WebSocketConn{conn: conn, reader: bufio.NewReader(conn)}
+		defer conn.Close()
+		handler(ws)
+	})
+	return nil
+}
+
+// WebSocketConn is a hijacked WebSocket connection. Writes are safe for concurrent use.
+type WebSocketConn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// WriteText sends a text message
+func (ws *WebSocketConn) WriteText(data []byte) error {
+	return ws.writeFrame(opText, data)
+}
+
+// WritePing sends a ping; browsers answer automatically
+func (ws *WebSocketConn) WritePing() error {
+	return ws.writeFrame(opPing, nil)
+}
+
+// Close sends a close frame with a status code and reason
+func (ws *WebSocketConn) Close(code uint16, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, code)
+	payload = append(payload, reason...)
+	return ws.writeFrame(opClose, payload)
+}
+
+func (ws *WebSocketConn) writeFrame(opcode byte, payload []byte) error {
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+
+	header := []byte{0x80 | opcode} // FIN, unmasked server frame
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126, byte(n>>8), byte(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	if err := ws.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	if _, err := ws.conn.Write(append(header, payload...)); err != nil {
+		return fmt.Errorf("failed to write websocket frame: %w", err)
+	}
+	return nil
+}
+
+// ReadLoop consumes client frames until the connection closes: pings are answered,
+// client messages are ignored. It returns when the client disconnects, sends close or
+// sends nothing (not even a pong) for idleTimeout.
+func (ws *WebSocketConn) ReadLoop(idleTimeout time.Duration) error {
+	for {
+		if err := ws.conn.SetReadDeadline(time.Now().Add(idleTimeout)); err != nil {
+			return err
+		}
+		opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return err
+		}
+		switch opcode {
+		case opPing:
+			if err := ws.writeFrame(opPong, payload); err != nil {
+				return err
+			}
+		case opClose:
+			_ = ws.writeFrame(opClose, payload)
+			return io.EOF
+		}
+	}
+}
+
+func (ws *WebSocketConn) readFrame() (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(ws.reader, head[:]); err != nil {
+		return 0, nil, err
+	}
+
+	fin := head[0]&0x80 != 0
+	opcode := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	if !fin || !masked {
+		return 0, nil, errors.New("unsupported websocket frame")
+	}
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxClientPayload {
+		return 0, nil, errors.New("websocket message too large")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(ws.reader, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
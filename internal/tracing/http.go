@@ -0,0 +1,128 @@
+package tracing
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader carries the trace ID of a request so clients can report it with X-Request-ID
+const TraceIDHeader = "X-Trace-ID"
+
+// Middleware starts a server span per request, continuing a trace from an incoming
+// traceparent header. It runs after the requestid middleware so spans carry the request
+// ID, and puts the span in the user context that handlers pass to repositories.
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{&c.Request().Header})
+
+		method := utils.CopyString(c.Method())
+		ctx, span := otel.Tracer(instrumentationName).Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", method),
+				attribute.String("url.path", utils.CopyString(c.Path())),
+				attribute.String("client.address", utils.CopyString(c.IP())),
+				attribute.String("user_agent.original", utils.CopyString(c.Get(fiber.HeaderUserAgent))),
+			),
+		)
+		defer span.End()
+
+		if requestID, ok := c.Locals("requestid").(string); ok {
+			span.SetAttributes(attribute.String("request.id", requestID))
+		}
+		if span.SpanContext().IsValid() {
+			c.Set(TraceIDHeader, span.SpanContext().TraceID().String())
+		}
+
+		c.SetUserContext(ctx)
+		err := c.Next()
+
+		// Errors returned to the app error handler have not set the status yet
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if fiberErr, ok := err.(*fiber.Error); ok {
+				status = fiberErr.Code
+			}
+			span.RecordError(err)
+		}
+
+		route := c.Route().Path
+		span.SetName(method + " " + route)
+		span.SetAttributes(
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", status),
+		)
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, strconv.Itoa(status))
+		}
+		return err
+	}
+}
+
+// headerCarrier reads propagation headers from a fasthttp request
+type headerCarrier struct {
+	h *fasthttp.RequestHeader
+}
+
+func (c headerCarrier) Get(key string) string {
+	return string(c.h.Peek(key))
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c.h.Set(key, value)
+}
+
+func (c headerCarrier) Keys() []string {
+	var keys []string
+	c.h.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+// Transport wraps base (http.DefaultTransport when nil) with client spans for outbound
+// requests. Spans record the host and path but not the query string, which carries API
+// keys for some providers, and trace context is not forwarded to third parties.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(instrumentationName).Start(req.Context(), req.Method+" "+req.URL.Host,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Hostname()),
+			attribute.String("url.path", req.URL.Path),
+		),
+	)
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	span.End()
+	return resp, nil
+}
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxStatementLength bounds the SQL text recorded on spans
+const maxStatementLength = 2000
+
+// QueryTracer returns a pgx tracer that records a client span per query. Query text is
+// recorded, arguments are not.
+func QueryTracer() pgx.QueryTracer {
+	return queryTracer{}
+}
+
+type queryTracer struct{}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation := sqlOperation(data.SQL)
+	statement := data.SQL
+	if len(statement) > maxStatementLength {
+		statement = statement[:maxStatementLength]
+	}
+
+	ctx, _ = otel.Tracer(instrumentationName).Start(ctx, "postgres "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.query.text", statement),
+		),
+	)
+	return ctx
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err == nil {
+		span.SetAttributes(attribute.Int64("db.response.rows_affected", data.CommandTag.RowsAffected()))
+	}
+	End(span, data.Err)
+}
+
+// sqlOperation returns the leading SQL keyword (SELECT, INSERT, ...) as a low-cardinality span name
+func sqlOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,12 @@
+package tracing
+
+import (
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
+)
+
+// InstrumentRedis adds a client span per Redis command. Command arguments are not
+// recorded since they include cached payloads.
+func InstrumentRedis(client *redis.Client) error {
+	return redisotel.InstrumentTracing(client, redisotel.WithDBStatement(false))
+}
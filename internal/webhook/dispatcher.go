@@ -0,0 +1,525 @@
+// Package webhook delivers events to partner endpoints registered as webhook subscriptions.
+//
+// Events from the scraper and the AI service are matched against the active subscriptions
+// and queued in webhook_deliveries. Workers claim due deliveries, POST them with an HMAC
+// signature and retry failures with exponential backoff. Subscriptions whose deliveries keep
+// failing are disabled automatically.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeffrey/intellinieuws/internal/models"
+	"github.com/jeffrey/intellinieuws/internal/repository"
+	"github.com/jeffrey/intellinieuws/pkg/logger"
+)
+
+const (
+	// SignatureHeader carries "t=<unix>,v1=<hex HMAC-SHA256(secret, t + "." + body)>"
+	SignatureHeader = "X-IntelliNieuws-Signature"
+	EventHeader     = "X-IntelliNieuws-Event"
+	DeliveryHeader  = "X-IntelliNieuws-Delivery"
+
+	userAgent        = "IntelliNieuws-Webhooks/1.0"
+	secretPrefix     = "whsec_"
+	baseBackoff      = 30 * time.Second
+	maxBackoff       = 6 * time.Hour
+	maxResponseBody  = 1024
+	subscriptionsTTL = 30 * time.Second
+	cleanupInterval  = time.Hour
+)
+
+// ErrPrivateAddress is returned when a webhook URL resolves to a non-public address
+var ErrPrivateAddress = errors.New("webhook URL resolves to a private address")
+
+// Config configures the dispatcher
+type Config struct {
+	Workers       int           // Concurrent deliveries
+	MaxAttempts   int           // Attempts per delivery before it is marked failed
+	DisableAfter  int           // Consecutive failed attempts before a subscription is disabled, 0 = never
+	Timeout       time.Duration // HTTP timeout per attempt
+	PollInterval  time.Duration // How often the queue is checked for due retries
+	AllowPrivate  bool          // Allow endpoints on private/loopback networks (development only)
+	RetentionDays int           // Finished deliveries are kept this long, 0 = forever
+}
+
+// Dispatcher matches events to subscriptions and delivers them
+type Dispatcher struct {
+	repo   *repository.WebhookRepository
+	config Config
+	client *http.Client
+	logger *logger.Logger
+
+	subsMu     sync.RWMutex
+	subs       []models.WebhookSubscription
+	subsLoaded time.Time
+
+	wake     chan struct{}
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+	mu       sync.Mutex
+}
+
+// NewDispatcher creates a webhook dispatcher
+func NewDispatcher(repo *repository.WebhookRepository, cfg Config, log *logger.Logger) *Dispatcher {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivate {
+		// Checked on the resolved address, so DNS names pointing inside are refused too
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+				return ErrPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	client := &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: cfg.Timeout,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// A redirect is reported as the 3xx it is; following it would bypass the URL the partner registered
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return &Dispatcher{
+		repo:     repo,
+		config:   cfg,
+		client:   client,
+		logger:   log.WithComponent("webhook-dispatcher"),
+		wake:     make(chan struct{}, 1),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start launches the delivery loop and the delivery log cleanup
+func (d *Dispatcher) Start(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.running {
+		return fmt.Errorf("webhook dispatcher already running")
+	}
+	d.running = true
+
+	d.wg.Add(2)
+	go d.deliveryLoop()
+	go d.cleanupLoop()
+
+	d.logger.Infof("Webhook dispatcher started (workers=%d, max_attempts=%d, disable_after=%d)",
+		d.config.Workers, d.config.MaxAttempts, d.config.DisableAfter)
+	return nil
+}
+
+// Stop waits for in-flight deliveries to finish
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return
+	}
+	d.running = false
+	d.mu.Unlock()
+
+	close(d.stopChan)
+	d.wg.Wait()
+	d.logger.Info("Webhook dispatcher stopped")
+}
+
+// Invalidate drops the cached subscriptions after they were changed through the API
+func (d *Dispatcher) Invalidate() {
+	d.subsMu.Lock()
+	d.subsLoaded = time.Time{}
+	d.subsMu.Unlock()
+}
+
+// activeSubscriptions returns the cached active subscriptions, reloading them when stale
+func (d *Dispatcher) activeSubscriptions(ctx context.Context) []models.WebhookSubscription {
+	d.subsMu.RLock()
+	if time.Since(d.subsLoaded) < subscriptionsTTL {
+		subs := d.subs
+		d.subsMu.RUnlock()
+		return subs
+	}
+	d.subsMu.RUnlock()
+
+	subs, err := d.repo.ListActive(ctx)
+	if err != nil {
+		d.logger.WithError(err).Warn("Failed to load webhook subscriptions")
+		d.subsMu.RLock()
+		defer d.subsMu.RUnlock()
+		return d.subs
+	}
+
+	d.subsMu.Lock()
+	d.subs = subs
+	d.subsLoaded = time.Now()
+	d.subsMu.Unlock()
+	return subs
+}
+
+// articleEvent is the data of article.created and article.enriched events
+type articleEvent struct {
+	Article    models.Article           `json:"article"`
+	Enrichment *models.StreamEnrichment `json:"enrichment,omitempty"`
+}
+
+// PublishArticles queues article.created events for newly stored articles
+func (d *Dispatcher) PublishArticles(ctx context.Context, articles []models.Article) {
+	for _, article := range articles {
+		d.enqueue(ctx, models.WebhookEventArticleCreated, articleEvent{Article: article}, func(sub *models.WebhookSubscription) bool {
+			return matchArticle(sub, &article, nil)
+		})
+	}
+}
+
+// PublishEnrichment queues an article.enriched event
+func (d *Dispatcher) PublishEnrichment(ctx context.Context, article models.Article, enrichment *models.StreamEnrichment) {
+	d.enqueue(ctx, models.WebhookEventArticleEnriched, articleEvent{Article: article, Enrichment: enrichment}, func(sub *models.WebhookSubscription) bool {
+		return matchArticle(sub, &article, enrichment)
+	})
+}
+
+// PublishScrapeFailure queues a scrape.failed event; only the source filter applies
+func (d *Dispatcher) PublishScrapeFailure(ctx context.Context, failure models.ScrapeFailureEvent) {
+	d.enqueue(ctx, models.WebhookEventScrapeFailed, failure, func(sub *models.WebhookSubscription) bool {
+		return len(sub.Sources) == 0 || containsFold(sub.Sources, failure.Source)
+	})
+}
+
+// PublishSavedSearchMatch queues a saved_search.matched alert for the subscription the
+// saved search alerts through. The saved search is the filter, so the subscription's own
+// event and article filters do not apply.
+func (d *Dispatcher) PublishSavedSearchMatch(ctx context.Context, subscriptionID int64, alert models.SavedSearchAlert) {
+	d.enqueue(ctx, models.WebhookEventSavedSearchHit, alert, func(sub *models.WebhookSubscription) bool {
+		return sub.ID == subscriptionID
+	})
+}
+
+// enqueue stores one delivery per matching subscription and wakes the workers
+func (d *Dispatcher) enqueue(ctx context.Context, eventType string, data interface{}, match func(*models.WebhookSubscription) bool) {
+	subs := d.activeSubscriptions(ctx)
+	if len(subs) == 0 {
+		return
+	}
+
+	eventID := uuid.NewString() // Shared by all subscribers and identical across retries
+	var deliveries []models.WebhookDelivery
+	var payload json.RawMessage
+	for i := range subs {
+		sub := &subs[i]
+		targeted := eventType == models.WebhookEventSavedSearchHit
+		if (!targeted && !subscribesTo(sub, eventType)) || !match(sub) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			payload, err = buildPayload(eventID, eventType, data)
+			if err != nil {
+				d.logger.WithError(err).Errorf("Failed to encode %s webhook payload", eventType)
+				return
+			}
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        eventID,
+			EventType:      eventType,
+			Payload:        payload,
+		})
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+
+	if err := d.repo.Enqueue(ctx, deliveries); err != nil {
+		d.logger.WithError(err).Errorf("Failed to queue %s webhooks", eventType)
+		return
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// SendTest delivers a webhook.test event synchronously, bypassing the event filters.
+// The attempt is logged like any delivery but is not retried and does not count towards auto-disable.
+func (d *Dispatcher) SendTest(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookDelivery, error) {
+	eventID := uuid.NewString()
+	payload, err := buildPayload(eventID, models.WebhookEventTest, map[string]interface{}{
+		"subscription_id": sub.ID,
+		"message":         "Test event from IntelliNieuws",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		EventID:        eventID,
+		EventType:      models.WebhookEventTest,
+		Payload:        payload,
+	}
+	if err := d.repo.CreateDelivering(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	d.attempt(ctx, delivery, sub.URL, sub.Secret)
+	if delivery.Status != models.WebhookDeliverySucceeded {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+	}
+	if err := d.repo.RecordAttempt(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// deliveryLoop claims due deliveries whenever woken or on the poll interval
+func (d *Dispatcher) deliveryLoop() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stopChan:
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+
+		// Keep draining while full batches come back
+		for d.processBatch() == d.config.Workers*4 {
+			select {
+			case <-d.stopChan:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// processBatch delivers one batch of due deliveries concurrently and returns its size
+func (d *Dispatcher) processBatch() int {
+	ctx := context.Background()
+
+	// The lease outlives an attempt, so a delivery is only reclaimed if this worker died
+	lease := 2*d.config.Timeout + time.Minute
+	claimed, err := d.repo.ClaimDue(ctx, d.config.Workers*4, lease)
+	if err != nil {
+		d.logger.WithError(err).Warn("Failed to claim webhook deliveries")
+		return 0
+	}
+
+	sem := make(chan struct{}, d.config.Workers)
+	var wg sync.WaitGroup
+	for i := range claimed {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(c *repository.ClaimedDelivery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			d.deliver(ctx, c)
+		}(&claimed[i])
+	}
+	wg.Wait()
+
+	return len(claimed)
+}
+
+// deliver performs one attempt and schedules a retry or finishes the delivery
+func (d *Dispatcher) deliver(ctx context.Context, c *repository.ClaimedDelivery) {
+	delivery := &c.WebhookDelivery
+	d.attempt(ctx, delivery, c.URL, c.Secret)
+
+	if delivery.Status == models.WebhookDeliverySucceeded {
+		if err := d.repo.RecordSuccess(ctx, delivery.SubscriptionID); err != nil {
+			d.logger.WithError(err).Warn("Failed to record webhook success")
+		}
+	} else {
+		if delivery.Attempts >= d.config.MaxAttempts {
+			delivery.Status = models.WebhookDeliveryFailed
+		} else {
+			delivery.Status = models.WebhookDeliveryRetrying
+			next := time.Now().Add(backoff(delivery.Attempts))
+			delivery.NextAttemptAt = &next
+		}
+
+		disabled, err := d.repo.RecordFailure(ctx, delivery.SubscriptionID, d.config.DisableAfter)
+		if err != nil {
+			d.logger.WithError(err).Warn("Failed to record webhook failure")
+		}
+		if disabled {
+			d.logger.Warnf("Webhook subscription %d disabled after %d consecutive failed deliveries",
+				delivery.SubscriptionID, d.config.DisableAfter)
+			d.Invalidate()
+		}
+	}
+
+	if err := d.repo.RecordAttempt(ctx, delivery); err != nil {
+		d.logger.WithError(err).Errorf("Failed to record webhook delivery %d", delivery.ID)
+	}
+}
+
+// attempt POSTs the payload once and records the response on the delivery
+func (d *Dispatcher) attempt(ctx context.Context, delivery *models.WebhookDelivery, url, secret string) {
+	delivery.Attempts++
+	delivery.ResponseStatus = nil
+	delivery.ResponseBody = ""
+	delivery.ErrorMessage = ""
+
+	start := time.Now()
+	defer func() {
+		ms := int(time.Since(start).Milliseconds())
+		delivery.DurationMs = &ms
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		delivery.Status = models.WebhookDeliveryRetrying
+		delivery.ErrorMessage = err.Error()
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.EventID)
+	req.Header.Set(SignatureHeader, Sign(secret, time.Now(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		delivery.Status = models.WebhookDeliveryRetrying
+		delivery.ErrorMessage = err.Error()
+		return
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	status := resp.StatusCode
+	delivery.ResponseStatus = &status
+	delivery.ResponseBody = strings.ToValidUTF8(string(body), "")
+
+	if status >= 200 && status < 300 {
+		now := time.Now()
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		return
+	}
+	delivery.Status = models.WebhookDeliveryRetrying
+	delivery.ErrorMessage = fmt.Sprintf("endpoint returned HTTP %d", status)
+}
+
+// cleanupLoop removes finished deliveries past the retention period
+func (d *Dispatcher) cleanupLoop() {
+	defer d.wg.Done()
+	if d.config.RetentionDays <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stopChan:
+			return
+		case <-ticker.C:
+			cutoff := time.Now().AddDate(0, 0, -d.config.RetentionDays)
+			deleted, err := d.repo.DeleteDeliveriesBefore(context.Background(), cutoff)
+			if err != nil {
+				d.logger.WithError(err).Warn("Failed to clean up webhook deliveries")
+			} else if deleted > 0 {
+				d.logger.Infof("Removed %d webhook deliveries older than %d days", deleted, d.config.RetentionDays)
+			}
+		}
+	}
+}
+
+// Sign returns the signature header value for a payload sent at t. Receivers recompute
+// HMAC-SHA256 over "<t>.<body>" with their secret and should reject stale timestamps.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret returns a new random signing secret
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return secretPrefix + hex.EncodeToString(buf), nil
+}
+
+// backoff returns the delay before the next attempt: 30s, 1m, 2m, ... capped at 6h
+func backoff(attempts int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
+
+func buildPayload(eventID, eventType string, data interface{}) (json.RawMessage, error) {
+	return json.Marshal(models.WebhookPayload{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+}
+
+// isPrivateIP reports whether ip is loopback, private, link-local or otherwise not public
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
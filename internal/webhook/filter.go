@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"strings"
+
+	"github.com/jeffrey/intellinieuws/internal/models"
+)
+
+// subscribesTo reports whether the subscription selected the event type (none selected = all)
+func subscribesTo(sub *models.WebhookSubscription, eventType string) bool {
+	if len(sub.Events) == 0 {
+		return true
+	}
+	for _, e := range sub.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// matchArticle applies the subscription filters to an article event. Values within a field
+// are OR-ed, fields are AND-ed. Entity, ticker and sentiment filters need AI enrichment, so
+// they only match article.enriched events.
+func matchArticle(sub *models.WebhookSubscription, article *models.Article, enrichment *models.StreamEnrichment) bool {
+	if len(sub.Sources) > 0 && !containsFold(sub.Sources, article.Source) {
+		return false
+	}
+	if len(sub.Keywords) > 0 && !matchKeywords(sub.Keywords, article) {
+		return false
+	}
+
+	needsEnrichment := len(sub.Entities) > 0 || len(sub.Tickers) > 0 ||
+		sub.MinSentiment != nil || sub.MaxSentiment != nil
+	if !needsEnrichment {
+		return true
+	}
+	if enrichment == nil {
+		return false
+	}
+
+	if len(sub.Entities) > 0 {
+		names := make([]string, 0, len(enrichment.Persons)+len(enrichment.Organizations)+len(enrichment.Locations))
+		names = append(names, enrichment.Persons...)
+		names = append(names, enrichment.Organizations...)
+		names = append(names, enrichment.Locations...)
+		if !anyFold(sub.Entities, names) {
+			return false
+		}
+	}
+	if len(sub.Tickers) > 0 && !anyFold(sub.Tickers, enrichment.StockTickers) {
+		return false
+	}
+	if sub.MinSentiment != nil || sub.MaxSentiment != nil {
+		if enrichment.Sentiment == nil {
+			return false
+		}
+		if sub.MinSentiment != nil && *enrichment.Sentiment < *sub.MinSentiment {
+			return false
+		}
+		if sub.MaxSentiment != nil && *enrichment.Sentiment > *sub.MaxSentiment {
+			return false
+		}
+	}
+	return true
+}
+
+// matchKeywords reports whether any keyword is one of the article keywords or occurs in its title
+func matchKeywords(keywords []string, article *models.Article) bool {
+	if anyFold(keywords, article.Keywords) {
+		return true
+	}
+	title := strings.ToLower(article.Title)
+	for _, keyword := range keywords {
+		if strings.Contains(title, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether value is in wanted, ignoring case
+func containsFold(wanted []string, value string) bool {
+	for _, w := range wanted {
+		if strings.EqualFold(w, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyFold reports whether any of values is in wanted, ignoring case
+func anyFold(wanted []string, values []string) bool {
+	for _, value := range values {
+		if containsFold(wanted, value) {
+			return true
+		}
+	}
+	return false
+}
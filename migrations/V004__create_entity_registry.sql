@@ -0,0 +1,263 @@
+-- ============================================================================
+-- Migration: V004__create_entity_registry.sql
+-- Description: Canonical entity registry with aliases and per-article links
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-03
+-- Dependencies: V001__create_base_schema.sql, V003__create_analytics_views.sql
+-- ============================================================================
+
+-- ============================================================================
+-- ENTITIES TABLE (canonical registry)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS entities (
+    id BIGSERIAL PRIMARY KEY,
+
+    -- Canonical identity
+    canonical_name VARCHAR(300) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL
+        CHECK (entity_type IN ('person', 'organization', 'location')),
+    external_id VARCHAR(50), -- Wikidata-style identifier (e.g. Q57792)
+    description TEXT,
+
+    -- Merge tracking (merged entities point to their survivor)
+    merged_into BIGINT REFERENCES entities(id) ON DELETE SET NULL,
+
+    -- Provenance
+    origin VARCHAR(20) NOT NULL DEFAULT 'auto'
+        CHECK (origin IN ('auto', 'dump', 'manual')),
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT entities_no_self_merge CHECK (merged_into IS NULL OR merged_into <> id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_entities_external_id
+    ON entities(external_id) WHERE external_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_entities_type
+    ON entities(entity_type) WHERE merged_into IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_entities_canonical_name
+    ON entities(LOWER(canonical_name));
+
+CREATE INDEX IF NOT EXISTS idx_entities_merged_into
+    ON entities(merged_into) WHERE merged_into IS NOT NULL;
+
+CREATE TRIGGER trg_entities_updated_at
+    BEFORE UPDATE ON entities
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+COMMENT ON TABLE entities IS 'Canonical registry of persons, organizations and locations';
+COMMENT ON COLUMN entities.external_id IS 'Optional Wikidata-style identifier loaded from a local dump';
+COMMENT ON COLUMN entities.merged_into IS 'Survivor entity when this entity has been merged';
+
+-- ============================================================================
+-- ENTITY ALIASES TABLE
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS entity_aliases (
+    id BIGSERIAL PRIMARY KEY,
+    entity_id BIGINT NOT NULL REFERENCES entities(id) ON DELETE CASCADE,
+
+    alias VARCHAR(300) NOT NULL,
+    alias_normalized VARCHAR(300) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL
+        CHECK (entity_type IN ('person', 'organization', 'location')),
+
+    origin VARCHAR(20) NOT NULL DEFAULT 'auto'
+        CHECK (origin IN ('auto', 'dump', 'manual')),
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT entity_aliases_unique UNIQUE (alias_normalized, entity_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_entity_aliases_entity
+    ON entity_aliases(entity_id);
+
+COMMENT ON TABLE entity_aliases IS 'Surface forms that resolve to a canonical entity';
+COMMENT ON COLUMN entity_aliases.alias_normalized IS 'Lowercased, accent-folded form without titles or legal suffixes';
+
+-- ============================================================================
+-- ARTICLE ENTITIES TABLE (resolved mentions)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS article_entities (
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    entity_id BIGINT NOT NULL REFERENCES entities(id) ON DELETE CASCADE,
+    mention VARCHAR(300) NOT NULL, -- Raw surface form as extracted
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (article_id, entity_id, mention)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_entities_entity
+    ON article_entities(entity_id, article_id);
+
+COMMENT ON TABLE article_entities IS 'Extracted entity mentions resolved to canonical entity IDs';
+
+-- ============================================================================
+-- ENTITY MENTIONS (rebuilt on canonical IDs)
+-- ============================================================================
+
+DROP MATERIALIZED VIEW IF EXISTS mv_entity_mentions CASCADE;
+
+CREATE MATERIALIZED VIEW mv_entity_mentions AS
+WITH entity_extraction AS (
+    SELECT DISTINCT
+        ae.article_id,
+        ae.entity_id,
+        a.source,
+        a.published,
+        a.ai_sentiment,
+        a.category,
+        DATE_TRUNC('day', a.published) AS day_bucket
+    FROM article_entities ae
+    JOIN articles a ON a.id = ae.article_id
+    WHERE a.ai_processed = TRUE
+      AND a.published >= CURRENT_TIMESTAMP - INTERVAL '90 days'
+)
+SELECT
+    e.id AS entity_id,
+    e.canonical_name AS entity,
+    e.entity_type,
+    x.day_bucket,
+    COUNT(DISTINCT x.article_id) AS mention_count,
+    COUNT(DISTINCT x.source) AS source_count,
+    ARRAY_AGG(DISTINCT x.source ORDER BY x.source) AS sources,
+    ARRAY_AGG(DISTINCT x.category ORDER BY x.category) FILTER (WHERE x.category IS NOT NULL) AS categories,
+    ROUND(AVG(x.ai_sentiment)::NUMERIC, 3) FILTER (WHERE x.ai_sentiment IS NOT NULL) AS avg_sentiment,
+    MAX(x.published) AS last_mentioned,
+    ARRAY_AGG(x.article_id ORDER BY x.published DESC) AS recent_article_ids
+FROM entity_extraction x
+JOIN entities e ON e.id = x.entity_id
+GROUP BY e.id, e.canonical_name, e.entity_type, x.day_bucket
+HAVING COUNT(DISTINCT x.article_id) >= 2; -- Minimum 2 mentions
+
+CREATE UNIQUE INDEX idx_mv_entity_mentions_unique
+    ON mv_entity_mentions(entity_id, day_bucket);
+
+CREATE INDEX idx_mv_entity_mentions_entity
+    ON mv_entity_mentions(entity);
+
+CREATE INDEX idx_mv_entity_mentions_type
+    ON mv_entity_mentions(entity_type, mention_count DESC);
+
+CREATE INDEX idx_mv_entity_mentions_count
+    ON mv_entity_mentions(mention_count DESC);
+
+CREATE INDEX idx_mv_entity_mentions_day
+    ON mv_entity_mentions(day_bucket DESC);
+
+CREATE INDEX idx_mv_entity_mentions_last_mentioned
+    ON mv_entity_mentions(last_mentioned DESC);
+
+COMMENT ON MATERIALIZED VIEW mv_entity_mentions IS 'Canonical entity mentions aggregated by day with sentiment';
+
+-- View: Hot entities (last 7 days), keyed by canonical entity
+CREATE OR REPLACE VIEW v_hot_entities_7d AS
+SELECT
+    entity,
+    entity_type,
+    SUM(mention_count) AS total_mentions,
+    COUNT(DISTINCT day_bucket) AS days_mentioned,
+    ARRAY_AGG(DISTINCT unnest ORDER BY unnest) AS all_sources,
+    ROUND(AVG(avg_sentiment)::NUMERIC, 3) AS overall_sentiment,
+    MAX(last_mentioned) AS most_recent_mention,
+    entity_id
+FROM mv_entity_mentions
+CROSS JOIN LATERAL unnest(sources) AS unnest
+WHERE day_bucket >= CURRENT_DATE - INTERVAL '7 days'
+GROUP BY entity_id, entity, entity_type
+HAVING SUM(mention_count) >= 5
+ORDER BY total_mentions DESC, days_mentioned DESC
+LIMIT 100;
+
+COMMENT ON VIEW v_hot_entities_7d IS 'Top 100 most mentioned canonical entities in last 7 days';
+
+-- ============================================================================
+-- FUNCTIONS
+-- ============================================================================
+
+-- Function: Resolve a surface form to its canonical entity ID
+CREATE OR REPLACE FUNCTION resolve_entity_id(p_name TEXT)
+RETURNS BIGINT AS $$
+    SELECT COALESCE(e.merged_into, e.id)
+    FROM entities e
+    LEFT JOIN entity_aliases ea ON ea.entity_id = e.id
+    WHERE LOWER(ea.alias) = LOWER(p_name)
+       OR LOWER(e.canonical_name) = LOWER(p_name)
+       OR e.external_id = p_name
+    ORDER BY (e.merged_into IS NULL) DESC, e.id
+    LIMIT 1;
+$$ LANGUAGE sql STABLE;
+
+COMMENT ON FUNCTION resolve_entity_id IS 'Resolve an alias, canonical name or external ID to a canonical entity ID';
+
+-- Function: Get sentiment analysis for entity (resolves aliases first)
+CREATE OR REPLACE FUNCTION get_entity_sentiment_analysis(
+    p_entity TEXT,
+    p_days_back INTEGER DEFAULT 30
+)
+RETURNS TABLE (
+    day DATE,
+    mention_count BIGINT,
+    avg_sentiment NUMERIC,
+    sources TEXT[],
+    categories TEXT[]
+) AS $$
+DECLARE
+    v_entity_id BIGINT;
+BEGIN
+    v_entity_id := resolve_entity_id(p_entity);
+
+    RETURN QUERY
+    SELECT
+        em.day_bucket::DATE AS day,
+        SUM(em.mention_count)::BIGINT AS mention_count,
+        ROUND(AVG(em.avg_sentiment)::NUMERIC, 3) AS avg_sentiment,
+        ARRAY_AGG(DISTINCT unnest ORDER BY unnest) AS sources,
+        ARRAY_AGG(DISTINCT cat ORDER BY cat) FILTER (WHERE cat IS NOT NULL) AS categories
+    FROM mv_entity_mentions em
+    CROSS JOIN LATERAL unnest(em.sources) AS unnest
+    CROSS JOIN LATERAL unnest(em.categories) AS cat
+    WHERE em.entity_id = v_entity_id
+      AND em.day_bucket >= CURRENT_DATE - (p_days_back || ' days')::INTERVAL
+    GROUP BY em.day_bucket
+    ORDER BY day DESC;
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+COMMENT ON FUNCTION get_entity_sentiment_analysis IS 'Analyze sentiment for a canonical entity (any alias) over time';
+
+-- ============================================================================
+-- PERMISSIONS
+-- ============================================================================
+
+GRANT SELECT ON mv_entity_mentions TO PUBLIC;
+GRANT SELECT ON v_hot_entities_7d TO PUBLIC;
+
+-- ============================================================================
+-- FINALIZE MIGRATION
+-- ============================================================================
+
+-- Record migration
+INSERT INTO schema_migrations (version, description, checksum)
+VALUES (
+    'V004',
+    'Create canonical entity registry and rebuild entity analytics on entity IDs',
+    'entity_registry_v1'
+) ON CONFLICT (version) DO NOTHING;
+
+-- Success notification
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Migration V004 completed successfully';
+    RAISE NOTICE 'Created tables: entities, entity_aliases, article_entities';
+    RAISE NOTICE 'Rebuilt mv_entity_mentions and v_hot_entities_7d on canonical entity IDs';
+    RAISE NOTICE 'NOTE: Run the entity backfill (POST /api/v1/entities/backfill) to resolve existing articles';
+END $$;
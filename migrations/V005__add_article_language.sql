@@ -0,0 +1,109 @@
+-- ============================================================================
+-- Migration: V005__add_article_language.sql
+-- Description: Per-article language, language-aware full-text search and cached translations
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-04
+-- Dependencies: V001__create_base_schema.sql
+-- ============================================================================
+
+-- ============================================================================
+-- FUNCTIONS
+-- ============================================================================
+
+-- Function: Map an ISO 639-1 language code to a text-search configuration.
+-- Must match internal/language.SearchConfig. Unknown languages use 'simple'.
+-- NULL (not yet detected) is treated as Dutch, the historical default.
+CREATE OR REPLACE FUNCTION article_search_config(p_language TEXT)
+RETURNS regconfig AS $$
+    SELECT CASE COALESCE(p_language, 'nl')
+        WHEN 'nl' THEN 'pg_catalog.dutch'::regconfig
+        WHEN 'en' THEN 'pg_catalog.english'::regconfig
+        WHEN 'de' THEN 'pg_catalog.german'::regconfig
+        WHEN 'fr' THEN 'pg_catalog.french'::regconfig
+        WHEN 'es' THEN 'pg_catalog.spanish'::regconfig
+        ELSE 'pg_catalog.simple'::regconfig
+    END;
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+COMMENT ON FUNCTION article_search_config IS 'Text-search configuration for an article language code';
+
+-- ============================================================================
+-- ARTICLES: LANGUAGE + SEARCH VECTOR
+-- ============================================================================
+
+-- NULL until detected; new articles are detected on ingest
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS language VARCHAR(8);
+
+-- Title + summary stemmed with the article's own language
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector(article_search_config(language), title || ' ' || COALESCE(summary, ''))
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector
+    ON articles USING GIN(search_vector);
+
+CREATE INDEX IF NOT EXISTS idx_articles_language
+    ON articles(language, published DESC);
+
+CREATE INDEX IF NOT EXISTS idx_articles_language_pending
+    ON articles(id) WHERE language IS NULL;
+
+COMMENT ON COLUMN articles.language IS 'Detected ISO 639-1 language code (NULL = not yet detected)';
+COMMENT ON COLUMN articles.search_vector IS 'Full-text vector of title and summary using article_search_config(language)';
+
+-- English-only indexes on title/summary are superseded by search_vector
+DROP INDEX IF EXISTS idx_articles_title_fts;
+DROP INDEX IF EXISTS idx_articles_summary_fts;
+DROP INDEX IF EXISTS idx_articles_combined_fts;
+
+-- ============================================================================
+-- ARTICLE TRANSLATIONS TABLE (machine translation cache)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS article_translations (
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    language VARCHAR(8) NOT NULL,
+
+    title TEXT NOT NULL,
+    summary TEXT,
+
+    source_language VARCHAR(8),
+    model VARCHAR(100),
+    -- Hash of the source title+summary; a changed article invalidates its translation
+    source_hash VARCHAR(64) NOT NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (article_id, language)
+);
+
+CREATE TRIGGER trg_article_translations_updated_at
+    BEFORE UPDATE ON article_translations
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+COMMENT ON TABLE article_translations IS 'Machine translations of article title and summary, per target language';
+
+-- ============================================================================
+-- FINALIZE MIGRATION
+-- ============================================================================
+
+-- Record migration
+INSERT INTO schema_migrations (version, description, checksum)
+VALUES (
+    'V005',
+    'Add article language, language-aware full-text search and translation cache',
+    'article_language_v1'
+) ON CONFLICT (version) DO NOTHING;
+
+-- Success notification
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Migration V005 completed successfully';
+    RAISE NOTICE 'Added columns: articles.language, articles.search_vector';
+    RAISE NOTICE 'Created table: article_translations';
+    RAISE NOTICE 'NOTE: Run the language backfill (POST /api/v1/articles/languages/backfill) to detect existing articles';
+END $$;
@@ -0,0 +1,77 @@
+-- ============================================================================
+-- Migration: V006__create_api_keys.sql
+-- Description: Per-client API keys with scopes, quotas, expiry and rotation
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-05
+-- Dependencies: V001__create_base_schema.sql
+-- ============================================================================
+
+-- ============================================================================
+-- API KEYS TABLE
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGSERIAL PRIMARY KEY,
+
+    -- Identification (the raw key is never stored)
+    key_prefix VARCHAR(16) NOT NULL UNIQUE, -- Shown in listings, e.g. inn_3f9a1c2b
+    key_hash VARCHAR(64) NOT NULL UNIQUE,   -- SHA-256 of the full key (hex)
+
+    -- Ownership
+    name VARCHAR(100) NOT NULL,
+    owner VARCHAR(200) NOT NULL,
+
+    -- Authorization
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    daily_quota INTEGER NOT NULL DEFAULT 0 CHECK (daily_quota >= 0), -- 0 = unlimited
+
+    -- Lifecycle
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    last_used_ip VARCHAR(45),
+    revoked_at TIMESTAMPTZ,
+    revoked_reason TEXT,
+    rotated_from BIGINT REFERENCES api_keys(id) ON DELETE SET NULL,
+
+    created_by VARCHAR(100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_owner
+    ON api_keys(owner);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_active
+    ON api_keys(created_at DESC) WHERE revoked_at IS NULL;
+
+CREATE TRIGGER trg_api_keys_updated_at
+    BEFORE UPDATE ON api_keys
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+COMMENT ON TABLE api_keys IS 'Per-client API keys; only a SHA-256 hash of each key is stored';
+COMMENT ON COLUMN api_keys.key_prefix IS 'Non-secret key prefix used to identify a key in listings and logs';
+COMMENT ON COLUMN api_keys.scopes IS 'Granted scopes, e.g. read:articles, write:scrape, admin:config';
+COMMENT ON COLUMN api_keys.daily_quota IS 'Maximum requests per UTC day (0 = unlimited)';
+COMMENT ON COLUMN api_keys.rotated_from IS 'Key this key replaced during rotation';
+
+-- ============================================================================
+-- FINALIZE MIGRATION
+-- ============================================================================
+
+-- Record migration
+INSERT INTO schema_migrations (version, description, checksum)
+VALUES (
+    'V006',
+    'Create per-client API keys with scopes, quotas, expiry and rotation',
+    'api_keys_v1'
+) ON CONFLICT (version) DO NOTHING;
+
+-- Success notification
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Migration V006 completed successfully';
+    RAISE NOTICE 'Created table: api_keys';
+    RAISE NOTICE 'NOTE: Issue client keys with POST /api/v1/admin/api-keys using the shared API_KEY';
+END $$;
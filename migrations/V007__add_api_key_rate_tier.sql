@@ -0,0 +1,37 @@
+-- ============================================================================
+-- Migration: V007__add_api_key_rate_tier.sql
+-- Description: Rate limit tier per API key
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-05
+-- Dependencies: V006__create_api_keys.sql
+-- ============================================================================
+
+-- ============================================================================
+-- RATE TIER COLUMN
+-- ============================================================================
+
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS rate_tier VARCHAR(20) NOT NULL DEFAULT 'standard'
+    CHECK (rate_tier IN ('standard', 'premium', 'unlimited'));
+
+COMMENT ON COLUMN api_keys.rate_tier IS 'Rate limit tier: standard, premium or unlimited (limits set by API_RATE_LIMIT_* settings)';
+
+-- ============================================================================
+-- FINALIZE MIGRATION
+-- ============================================================================
+
+-- Record migration
+INSERT INTO schema_migrations (version, description, checksum)
+VALUES (
+    'V007',
+    'Add rate limit tier to API keys',
+    'api_key_rate_tier_v1'
+) ON CONFLICT (version) DO NOTHING;
+
+-- Success notification
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Migration V007 completed successfully';
+    RAISE NOTICE 'Added column: api_keys.rate_tier (existing keys use the standard tier)';
+END $$;
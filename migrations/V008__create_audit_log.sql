@@ -0,0 +1,96 @@
+-- ============================================================================
+-- Migration: V008__create_audit_log.sql
+-- Description: Append-only audit log for administrative operations
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-05
+-- Dependencies: V001__create_base_schema.sql
+-- ============================================================================
+
+-- ============================================================================
+-- AUDIT LOG TABLE
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- Who
+    actor_type VARCHAR(20) NOT NULL, -- api_key, user, shared_key, anonymous
+    actor_id VARCHAR(255),           -- API key ID or JWT subject
+    actor_name VARCHAR(255),
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+
+    -- What
+    action VARCHAR(100) NOT NULL,    -- e.g. config.setting.update, cache.invalidate
+    resource VARCHAR(255),           -- e.g. profile name, setting, cache pattern
+    before_value JSONB,
+    after_value JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'success' CHECK (status IN ('success', 'failure')),
+    error_message TEXT,
+
+    -- Request context
+    request_id VARCHAR(100),
+    http_method VARCHAR(10),
+    path TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_occurred_at
+    ON audit_log(occurred_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_action
+    ON audit_log(action, occurred_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor
+    ON audit_log(actor_id, occurred_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_request_id
+    ON audit_log(request_id) WHERE request_id IS NOT NULL;
+
+COMMENT ON TABLE audit_log IS 'Append-only record of administrative operations (config, scraping, cache, API keys)';
+COMMENT ON COLUMN audit_log.before_value IS 'State before the operation, NULL when not applicable';
+COMMENT ON COLUMN audit_log.after_value IS 'State after the operation or the requested change';
+
+-- ============================================================================
+-- APPEND-ONLY ENFORCEMENT
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION audit_log_prevent_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only (% not allowed)', TG_OP;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION audit_log_prevent_modification() IS 'Rejects UPDATE, DELETE and TRUNCATE on audit_log';
+
+CREATE TRIGGER trg_audit_log_no_update
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW
+    EXECUTE FUNCTION audit_log_prevent_modification();
+
+CREATE TRIGGER trg_audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION audit_log_prevent_modification();
+
+-- ============================================================================
+-- FINALIZE MIGRATION
+-- ============================================================================
+
+-- Record migration
+INSERT INTO schema_migrations (version, description, checksum)
+VALUES (
+    'V008',
+    'Create append-only audit log for administrative operations',
+    'audit_log_v1'
+) ON CONFLICT (version) DO NOTHING;
+
+-- Success notification
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Migration V008 completed successfully';
+    RAISE NOTICE 'Created table: audit_log (append-only)';
+    RAISE NOTICE 'Query with GET /api/v1/admin/audit';
+END $$;
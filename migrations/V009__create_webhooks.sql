@@ -0,0 +1,110 @@
+-- ============================================================================
+-- Migration: V009__create_webhooks.sql
+-- Description: Outgoing webhook subscriptions and their delivery log
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-06
+-- Dependencies: V001__create_base_schema.sql
+-- ============================================================================
+
+-- ============================================================================
+-- WEBHOOK SUBSCRIPTIONS
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    owner VARCHAR(255),
+    url TEXT NOT NULL,
+    secret VARCHAR(100) NOT NULL,                -- HMAC-SHA256 signing secret
+
+    -- Triggers and filters (empty array = no filter)
+    events TEXT[] NOT NULL DEFAULT '{}',         -- article.created, article.enriched, scrape.failed
+    sources TEXT[] NOT NULL DEFAULT '{}',
+    keywords TEXT[] NOT NULL DEFAULT '{}',
+    entities TEXT[] NOT NULL DEFAULT '{}',
+    tickers TEXT[] NOT NULL DEFAULT '{}',
+    min_sentiment NUMERIC(4,3),                  -- -1.0 .. 1.0
+    max_sentiment NUMERIC(4,3),
+
+    -- State
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMPTZ,
+    disabled_reason TEXT,
+    last_delivery_at TIMESTAMPTZ,
+    last_success_at TIMESTAMPTZ,
+
+    created_by VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chk_webhook_sentiment_range CHECK (
+        (min_sentiment IS NULL OR min_sentiment BETWEEN -1 AND 1) AND
+        (max_sentiment IS NULL OR max_sentiment BETWEEN -1 AND 1)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_active
+    ON webhook_subscriptions(active) WHERE active = TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_owner
+    ON webhook_subscriptions(owner);
+
+COMMENT ON TABLE webhook_subscriptions IS 'Partner webhook endpoints notified of new articles, enrichments and scrape failures';
+COMMENT ON COLUMN webhook_subscriptions.consecutive_failures IS 'Failed delivery attempts since the last success; the subscription is disabled at WEBHOOK_DISABLE_AFTER_FAILURES';
+
+-- ============================================================================
+-- DELIVERY LOG
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,                      -- Same for every attempt; receivers deduplicate on it
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'delivering', 'retrying', 'succeeded', 'failed', 'cancelled')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    response_status INTEGER,
+    response_body TEXT,                          -- Truncated
+    error_message TEXT,
+    duration_ms INTEGER,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+    ON webhook_deliveries(next_attempt_at)
+    WHERE status IN ('pending', 'delivering', 'retrying');
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription
+    ON webhook_deliveries(subscription_id, created_at DESC);
+
+COMMENT ON TABLE webhook_deliveries IS 'Per-subscription delivery log with retry state';
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'When the delivery is due; for status delivering it is the lease expiry';
+
+-- ============================================================================
+-- FINALIZE MIGRATION
+-- ============================================================================
+
+-- Record migration
+INSERT INTO schema_migrations (version, description, checksum)
+VALUES (
+    'V009',
+    'Create webhook subscriptions and delivery log',
+    'webhooks_v1'
+) ON CONFLICT (version) DO NOTHING;
+
+-- Success notification
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Migration V009 completed successfully';
+    RAISE NOTICE 'Created tables: webhook_subscriptions, webhook_deliveries';
+    RAISE NOTICE 'Manage with /api/v1/admin/webhooks';
+END $$;
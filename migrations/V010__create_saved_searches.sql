@@ -0,0 +1,76 @@
+-- ============================================================================
+-- Migration: V010__create_saved_searches.sql
+-- Description: Saved article searches and the alerts raised for them
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-07
+-- Dependencies: V001__create_base_schema.sql, V009__create_webhooks.sql
+-- ============================================================================
+
+-- ============================================================================
+-- SAVED SEARCHES
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id BIGSERIAL PRIMARY KEY,
+    owner VARCHAR(255) NOT NULL DEFAULT '',      -- user:<sub>, key:<id> or '' when auth is disabled
+    name VARCHAR(100) NOT NULL,
+    query JSONB NOT NULL,                        -- Article filter plus entity, ticker and sentiment range
+
+    -- Alerting (empty channels = no alerts)
+    alert_channels TEXT[] NOT NULL DEFAULT '{}', -- webhook, email, stream
+    webhook_subscription_id BIGINT REFERENCES webhook_subscriptions(id) ON DELETE SET NULL,
+    email VARCHAR(255),                          -- Digest recipient
+
+    last_viewed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_matched_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uq_saved_searches_owner_name UNIQUE (owner, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_alerting
+    ON saved_searches(id) WHERE alert_channels <> '{}';
+
+COMMENT ON TABLE saved_searches IS 'Named article queries evaluated against new and enriched articles';
+COMMENT ON COLUMN saved_searches.last_viewed_at IS 'Results created after this are counted as new';
+
+-- ============================================================================
+-- MATCHES
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+    saved_search_id BIGINT NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    matched_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    emailed_at TIMESTAMPTZ,                      -- Set once included in an email digest
+
+    PRIMARY KEY (saved_search_id, article_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_search_matches_digest
+    ON saved_search_matches(saved_search_id, matched_at)
+    WHERE emailed_at IS NULL;
+
+COMMENT ON TABLE saved_search_matches IS 'Articles that raised an alert for a saved search; one row per article so alerts fire once';
+
+-- ============================================================================
+-- FINALIZE MIGRATION
+-- ============================================================================
+
+-- Record migration
+INSERT INTO schema_migrations (version, description, checksum)
+VALUES (
+    'V010',
+    'Create saved searches and alert matches',
+    'saved_searches_v1'
+) ON CONFLICT (version) DO NOTHING;
+
+-- Success notification
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Migration V010 completed successfully';
+    RAISE NOTICE 'Created tables: saved_searches, saved_search_matches';
+    RAISE NOTICE 'Manage with /api/v1/saved-searches';
+END $$;
@@ -0,0 +1,41 @@
+-- ============================================================================
+-- Migration: V011__add_article_scrape_job.sql
+-- Description: Link articles to the scrape job that stored them for log correlation
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-08
+-- Dependencies: V001__create_base_schema.sql
+-- ============================================================================
+
+-- ============================================================================
+-- ARTICLES: SCRAPE JOB
+-- ============================================================================
+
+-- No foreign key: scrapes continue when the job record could not be created, and old
+-- jobs may be cleaned up while their articles are kept
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS scrape_job_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_articles_scrape_job
+    ON articles(scrape_job_id) WHERE scrape_job_id IS NOT NULL;
+
+COMMENT ON COLUMN articles.scrape_job_id IS 'scraping_jobs.job_uuid of the scrape that stored the article (NULL for email and manual articles)';
+
+-- ============================================================================
+-- FINALIZE MIGRATION
+-- ============================================================================
+
+-- Record migration
+INSERT INTO schema_migrations (version, description, checksum)
+VALUES (
+    'V011',
+    'Add scrape job reference to articles',
+    'article_scrape_job_v1'
+) ON CONFLICT (version) DO NOTHING;
+
+-- Success notification
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Migration V011 completed successfully';
+    RAISE NOTICE 'Added column: articles.scrape_job_id';
+    RAISE NOTICE 'AI enrichment logs now carry the job_id of the scrape that stored the article';
+END $$;
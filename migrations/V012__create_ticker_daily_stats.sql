@@ -0,0 +1,60 @@
+-- ============================================================================
+-- Migration: V012__create_ticker_daily_stats.sql
+-- Description: Daily news volume, sentiment and price returns per stock ticker
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-09
+-- Dependencies: V001__create_base_schema.sql
+-- ============================================================================
+
+-- ============================================================================
+-- TICKER DAILY STATS
+-- ============================================================================
+
+-- One row per ticker and UTC day. News columns are aggregated from articles.ai_stock_tickers,
+-- price columns from the stock provider's historical prices (NULL on non-trading days).
+CREATE TABLE IF NOT EXISTS ticker_daily_stats (
+    symbol VARCHAR(20) NOT NULL,
+    day DATE NOT NULL,
+
+    -- News
+    article_count INT NOT NULL DEFAULT 0,
+    positive_count INT NOT NULL DEFAULT 0,
+    negative_count INT NOT NULL DEFAULT 0,
+    avg_sentiment DOUBLE PRECISION,             -- NULL without sentiment-scored articles
+    news_updated_at TIMESTAMPTZ,
+
+    -- Prices
+    close_price DOUBLE PRECISION,               -- Adjusted close
+    daily_return DOUBLE PRECISION,              -- Close over the previous trading day's close, minus 1
+    price_updated_at TIMESTAMPTZ,
+
+    PRIMARY KEY (symbol, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ticker_daily_stats_news
+    ON ticker_daily_stats(day DESC, article_count DESC)
+    WHERE article_count > 0;
+
+COMMENT ON TABLE ticker_daily_stats IS 'Daily news volume and sentiment per ticker joined with daily price returns';
+COMMENT ON COLUMN ticker_daily_stats.daily_return IS 'Simple return of the adjusted close versus the previous trading day';
+
+-- ============================================================================
+-- FINALIZE MIGRATION
+-- ============================================================================
+
+-- Record migration
+INSERT INTO schema_migrations (version, description, checksum)
+VALUES (
+    'V012',
+    'Create ticker daily news sentiment and price return series',
+    'ticker_daily_stats_v1'
+) ON CONFLICT (version) DO NOTHING;
+
+-- Success notification
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Migration V012 completed successfully';
+    RAISE NOTICE 'Created table: ticker_daily_stats';
+    RAISE NOTICE 'Filled per ticker by GET /api/v1/stocks/sentiment/:symbol';
+END $$;
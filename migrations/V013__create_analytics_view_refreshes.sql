@@ -0,0 +1,60 @@
+-- ============================================================================
+-- Migration: V013__create_analytics_view_refreshes.sql
+-- Description: Track refreshes of the analytics materialized views
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-09
+-- Dependencies: V003__create_analytics_views.sql, V004__create_entity_registry.sql
+-- ============================================================================
+
+-- ============================================================================
+-- ANALYTICS VIEW REFRESHES
+-- ============================================================================
+
+-- One row per materialized view managed by the refresh coordinator. The coordinator holds
+-- a Postgres advisory lock while refreshing, so replicas and manual triggers never refresh
+-- concurrently; each view is refreshed when its configured interval has passed.
+CREATE TABLE IF NOT EXISTS analytics_view_refreshes (
+    view_name VARCHAR(100) PRIMARY KEY,
+
+    -- Last successful refresh
+    last_refreshed_at TIMESTAMPTZ,              -- Start of the refresh: data is as of this time
+    last_duration_ms INTEGER,
+    last_row_count BIGINT,
+    refresh_count BIGINT NOT NULL DEFAULT 0,
+
+    -- Last attempt
+    last_attempt_at TIMESTAMPTZ,
+    last_error TEXT,                            -- NULL when the last attempt succeeded
+    refreshed_by VARCHAR(255),                  -- Host of the last attempt
+
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE analytics_view_refreshes IS 'Last refresh time, duration and outcome per analytics materialized view';
+COMMENT ON COLUMN analytics_view_refreshes.last_refreshed_at IS 'Start of the last successful refresh, reported as data_as_of by the analytics API';
+
+-- Managed views, refreshed on the first coordinator run
+INSERT INTO analytics_view_refreshes (view_name)
+VALUES ('mv_trending_keywords'), ('mv_sentiment_timeline'), ('mv_entity_mentions')
+ON CONFLICT (view_name) DO NOTHING;
+
+-- ============================================================================
+-- FINALIZE MIGRATION
+-- ============================================================================
+
+-- Record migration
+INSERT INTO schema_migrations (version, description, checksum)
+VALUES (
+    'V013',
+    'Create analytics materialized view refresh tracking',
+    'analytics_view_refreshes_v1'
+) ON CONFLICT (version) DO NOTHING;
+
+-- Success notification
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Migration V013 completed successfully';
+    RAISE NOTICE 'Created table: analytics_view_refreshes';
+    RAISE NOTICE 'Refresh cadence per view: ANALYTICS_*_REFRESH_MINUTES';
+END $$;
@@ -0,0 +1,47 @@
+-- ============================================================================
+-- Migration: V014__create_entity_merge_candidates.sql
+-- Description: Queue of entity pairs that may be the same and await review
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-10
+-- Dependencies: V004__create_entity_registry.sql
+-- ============================================================================
+
+-- ============================================================================
+-- ENTITY MERGE CANDIDATES
+-- ============================================================================
+
+-- Pairs the resolver could not link on its own, e.g. a new full name ("Lisa Rutte") whose
+-- surname is already claimed by an entity that carries another full name. Reviewed through
+-- GET /api/v1/entities/merge-candidates and merged with POST /api/v1/entities/merge.
+CREATE TABLE IF NOT EXISTS entity_merge_candidates (
+    id BIGSERIAL PRIMARY KEY,
+    entity_id BIGINT NOT NULL REFERENCES entities(id) ON DELETE CASCADE,    -- The newly created entity
+    candidate_id BIGINT NOT NULL REFERENCES entities(id) ON DELETE CASCADE, -- The entity it may duplicate
+    reason VARCHAR(50) NOT NULL,                                            -- Why the pair was queued, e.g. 'surname'
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT entity_merge_candidates_unique UNIQUE (entity_id, candidate_id)
+);
+
+COMMENT ON TABLE entity_merge_candidates IS 'Entity pairs that may refer to the same entity, awaiting a manual merge or dismissal';
+
+-- ============================================================================
+-- FINALIZE MIGRATION
+-- ============================================================================
+
+-- Record migration
+INSERT INTO schema_migrations (version, description, checksum)
+VALUES (
+    'V014',
+    'Create entity merge candidate queue',
+    'entity_merge_candidates_v1'
+) ON CONFLICT (version) DO NOTHING;
+
+-- Success notification
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Migration V014 completed successfully';
+    RAISE NOTICE 'Created table: entity_merge_candidates';
+    RAISE NOTICE 'Review with GET /api/v1/entities/merge-candidates';
+END $$;
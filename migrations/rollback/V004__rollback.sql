@@ -0,0 +1,143 @@
+-- ============================================================================
+-- Rollback Script: V004__create_entity_registry.sql
+-- Description: Rollback canonical entity registry
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-03
+-- WARNING: This will delete all canonical entities, aliases and merge history
+-- ============================================================================
+
+-- Confirm before execution
+DO $$
+BEGIN
+    RAISE NOTICE '⚠️  WARNING: This rollback will DROP the entity registry!';
+    RAISE NOTICE 'All manual merges, splits and imported aliases will be lost';
+    RAISE NOTICE 'Press Ctrl+C within 5 seconds to cancel...';
+    PERFORM pg_sleep(5);
+END $$;
+
+-- ============================================================================
+-- RESTORE V003 ENTITY ANALYTICS (raw entity strings)
+-- ============================================================================
+
+DROP MATERIALIZED VIEW IF EXISTS mv_entity_mentions CASCADE;
+
+CREATE MATERIALIZED VIEW mv_entity_mentions AS
+WITH entity_extraction AS (
+    SELECT 
+        a.id AS article_id,
+        a.source,
+        a.published,
+        a.ai_sentiment,
+        a.category,
+        DATE_TRUNC('day', a.published) AS day_bucket,
+        entity_type,
+        entity_value
+    FROM articles a
+    CROSS JOIN LATERAL (
+        SELECT 'person' AS entity_type, jsonb_array_elements_text(a.ai_entities->'persons') AS entity_value
+        UNION ALL
+        SELECT 'organization', jsonb_array_elements_text(a.ai_entities->'organizations')
+        UNION ALL
+        SELECT 'location', jsonb_array_elements_text(a.ai_entities->'locations')
+    ) entities
+    WHERE a.ai_processed = TRUE
+      AND a.ai_entities IS NOT NULL
+      AND a.published >= CURRENT_TIMESTAMP - INTERVAL '90 days'
+)
+SELECT 
+    entity_value AS entity,
+    entity_type,
+    day_bucket,
+    COUNT(DISTINCT article_id) AS mention_count,
+    COUNT(DISTINCT source) AS source_count,
+    ARRAY_AGG(DISTINCT source ORDER BY source) AS sources,
+    ARRAY_AGG(DISTINCT category ORDER BY category) FILTER (WHERE category IS NOT NULL) AS categories,
+    ROUND(AVG(ai_sentiment)::NUMERIC, 3) FILTER (WHERE ai_sentiment IS NOT NULL) AS avg_sentiment,
+    MAX(published) AS last_mentioned,
+    ARRAY_AGG(article_id ORDER BY published DESC) AS recent_article_ids
+FROM entity_extraction
+GROUP BY entity_value, entity_type, day_bucket
+HAVING COUNT(DISTINCT article_id) >= 2;
+
+CREATE UNIQUE INDEX idx_mv_entity_mentions_unique
+    ON mv_entity_mentions(entity, entity_type, day_bucket);
+CREATE INDEX idx_mv_entity_mentions_entity ON mv_entity_mentions(entity);
+CREATE INDEX idx_mv_entity_mentions_type ON mv_entity_mentions(entity_type, mention_count DESC);
+CREATE INDEX idx_mv_entity_mentions_count ON mv_entity_mentions(mention_count DESC);
+CREATE INDEX idx_mv_entity_mentions_day ON mv_entity_mentions(day_bucket DESC);
+CREATE INDEX idx_mv_entity_mentions_last_mentioned ON mv_entity_mentions(last_mentioned DESC);
+
+CREATE OR REPLACE VIEW v_hot_entities_7d AS
+SELECT 
+    entity,
+    entity_type,
+    SUM(mention_count) AS total_mentions,
+    COUNT(DISTINCT day_bucket) AS days_mentioned,
+    ARRAY_AGG(DISTINCT unnest ORDER BY unnest) AS all_sources,
+    ROUND(AVG(avg_sentiment)::NUMERIC, 3) AS overall_sentiment,
+    MAX(last_mentioned) AS most_recent_mention
+FROM mv_entity_mentions
+CROSS JOIN LATERAL unnest(sources) AS unnest
+WHERE day_bucket >= CURRENT_DATE - INTERVAL '7 days'
+GROUP BY entity, entity_type
+HAVING SUM(mention_count) >= 5
+ORDER BY total_mentions DESC, days_mentioned DESC
+LIMIT 100;
+
+CREATE OR REPLACE FUNCTION get_entity_sentiment_analysis(
+    p_entity TEXT,
+    p_days_back INTEGER DEFAULT 30
+)
+RETURNS TABLE (
+    day DATE,
+    mention_count BIGINT,
+    avg_sentiment NUMERIC,
+    sources TEXT[],
+    categories TEXT[]
+) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT 
+        em.day_bucket::DATE AS day,
+        SUM(em.mention_count)::BIGINT AS mention_count,
+        ROUND(AVG(em.avg_sentiment)::NUMERIC, 3) AS avg_sentiment,
+        ARRAY_AGG(DISTINCT unnest ORDER BY unnest) AS sources,
+        ARRAY_AGG(DISTINCT cat ORDER BY cat) FILTER (WHERE cat IS NOT NULL) AS categories
+    FROM mv_entity_mentions em
+    CROSS JOIN LATERAL unnest(em.sources) AS unnest
+    CROSS JOIN LATERAL unnest(em.categories) AS cat
+    WHERE LOWER(em.entity) = LOWER(p_entity)
+      AND em.day_bucket >= CURRENT_DATE - (p_days_back || ' days')::INTERVAL
+    GROUP BY em.day_bucket
+    ORDER BY day DESC;
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+REFRESH MATERIALIZED VIEW mv_entity_mentions;
+
+-- ============================================================================
+-- DROP ENTITY REGISTRY
+-- ============================================================================
+
+DROP FUNCTION IF EXISTS resolve_entity_id(TEXT) CASCADE;
+DROP TABLE IF EXISTS article_entities CASCADE;
+DROP TABLE IF EXISTS entity_aliases CASCADE;
+DROP TABLE IF EXISTS entities CASCADE;
+
+-- ============================================================================
+-- REMOVE MIGRATION RECORD
+-- ============================================================================
+
+DELETE FROM schema_migrations WHERE version = 'V004';
+
+-- ============================================================================
+-- FINALIZE ROLLBACK
+-- ============================================================================
+
+DO $$ 
+BEGIN 
+    RAISE NOTICE '✅ Rollback V004 completed successfully';
+    RAISE NOTICE 'Entity analytics restored to raw entity strings';
+    RAISE NOTICE 'Database is now in post-V003 state';
+END $$;
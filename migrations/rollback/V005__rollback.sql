@@ -0,0 +1,62 @@
+-- ============================================================================
+-- Rollback Script: V005__add_article_language.sql
+-- Description: Rollback article language, language-aware search and translations
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-04
+-- WARNING: This will delete detected languages and all cached translations
+-- ============================================================================
+
+-- Confirm before execution
+DO $$
+BEGIN
+    RAISE NOTICE '⚠️  WARNING: This rollback will DROP article languages and translations!';
+    RAISE NOTICE 'Search falls back to the English text-search configuration';
+    RAISE NOTICE 'Press Ctrl+C within 5 seconds to cancel...';
+    PERFORM pg_sleep(5);
+END $$;
+
+-- ============================================================================
+-- DROP TRANSLATIONS
+-- ============================================================================
+
+DROP TABLE IF EXISTS article_translations CASCADE;
+
+-- ============================================================================
+-- DROP LANGUAGE COLUMNS
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_articles_search_vector;
+DROP INDEX IF EXISTS idx_articles_language;
+DROP INDEX IF EXISTS idx_articles_language_pending;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE articles DROP COLUMN IF EXISTS language;
+
+DROP FUNCTION IF EXISTS article_search_config(TEXT);
+
+-- ============================================================================
+-- RESTORE V001 FULL-TEXT INDEXES
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_articles_title_fts ON articles USING GIN(to_tsvector('english', title));
+CREATE INDEX IF NOT EXISTS idx_articles_summary_fts ON articles USING GIN(to_tsvector('english', COALESCE(summary, '')));
+CREATE INDEX IF NOT EXISTS idx_articles_combined_fts ON articles USING GIN(
+    to_tsvector('english', title || ' ' || COALESCE(summary, '') || ' ' || COALESCE(content, ''))
+);
+
+-- ============================================================================
+-- REMOVE MIGRATION RECORD
+-- ============================================================================
+
+DELETE FROM schema_migrations WHERE version = 'V005';
+
+-- ============================================================================
+-- FINALIZE ROLLBACK
+-- ============================================================================
+
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Rollback V005 completed successfully';
+    RAISE NOTICE 'Database is now in post-V004 state';
+END $$;
@@ -0,0 +1,39 @@
+-- ============================================================================
+-- Rollback Script: V006__create_api_keys.sql
+-- Description: Rollback per-client API keys
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-05
+-- WARNING: This will delete all issued API keys; clients fall back to the shared API_KEY
+-- ============================================================================
+
+-- Confirm before execution
+DO $$
+BEGIN
+    RAISE NOTICE '⚠️  WARNING: This rollback will DROP all issued API keys!';
+    RAISE NOTICE 'Every client except holders of the shared API_KEY loses access';
+    RAISE NOTICE 'Press Ctrl+C within 5 seconds to cancel...';
+    PERFORM pg_sleep(5);
+END $$;
+
+-- ============================================================================
+-- DROP API KEYS
+-- ============================================================================
+
+DROP TABLE IF EXISTS api_keys CASCADE;
+
+-- ============================================================================
+-- REMOVE MIGRATION RECORD
+-- ============================================================================
+
+DELETE FROM schema_migrations WHERE version = 'V006';
+
+-- ============================================================================
+-- FINALIZE ROLLBACK
+-- ============================================================================
+
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Rollback V006 completed successfully';
+    RAISE NOTICE 'Database is now in post-V005 state';
+END $$;
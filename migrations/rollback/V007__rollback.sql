@@ -0,0 +1,39 @@
+-- ============================================================================
+-- Rollback Script: V007__add_api_key_rate_tier.sql
+-- Description: Rollback API key rate tiers
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-05
+-- WARNING: All keys fall back to the standard rate limit tier
+-- ============================================================================
+
+-- Confirm before execution
+DO $$
+BEGIN
+    RAISE NOTICE '⚠️  WARNING: This rollback will DROP api_keys.rate_tier!';
+    RAISE NOTICE 'Premium and unlimited keys lose their tier';
+    RAISE NOTICE 'Press Ctrl+C within 5 seconds to cancel...';
+    PERFORM pg_sleep(5);
+END $$;
+
+-- ============================================================================
+-- DROP RATE TIER
+-- ============================================================================
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS rate_tier;
+
+-- ============================================================================
+-- REMOVE MIGRATION RECORD
+-- ============================================================================
+
+DELETE FROM schema_migrations WHERE version = 'V007';
+
+-- ============================================================================
+-- FINALIZE ROLLBACK
+-- ============================================================================
+
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Rollback V007 completed successfully';
+    RAISE NOTICE 'Database is now in post-V006 state';
+END $$;
@@ -0,0 +1,40 @@
+-- ============================================================================
+-- Rollback Script: V008__create_audit_log.sql
+-- Description: Rollback the audit log
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-05
+-- WARNING: This will permanently delete the audit trail
+-- ============================================================================
+
+-- Confirm before execution
+DO $$
+BEGIN
+    RAISE NOTICE '⚠️  WARNING: This rollback will DROP the audit log!';
+    RAISE NOTICE 'All recorded administrative operations will be lost';
+    RAISE NOTICE 'Press Ctrl+C within 5 seconds to cancel...';
+    PERFORM pg_sleep(5);
+END $$;
+
+-- ============================================================================
+-- DROP AUDIT LOG
+-- ============================================================================
+
+DROP TABLE IF EXISTS audit_log CASCADE;
+DROP FUNCTION IF EXISTS audit_log_prevent_modification() CASCADE;
+
+-- ============================================================================
+-- REMOVE MIGRATION RECORD
+-- ============================================================================
+
+DELETE FROM schema_migrations WHERE version = 'V008';
+
+-- ============================================================================
+-- FINALIZE ROLLBACK
+-- ============================================================================
+
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Rollback V008 completed successfully';
+    RAISE NOTICE 'Database is now in post-V007 state';
+END $$;
@@ -0,0 +1,40 @@
+-- ============================================================================
+-- Rollback Script: V009__create_webhooks.sql
+-- Description: Rollback webhook subscriptions and delivery log
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-06
+-- WARNING: This will permanently delete all webhook subscriptions and deliveries
+-- ============================================================================
+
+-- Confirm before execution
+DO $$
+BEGIN
+    RAISE NOTICE '⚠️  WARNING: This rollback will DROP all webhook subscriptions!';
+    RAISE NOTICE 'Partners will stop receiving notifications and the delivery log will be lost';
+    RAISE NOTICE 'Press Ctrl+C within 5 seconds to cancel...';
+    PERFORM pg_sleep(5);
+END $$;
+
+-- ============================================================================
+-- DROP WEBHOOK TABLES
+-- ============================================================================
+
+DROP TABLE IF EXISTS webhook_deliveries CASCADE;
+DROP TABLE IF EXISTS webhook_subscriptions CASCADE;
+
+-- ============================================================================
+-- REMOVE MIGRATION RECORD
+-- ============================================================================
+
+DELETE FROM schema_migrations WHERE version = 'V009';
+
+-- ============================================================================
+-- FINALIZE ROLLBACK
+-- ============================================================================
+
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Rollback V009 completed successfully';
+    RAISE NOTICE 'Database is now in post-V008 state';
+END $$;
@@ -0,0 +1,40 @@
+-- ============================================================================
+-- Rollback Script: V010__create_saved_searches.sql
+-- Description: Rollback saved searches and alert matches
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-07
+-- WARNING: This will permanently delete all saved searches and their alerts
+-- ============================================================================
+
+-- Confirm before execution
+DO $$
+BEGIN
+    RAISE NOTICE '⚠️  WARNING: This rollback will DROP all saved searches!';
+    RAISE NOTICE 'Users lose their saved queries and no further alerts are raised';
+    RAISE NOTICE 'Press Ctrl+C within 5 seconds to cancel...';
+    PERFORM pg_sleep(5);
+END $$;
+
+-- ============================================================================
+-- DROP SAVED SEARCH TABLES
+-- ============================================================================
+
+DROP TABLE IF EXISTS saved_search_matches CASCADE;
+DROP TABLE IF EXISTS saved_searches CASCADE;
+
+-- ============================================================================
+-- REMOVE MIGRATION RECORD
+-- ============================================================================
+
+DELETE FROM schema_migrations WHERE version = 'V010';
+
+-- ============================================================================
+-- FINALIZE ROLLBACK
+-- ============================================================================
+
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Rollback V010 completed successfully';
+    RAISE NOTICE 'Database is now in post-V009 state';
+END $$;
@@ -0,0 +1,40 @@
+-- ============================================================================
+-- Rollback Script: V011__add_article_scrape_job.sql
+-- Description: Rollback the scrape job reference on articles
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-08
+-- WARNING: This will delete the link between articles and their scrape jobs
+-- ============================================================================
+
+-- Confirm before execution
+DO $$
+BEGIN
+    RAISE NOTICE '⚠️  WARNING: This rollback will DROP articles.scrape_job_id!';
+    RAISE NOTICE 'AI enrichment logs can no longer be correlated with scrape jobs';
+    RAISE NOTICE 'Press Ctrl+C within 5 seconds to cancel...';
+    PERFORM pg_sleep(5);
+END $$;
+
+-- ============================================================================
+-- DROP SCRAPE JOB COLUMN
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_articles_scrape_job;
+ALTER TABLE articles DROP COLUMN IF EXISTS scrape_job_id;
+
+-- ============================================================================
+-- REMOVE MIGRATION RECORD
+-- ============================================================================
+
+DELETE FROM schema_migrations WHERE version = 'V011';
+
+-- ============================================================================
+-- FINALIZE ROLLBACK
+-- ============================================================================
+
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Rollback V011 completed successfully';
+    RAISE NOTICE 'Database is now in post-V010 state';
+END $$;
@@ -0,0 +1,39 @@
+-- ============================================================================
+-- Rollback Script: V012__create_ticker_daily_stats.sql
+-- Description: Rollback the ticker daily news sentiment and price return series
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-09
+-- WARNING: This will delete the persisted ticker series (rebuilt on demand)
+-- ============================================================================
+
+-- Confirm before execution
+DO $$
+BEGIN
+    RAISE NOTICE '⚠️  WARNING: This rollback will DROP ticker_daily_stats!';
+    RAISE NOTICE 'Sentiment versus price analysis stops working';
+    RAISE NOTICE 'Press Ctrl+C within 5 seconds to cancel...';
+    PERFORM pg_sleep(5);
+END $$;
+
+-- ============================================================================
+-- DROP TICKER DAILY STATS
+-- ============================================================================
+
+DROP TABLE IF EXISTS ticker_daily_stats CASCADE;
+
+-- ============================================================================
+-- REMOVE MIGRATION RECORD
+-- ============================================================================
+
+DELETE FROM schema_migrations WHERE version = 'V012';
+
+-- ============================================================================
+-- FINALIZE ROLLBACK
+-- ============================================================================
+
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Rollback V012 completed successfully';
+    RAISE NOTICE 'Database is now in post-V011 state';
+END $$;
@@ -0,0 +1,39 @@
+-- ============================================================================
+-- Rollback Script: V013__create_analytics_view_refreshes.sql
+-- Description: Rollback the analytics materialized view refresh tracking
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-09
+-- WARNING: This will delete the refresh history of the analytics views
+-- ============================================================================
+
+-- Confirm before execution
+DO $$
+BEGIN
+    RAISE NOTICE '⚠️  WARNING: This rollback will DROP analytics_view_refreshes!';
+    RAISE NOTICE 'Scheduled analytics refreshes and data_as_of stop working';
+    RAISE NOTICE 'Press Ctrl+C within 5 seconds to cancel...';
+    PERFORM pg_sleep(5);
+END $$;
+
+-- ============================================================================
+-- DROP ANALYTICS VIEW REFRESHES
+-- ============================================================================
+
+DROP TABLE IF EXISTS analytics_view_refreshes CASCADE;
+
+-- ============================================================================
+-- REMOVE MIGRATION RECORD
+-- ============================================================================
+
+DELETE FROM schema_migrations WHERE version = 'V013';
+
+-- ============================================================================
+-- FINALIZE ROLLBACK
+-- ============================================================================
+
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Rollback V013 completed successfully';
+    RAISE NOTICE 'Database is now in post-V012 state';
+END $$;
@@ -0,0 +1,39 @@
+-- ============================================================================
+-- Rollback Script: V014__create_entity_merge_candidates.sql
+-- Description: Rollback the entity merge candidate queue
+-- Version: 1.0.0
+-- Author: NieuwsScraper Team
+-- Date: 2025-11-10
+-- WARNING: This will delete all queued merge candidates
+-- ============================================================================
+
+-- Confirm before execution
+DO $$
+BEGIN
+    RAISE NOTICE '⚠️  WARNING: This rollback will DROP entity_merge_candidates!';
+    RAISE NOTICE 'Entities auto-created from full names are no longer queued for review';
+    RAISE NOTICE 'Press Ctrl+C within 5 seconds to cancel...';
+    PERFORM pg_sleep(5);
+END $$;
+
+-- ============================================================================
+-- DROP ENTITY MERGE CANDIDATES
+-- ============================================================================
+
+DROP TABLE IF EXISTS entity_merge_candidates CASCADE;
+
+-- ============================================================================
+-- REMOVE MIGRATION RECORD
+-- ============================================================================
+
+DELETE FROM schema_migrations WHERE version = 'V014';
+
+-- ============================================================================
+-- FINALIZE ROLLBACK
+-- ============================================================================
+
+DO $$
+BEGIN
+    RAISE NOTICE '✅ Rollback V014 completed successfully';
+    RAISE NOTICE 'Database is now in post-V013 state';
+END $$;
@@ -10,16 +10,24 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	NATS     NATSConfig
-	Scraper  ScraperConfig
-	API      APIConfig
-	Logging  LoggingConfig
-	AI       AIConfig
-	Stock    StockConfig
-	Email    EmailConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	NATS      NATSConfig
+	Scraper   ScraperConfig
+	API       APIConfig
+	Logging   LoggingConfig
+	AI        AIConfig
+	Stock     StockConfig
+	Email     EmailConfig
+	Entity    EntityConfig
+	Auth      AuthConfig
+	Stream    StreamConfig
+	Webhook   WebhookConfig
+	Alerts    AlertConfig
+	Tracing   TracingConfig
+	Analytics AnalyticsConfig
+	Leader    LeaderConfig
 }
 
 // ServerConfig holds server-specific configuration
@@ -94,11 +102,15 @@ type ScraperConfig struct {
 
 // APIConfig holds API-specific configuration
 type APIConfig struct {
-	RateLimitRequests      int
+	RateLimitRequests      int // standard tier
 	RateLimitWindowSeconds int
+	RateLimitAnonymous     int
+	RateLimitPremium       int
+	RateLimitRouteCosts    string // "pattern=cost,..." e.g. "/api/v1/ai/chat=20"
 	TimeoutSeconds         int
 	APIKeyHeader           string
 	APIKey                 string
+	RequireKeyForReads     bool
 	EnableMetrics          bool
 	MetricsPort            int
 }
@@ -115,6 +127,7 @@ type AIConfig struct {
 	OpenAIAPIKey    string
 	OpenAIModel     string
 	OpenAIMaxTokens int
+	OpenAIBaseURL   string
 
 	// Processing settings
 	Enabled         bool
@@ -125,12 +138,13 @@ type AIConfig struct {
 	MaxRetries      int
 
 	// Feature toggles
-	EnableSentiment  bool
-	EnableEntities   bool
-	EnableCategories bool
-	EnableKeywords   bool
-	EnableSummary    bool
-	EnableSimilarity bool
+	EnableSentiment   bool
+	EnableEntities    bool
+	EnableCategories  bool
+	EnableKeywords    bool
+	EnableSummary     bool
+	EnableSimilarity  bool
+	EnableTranslation bool
 
 	// Cost control
 	MaxDailyCost       float64
@@ -146,6 +160,13 @@ type StockConfig struct {
 	RateLimitPerMin int
 	Timeout         time.Duration
 	EnableCache     bool
+
+	// Ticker validation against the local symbol master
+	SymbolMasterPath    string // Written on refresh; keep it outside version control
+	SymbolSeedPath      string // Loaded while SymbolMasterPath does not exist yet
+	TickerPolicy        string // "flag" or "drop"
+	TickerMinConfidence float64
+	SymbolAutoRefresh   bool
 }
 
 // EmailConfig holds email integration configuration
@@ -166,6 +187,84 @@ type EmailConfig struct {
 	MaxDaysBack     int  // How many days back to fetch (default: 30)
 }
 
+// EntityConfig holds canonical entity registry configuration
+type EntityConfig struct {
+	DumpPath   string // Local Wikidata-style dump (JSON Lines or JSON array), optional
+	AutoCreate bool   // Create new entities for unknown mentions
+}
+
+// AuthConfig holds end-user JWT/OIDC bearer token configuration
+type AuthConfig struct {
+	JWTIssuer       string // Enables bearer tokens; JWKS is discovered from the issuer unless set below
+	JWTAudience     string
+	JWKSURL         string
+	JWKSFile        string
+	JWTRoleClaim    string // Dotted path, e.g. "roles" or "realm_access.roles"
+	JWTAdminRole    string
+	JWTUserScopes   []string
+	JWTRateTier     string
+	JWKSRefreshTTL  time.Duration
+	JWTClockSkewSec int
+}
+
+// StreamConfig holds live article stream (SSE/WebSocket) configuration
+type StreamConfig struct {
+	Enabled     bool
+	HistorySize int           // Events kept for Last-Event-ID resume
+	MaxClients  int           // Concurrent clients per instance, 0 = unlimited
+	Heartbeat   time.Duration // Keep-alive interval
+}
+
+// WebhookConfig holds outgoing webhook delivery configuration
+type WebhookConfig struct {
+	Enabled       bool
+	Workers       int           // Concurrent deliveries
+	MaxAttempts   int           // Attempts per delivery before it is marked failed
+	DisableAfter  int           // Consecutive failed attempts before a subscription is disabled, 0 = never
+	Timeout       time.Duration // HTTP timeout per attempt
+	AllowPrivate  bool          // Allow endpoints on private networks (development only)
+	RetentionDays int           // Delivery log retention
+}
+
+// AlertConfig holds saved search alert configuration
+type AlertConfig struct {
+	Enabled        bool
+	DigestInterval time.Duration // How often email digests are sent
+	SMTPHost       string        // Email digests are disabled without a host
+	SMTPPort       int
+	SMTPUsername   string
+	SMTPPassword   string
+	SMTPFrom       string
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Enabled      bool
+	OTLPEndpoint string  // OTLP/HTTP collector URL, e.g. http://localhost:4318
+	ServiceName  string  // service.name resource attribute
+	SampleRatio  float64 // Fraction of new traces recorded; remote parent decisions are honoured
+}
+
+// AnalyticsConfig holds materialized view refresh intervals
+type AnalyticsConfig struct {
+	TrendingRefresh  time.Duration // mv_trending_keywords
+	SentimentRefresh time.Duration // mv_sentiment_timeline
+	EntityRefresh    time.Duration // mv_entity_mentions
+}
+
+// LeaderConfig holds leader election configuration. The leader is the one instance that
+// runs the scheduler and the background processors.
+type LeaderConfig struct {
+	Enabled       bool          // When false this instance always runs the background workers
+	InstanceID    string        // Reported as leader; defaults to hostname-pid
+	RenewInterval time.Duration // Leader lock check and follower retry interval
+}
+
+// EmailEnabled reports whether email digests can be sent
+func (c AlertConfig) EmailEnabled() bool {
+	return c.SMTPHost != "" && c.SMTPFrom != ""
+}
+
 // Load reads configuration from environment variables and .env file
 func Load() (*Config, error) {
 	v := viper.New()
@@ -246,9 +345,13 @@ func Load() (*Config, error) {
 		API: APIConfig{
 			RateLimitRequests:      v.GetInt("API_RATE_LIMIT_REQUESTS"),
 			RateLimitWindowSeconds: v.GetInt("API_RATE_LIMIT_WINDOW_SECONDS"),
+			RateLimitAnonymous:     v.GetInt("API_RATE_LIMIT_ANONYMOUS_REQUESTS"),
+			RateLimitPremium:       v.GetInt("API_RATE_LIMIT_PREMIUM_REQUESTS"),
+			RateLimitRouteCosts:    v.GetString("API_RATE_LIMIT_ROUTE_COSTS"),
 			TimeoutSeconds:         v.GetInt("API_TIMEOUT_SECONDS"),
 			APIKeyHeader:           v.GetString("API_KEY_HEADER"),
 			APIKey:                 v.GetString("API_KEY"),
+			RequireKeyForReads:     v.GetBool("API_REQUIRE_KEY_FOR_READS"),
 			EnableMetrics:          v.GetBool("ENABLE_METRICS"),
 			MetricsPort:            v.GetInt("METRICS_PORT"),
 		},
@@ -260,6 +363,7 @@ func Load() (*Config, error) {
 			OpenAIAPIKey:       v.GetString("OPENAI_API_KEY"),
 			OpenAIModel:        v.GetString("OPENAI_MODEL"),
 			OpenAIMaxTokens:    v.GetInt("OPENAI_MAX_TOKENS"),
+			OpenAIBaseURL:      v.GetString("OPENAI_BASE_URL"),
 			Enabled:            v.GetBool("AI_ENABLED"),
 			AsyncProcessing:    v.GetBool("AI_ASYNC_PROCESSING"),
 			BatchSize:          v.GetInt("AI_BATCH_SIZE"),
@@ -272,6 +376,7 @@ func Load() (*Config, error) {
 			EnableKeywords:     v.GetBool("AI_ENABLE_KEYWORDS"),
 			EnableSummary:      v.GetBool("AI_ENABLE_SUMMARY"),
 			EnableSimilarity:   v.GetBool("AI_ENABLE_SIMILARITY"),
+			EnableTranslation:  v.GetBool("AI_ENABLE_TRANSLATION"),
 			MaxDailyCost:       v.GetFloat64("AI_MAX_DAILY_COST"),
 			RateLimitPerMinute: v.GetInt("AI_RATE_LIMIT_PER_MINUTE"),
 			Timeout:            time.Duration(v.GetInt("AI_TIMEOUT_SECONDS")) * time.Second,
@@ -283,6 +388,12 @@ func Load() (*Config, error) {
 			RateLimitPerMin: v.GetInt("STOCK_API_RATE_LIMIT_PER_MINUTE"),
 			Timeout:         time.Duration(v.GetInt("STOCK_API_TIMEOUT_SECONDS")) * time.Second,
 			EnableCache:     v.GetBool("STOCK_API_ENABLE_CACHE"),
+
+			SymbolMasterPath:    v.GetString("STOCK_SYMBOL_MASTER_PATH"),
+			SymbolSeedPath:      v.GetString("STOCK_SYMBOL_SEED_PATH"),
+			TickerPolicy:        v.GetString("STOCK_TICKER_POLICY"),
+			TickerMinConfidence: v.GetFloat64("STOCK_TICKER_MIN_CONFIDENCE"),
+			SymbolAutoRefresh:   v.GetBool("STOCK_SYMBOL_AUTO_REFRESH"),
 		},
 		Email: EmailConfig{
 			Enabled:         v.GetBool("EMAIL_ENABLED"),
@@ -300,6 +411,62 @@ func Load() (*Config, error) {
 			FetchExisting:   v.GetBool("EMAIL_FETCH_EXISTING"),
 			MaxDaysBack:     v.GetInt("EMAIL_MAX_DAYS_BACK"),
 		},
+		Entity: EntityConfig{
+			DumpPath:   v.GetString("ENTITY_DUMP_PATH"),
+			AutoCreate: v.GetBool("ENTITY_AUTO_CREATE"),
+		},
+		Auth: AuthConfig{
+			JWTIssuer:       v.GetString("JWT_ISSUER"),
+			JWTAudience:     v.GetString("JWT_AUDIENCE"),
+			JWKSURL:         v.GetString("JWT_JWKS_URL"),
+			JWKSFile:        v.GetString("JWT_JWKS_FILE"),
+			JWTRoleClaim:    v.GetString("JWT_ROLE_CLAIM"),
+			JWTAdminRole:    v.GetString("JWT_ADMIN_ROLE"),
+			JWTUserScopes:   splitList(v.GetString("JWT_USER_SCOPES")),
+			JWTRateTier:     v.GetString("JWT_RATE_TIER"),
+			JWKSRefreshTTL:  time.Duration(v.GetInt("JWT_JWKS_REFRESH_MINUTES")) * time.Minute,
+			JWTClockSkewSec: v.GetInt("JWT_CLOCK_SKEW_SECONDS"),
+		},
+		Stream: StreamConfig{
+			Enabled:     v.GetBool("STREAM_ENABLED"),
+			HistorySize: v.GetInt("STREAM_HISTORY_SIZE"),
+			MaxClients:  v.GetInt("STREAM_MAX_CLIENTS"),
+			Heartbeat:   time.Duration(v.GetInt("STREAM_HEARTBEAT_SECONDS")) * time.Second,
+		},
+		Webhook: WebhookConfig{
+			Enabled:       v.GetBool("WEBHOOK_ENABLED"),
+			Workers:       v.GetInt("WEBHOOK_WORKERS"),
+			MaxAttempts:   v.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			DisableAfter:  v.GetInt("WEBHOOK_DISABLE_AFTER_FAILURES"),
+			Timeout:       time.Duration(v.GetInt("WEBHOOK_TIMEOUT_SECONDS")) * time.Second,
+			AllowPrivate:  v.GetBool("WEBHOOK_ALLOW_PRIVATE_URLS"),
+			RetentionDays: v.GetInt("WEBHOOK_DELIVERY_RETENTION_DAYS"),
+		},
+		Alerts: AlertConfig{
+			Enabled:        v.GetBool("ALERTS_ENABLED"),
+			DigestInterval: time.Duration(v.GetInt("ALERT_DIGEST_INTERVAL_MINUTES")) * time.Minute,
+			SMTPHost:       v.GetString("SMTP_HOST"),
+			SMTPPort:       v.GetInt("SMTP_PORT"),
+			SMTPUsername:   v.GetString("SMTP_USERNAME"),
+			SMTPPassword:   v.GetString("SMTP_PASSWORD"),
+			SMTPFrom:       v.GetString("SMTP_FROM"),
+		},
+		Tracing: TracingConfig{
+			Enabled:      v.GetBool("TRACING_ENABLED"),
+			OTLPEndpoint: v.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"),
+			ServiceName:  v.GetString("OTEL_SERVICE_NAME"),
+			SampleRatio:  v.GetFloat64("TRACING_SAMPLE_RATIO"),
+		},
+		Analytics: AnalyticsConfig{
+			TrendingRefresh:  time.Duration(v.GetInt("ANALYTICS_TRENDING_REFRESH_MINUTES")) * time.Minute,
+			SentimentRefresh: time.Duration(v.GetInt("ANALYTICS_SENTIMENT_REFRESH_MINUTES")) * time.Minute,
+			EntityRefresh:    time.Duration(v.GetInt("ANALYTICS_ENTITY_REFRESH_MINUTES")) * time.Minute,
+		},
+		Leader: LeaderConfig{
+			Enabled:       v.GetBool("LEADER_ELECTION_ENABLED"),
+			InstanceID:    v.GetString("LEADER_INSTANCE_ID"),
+			RenewInterval: time.Duration(v.GetInt("LEADER_RENEW_SECONDS")) * time.Second,
+		},
 	}
 
 	return cfg, nil
@@ -350,8 +517,12 @@ func setDefaults(v *viper.Viper) {
 	// API defaults
 	v.SetDefault("API_RATE_LIMIT_REQUESTS", 100)
 	v.SetDefault("API_RATE_LIMIT_WINDOW_SECONDS", 60)
+	v.SetDefault("API_RATE_LIMIT_ANONYMOUS_REQUESTS", 60)
+	v.SetDefault("API_RATE_LIMIT_PREMIUM_REQUESTS", 1000)
+	v.SetDefault("API_RATE_LIMIT_ROUTE_COSTS", "/api/v1/ai/chat=20,/api/v1/ai/process=10,/api/v1/scrape=10,/api/v1/articles/*/extract-content=5")
 	v.SetDefault("API_TIMEOUT_SECONDS", 30)
 	v.SetDefault("API_KEY_HEADER", "X-API-Key")
+	v.SetDefault("API_REQUIRE_KEY_FOR_READS", false)
 	v.SetDefault("ENABLE_METRICS", true)
 	v.SetDefault("METRICS_PORT", 9090)
 
@@ -374,6 +545,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("AI_ENABLE_KEYWORDS", true)
 	v.SetDefault("AI_ENABLE_SUMMARY", false)
 	v.SetDefault("AI_ENABLE_SIMILARITY", false)
+	v.SetDefault("AI_ENABLE_TRANSLATION", true)
 	v.SetDefault("AI_MAX_DAILY_COST", 10.0)
 	v.SetDefault("AI_RATE_LIMIT_PER_MINUTE", 60)
 	v.SetDefault("AI_TIMEOUT_SECONDS", 30)
@@ -407,6 +579,11 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("STOCK_API_RATE_LIMIT_PER_MINUTE", 30)
 	v.SetDefault("STOCK_API_TIMEOUT_SECONDS", 10)
 	v.SetDefault("STOCK_API_ENABLE_CACHE", true)
+	v.SetDefault("STOCK_SYMBOL_MASTER_PATH", "./var/symbol_master.csv")
+	v.SetDefault("STOCK_SYMBOL_SEED_PATH", "./data/symbol_master.csv")
+	v.SetDefault("STOCK_TICKER_POLICY", "flag")
+	v.SetDefault("STOCK_TICKER_MIN_CONFIDENCE", 0.5)
+	v.SetDefault("STOCK_SYMBOL_AUTO_REFRESH", false)
 
 	// Email defaults
 	v.SetDefault("EMAIL_ENABLED", false)
@@ -421,6 +598,73 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("EMAIL_DELETE_AFTER_READ", false)
 	v.SetDefault("EMAIL_FETCH_EXISTING", true)
 	v.SetDefault("EMAIL_MAX_DAYS_BACK", 30)
+
+	// Entity registry defaults
+	v.SetDefault("ENTITY_DUMP_PATH", "")
+	v.SetDefault("ENTITY_AUTO_CREATE", true)
+
+	// JWT/OIDC defaults (disabled until JWT_ISSUER is set)
+	v.SetDefault("JWT_ISSUER", "")
+	v.SetDefault("JWT_AUDIENCE", "")
+	v.SetDefault("JWT_JWKS_URL", "")
+	v.SetDefault("JWT_JWKS_FILE", "")
+	v.SetDefault("JWT_ROLE_CLAIM", "roles")
+	v.SetDefault("JWT_ADMIN_ROLE", "admin")
+	v.SetDefault("JWT_USER_SCOPES", "read:articles,ai:chat,searches:save")
+	v.SetDefault("JWT_RATE_TIER", "standard")
+	v.SetDefault("JWT_JWKS_REFRESH_MINUTES", 60)
+	v.SetDefault("JWT_CLOCK_SKEW_SECONDS", 60)
+
+	// Live stream defaults
+	v.SetDefault("STREAM_ENABLED", true)
+	v.SetDefault("STREAM_HISTORY_SIZE", 1000)
+	v.SetDefault("STREAM_MAX_CLIENTS", 1000)
+	v.SetDefault("STREAM_HEARTBEAT_SECONDS", 25)
+
+	// Webhook defaults
+	v.SetDefault("WEBHOOK_ENABLED", true)
+	v.SetDefault("WEBHOOK_WORKERS", 4)
+	v.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	v.SetDefault("WEBHOOK_DISABLE_AFTER_FAILURES", 15)
+	v.SetDefault("WEBHOOK_TIMEOUT_SECONDS", 10)
+	v.SetDefault("WEBHOOK_ALLOW_PRIVATE_URLS", false)
+	v.SetDefault("WEBHOOK_DELIVERY_RETENTION_DAYS", 30)
+
+	// Saved search alert defaults
+	v.SetDefault("ALERTS_ENABLED", true)
+	v.SetDefault("ALERT_DIGEST_INTERVAL_MINUTES", 60)
+	v.SetDefault("SMTP_HOST", "")
+	v.SetDefault("SMTP_PORT", 587)
+	v.SetDefault("SMTP_USERNAME", "")
+	v.SetDefault("SMTP_PASSWORD", "")
+	v.SetDefault("SMTP_FROM", "")
+
+	// Tracing defaults (local collector in development)
+	v.SetDefault("TRACING_ENABLED", false)
+	v.SetDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	v.SetDefault("OTEL_SERVICE_NAME", "intellinieuws-api")
+	v.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+
+	// Analytics materialized view refresh intervals
+	v.SetDefault("ANALYTICS_TRENDING_REFRESH_MINUTES", 5)
+	v.SetDefault("ANALYTICS_SENTIMENT_REFRESH_MINUTES", 15)
+	v.SetDefault("ANALYTICS_ENTITY_REFRESH_MINUTES", 15)
+
+	// Leader election for background workers
+	v.SetDefault("LEADER_ELECTION_ENABLED", true)
+	v.SetDefault("LEADER_INSTANCE_ID", "")
+	v.SetDefault("LEADER_RENEW_SECONDS", 5)
+}
+
+// splitList splits a comma-separated setting, dropping empty entries
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 // GetDSN returns PostgreSQL connection string
@@ -461,6 +705,11 @@ func (c *APIConfig) GetRateLimitWindow() time.Duration {
 	return time.Duration(c.RateLimitWindowSeconds) * time.Second
 }
 
+// JWTEnabled reports whether bearer tokens are accepted
+func (c *AuthConfig) JWTEnabled() bool {
+	return c.JWTIssuer != ""
+}
+
 // IsDevelopment checks if running in development mode
 func (c *ServerConfig) IsDevelopment() bool {
 	return c.Environment == "development"
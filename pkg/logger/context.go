@@ -0,0 +1,42 @@
+package logger
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying l. The API stores a request-scoped logger
+// here and background jobs store one with their job ID, so services called with ctx log
+// the same correlation IDs, including from goroutines started with a derived context.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns fallback with the request, trace and job IDs of the logger in ctx
+// added. Services pass their component logger as fallback so log lines keep their
+// component. Without a logger in ctx, fallback is returned unchanged.
+func FromContext(ctx context.Context, fallback *Logger) *Logger {
+	scoped, ok := ctx.Value(contextKey{}).(*Logger)
+	if !ok || scoped == nil {
+		return fallback
+	}
+	if fallback == nil {
+		return scoped
+	}
+
+	l := fallback
+	for _, f := range scoped.correlation {
+		if !l.hasCorrelation(f.key) {
+			l = l.withCorrelation(f.key, f.value)
+		}
+	}
+	return l
+}
+
+func (l *Logger) hasCorrelation(key string) bool {
+	for _, f := range l.correlation {
+		if f.key == key {
+			return true
+		}
+	}
+	return false
+}
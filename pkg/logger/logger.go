@@ -11,6 +11,15 @@ import (
 // Logger wraps zerolog.Logger with additional functionality
 type Logger struct {
 	*zerolog.Logger
+
+	// correlation holds the request, trace and job IDs of this logger so FromContext
+	// can copy them onto component loggers
+	correlation []field
+}
+
+type field struct {
+	key   string
+	value string
 }
 
 // Config holds logger configuration
@@ -25,7 +34,7 @@ func New(cfg Config) *Logger {
 	level := parseLevel(cfg.Level)
 	zerolog.SetGlobalLevel(level)
 
-	// Configure output format
+	// Configure output format. Secrets are scrubbed from every line before it is written.
 	var output io.Writer = os.Stdout
 	if cfg.Format == "console" {
 		output = zerolog.ConsoleWriter{
@@ -34,6 +43,7 @@ func New(cfg Config) *Logger {
 			NoColor:    false,
 		}
 	}
+	output = &redactingWriter{out: output}
 
 	// Create logger
 	zlog := zerolog.New(output).
@@ -64,19 +74,50 @@ func parseLevel(level string) zerolog.Level {
 // WithComponent adds a component field to the logger
 func (l *Logger) WithComponent(component string) *Logger {
 	newLogger := l.Logger.With().Str("component", component).Logger()
-	return &Logger{Logger: &newLogger}
+	return &Logger{Logger: &newLogger, correlation: l.correlation}
 }
 
 // WithRequestID adds a request ID field to the logger
 func (l *Logger) WithRequestID(requestID string) *Logger {
-	newLogger := l.Logger.With().Str("request_id", requestID).Logger()
-	return &Logger{Logger: &newLogger}
+	return l.withCorrelation("request_id", requestID)
+}
+
+// WithTraceID adds the OpenTelemetry trace ID so log lines can be matched to traces
+func (l *Logger) WithTraceID(traceID string) *Logger {
+	return l.withCorrelation("trace_id", traceID)
+}
+
+// WithJobID adds the UUID of the scrape job that started the work being logged
+func (l *Logger) WithJobID(jobID string) *Logger {
+	return l.withCorrelation("job_id", jobID)
+}
+
+// WithCorrelationID adds an ID tying together background work that has no request or
+// scrape job, e.g. an email processed into an article
+func (l *Logger) WithCorrelationID(correlationID string) *Logger {
+	return l.withCorrelation("correlation_id", correlationID)
+}
+
+// withCorrelation adds an ID field that FromContext carries over; empty values are ignored
+func (l *Logger) withCorrelation(key, value string) *Logger {
+	if value == "" {
+		return l
+	}
+	for _, f := range l.correlation {
+		if f.key == key && f.value == value {
+			return l
+		}
+	}
+	newLogger := l.Logger.With().Str(key, value).Logger()
+	correlation := make([]field, 0, len(l.correlation)+1)
+	correlation = append(correlation, l.correlation...)
+	return &Logger{Logger: &newLogger, correlation: append(correlation, field{key, value})}
 }
 
 // WithError adds an error field to the logger
 func (l *Logger) WithError(err error) *Logger {
 	newLogger := l.Logger.With().Err(err).Logger()
-	return &Logger{Logger: &newLogger}
+	return &Logger{Logger: &newLogger, correlation: l.correlation}
 }
 
 // WithFields adds multiple fields to the logger
@@ -86,7 +127,7 @@ func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
 		ctx = ctx.Interface(k, v)
 	}
 	newLogger := ctx.Logger()
-	return &Logger{Logger: &newLogger}
+	return &Logger{Logger: &newLogger, correlation: l.correlation}
 }
 
 // Debug logs a debug message
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"io"
+	"regexp"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	// secretParamPattern matches credentials in query strings, e.g. the FMP apikey that
+	// net/http includes in the URL of a failed request
+	secretParamPattern = regexp.MustCompile(`(?i)((?:api_?key|access_token|token|password|secret)=)[^&\s"\\]+`)
+	// bearerPattern matches Authorization header values
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+	// apiKeyPattern matches IntelliNieuws (inn_) and OpenAI (sk-) keys anywhere in a line
+	apiKeyPattern = regexp.MustCompile(`\b(inn_|sk-)[A-Za-z0-9_-]{8,}`)
+	// sensitiveFieldPattern matches JSON string fields holding credentials or email bodies
+	sensitiveFieldPattern = regexp.MustCompile(
+		`"((?i:api_?key|x-api-key|authorization|password|secret|token|body|body_text|body_html|email_body))":"(?:[^"\\]|\\.)*"`)
+)
+
+// Redact removes API keys, bearer tokens and other credentials from s
+func Redact(s string) string {
+	return string(redact([]byte(s)))
+}
+
+func redact(p []byte) []byte {
+	p = secretParamPattern.ReplaceAll(p, []byte("${1}"+redacted))
+	p = bearerPattern.ReplaceAll(p, []byte("${1}"+redacted))
+	p = apiKeyPattern.ReplaceAll(p, []byte("${1}"+redacted))
+	p = sensitiveFieldPattern.ReplaceAll(p, []byte(`"${1}":"`+redacted+`"`))
+	return p
+}
+
+// redactingWriter scrubs credentials and email bodies from JSON log lines, so a secret
+// that ends up in a message or error string is never written out
+type redactingWriter struct {
+	out io.Writer
+}
+
+// Write redacts p and reports the original length, as zerolog expects
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := w.out.Write(redact(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
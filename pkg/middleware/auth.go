@@ -1,14 +1,93 @@
 package middleware
 
 import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// Context locals set by APIKeyAuth
+const (
+	LocalAuthenticated = "authenticated"
+	LocalPrincipal     = "api_key"
+	LocalUserID        = "user_id"    // JWT subject, only for bearer tokens
+	LocalUserRoles     = "user_roles" // []string from the role claim
 )
 
-// APIKeyAuth middleware for API key authentication
+// Principal is the client authenticated by an API key or bearer token
+type Principal struct {
+	KeyID      int64 // 0 for the shared API_KEY and bearer tokens
+	Name       string
+	Owner      string
+	Scopes     []string
+	DailyQuota int    // requests per UTC day, 0 = unlimited
+	RateTier   string // rate limit tier, see Tier* constants
+	Legacy     bool   // authenticated with the shared API_KEY
+
+	// End users authenticated with a JWT
+	UserID string
+	Email  string
+	Roles  []string
+}
+
+// IsUser reports whether the principal is an end user authenticated with a bearer token
+func (p *Principal) IsUser() bool {
+	return p.UserID != ""
+}
+
+// HasRole reports whether a bearer token carried a role
+func (p *Principal) HasRole(role string) bool {
+	return containsString(p.Roles, role)
+}
+
+// HasScope reports whether the principal was granted a scope
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyStore resolves per-client API keys
+type KeyStore interface {
+	// Lookup returns the principal for a raw key, or nil when the key is unknown, expired or revoked
+	Lookup(ctx context.Context, rawKey string) (*Principal, error)
+	// Touch records that a key was used
+	Touch(keyID int64, ip string)
+}
+
+// BearerVerifier validates "Authorization: Bearer" tokens
+type BearerVerifier interface {
+	Verify(ctx context.Context, token string) (*Principal, error)
+}
+
+// PrincipalFromContext returns the authenticated principal, or nil for anonymous requests
+func PrincipalFromContext(c *fiber.Ctx) *Principal {
+	if p, ok := c.Locals(LocalPrincipal).(*Principal); ok {
+		return p
+	}
+	return nil
+}
+
+// APIKeyAuth middleware for API key authentication.
+// The shared API_KEY acts as a bootstrap admin key with every scope; per-client keys
+// come from an optional KeyStore.
 type APIKeyAuth struct {
-	apiKey       string
-	headerName   string
-	errorMessage string
+	apiKey             string
+	headerName         string
+	errorMessage       string
+	store              KeyStore
+	bearer             BearerVerifier
+	quota              *quotaCounter
+	requireKeyForReads bool
 }
 
 // NewAPIKeyAuth creates a new API key authentication middleware
@@ -21,28 +100,95 @@ func NewAPIKeyAuth(apiKey, headerName string) *APIKeyAuth {
 		apiKey:       apiKey,
 		headerName:   headerName,
 		errorMessage: "Invalid or missing API key",
+		quota:        newQuotaCounter(nil),
+	}
+}
+
+// SetKeyStore enables per-client API keys
+func (a *APIKeyAuth) SetKeyStore(store KeyStore) {
+	a.store = store
+}
+
+// SetBearerVerifier enables JWT bearer tokens for end users
+func (a *APIKeyAuth) SetBearerVerifier(verifier BearerVerifier) {
+	a.bearer = verifier
+}
+
+// SetQuotaRedis shares daily quota counters between instances through Redis
+func (a *APIKeyAuth) SetQuotaRedis(client *redis.Client) {
+	a.quota = newQuotaCounter(client)
+}
+
+// SetRequireKeyForReads makes public read endpoints require a key with read:articles
+func (a *APIKeyAuth) SetRequireKeyForReads(required bool) {
+	a.requireKeyForReads = required
+}
+
+// HeaderName returns the header carrying the API key
+func (a *APIKeyAuth) HeaderName() string {
+	return a.headerName
+}
+
+// enabled reports whether any credential is configured
+func (a *APIKeyAuth) enabled() bool {
+	return a.apiKey != "" || a.store != nil || a.bearer != nil
+}
+
+// hasCredentials reports whether the request carries an API key or bearer token
+func (a *APIKeyAuth) hasCredentials(c *fiber.Ctx) bool {
+	return c.Get(a.headerName) != "" || bearerToken(c) != ""
+}
+
+func bearerToken(c *fiber.Ctx) string {
+	header := c.Get(fiber.HeaderAuthorization)
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
 	}
+	return ""
+}
+
+// authFailure is a rejection decided while authenticating
+type authFailure struct {
+	status int
+	body   fiber.Map
+}
+
+func (f *authFailure) respond(c *fiber.Ctx) error {
+	return c.Status(f.status).JSON(f.body)
 }
 
-// Handler returns the Fiber middleware handler
+// Handler returns the Fiber middleware handler requiring any valid API key
 func (a *APIKeyAuth) Handler() fiber.Handler {
+	return a.Require()
+}
+
+// Require returns a middleware requiring a valid API key with all of the given scopes
+func (a *APIKeyAuth) Require(scopes ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Skip if no API key is configured
-		if a.apiKey == "" {
+		if !a.enabled() {
 			return c.Next()
 		}
 
-		// Get API key from header
-		providedKey := c.Get(a.headerName)
-
-		// Check if API key matches
-		if providedKey != a.apiKey {
+		principal, failure := a.authenticate(c)
+		if failure != nil {
+			return failure.respond(c)
+		}
+		if principal == nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error":   "Unauthorized",
 				"message": a.errorMessage,
 			})
 		}
 
+		if missing := missingScopes(principal, scopes); len(missing) > 0 {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":           "Forbidden",
+				"message":         "Credentials lack the required scope",
+				"required_scopes": missing,
+			})
+		}
+
 		return c.Next()
 	}
 }
@@ -50,22 +196,176 @@ func (a *APIKeyAuth) Handler() fiber.Handler {
 // Optional returns a middleware that allows requests without API key
 func (a *APIKeyAuth) Optional() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Get API key from header
-		providedKey := c.Get(a.headerName)
+		if !a.enabled() {
+			return c.Next()
+		}
+
+		// If a key or token is provided, validate it
+		if !a.hasCredentials(c) {
+			if a.requireKeyForReads {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error":   "Unauthorized",
+					"message": a.errorMessage,
+				})
+			}
+			return c.Next()
+		}
 
-		// If key is provided, validate it
-		if providedKey != "" && providedKey != a.apiKey {
+		principal, failure := a.authenticate(c)
+		if failure != nil {
+			return failure.respond(c)
+		}
+		if principal == nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error":   "Unauthorized",
 				"message": "Invalid API key",
 			})
 		}
 
-		// Set authenticated flag in context
-		if providedKey == a.apiKey {
-			c.Locals("authenticated", true)
+		if a.requireKeyForReads && !principal.HasScope(ScopeReadArticles) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":           "Forbidden",
+				"message":         "Credentials lack the required scope",
+				"required_scopes": []string{ScopeReadArticles},
+			})
 		}
 
 		return c.Next()
 	}
 }
+
+// authenticate resolves the request's credentials once per request and applies the daily quota.
+// API keys take precedence over bearer tokens. Returns a nil principal for missing or
+// invalid keys; a non-nil failure must be sent as the response.
+func (a *APIKeyAuth) authenticate(c *fiber.Ctx) (*Principal, *authFailure) {
+	if p := PrincipalFromContext(c); p != nil {
+		return p, nil
+	}
+
+	providedKey := c.Get(a.headerName)
+	if providedKey == "" {
+		return a.authenticateBearer(c)
+	}
+
+	var principal *Principal
+	if a.apiKey != "" && subtle.ConstantTimeCompare([]byte(providedKey), []byte(a.apiKey)) == 1 {
+		principal = &Principal{
+			Name:   "shared API_KEY",
+			Scopes: AllScopes(),
+			Legacy: true,
+		}
+	} else if a.store != nil {
+		p, err := a.store.Lookup(c.UserContext(), providedKey)
+		if err != nil {
+			return nil, &authFailure{status: fiber.StatusServiceUnavailable, body: fiber.Map{
+				"error":   "Service Unavailable",
+				"message": "API key verification failed",
+			}}
+		}
+		principal = p
+	}
+	if principal == nil {
+		return nil, nil
+	}
+
+	if principal.KeyID > 0 {
+		a.store.Touch(principal.KeyID, c.IP())
+
+		if principal.DailyQuota > 0 {
+			used := a.quota.incr(c.UserContext(), principal.KeyID)
+			remaining := int64(principal.DailyQuota) - used
+			if remaining < 0 {
+				remaining = 0
+			}
+			c.Set("X-Quota-Limit", fmt.Sprintf("%d", principal.DailyQuota))
+			c.Set("X-Quota-Remaining", fmt.Sprintf("%d", remaining))
+
+			if used > int64(principal.DailyQuota) {
+				return nil, &authFailure{status: fiber.StatusTooManyRequests, body: fiber.Map{
+					"error":   "Quota exceeded",
+					"message": fmt.Sprintf("Daily quota of %d requests exceeded for key %q", principal.DailyQuota, principal.Name),
+				}}
+			}
+		}
+	}
+
+	// Set authenticated flag in context
+	c.Locals(LocalAuthenticated, true)
+	c.Locals(LocalPrincipal, principal)
+
+	return principal, nil
+}
+
+// authenticateBearer validates a JWT; invalid tokens are rejected with the reason
+func (a *APIKeyAuth) authenticateBearer(c *fiber.Ctx) (*Principal, *authFailure) {
+	token := bearerToken(c)
+	if token == "" || a.bearer == nil {
+		return nil, nil
+	}
+
+	principal, err := a.bearer.Verify(c.UserContext(), token)
+	if err != nil {
+		return nil, &authFailure{status: fiber.StatusUnauthorized, body: fiber.Map{
+			"error":   "Unauthorized",
+			"message": "Invalid bearer token: " + err.Error(),
+		}}
+	}
+
+	c.Locals(LocalAuthenticated, true)
+	c.Locals(LocalPrincipal, principal)
+	c.Locals(LocalUserID, principal.UserID)
+	c.Locals(LocalUserRoles, principal.Roles)
+
+	return principal, nil
+}
+
+func missingScopes(p *Principal, required []string) []string {
+	missing := make([]string, 0)
+	for _, scope := range required {
+		if !p.HasScope(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// quotaCounter counts requests per key per UTC day, in Redis when available
+// and in memory otherwise (per instance)
+type quotaCounter struct {
+	redis  *redis.Client
+	mu     sync.Mutex
+	day    string
+	counts map[int64]int64
+}
+
+func newQuotaCounter(client *redis.Client) *quotaCounter {
+	return &quotaCounter{
+		redis:  client,
+		counts: make(map[int64]int64),
+	}
+}
+
+// incr increments and returns today's request count for a key
+func (q *quotaCounter) incr(ctx context.Context, keyID int64) int64 {
+	day := time.Now().UTC().Format("20060102")
+
+	if q.redis != nil {
+		key := fmt.Sprintf("api_quota:%d:%s", keyID, day)
+		pipe := q.redis.Pipeline()
+		incr := pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, 25*time.Hour)
+		if _, err := pipe.Exec(ctx); err == nil {
+			return incr.Val()
+		}
+		// Fall through to the in-memory counter when Redis is unavailable
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.day != day {
+		q.day = day
+		q.counts = make(map[int64]int64)
+	}
+	q.counts[keyID]++
+	return q.counts[keyID]
+}
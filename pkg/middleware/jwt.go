@@ -0,0 +1,509 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bearer token errors
+var (
+	ErrTokenMalformed = errors.New("malformed token")
+	ErrTokenSignature = errors.New("invalid token signature")
+	ErrTokenExpired   = errors.New("token expired")
+	ErrTokenClaims    = errors.New("invalid token claims")
+	ErrUnknownKey     = errors.New("unknown signing key")
+)
+
+// JWTConfig configures bearer token validation
+type JWTConfig struct {
+	JWKSURL    string   // JWKS endpoint; discovered from the issuer when empty
+	JWKSFile   string   // Local JWKS file, takes precedence over JWKSURL
+	Issuer     string   // Required "iss"
+	Audience   string   // Required "aud" (optional when empty)
+	RoleClaim  string   // Claim holding roles, dotted for nested claims (e.g. realm_access.roles)
+	AdminRole  string   // Role granted every scope
+	UserScopes []string // Scopes for authenticated users without the admin role
+	RateTier   string   // Rate limit tier for users
+	ClockSkew  time.Duration
+	RefreshTTL time.Duration // How long fetched keys are trusted before refetching
+}
+
+// JWTVerifier validates JWTs signed with keys from a JWKS (RS*, PS*, ES* and EdDSA).
+// Unknown key IDs trigger a refetch so IdP key rotation is picked up automatically.
+type JWTVerifier struct {
+	cfg    JWTConfig
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+// minRefetchInterval limits JWKS refetches triggered by unknown key IDs
+const minRefetchInterval = 30 * time.Second
+
+// NewJWTVerifier creates a verifier and loads the signing keys
+func NewJWTVerifier(ctx context.Context, cfg JWTConfig) (*JWTVerifier, error) {
+	if cfg.Issuer == "" {
+		return nil, errors.New("jwt issuer is required")
+	}
+	if cfg.RoleClaim == "" {
+		cfg.RoleClaim = "roles"
+	}
+	if cfg.AdminRole == "" {
+		cfg.AdminRole = "admin"
+	}
+	if cfg.RateTier == "" {
+		cfg.RateTier = TierStandard
+	}
+	if cfg.ClockSkew == 0 {
+		cfg.ClockSkew = time.Minute
+	}
+	if cfg.RefreshTTL == 0 {
+		cfg.RefreshTTL = time.Hour
+	}
+	for _, scope := range cfg.UserScopes {
+		if !IsValidScope(scope) {
+			return nil, fmt.Errorf("invalid user scope %q", scope)
+		}
+	}
+
+	v := &JWTVerifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		keys:   make(map[string]crypto.PublicKey),
+	}
+
+	if cfg.JWKSFile == "" && cfg.JWKSURL == "" {
+		uri, err := v.discoverJWKS(ctx)
+		if err != nil {
+			return nil, err
+		}
+		v.cfg.JWKSURL = uri
+	}
+
+	if err := v.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// KeyCount returns the number of loaded signing keys
+func (v *JWTVerifier) KeyCount() int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return len(v.keys)
+}
+
+// Verify validates a bearer token and returns the user principal
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	return v.principal(claims)
+}
+
+// principal validates registered claims and maps roles to scopes
+func (v *JWTVerifier) principal(claims map[string]interface{}) (*Principal, error) {
+	now := time.Now()
+
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing exp", ErrTokenClaims)
+	}
+	if now.After(exp.Add(v.cfg.ClockSkew)) {
+		return nil, ErrTokenExpired
+	}
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Add(v.cfg.ClockSkew).Before(nbf) {
+		return nil, fmt.Errorf("%w: token not yet valid", ErrTokenClaims)
+	}
+
+	if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != strings.TrimSuffix(v.cfg.Issuer, "/") {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrTokenClaims)
+	}
+	if v.cfg.Audience != "" && !containsString(stringList(claims["aud"]), v.cfg.Audience) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrTokenClaims)
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrTokenClaims)
+	}
+
+	roles := stringList(lookupClaim(claims, v.cfg.RoleClaim))
+	scopes := v.cfg.UserScopes
+	if containsString(roles, v.cfg.AdminRole) {
+		scopes = AllScopes()
+	}
+
+	name, _ := claims["name"].(string)
+	if name == "" {
+		name, _ = claims["preferred_username"].(string)
+	}
+	email, _ := claims["email"].(string)
+	if name == "" {
+		name = email
+	}
+	if name == "" {
+		name = subject
+	}
+
+	return &Principal{
+		Name:     name,
+		Owner:    subject,
+		Scopes:   scopes,
+		RateTier: v.cfg.RateTier,
+		UserID:   subject,
+		Email:    email,
+		Roles:    roles,
+	}, nil
+}
+
+// key returns the signing key for a key ID, refetching the JWKS for unknown IDs
+func (v *JWTVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := v.lookup(kid); ok && !v.stale() {
+		return key, nil
+	}
+
+	if err := v.refresh(ctx); err != nil {
+		// Keep serving known keys when the IdP is briefly unavailable
+		if key, ok := v.lookup(kid); ok {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+func (v *JWTVerifier) lookup(kid string) (crypto.PublicKey, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if kid != "" {
+		key, ok := v.keys[kid]
+		return key, ok
+	}
+	// Tokens without kid are only accepted when the JWKS holds a single key
+	if len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func (v *JWTVerifier) stale() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return time.Since(v.fetchedAt) > v.cfg.RefreshTTL
+}
+
+// refresh reloads the JWKS, at most once per minRefetchInterval
+func (v *JWTVerifier) refresh(ctx context.Context) error {
+	v.mu.Lock()
+	if !v.lastAttempt.IsZero() && time.Since(v.lastAttempt) < minRefetchInterval {
+		v.mu.Unlock()
+		return nil
+	}
+	v.lastAttempt = time.Now()
+	v.mu.Unlock()
+
+	var data []byte
+	var err error
+	if v.cfg.JWKSFile != "" {
+		data, err = os.ReadFile(v.cfg.JWKSFile)
+	} else {
+		data, err = v.fetch(ctx, v.cfg.JWKSURL)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load JWKS: %w", err)
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+	return nil
+}
+
+// discoverJWKS reads jwks_uri from the issuer's OpenID Connect discovery document
+func (v *JWTVerifier) discoverJWKS(ctx context.Context) (string, error) {
+	url := strings.TrimSuffix(v.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	data, err := v.fetch(ctx, url)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover JWKS: %w", err)
+	}
+
+	var doc struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil || doc.JWKSURI == "" {
+		return "", fmt.Errorf("failed to discover JWKS: no jwks_uri in %s", url)
+	}
+	return doc.JWKSURI, nil
+}
+
+func (v *JWTVerifier) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// parseJWKS parses the signing keys in a JWK set; unsupported keys are skipped
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			Crv string `json:"crv"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		switch jwk.Kty {
+		case "RSA":
+			n, errN := decodeBigInt(jwk.N)
+			e, errE := decodeBigInt(jwk.E)
+			if errN != nil || errE != nil || !e.IsInt64() {
+				continue
+			}
+			keys[jwk.Kid] = &rsa.PublicKey{N: n, E: int(e.Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch jwk.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				continue
+			}
+			x, errX := decodeBigInt(jwk.X)
+			y, errY := decodeBigInt(jwk.Y)
+			if errX != nil || errY != nil || !curve.IsOnCurve(x, y) {
+				continue
+			}
+			keys[jwk.Kid] = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		case "OKP":
+			x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+			if jwk.Crv != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+				continue
+			}
+			keys[jwk.Kid] = ed25519.PublicKey(x)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS contains no usable signing keys")
+	}
+	return keys, nil
+}
+
+// verifySignature checks a JWS signature; "none" and HMAC algorithms are rejected
+func verifySignature(alg string, key crypto.PublicKey, signed, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		hash = crypto.SHA512
+	case "EdDSA":
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(pub, signed, signature) {
+			return ErrTokenSignature
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrTokenSignature, alg)
+	}
+
+	digest := digestFor(hash, signed)
+
+	switch alg[:2] {
+	case "RS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPKCS1v15(pub, hash, digest, signature) != nil {
+			return ErrTokenSignature
+		}
+	case "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPSS(pub, hash, digest, signature, nil) != nil {
+			return ErrTokenSignature
+		}
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return ErrTokenSignature
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return ErrTokenSignature
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return ErrTokenSignature
+		}
+	}
+	return nil
+}
+
+func digestFor(hash crypto.Hash, data []byte) []byte {
+	switch hash {
+	case crypto.SHA384:
+		sum := sha512.Sum384(data)
+		return sum[:]
+	case crypto.SHA512:
+		sum := sha512.Sum512(data)
+		return sum[:]
+	default:
+		sum := sha256.Sum256(data)
+		return sum[:]
+	}
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(data) == 0 {
+		return nil, ErrTokenMalformed
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+func numericClaim(claims map[string]interface{}, name string) (time.Time, bool) {
+	value, ok := claims[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(value), 0), true
+}
+
+// lookupClaim resolves a dotted claim path such as realm_access.roles
+func lookupClaim(claims map[string]interface{}, path string) interface{} {
+	var current interface{} = claims
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+// stringList accepts a JSON string array, a single string or a space-separated list
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
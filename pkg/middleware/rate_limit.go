@@ -3,81 +3,328 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/redis/go-redis/v9"
 )
 
-// RateLimiter middleware for API rate limiting using Redis
+// Rate limit tiers. Per-client keys carry their tier; anonymous clients are limited per IP.
+const (
+	TierAnonymous = "anonymous"
+	TierStandard  = "standard"
+	TierPremium   = "premium"
+	TierUnlimited = "unlimited"
+)
+
+// IsValidRateTier reports whether a tier can be assigned to an API key
+func IsValidRateTier(tier string) bool {
+	switch tier {
+	case TierStandard, TierPremium, TierUnlimited:
+		return true
+	}
+	return false
+}
+
+// rateLimitScript implements GCRA (a token bucket tracked as a single "theoretical
+// arrival time") so limits slide smoothly instead of resetting at window edges.
+// Redis TIME is used so all instances share one clock.
+// Returns {allowed, microseconds until the bucket is full, microseconds until retry}.
+var rateLimitScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+  tat = now
+end
+local new_tat = tat + cost * interval
+local allow_at = new_tat - burst
+if now < allow_at then
+  return {0, tat - now, allow_at - now}
+end
+redis.call('SET', KEYS[1], string.format('%d', new_tat), 'PX', math.ceil((new_tat - now) / 1000))
+return {1, new_tat - now, 0}
+`)
+
+// routeCost is the number of units a request matching pattern consumes
+type routeCost struct {
+	segments []string
+	cost     int
+}
+
+// rateDecision is the outcome of charging a bucket
+type rateDecision struct {
+	allowed    bool
+	untilFull  time.Duration // time until the bucket is completely refilled
+	retryAfter time.Duration
+}
+
+// RateLimiter limits API usage per client with a sliding (GCRA) window.
+// Clients are identified by their authenticated API key, never by an unverified
+// header; tiers set the number of units per window and routes can cost more than one unit.
+// Redis is used when available with a per-instance in-memory fallback.
 type RateLimiter struct {
-	redis         *redis.Client
-	maxRequests   int
-	windowSeconds int
+	redis  *redis.Client
+	window time.Duration
+	tiers  map[string]int // units per window, 0 = unlimited
+	costs  []routeCost
+	auth   *APIKeyAuth
+	local  *localBuckets
 }
 
-// NewRateLimiter creates a new rate limiter middleware
-func NewRateLimiter(redisClient *redis.Client, maxRequests, windowSeconds int) *RateLimiter {
+// NewRateLimiter creates a new rate limiter middleware.
+// tiers maps tier names to units per window; redisClient may be nil.
+func NewRateLimiter(redisClient *redis.Client, window time.Duration, tiers map[string]int) *RateLimiter {
+	if window <= 0 {
+		window = time.Minute
+	}
+
+	limits := map[string]int{
+		TierAnonymous: 60,
+		TierStandard:  100,
+		TierPremium:   1000,
+		TierUnlimited: 0,
+	}
+	for tier, limit := range tiers {
+		limits[tier] = limit
+	}
+
 	return &RateLimiter{
-		redis:         redisClient,
-		maxRequests:   maxRequests,
-		windowSeconds: windowSeconds,
+		redis:  redisClient,
+		window: window,
+		tiers:  limits,
+		local:  newLocalBuckets(),
+	}
+}
+
+// SetAuth lets the limiter identify clients by their verified API key
+func (rl *RateLimiter) SetAuth(auth *APIKeyAuth) {
+	rl.auth = auth
+}
+
+// SetRouteCosts sets how many units requests to matching paths consume (default 1).
+// Patterns are path prefixes where "*" matches a single segment, e.g.
+// "/api/v1/articles/*/extract-content". The most specific pattern wins.
+func (rl *RateLimiter) SetRouteCosts(costs map[string]int) {
+	rl.costs = make([]routeCost, 0, len(costs))
+	for pattern, cost := range costs {
+		if cost < 1 {
+			cost = 1
+		}
+		rl.costs = append(rl.costs, routeCost{segments: splitPath(pattern), cost: cost})
+	}
+	sort.SliceStable(rl.costs, func(i, j int) bool {
+		return len(rl.costs[i].segments) > len(rl.costs[j].segments)
+	})
+}
+
+// ParseRouteCosts parses "pattern=cost,pattern=cost" as used by API_RATE_LIMIT_ROUTE_COSTS
+func ParseRouteCosts(spec string) (map[string]int, error) {
+	costs := make(map[string]int)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pattern, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid route cost %q: expected pattern=cost", entry)
+		}
+		cost, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || cost < 1 {
+			return nil, fmt.Errorf("invalid route cost %q: cost must be a positive integer", entry)
+		}
+		costs[strings.TrimSpace(pattern)] = cost
 	}
+	return costs, nil
 }
 
 // Handler returns the Fiber middleware handler
 func (rl *RateLimiter) Handler() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Get client identifier (IP address or API key)
-		identifier := c.IP()
-		if apiKey := c.Get("X-API-Key"); apiKey != "" {
-			identifier = apiKey
+		identifier, tier, failure := rl.identify(c)
+		if failure != nil {
+			return failure.respond(c)
 		}
 
-		// Create Redis key
-		key := fmt.Sprintf("rate_limit:%s", identifier)
-
-		// Get current count
-		ctx := context.Background()
-		count, err := rl.redis.Get(ctx, key).Int()
-		if err != nil && err != redis.Nil {
-			// Log error but don't block the request
+		limit, ok := rl.tiers[tier]
+		if !ok {
+			limit = rl.tiers[TierStandard]
+		}
+		if limit <= 0 {
 			return c.Next()
 		}
 
-		// Check if rate limit exceeded
-		if count >= rl.maxRequests {
-			c.Set("X-RateLimit-Limit", fmt.Sprintf("%d", rl.maxRequests))
-			c.Set("X-RateLimit-Remaining", "0")
-			c.Set("Retry-After", fmt.Sprintf("%d", rl.windowSeconds))
+		cost := rl.costFor(c.Path())
+		if cost > limit {
+			cost = limit
+		}
+
+		decision := rl.take(c.UserContext(), "rate_limit:"+identifier, limit, cost)
+
+		remaining := 0
+		interval := rl.window / time.Duration(limit)
+		if interval > 0 {
+			remaining = int((rl.window - decision.untilFull) / interval)
+		}
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		c.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.untilFull)))
+		c.Set("X-RateLimit-Cost", strconv.Itoa(cost))
 
+		if !decision.allowed {
+			c.Set("Retry-After", strconv.Itoa(ceilSeconds(decision.retryAfter)))
 			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
 				"error":   "Rate limit exceeded",
-				"message": fmt.Sprintf("Maximum %d requests per %d seconds", rl.maxRequests, rl.windowSeconds),
+				"message": fmt.Sprintf("Maximum %d units per %d seconds for the %s tier (this request costs %d)", limit, int(rl.window.Seconds()), tier, cost),
+				"tier":    tier,
 			})
 		}
 
-		// Increment counter
-		pipe := rl.redis.Pipeline()
-		pipe.Incr(ctx, key)
-		if count == 0 {
-			// Set expiry only on first request
-			pipe.Expire(ctx, key, time.Duration(rl.windowSeconds)*time.Second)
+		return c.Next()
+	}
+}
+
+// identify returns the bucket identifier and tier for a request.
+// A non-nil failure (invalid token, exhausted quota) must be sent as the response.
+func (rl *RateLimiter) identify(c *fiber.Ctx) (string, string, *authFailure) {
+	if rl.auth != nil && rl.auth.enabled() && rl.auth.hasCredentials(c) {
+		principal, failure := rl.auth.authenticate(c)
+		if failure != nil {
+			return "", "", failure
 		}
-		_, err = pipe.Exec(ctx)
-		if err != nil {
-			// Log error but don't block the request
-			return c.Next()
+		if principal != nil {
+			if principal.Legacy {
+				return "shared", TierUnlimited, nil
+			}
+			tier := principal.RateTier
+			if tier == "" {
+				tier = TierStandard
+			}
+			if principal.IsUser() {
+				return "user:" + principal.UserID, tier, nil
+			}
+			return fmt.Sprintf("key:%d", principal.KeyID), tier, nil
 		}
+		// Invalid keys are limited like anonymous clients so they cannot mint buckets
+	}
+	return "ip:" + c.IP(), TierAnonymous, nil
+}
 
-		// Set rate limit headers
-		remaining := rl.maxRequests - count - 1
-		if remaining < 0 {
-			remaining = 0
+// costFor returns the units consumed by a request path
+func (rl *RateLimiter) costFor(path string) int {
+	segments := splitPath(path)
+	for _, rc := range rl.costs {
+		if matchSegments(rc.segments, segments) {
+			return rc.cost
 		}
-		c.Set("X-RateLimit-Limit", fmt.Sprintf("%d", rl.maxRequests))
-		c.Set("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+	}
+	return 1
+}
 
-		return c.Next()
+// take charges cost units to a bucket, in Redis when possible
+func (rl *RateLimiter) take(ctx context.Context, key string, limit, cost int) rateDecision {
+	interval := rl.window / time.Duration(limit)
+
+	if rl.redis != nil {
+		redisCtx, cancel := context.WithTimeout(ctx, 250*time.Millisecond)
+		defer cancel()
+
+		res, err := rateLimitScript.Run(redisCtx, rl.redis, []string{key},
+			interval.Microseconds(), rl.window.Microseconds(), cost,
+		).Int64Slice()
+		if err == nil && len(res) == 3 {
+			return rateDecision{
+				allowed:    res[0] == 1,
+				untilFull:  time.Duration(res[1]) * time.Microsecond,
+				retryAfter: time.Duration(res[2]) * time.Microsecond,
+			}
+		}
+		// Redis unavailable: fall back to per-instance buckets instead of disabling limits
+	}
+
+	return rl.local.take(key, interval, rl.window, cost)
+}
+
+// localBuckets is the in-memory GCRA fallback
+type localBuckets struct {
+	mu        sync.Mutex
+	tat       map[string]time.Time
+	lastSweep time.Time
+}
+
+func newLocalBuckets() *localBuckets {
+	return &localBuckets{
+		tat:       make(map[string]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+func (b *localBuckets) take(key string, interval, burst time.Duration, cost int) rateDecision {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(b.lastSweep) > time.Minute {
+		for k, t := range b.tat {
+			if t.Before(now) {
+				delete(b.tat, k)
+			}
+		}
+		b.lastSweep = now
+	}
+
+	tat, ok := b.tat[key]
+	if !ok || tat.Before(now) {
+		tat = now
+	}
+
+	newTat := tat.Add(time.Duration(cost) * interval)
+	allowAt := newTat.Add(-burst)
+	if now.Before(allowAt) {
+		return rateDecision{
+			allowed:    false,
+			untilFull:  tat.Sub(now),
+			retryAfter: allowAt.Sub(now),
+		}
+	}
+
+	b.tat[key] = newTat
+	return rateDecision{allowed: true, untilFull: newTat.Sub(now)}
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// matchSegments reports whether path starts with pattern; "*" matches any single segment
+func matchSegments(pattern, path []string) bool {
+	if len(path) < len(pattern) {
+		return false
+	}
+	for i, seg := range pattern {
+		if seg != "*" && seg != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
 	}
+	return int(math.Ceil(d.Seconds()))
 }
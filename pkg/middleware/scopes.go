@@ -0,0 +1,62 @@
+package middleware
+
+import "sort"
+
+// API key scopes. Route groups declare the scope they require with APIKeyAuth.Require.
+const (
+	ScopeReadArticles   = "read:articles"   // Public read endpoints (only enforced when reads require a key)
+	ScopeWriteArticles  = "write:articles"  // Content extraction, language backfill
+	ScopeWriteScrape    = "write:scrape"    // Trigger scrapes, email fetching, scraper/email stats
+	ScopeAIChat         = "ai:chat"         // Conversational AI endpoint
+	ScopeAIProcess      = "ai:process"      // Trigger AI enrichment
+	ScopeAITranslate    = "ai:translate"    // New ?lang= translations; others only get cached ones
+	ScopeSavedSearches  = "searches:save"   // Saved searches and their alerts
+	ScopeExportArticles = "export:articles" // Bulk export of articles and enrichments
+	ScopeAdminConfig    = "admin:config"    // Configuration, cache management, security stats, symbol master
+	ScopeAdminEntities  = "admin:entities"  // Entity registry merges, splits, imports and backfills
+	ScopeAdminKeys      = "admin:keys"      // Issue, rotate and revoke API keys
+	ScopeAdminAudit     = "admin:audit"     // Read the audit log
+	ScopeAdminWebhooks  = "admin:webhooks"  // Manage webhook subscriptions
+)
+
+// scopeDescriptions documents every known scope
+var scopeDescriptions = map[string]string{
+	ScopeReadArticles:   "Read articles, search, analytics and stock data",
+	ScopeWriteArticles:  "Extract article content and backfill article languages",
+	ScopeWriteScrape:    "Trigger scraping and email fetching, view scraper and email stats",
+	ScopeAIChat:         "Use the conversational AI endpoint",
+	ScopeAIProcess:      "Trigger AI enrichment of articles",
+	ScopeAITranslate:    "Translate articles on demand with ?lang= (without it only cached translations are served)",
+	ScopeSavedSearches:  "Save searches, view their new results and manage their alerts",
+	ScopeExportArticles: "Bulk export articles with their AI enrichment as NDJSON, CSV or Parquet",
+	ScopeAdminConfig:    "Change configuration, manage the cache, view security stats, refresh symbols",
+	ScopeAdminEntities:  "Merge, split, import and backfill canonical entities",
+	ScopeAdminKeys:      "Issue, rotate and revoke API keys",
+	ScopeAdminAudit:     "Read the audit log of administrative operations",
+	ScopeAdminWebhooks:  "Manage webhook subscriptions and inspect their deliveries",
+}
+
+// AllScopes returns every known scope in sorted order
+func AllScopes() []string {
+	scopes := make([]string, 0, len(scopeDescriptions))
+	for scope := range scopeDescriptions {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// ScopeDescriptions returns a copy of the scope documentation
+func ScopeDescriptions() map[string]string {
+	result := make(map[string]string, len(scopeDescriptions))
+	for scope, desc := range scopeDescriptions {
+		result[scope] = desc
+	}
+	return result
+}
+
+// IsValidScope reports whether a scope is known
+func IsValidScope(scope string) bool {
+	_, ok := scopeDescriptions[scope]
+	return ok
+}
@@ -222,3 +222,15 @@ func (m *CircuitBreakerManager) ResetAll() {
 		cb.Reset()
 	}
 }
+
+// States returns the current state of every circuit breaker by name
+func (m *CircuitBreakerManager) States() map[string]CircuitState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	states := make(map[string]CircuitState, len(m.breakers))
+	for name, cb := range m.breakers {
+		states[name] = cb.GetState()
+	}
+	return states
+}